@@ -0,0 +1,107 @@
+package glee
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// annotationPrefix is the prefix of directives in a function's doc comment.
+const annotationPrefix = "//glee:"
+
+// funcAnnotations represents the directives in the doc comment of a function
+// which guide the executor, such as:
+//
+//	//glee:skip
+//	//glee:maxpaths=100
+//
+// Annotations are read from the function's syntax so the package must be
+// built in debug mode. Otherwise, annotations are ignored.
+type funcAnnotations struct {
+	// If true, calls to the function bind fresh symbolic values to their
+	// results instead of executing the function.
+	skip bool
+
+	// Maximum number of states forked by branches within the function.
+	// States forked after the limit is reached are exhausted. Zero is
+	// unlimited.
+	maxPaths int
+}
+
+// parseFuncAnnotations returns the annotations in the doc comment of fn.
+// Returns nil if fn has no annotations.
+func parseFuncAnnotations(fn *ssa.Function) (*funcAnnotations, error) {
+	decl, ok := fn.Syntax().(*ast.FuncDecl)
+	if !ok || decl.Doc == nil {
+		return nil, nil
+	}
+
+	var a *funcAnnotations
+	for _, c := range decl.Doc.List {
+		if !strings.HasPrefix(c.Text, annotationPrefix) {
+			continue
+		} else if a == nil {
+			a = &funcAnnotations{}
+		}
+
+		name, value := strings.TrimPrefix(c.Text, annotationPrefix), ""
+		if i := strings.Index(name, "="); i != -1 {
+			name, value = name[:i], name[i+1:]
+		}
+
+		switch name {
+		case "skip":
+			a.skip = true
+		case "maxpaths":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("glee: invalid maxpaths annotation on %s: %q", fn, value)
+			}
+			a.maxPaths = n
+		default:
+			return nil, fmt.Errorf("glee: unknown annotation on %s: %s", fn, c.Text)
+		}
+	}
+	return a, nil
+}
+
+// annotations returns the annotations of fn, parsing them on first use.
+// Returns nil if fn has no annotations.
+func (e *Executor) annotations(fn *ssa.Function) (*funcAnnotations, error) {
+	if a, ok := e.funcAnnotations[fn]; ok {
+		return a, nil
+	}
+	a, err := parseFuncAnnotations(fn)
+	if err != nil {
+		return nil, err
+	}
+	e.funcAnnotations[fn] = a
+	return a, nil
+}
+
+// checkPathLimit exhausts state if it was forked by a branch within a
+// function whose maxpaths annotation has been reached. Invalid annotations
+// are ignored here as they are reported when the function is called.
+func (e *Executor) checkPathLimit(state *ExecutionState) {
+	if state.parent == nil || state.depth <= state.parent.depth {
+		return
+	}
+	frame := state.parent.Frame()
+	if frame == nil {
+		return
+	}
+
+	a, _ := e.annotations(frame.fn)
+	if a == nil || a.maxPaths == 0 {
+		return
+	}
+
+	if e.funcPathN[frame.fn] >= a.maxPaths {
+		exhaust(state, fmt.Sprintf("path limit reached: %s", frame.fn.Name()))
+		return
+	}
+	e.funcPathN[frame.fn]++
+}
@@ -9,6 +9,11 @@ type Array struct {
 	ID      uint64       // unique id
 	Size    uint         // width, in bytes
 	Updates *ArrayUpdate // linked list of symbolic updates
+
+	// Halves of a concatenated array. Concatenation is deferred until the
+	// bytes are accessed so strings built by repeated concatenation are not
+	// copied on every step. See concatArray().
+	Left, Right *Array
 }
 
 // NewArray returns a new Array of the given size.
@@ -33,15 +38,91 @@ func (a *Array) Clone() *Array {
 		ID:      a.ID,
 		Size:    a.Size,
 		Updates: a.Updates,
+		Left:    a.Left,
+		Right:   a.Right,
+	}
+}
+
+// concatArray returns an array of the bytes of x followed by the bytes of y.
+// The bytes are not copied until the array is first accessed.
+func concatArray(x, y *Array) *Array {
+	if x.Size == 0 {
+		return y
+	} else if y.Size == 0 {
+		return x
+	}
+	return &Array{Size: x.Size + y.Size, Left: x, Right: y}
+}
+
+// flatten copies the bytes of a concatenated array into its updates. This is
+// a no-op for other arrays.
+func (a *Array) flatten() {
+	if a.Left == nil {
+		return
+	}
+	a.Updates = newArrayUpdates(a.bytes())
+	a.Left, a.Right = nil, nil
+}
+
+// bytes returns the value of every byte in the array. This is equivalent to
+// calling selectByte() for each index but only traverses the updates once.
+func (a *Array) bytes() []Expr {
+	values := make([]Expr, 0, a.Size)
+
+	// Concatenated arrays are traversed in order using an explicit stack as
+	// strings built in a loop can be deeply nested.
+	stack := []*Array{a}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node.Left != nil {
+			stack = append(stack, node.Right, node.Left)
+			continue
+		}
+		values = append(values, node.leafBytes()...)
+	}
+	return values
+}
+
+// leafBytes returns the value of every byte of an array which is not
+// concatenated. Bytes without a concrete update, or shadowed by an update at
+// a symbolic index, are read with a select expression.
+func (a *Array) leafBytes() []Expr {
+	values := make([]Expr, a.Size)
+	for upd := a.Updates; upd != nil; upd = upd.Next {
+		index, ok := upd.Index.(*ConstantExpr)
+		if !ok {
+			break // found symbolic index, exit
+		} else if index.Value < uint64(a.Size) && values[index.Value] == nil {
+			values[index.Value] = upd.Value
+		}
+	}
+
+	for i := range values {
+		if values[i] == nil {
+			values[i] = NewSelectExpr(a, NewConstantExpr64(uint64(i)))
+		}
+	}
+	return values
+}
+
+// newArrayUpdates returns a chain of updates writing each value at its index.
+func newArrayUpdates(values []Expr) *ArrayUpdate {
+	var upd *ArrayUpdate
+	for i := len(values) - 1; i >= 0; i-- {
+		upd = NewArrayUpdate(NewConstantExpr64(uint64(i)), values[i], upd)
 	}
+	return upd
 }
 
 // zero initializes all bytes to zero in-place. Panic if updates already exist.
 func (a *Array) zero() {
 	assert(a.Updates == nil, "glee.Array: cannot zero-initialize array with updates")
-	for i := uint((0)); i < a.Size; i++ {
-		a.storeByte(NewConstantExpr64(uint64(i)), NewConstantExpr(0, 8))
+	values := make([]Expr, a.Size)
+	for i := range values {
+		values[i] = NewConstantExpr(0, 8)
 	}
+	a.Updates = newArrayUpdates(values)
 }
 
 // Select reads a value from the array.
@@ -79,6 +160,7 @@ func (a *Array) Select(offset Expr, width uint, isLittleEndian bool) Expr {
 // index is symbolic.
 func (a *Array) selectByte(index Expr) Expr {
 	assert(ExprWidth(index) == 64, "selectByte: invalid array index width: %d", ExprWidth(index))
+	a.flatten()
 	for upd := a.Updates; upd != nil; upd = upd.Next {
 		cond, ok := NewBinaryExpr(EQ, index, upd.Index).(*ConstantExpr)
 		if !ok {
@@ -124,27 +206,77 @@ func (a *Array) storeByte(index, value Expr) {
 	if index, ok := index.(*ConstantExpr); ok {
 		assert(index.Value < uint64(a.Size), "storeByte: index out of bounds: %d < %d", index.Value, a.Size)
 	}
+	a.flatten()
 
-	// Add update to the head of the chain.
+	// Skip writes at a constant index which do not change a constant byte.
+	_, isConstIndex := index.(*ConstantExpr)
+	if value, ok := value.(*ConstantExpr); ok && isConstIndex {
+		if prev, ok := a.selectByte(index).(*ConstantExpr); ok && prev.Value == value.Value {
+			return
+		}
+	}
+
+	// Add update to the head of the chain. Writes at a constant index may
+	// make older updates unreachable so the chain is compacted.
 	a.Updates = NewArrayUpdate(index, value, a.Updates)
+	if isConstIndex {
+		a.Updates = compactUpdates(a.Updates, a.Size)
+	}
+}
 
-	// Remove any previous updates to the index from the chain.
-	if index, ok := index.(*ConstantExpr); ok {
-		prev := a.Updates
-		for upd := prev.Next; upd != nil; upd = upd.Next {
-			if updIndex, ok := upd.Index.(*ConstantExpr); !ok {
-				break // symbolic index
-			} else if index.Value == updIndex.Value {
-				prev.Next = upd.Next // matching index, remove
-			} else {
-				prev = upd // no matching index, continue
+// compactUpdates returns an update chain equivalent to upd for an array of
+// size bytes. Updates at a constant index which are overwritten by a newer
+// update at the same index are removed. Once every byte has been written at
+// a constant index, all older updates, including those at symbolic indexes,
+// are removed as no read can reach them.
+//
+// Chains are shared between arrays so nodes newer than a removed update are
+// copied rather than modified. Returns upd if nothing is removed.
+func compactUpdates(upd *ArrayUpdate, size uint) *ArrayUpdate {
+	written := make(map[uint64]struct{})
+
+	// Find updates which are kept along with the oldest removed update and
+	// the number of kept updates after it, which can be shared as-is.
+	var kept []*ArrayUpdate
+	var removed *ArrayUpdate
+	var sharedN int
+	truncated := false
+	for u := upd; u != nil; u = u.Next {
+		if uint(len(written)) == size {
+			truncated = true
+			break
+		}
+
+		if index, ok := u.Index.(*ConstantExpr); ok {
+			if _, ok := written[index.Value]; ok || index.Value >= uint64(size) {
+				removed, sharedN = u, 0
+				continue
 			}
+			written[index.Value] = struct{}{}
 		}
+		kept, sharedN = append(kept, u), sharedN+1
 	}
+
+	var tail *ArrayUpdate
+	if truncated {
+		sharedN = 0
+	} else if removed == nil {
+		return upd
+	} else {
+		tail = removed.Next
+	}
+
+	// Copy the kept updates newer than the shared suffix.
+	for i := len(kept) - sharedN - 1; i >= 0; i-- {
+		tail = &ArrayUpdate{Index: kept[i].Index, Value: kept[i].Value, Next: tail}
+	}
+	return tail
 }
 
 // IsSymbolic returns true if any bytes in the array are symbolic.
 func (a *Array) IsSymbolic() bool {
+	a.flatten()
+
 	// Mark all bytes with concrete values.
 	bytes := make([]bool, a.Size)
 	for upd := a.Updates; upd != nil; upd = upd.Next {
@@ -175,10 +307,9 @@ func (a *Array) Equal(other *Array) Expr {
 	// Check equality for every byte.
 	// Exit early if any concrete byte is unequal.
 	var cond Expr
+	xs, ys := a.bytes(), other.bytes()
 	for i := uint(0); i < a.Size; i++ {
-		// Select one at index from each array.
-		index := NewConstantExpr64(uint64(i))
-		x, y := a.selectByte(index), other.selectByte(index)
+		x, y := xs[i], ys[i]
 
 		// Compare bytes, exit if known false.
 		expr := newEqExpr(x, y)
@@ -208,10 +339,9 @@ func (a *Array) NotEqual(other *Array) Expr {
 	// Check inequality for every byte.
 	// Exit early if any concrete byte is unequal.
 	var cond Expr
+	xs, ys := a.bytes(), other.bytes()
 	for i := uint(0); i < a.Size; i++ {
-		// Select one at index from each array.
-		index := NewConstantExpr64(uint64(i))
-		x, y := a.selectByte(index), other.selectByte(index)
+		x, y := xs[i], ys[i]
 
 		// Compare bytes, exit if known inequality.
 		expr := NewNotExpr(newEqExpr(x, y))
@@ -252,6 +382,8 @@ func CompareArray(a, b *Array) int {
 		return 1
 	}
 
+	a.flatten()
+	b.flatten()
 	return CompareArrayUpdate(a.Updates, b.Updates)
 }
 
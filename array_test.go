@@ -256,10 +256,6 @@ func TestArray(t *testing.T) {
 								Width: 64,
 							},
 							Value: glee.NewConstantExpr8(1),
-							Next: &glee.ArrayUpdate{
-								Index: glee.NewConstantExpr64(0),
-								Value: glee.NewConstantExpr8(0),
-							},
 						},
 					},
 				},
@@ -268,6 +264,56 @@ func TestArray(t *testing.T) {
 				t.Fatal(diff)
 			}
 		})
+
+		// Ensure updates older than the point at which every byte is written
+		// are removed, even if their index is symbolic.
+		t.Run("FullyWritten", func(t *testing.T) {
+			a, b := glee.NewArray(0, 2), glee.NewArray(0, 1)
+			a = a.Store(b.Select(glee.NewConstantExpr64(0), 8, false), glee.NewConstantExpr8(1), false) // symbolic index
+			a = a.Store(glee.NewConstantExpr64(0), glee.NewConstantExpr(0x0302, 16), false)
+
+			if diff := cmp.Diff(
+				&glee.Array{
+					Size: 2,
+					Updates: &glee.ArrayUpdate{
+						Index: glee.NewConstantExpr64(0),
+						Value: glee.NewConstantExpr8(3),
+						Next: &glee.ArrayUpdate{
+							Index: glee.NewConstantExpr64(1),
+							Value: glee.NewConstantExpr8(2),
+						},
+					},
+				},
+				a,
+			); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		// Ensure writing the existing value of a constant byte is skipped.
+		t.Run("Unchanged", func(t *testing.T) {
+			a := glee.NewArray(0, 2)
+			a = a.Store(glee.NewConstantExpr64(0), glee.NewConstantExpr8(1), false)
+			b := a.Store(glee.NewConstantExpr64(0), glee.NewConstantExpr8(1), false)
+			if b.Updates != a.Updates {
+				t.Fatal("expected updates to be unchanged")
+			}
+		})
+
+		// Ensure removing an update from a chain does not modify other arrays
+		// which share the chain.
+		t.Run("SharedChain", func(t *testing.T) {
+			a := glee.NewArray(0, 4)
+			a = a.Store(glee.NewConstantExpr64(0), glee.NewConstantExpr8(1), false)
+			a = a.Store(glee.NewConstantExpr64(1), glee.NewConstantExpr8(2), false)
+			b := a.Store(glee.NewConstantExpr64(0), glee.NewConstantExpr8(3), false)
+
+			if expr, ok := a.Select(glee.NewConstantExpr64(0), 8, false).(*glee.ConstantExpr); !ok || expr.Value != 1 {
+				t.Fatalf("unexpected original value: %v", expr)
+			} else if expr, ok := b.Select(glee.NewConstantExpr64(0), 8, false).(*glee.ConstantExpr); !ok || expr.Value != 3 {
+				t.Fatalf("unexpected new value: %v", expr)
+			}
+		})
 	})
 
 	t.Run("IsSymbolic", func(t *testing.T) {
@@ -0,0 +1,599 @@
+//go:build bitwuzla
+// +build bitwuzla
+
+package bitwuzla
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/benbjohnson/glee"
+)
+
+/*
+#cgo LDFLAGS: -lbitwuzla
+#include <bitwuzla/c/bitwuzla.h>
+#include <stdlib.h>
+#include <stdint.h>
+#include <time.h>
+
+// glee_terminator holds the state checked by Bitwuzla while solving.
+typedef struct {
+	int32_t interrupted;
+	int64_t deadline; // monotonic nanoseconds, zero if none
+} glee_terminator;
+
+static int64_t glee_now(void) {
+	struct timespec ts;
+	clock_gettime(CLOCK_MONOTONIC, &ts);
+	return (int64_t)ts.tv_sec * 1000000000 + ts.tv_nsec;
+}
+
+static int32_t glee_terminate(void *state) {
+	glee_terminator *t = (glee_terminator*)state;
+	if (__atomic_load_n(&t->interrupted, __ATOMIC_SEQ_CST)) {
+		return 1;
+	}
+	return t->deadline > 0 && glee_now() >= t->deadline;
+}
+
+static void glee_set_termination_callback(Bitwuzla *b, glee_terminator *t) {
+	bitwuzla_set_termination_callback(b, glee_terminate, t);
+}
+*/
+import "C"
+
+// Ensure solver implements interface.
+var _ glee.ContextSolver = (*Solver)(nil)
+var _ glee.SolverStats = (*Solver)(nil)
+
+// Solver represents a solver that uses an embedded Bitwuzla solver. Each query
+// is checked by a new Bitwuzla instance & terms are released afterward.
+type Solver struct {
+	tm    *C.BitwuzlaTermManager
+	term  *C.glee_terminator
+	stats glee.QueryStats
+
+	// Maximum memory used by each query, in megabytes. Zero is unlimited.
+	MaxMemory int
+
+	// Seed used by Bitwuzla for randomized heuristics. Zero uses the
+	// Bitwuzla default.
+	RandomSeed uint
+}
+
+// NewSolver returns a new instance of Solver.
+func NewSolver() *Solver {
+	term := (*C.glee_terminator)(C.calloc(1, C.sizeof_glee_terminator))
+	return &Solver{
+		tm:   C.bitwuzla_term_manager_new(),
+		term: term,
+	}
+}
+
+// Close deletes the underlying Bitwuzla term manager.
+func (s *Solver) Close() error {
+	C.bitwuzla_term_manager_delete(s.tm)
+	C.free(unsafe.Pointer(s.term))
+	return nil
+}
+
+// Interrupt cancels an in-flight call to Solve(). The interrupted call returns
+// glee.ErrSolverCanceled. This is safe to call from another goroutine.
+func (s *Solver) Interrupt() {
+	atomic.StoreInt32((*int32)(unsafe.Pointer(&s.term.interrupted)), 1)
+}
+
+// QueryStats returns statistics for the queries answered by the solver.
+func (s *Solver) QueryStats() glee.QueryStats {
+	return s.stats
+}
+
+// Solve returns the satisfiability of the constraints & a value for each array.
+func (s *Solver) Solve(constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	return s.solve(constraints, arrays, 0)
+}
+
+// SolveContext solves the constraints, same as Solve(), but stops early if ctx
+// is done. The context deadline is checked by Bitwuzla while solving and
+// cancellation interrupts the in-flight query. Returns ctx.Err() if ctx is done.
+func (s *Solver) SolveContext(ctx context.Context, constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, nil, err
+	}
+
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return false, nil, context.DeadlineExceeded
+		}
+	}
+
+	// Interrupt the query if the context is cancelled while solving.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Interrupt()
+		case <-done:
+		}
+	}()
+
+	satisfiable, values, err = s.solve(constraints, arrays, timeout)
+	if err != nil && ctx.Err() != nil {
+		return false, nil, ctx.Err()
+	}
+	return satisfiable, values, err
+}
+
+// solve returns the satisfiability of the constraints. If timeout is non-zero
+// then the query stops after the given duration.
+func (s *Solver) solve(constraints []glee.Expr, arrays []*glee.Array, timeout time.Duration) (satisfiable bool, values [][]byte, err error) {
+	t := time.Now()
+	defer func() {
+		s.stats.Record(len(constraints), satisfiable, time.Since(t), err)
+	}()
+
+	// Terms are only valid for a single query.
+	ctx := newBuilder(s.tm)
+	defer C.bitwuzla_term_manager_release(s.tm)
+
+	options := C.bitwuzla_options_new()
+	defer C.bitwuzla_options_delete(options)
+	C.bitwuzla_set_option(options, C.BITWUZLA_OPT_PRODUCE_MODELS, 1)
+	if s.MaxMemory > 0 {
+		C.bitwuzla_set_option(options, C.BITWUZLA_OPT_MEMORY_LIMIT, C.uint64_t(s.MaxMemory))
+	}
+	if s.RandomSeed != 0 {
+		C.bitwuzla_set_option(options, C.BITWUZLA_OPT_SEED, C.uint64_t(s.RandomSeed))
+	}
+
+	b := C.bitwuzla_new(s.tm, options)
+	defer C.bitwuzla_delete(b)
+
+	atomic.StoreInt32((*int32)(unsafe.Pointer(&s.term.interrupted)), 0)
+	s.term.deadline = 0
+	if timeout > 0 {
+		s.term.deadline = C.glee_now() + C.int64_t(timeout)
+	}
+	C.glee_set_termination_callback(b, s.term)
+
+	// Assert constraints along with any side conditions of their encoding.
+	for _, constraint := range constraints {
+		term, err := ctx.toTerm(constraint)
+		if err != nil {
+			return false, nil, err
+		}
+		C.bitwuzla_assert(b, term)
+	}
+	for _, term := range ctx.side {
+		C.bitwuzla_assert(b, term)
+	}
+
+	// Check equations with the solver.
+	// Exit immediately if unsatisfiable or the solver stopped early.
+	switch C.bitwuzla_check_sat(b) {
+	case C.BITWUZLA_UNSAT:
+		return false, nil, nil
+	case C.BITWUZLA_UNKNOWN:
+		switch {
+		case atomic.LoadInt32((*int32)(unsafe.Pointer(&s.term.interrupted))) != 0:
+			return false, nil, glee.ErrSolverCanceled
+		case timeout > 0 && C.glee_now() >= s.term.deadline:
+			return false, nil, glee.ErrSolverTimeout
+		default:
+			return false, nil, glee.ErrSolverUnknown
+		}
+	}
+	if len(arrays) == 0 {
+		return true, nil, nil // no symbolics, ignore model
+	}
+
+	// Fetch values for symbolic arrays.
+	values = make([][]byte, 0, len(arrays))
+	for _, array := range arrays {
+		value, err := ctx.evalArray(b, array)
+		if err != nil {
+			return true, nil, err
+		}
+		values = append(values, value)
+	}
+	return true, values, nil
+}
+
+// builder constructs the terms of a single query.
+type builder struct {
+	tm *C.BitwuzlaTermManager
+
+	// Root constant of each array, by ID. Bitwuzla creates a distinct
+	// constant on every call so each array is only created once.
+	arrays map[uint64]C.BitwuzlaTerm
+
+	// Side conditions of the encoding, asserted alongside the constraints.
+	side []C.BitwuzlaTerm
+}
+
+// newBuilder returns a new builder for constructing terms with tm.
+func newBuilder(tm *C.BitwuzlaTermManager) *builder {
+	return &builder{tm: tm, arrays: make(map[uint64]C.BitwuzlaTerm)}
+}
+
+// toTerm returns a new Bitwuzla term from a glee expression.
+func (ctx *builder) toTerm(expr glee.Expr) (C.BitwuzlaTerm, error) {
+	switch expr := expr.(type) {
+	case *glee.ConstantExpr:
+		return ctx.toConstantTerm(expr), nil
+	case *glee.NotOptimizedExpr:
+		return ctx.toTerm(expr.Src)
+	case *glee.SelectExpr:
+		return ctx.toSelectTerm(expr)
+	case *glee.ConcatExpr:
+		return ctx.toBinaryKindTerm(C.BITWUZLA_KIND_BV_CONCAT, expr.MSB, expr.LSB)
+	case *glee.ExtractExpr:
+		return ctx.toExtractTerm(expr)
+	case *glee.CastExpr:
+		return ctx.toCastTerm(expr)
+	case *glee.NotExpr:
+		return ctx.toNotTerm(expr)
+	case *glee.BinaryExpr:
+		return ctx.toBinaryTerm(expr)
+	case *glee.FPBinaryExpr:
+		return ctx.toFPBinaryTerm(expr)
+	case *glee.FPCastExpr:
+		return ctx.toFPCastTerm(expr)
+	case *glee.IteExpr:
+		return ctx.toIteTerm(expr)
+	default:
+		return nil, fmt.Errorf("bitwuzla: invalid expression type: %T", expr)
+	}
+}
+
+func (ctx *builder) toConstantTerm(expr *glee.ConstantExpr) C.BitwuzlaTerm {
+	if expr.Width == 1 {
+		if expr.IsTrue() {
+			return C.bitwuzla_mk_true(ctx.tm)
+		}
+		return C.bitwuzla_mk_false(ctx.tm)
+	} else if expr.Width <= 64 {
+		return ctx.makeUint64(expr.Width, expr.Value)
+	}
+
+	cvalue := C.CString(expr.BigInt().String())
+	defer C.free(unsafe.Pointer(cvalue))
+	return C.bitwuzla_mk_bv_value(ctx.tm, ctx.makeBVSort(expr.Width), cvalue, 10)
+}
+
+func (ctx *builder) toSelectTerm(expr *glee.SelectExpr) (C.BitwuzlaTerm, error) {
+	index, err := ctx.toTerm(expr.Index)
+	if err != nil {
+		return nil, err
+	}
+	array, err := ctx.makeArrayWithUpdate(expr.Array, expr.Array.Updates)
+	if err != nil {
+		return nil, err
+	}
+	return C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_ARRAY_SELECT, array, index), nil
+}
+
+func (ctx *builder) toExtractTerm(expr *glee.ExtractExpr) (C.BitwuzlaTerm, error) {
+	src, err := ctx.toTerm(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// If extracting single bit, use EQ expression to convert to bool sort.
+	if expr.Width == 1 {
+		bit := C.bitwuzla_mk_term1_indexed2(ctx.tm, C.BITWUZLA_KIND_BV_EXTRACT, src, C.uint64_t(expr.Offset), C.uint64_t(expr.Offset))
+		return C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_EQUAL, bit, ctx.makeUint64(1, 1)), nil
+	}
+	return C.bitwuzla_mk_term1_indexed2(ctx.tm, C.BITWUZLA_KIND_BV_EXTRACT, src, C.uint64_t(expr.Offset+expr.Width-1), C.uint64_t(expr.Offset)), nil
+}
+
+func (ctx *builder) toCastTerm(expr *glee.CastExpr) (C.BitwuzlaTerm, error) {
+	src, err := ctx.toTerm(expr.Src)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert boolean cast to if-then-else expression.
+	srcWidth := glee.ExprWidth(expr.Src)
+	if srcWidth == 1 {
+		whenTrue := ctx.makeUint64(expr.Width, 1)
+		if expr.Signed {
+			minusOne := int64(-1)
+			whenTrue = ctx.makeUint64(expr.Width, uint64(minusOne))
+		}
+		return C.bitwuzla_mk_term3(ctx.tm, C.BITWUZLA_KIND_ITE, src, whenTrue, ctx.makeUint64(expr.Width, 0)), nil
+	}
+
+	kind := C.BitwuzlaKind(C.BITWUZLA_KIND_BV_ZERO_EXTEND)
+	if expr.Signed {
+		kind = C.BITWUZLA_KIND_BV_SIGN_EXTEND
+	}
+	return C.bitwuzla_mk_term1_indexed1(ctx.tm, kind, src, C.uint64_t(expr.Width-srcWidth)), nil
+}
+
+func (ctx *builder) toNotTerm(expr *glee.NotExpr) (C.BitwuzlaTerm, error) {
+	src, err := ctx.toTerm(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// If boolean, use boolean NOT operation.
+	if glee.ExprWidth(expr.Expr) == 1 {
+		return C.bitwuzla_mk_term1(ctx.tm, C.BITWUZLA_KIND_NOT, src), nil
+	}
+	return C.bitwuzla_mk_term1(ctx.tm, C.BITWUZLA_KIND_BV_NOT, src), nil
+}
+
+func (ctx *builder) toIteTerm(expr *glee.IteExpr) (C.BitwuzlaTerm, error) {
+	cond, err := ctx.toTerm(expr.Cond)
+	if err != nil {
+		return nil, err
+	}
+	then, err := ctx.toTerm(expr.Then)
+	if err != nil {
+		return nil, err
+	}
+	els, err := ctx.toTerm(expr.Else)
+	if err != nil {
+		return nil, err
+	}
+	return C.bitwuzla_mk_term3(ctx.tm, C.BITWUZLA_KIND_ITE, cond, then, els), nil
+}
+
+// binaryKinds maps operations to the kinds of their bit vector terms.
+var binaryKinds = map[glee.BinaryOp]C.BitwuzlaKind{
+	glee.ADD:  C.BITWUZLA_KIND_BV_ADD,
+	glee.SUB:  C.BITWUZLA_KIND_BV_SUB,
+	glee.MUL:  C.BITWUZLA_KIND_BV_MUL,
+	glee.UDIV: C.BITWUZLA_KIND_BV_UDIV,
+	glee.SDIV: C.BITWUZLA_KIND_BV_SDIV,
+	glee.UREM: C.BITWUZLA_KIND_BV_UREM,
+	glee.SREM: C.BITWUZLA_KIND_BV_SREM,
+	glee.AND:  C.BITWUZLA_KIND_BV_AND,
+	glee.OR:   C.BITWUZLA_KIND_BV_OR,
+	glee.XOR:  C.BITWUZLA_KIND_BV_XOR,
+	glee.SHL:  C.BITWUZLA_KIND_BV_SHL,
+	glee.LSHR: C.BITWUZLA_KIND_BV_SHR,
+	glee.ASHR: C.BITWUZLA_KIND_BV_ASHR,
+	glee.EQ:   C.BITWUZLA_KIND_EQUAL,
+	glee.ULT:  C.BITWUZLA_KIND_BV_ULT,
+	glee.ULE:  C.BITWUZLA_KIND_BV_ULE,
+	glee.SLT:  C.BITWUZLA_KIND_BV_SLT,
+	glee.SLE:  C.BITWUZLA_KIND_BV_SLE,
+}
+
+// boolKinds maps logical operations to the kinds of their boolean terms.
+var boolKinds = map[glee.BinaryOp]C.BitwuzlaKind{
+	glee.AND: C.BITWUZLA_KIND_AND,
+	glee.OR:  C.BITWUZLA_KIND_OR,
+	glee.XOR: C.BITWUZLA_KIND_XOR,
+	glee.EQ:  C.BITWUZLA_KIND_EQUAL,
+}
+
+func (ctx *builder) toBinaryTerm(expr *glee.BinaryExpr) (C.BitwuzlaTerm, error) {
+	kind, ok := binaryKinds[expr.Op]
+	if !ok {
+		return nil, fmt.Errorf("bitwuzla: unexpected operation: %s", expr.Op)
+	}
+
+	// Logical operations on booleans use boolean terms.
+	if glee.ExprWidth(expr.LHS) == 1 {
+		if kind, ok = boolKinds[expr.Op]; !ok {
+			return nil, fmt.Errorf("bitwuzla: unexpected boolean operation: %s", expr.Op)
+		}
+	}
+
+	// Shift amounts must have the sort of the shifted value.
+	switch expr.Op {
+	case glee.SHL, glee.LSHR, glee.ASHR:
+		return ctx.toBinaryKindTerm(kind, expr.LHS, glee.ShiftAmountExpr(expr.RHS, glee.ExprWidth(expr.LHS)))
+	}
+	return ctx.toBinaryKindTerm(kind, expr.LHS, expr.RHS)
+}
+
+// toBinaryKindTerm returns a term of the given kind applied to lhs & rhs.
+func (ctx *builder) toBinaryKindTerm(kind C.BitwuzlaKind, lhs, rhs glee.Expr) (C.BitwuzlaTerm, error) {
+	x, err := ctx.toTerm(lhs)
+	if err != nil {
+		return nil, err
+	}
+	y, err := ctx.toTerm(rhs)
+	if err != nil {
+		return nil, err
+	}
+	return C.bitwuzla_mk_term2(ctx.tm, kind, x, y), nil
+}
+
+// toFPBinaryTerm converts the IEEE 754 bit vector operands to floating-point
+// terms, applies the operation, and converts arithmetic results back.
+func (ctx *builder) toFPBinaryTerm(expr *glee.FPBinaryExpr) (C.BitwuzlaTerm, error) {
+	lhs, err := ctx.toFPTerm(expr.LHS)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := ctx.toFPTerm(expr.RHS)
+	if err != nil {
+		return nil, err
+	}
+
+	rm := C.bitwuzla_mk_rm_value(ctx.tm, C.BITWUZLA_RM_RNE)
+
+	var kind C.BitwuzlaKind
+	switch expr.Op {
+	case glee.FADD:
+		kind = C.BITWUZLA_KIND_FP_ADD
+	case glee.FSUB:
+		kind = C.BITWUZLA_KIND_FP_SUB
+	case glee.FMUL:
+		kind = C.BITWUZLA_KIND_FP_MUL
+	case glee.FDIV:
+		kind = C.BITWUZLA_KIND_FP_DIV
+	case glee.FEQ:
+		return C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_FP_EQUAL, lhs, rhs), nil
+	case glee.FLT:
+		return C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_FP_LT, lhs, rhs), nil
+	case glee.FLE:
+		return C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_FP_LEQ, lhs, rhs), nil
+	default:
+		return nil, fmt.Errorf("bitwuzla: unexpected operation: %s", expr.Op)
+	}
+	return ctx.toIEEEBV(C.bitwuzla_mk_term3(ctx.tm, kind, rm, lhs, rhs), glee.ExprWidth(expr.LHS))
+}
+
+func (ctx *builder) toFPCastTerm(expr *glee.FPCastExpr) (C.BitwuzlaTerm, error) {
+	// Go rounds to nearest when converting to floats & truncates to integers.
+	rne := C.bitwuzla_mk_rm_value(ctx.tm, C.BITWUZLA_RM_RNE)
+	rtz := C.bitwuzla_mk_rm_value(ctx.tm, C.BITWUZLA_RM_RTZ)
+
+	switch expr.Op {
+	case glee.FPEXT, glee.FPTOSI, glee.FPTOUI:
+		src, err := ctx.toFPTerm(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+
+		switch expr.Op {
+		case glee.FPTOSI:
+			return C.bitwuzla_mk_term2_indexed1(ctx.tm, C.BITWUZLA_KIND_FP_TO_SBV, rtz, src, C.uint64_t(expr.Width)), nil
+		case glee.FPTOUI:
+			return C.bitwuzla_mk_term2_indexed1(ctx.tm, C.BITWUZLA_KIND_FP_TO_UBV, rtz, src, C.uint64_t(expr.Width)), nil
+		}
+
+		exp, sig, err := fpFormat(expr.Width)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.toIEEEBV(C.bitwuzla_mk_term2_indexed2(ctx.tm, C.BITWUZLA_KIND_FP_TO_FP_FROM_FP, rne, src, exp, sig), expr.Width)
+
+	case glee.SITOFP, glee.UITOFP:
+		src, err := ctx.toTerm(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+		exp, sig, err := fpFormat(expr.Width)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := C.BitwuzlaKind(C.BITWUZLA_KIND_FP_TO_FP_FROM_UBV)
+		if expr.Op == glee.SITOFP {
+			kind = C.BITWUZLA_KIND_FP_TO_FP_FROM_SBV
+		}
+		return ctx.toIEEEBV(C.bitwuzla_mk_term2_indexed2(ctx.tm, kind, rne, src, exp, sig), expr.Width)
+
+	default:
+		return nil, fmt.Errorf("bitwuzla: unexpected operation: %s", expr.Op)
+	}
+}
+
+// toFPTerm returns a floating-point term from an expression holding an IEEE 754 encoding.
+func (ctx *builder) toFPTerm(expr glee.Expr) (C.BitwuzlaTerm, error) {
+	src, err := ctx.toTerm(expr)
+	if err != nil {
+		return nil, err
+	}
+	exp, sig, err := fpFormat(glee.ExprWidth(expr))
+	if err != nil {
+		return nil, err
+	}
+	return C.bitwuzla_mk_term1_indexed2(ctx.tm, C.BITWUZLA_KIND_FP_TO_FP_FROM_BV, src, exp, sig), nil
+}
+
+// toIEEEBV returns a bit vector holding the IEEE 754 encoding of the
+// floating-point term fp. Bitwuzla has no conversion to bit vectors so a new
+// constant is constrained to encode the same value. A NaN result may take any
+// NaN encoding.
+func (ctx *builder) toIEEEBV(fp C.BitwuzlaTerm, width uint) (C.BitwuzlaTerm, error) {
+	exp, sig, err := fpFormat(width)
+	if err != nil {
+		return nil, err
+	}
+
+	bv := C.bitwuzla_mk_const(ctx.tm, ctx.makeBVSort(width), nil)
+	value := C.bitwuzla_mk_term1_indexed2(ctx.tm, C.BITWUZLA_KIND_FP_TO_FP_FROM_BV, bv, exp, sig)
+	ctx.side = append(ctx.side, C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_EQUAL, value, fp))
+	return bv, nil
+}
+
+// fpFormat returns the exponent & significand sizes of the IEEE 754 format
+// for a 32 or 64-bit width.
+func fpFormat(width uint) (exp, sig C.uint64_t, err error) {
+	switch width {
+	case glee.Width32:
+		return 8, 24, nil
+	case glee.Width64:
+		return 11, 53, nil
+	default:
+		return 0, 0, fmt.Errorf("bitwuzla: invalid floating-point width: %d", width)
+	}
+}
+
+func (ctx *builder) makeBVSort(width uint) C.BitwuzlaSort {
+	return C.bitwuzla_mk_bv_sort(ctx.tm, C.uint64_t(width))
+}
+
+func (ctx *builder) makeUint64(width uint, value uint64) C.BitwuzlaTerm {
+	return C.bitwuzla_mk_bv_value_uint64(ctx.tm, ctx.makeBVSort(width), C.uint64_t(value))
+}
+
+// makeArrayConst returns the root constant array with no updates.
+func (ctx *builder) makeArrayConst(array *glee.Array) C.BitwuzlaTerm {
+	if term, ok := ctx.arrays[array.ID]; ok {
+		return term
+	}
+
+	sort := C.bitwuzla_mk_array_sort(ctx.tm, ctx.makeBVSort(glee.Width64), ctx.makeBVSort(glee.Width8))
+
+	cname := C.CString(fmt.Sprintf("A%d", array.ID))
+	defer C.free(unsafe.Pointer(cname))
+
+	term := C.bitwuzla_mk_const(ctx.tm, sort, cname)
+	ctx.arrays[array.ID] = term
+	return term
+}
+
+// makeArrayWithUpdate returns an array with updates recursively applied.
+func (ctx *builder) makeArrayWithUpdate(root *glee.Array, upd *glee.ArrayUpdate) (C.BitwuzlaTerm, error) {
+	if upd == nil {
+		return ctx.makeArrayConst(root), nil
+	}
+
+	array, err := ctx.makeArrayWithUpdate(root, upd.Next)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ctx.toTerm(upd.Index)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ctx.toTerm(upd.Value)
+	if err != nil {
+		return nil, err
+	}
+	return C.bitwuzla_mk_term3(ctx.tm, C.BITWUZLA_KIND_ARRAY_STORE, array, index, value), nil
+}
+
+// evalArray evaluates a single array into its initial byte slice value.
+func (ctx *builder) evalArray(b *C.Bitwuzla, array *glee.Array) ([]byte, error) {
+	root := ctx.makeArrayConst(array)
+
+	value := make([]byte, 0, array.Size)
+	for offset := uint(0); offset < array.Size; offset++ {
+		sel := C.bitwuzla_mk_term2(ctx.tm, C.BITWUZLA_KIND_ARRAY_SELECT, root, ctx.makeUint64(glee.Width64, uint64(offset)))
+		s := C.GoString(C.bitwuzla_term_value_get_str_fmt(C.bitwuzla_get_value(b, sel), 10))
+
+		v, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("bitwuzla: invalid array value: %q", s)
+		}
+		value = append(value, byte(v))
+	}
+	return value, nil
+}
@@ -0,0 +1,168 @@
+//go:build bitwuzla
+// +build bitwuzla
+
+package bitwuzla_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/bitwuzla"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSolver_Solve(t *testing.T) {
+	t.Run("Constant", func(t *testing.T) {
+		t.Run("True", func(t *testing.T) {
+			s := bitwuzla.NewSolver()
+			defer MustCloseSolver(s)
+			if satisfiable, _, err := s.Solve([]glee.Expr{glee.NewBoolConstantExpr(true)}, nil); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			}
+		})
+		t.Run("False", func(t *testing.T) {
+			s := bitwuzla.NewSolver()
+			defer MustCloseSolver(s)
+			if satisfiable, _, err := s.Solve([]glee.Expr{glee.NewBoolConstantExpr(false)}, nil); err != nil {
+				t.Fatal(err)
+			} else if satisfiable {
+				t.Fatal("expected unsatisfiable")
+			}
+		})
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		t.Run("Width16", func(t *testing.T) {
+			s := bitwuzla.NewSolver()
+			defer MustCloseSolver(s)
+
+			array := glee.NewArray(100, 2)
+
+			if satisfiable, values, err := s.Solve(
+				[]glee.Expr{
+					glee.NewBinaryExpr(glee.EQ,
+						array.Select(glee.NewConstantExpr(0, 64), 16, false),
+						glee.NewConstantExpr(0xAABB, 16),
+					),
+				},
+				[]*glee.Array{array},
+			); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if diff := cmp.Diff(values, [][]byte{{0xAA, 0xBB}}); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		// Ensure a read at a symbolic index observes an earlier update.
+		t.Run("SymbolicIndex", func(t *testing.T) {
+			s := bitwuzla.NewSolver()
+			defer MustCloseSolver(s)
+
+			array, indexArray := glee.NewArray(100, 8), glee.NewArray(200, 1)
+			index := glee.NewCastExpr(indexArray.Select(glee.NewConstantExpr64(0), 8, false), 64, false)
+			updated := array.Store(glee.NewConstantExpr64(3), glee.NewConstantExpr(7, 8), false)
+
+			if satisfiable, values, err := s.Solve(
+				[]glee.Expr{
+					glee.NewBinaryExpr(glee.ULT, index, glee.NewConstantExpr64(8)),
+					glee.NewBinaryExpr(glee.EQ, updated.Select(index, 8, false), glee.NewConstantExpr(7, 8)),
+					glee.NewNotExpr(glee.NewBinaryExpr(glee.EQ, index, glee.NewConstantExpr64(3))),
+				},
+				[]*glee.Array{array, indexArray},
+			); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if i := values[1][0]; i >= 8 || i == 3 || values[0][i] != 7 {
+				t.Fatalf("unexpected values: %v", values)
+			}
+		})
+	})
+
+	t.Run("BinaryExpr", func(t *testing.T) {
+		array := glee.NewArray(100, 1)
+		x := array.Select(glee.NewConstantExpr(0, 64), 8, false)
+
+		for _, tt := range []struct {
+			name string
+			expr glee.Expr
+			want byte
+		}{
+			{"ADD", glee.NewBinaryExpr(glee.EQ, glee.NewBinaryExpr(glee.ADD, x, glee.NewConstantExpr(2, 8)), glee.NewConstantExpr(5, 8)), 3},
+			{"MUL", glee.NewBinaryExpr(glee.EQ, glee.NewBinaryExpr(glee.MUL, x, glee.NewConstantExpr(3, 8)), glee.NewConstantExpr(12, 8)), 4},
+			{"XOR", glee.NewBinaryExpr(glee.EQ, glee.NewBinaryExpr(glee.XOR, x, glee.NewConstantExpr(0xFF, 8)), glee.NewConstantExpr(0xF0, 8)), 0x0F},
+			{"SDIV", glee.NewBinaryExpr(glee.EQ, glee.NewBinaryExpr(glee.SDIV, x, glee.NewConstantExpr(1, 8)), glee.NewConstantExpr(0xFE, 8)), 0xFE},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				s := bitwuzla.NewSolver()
+				defer MustCloseSolver(s)
+				if satisfiable, values, err := s.Solve([]glee.Expr{tt.expr}, []*glee.Array{array}); err != nil {
+					t.Fatal(err)
+				} else if !satisfiable {
+					t.Fatal("expected satisfiable")
+				} else if values[0][0] != tt.want {
+					t.Fatalf("unexpected value: %d", values[0][0])
+				}
+			})
+		}
+	})
+
+	// Ensure floating-point results are converted back to bit vectors.
+	t.Run("FPBinaryExpr", func(t *testing.T) {
+		s := bitwuzla.NewSolver()
+		defer MustCloseSolver(s)
+
+		array := glee.NewArray(100, 8)
+		x := array.Select(glee.NewConstantExpr64(0), 64, false)
+		sum := glee.NewFPBinaryExpr(glee.FADD, x, glee.NewConstantExpr64(math.Float64bits(1.5)))
+
+		if satisfiable, values, err := s.Solve(
+			[]glee.Expr{glee.NewBinaryExpr(glee.EQ, sum, glee.NewConstantExpr64(math.Float64bits(4)))},
+			[]*glee.Array{array},
+		); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if diff := cmp.Diff(values, [][]byte{{0x40, 0x04, 0, 0, 0, 0, 0, 0}}); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
+
+func TestSolver_SolveContext(t *testing.T) {
+	t.Run("Canceled", func(t *testing.T) {
+		s := bitwuzla.NewSolver()
+		defer MustCloseSolver(s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := s.SolveContext(ctx, []glee.Expr{glee.NewBoolConstantExpr(true)}, nil); err != context.Canceled {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// Ensure a query succeeds after an earlier query was interrupted.
+	t.Run("AfterInterrupt", func(t *testing.T) {
+		s := bitwuzla.NewSolver()
+		defer MustCloseSolver(s)
+
+		s.Interrupt()
+		if satisfiable, _, err := s.SolveContext(context.Background(), []glee.Expr{glee.NewBoolConstantExpr(true)}, nil); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		}
+	})
+}
+
+func MustCloseSolver(s *bitwuzla.Solver) {
+	if err := s.Close(); err != nil {
+		panic(err)
+	}
+}
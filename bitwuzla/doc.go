@@ -0,0 +1,8 @@
+// Package bitwuzla implements a solver using the embedded Bitwuzla library,
+// which is often faster than Z3 on queries of bit vectors & arrays.
+//
+// The package requires Bitwuzla 0.5 or later & is only built with the
+// "bitwuzla" build tag:
+//
+//	$ go build -tags bitwuzla ./...
+package bitwuzla
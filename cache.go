@@ -0,0 +1,294 @@
+package glee
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCacheSize is the default number of results kept by a CachingSolver.
+const DefaultCacheSize = 1024
+
+// Ensure type implements interface.
+var _ ContextSolver = (*CachingSolver)(nil)
+var _ SolverStats = (*CachingSolver)(nil)
+
+// CachingSolver wraps a solver and memoizes its results. Queries are keyed by
+// their set of constraints so order & duplicates do not matter.
+//
+// Similar to KLEE's counterexample cache, a query is also answered without
+// the underlying solver if a previously unsatisfiable set of constraints is a
+// subset of the query or if a previous solution satisfies every constraint in
+// the query.
+type CachingSolver struct {
+	Solver Solver
+
+	// Maximum number of results & solutions to keep. Older entries are
+	// evicted once the size is exceeded.
+	CacheSize int
+
+	results   map[string]*cacheResult
+	unsat     [][]string       // unsatisfiable constraint sets
+	solutions []*cacheSolution // satisfying assignments, newest last
+
+	stats CachingSolverStats
+}
+
+// NewCachingSolver returns a new instance of CachingSolver.
+func NewCachingSolver(solver Solver) *CachingSolver {
+	return &CachingSolver{
+		Solver:    solver,
+		CacheSize: DefaultCacheSize,
+		results:   make(map[string]*cacheResult),
+	}
+}
+
+// CachingSolverStats represents statistics for a CachingSolver.
+type CachingSolverStats struct {
+	HitN  int // queries answered from the cache
+	MissN int // queries passed to the underlying solver
+}
+
+// Stats returns statistics for the solver.
+func (s *CachingSolver) Stats() CachingSolverStats {
+	return s.stats
+}
+
+// QueryStats returns the statistics of the underlying solver, if available,
+// along with the cache hits & misses.
+func (s *CachingSolver) QueryStats() QueryStats {
+	var stats QueryStats
+	if solver, ok := s.Solver.(SolverStats); ok {
+		stats = solver.QueryStats()
+	}
+	stats.CacheHitN += s.stats.HitN
+	stats.CacheMissN += s.stats.MissN
+	return stats
+}
+
+// cacheResult represents the result of a previously solved constraint set.
+type cacheResult struct {
+	satisfiable bool
+	solution    *cacheSolution
+}
+
+// cacheSolution represents a satisfying assignment of values to arrays.
+type cacheSolution struct {
+	evaluator *ExprEvaluator
+	m         map[uint64][]byte
+}
+
+// newCacheSolution returns a solution for the given arrays & values.
+func newCacheSolution(arrays []*Array, values [][]byte) *cacheSolution {
+	m := make(map[uint64][]byte, len(arrays))
+	for i, array := range arrays {
+		m[array.ID] = values[i]
+	}
+	return &cacheSolution{evaluator: NewExprEvaluator(arrays, values), m: m}
+}
+
+// values returns the solution's value for each array. Returns false if any
+// array is not part of the solution.
+func (sol *cacheSolution) values(arrays []*Array) ([][]byte, bool) {
+	values := make([][]byte, len(arrays))
+	for i, array := range arrays {
+		value, ok := sol.m[array.ID]
+		if !ok {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// satisfies returns true if every constraint evaluates to true under sol.
+func (sol *cacheSolution) satisfies(constraints []Expr) bool {
+	for _, constraint := range constraints {
+		if v, err := sol.evaluator.Evaluate(constraint); err != nil || !v.IsTrue() {
+			return false
+		}
+	}
+	return true
+}
+
+// Solve returns the cached result for the constraints, if available.
+// Otherwise the query is passed to the underlying solver and its result is
+// cached. Solutions are always requested for every array referenced by the
+// constraints so they can be reused by later queries.
+func (s *CachingSolver) Solve(constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	return s.SolveContext(context.Background(), constraints, arrays)
+}
+
+// SolveContext is the same as Solve() but passes ctx to the underlying solver.
+// Errors, including cancellation, are not cached.
+func (s *CachingSolver) SolveContext(ctx context.Context, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	keys, ok := constraintKeys(constraints)
+	if !ok {
+		return false, nil, nil
+	}
+	key := strings.Join(keys, "\n")
+
+	if satisfiable, values, ok := s.lookup(key, keys, constraints, arrays); ok {
+		s.stats.HitN++
+		return satisfiable, values, nil
+	}
+	s.stats.MissN++
+
+	// Solve for all referenced arrays in addition to the requested arrays.
+	allArrays := FindArrays(constraints...)
+	for _, array := range arrays {
+		if !containsArray(allArrays, array) {
+			allArrays = append(allArrays, array)
+		}
+	}
+
+	satisfiable, allValues, err := SolveContext(ctx, s.Solver, constraints, allArrays)
+	if err != nil {
+		return false, nil, err
+	} else if !satisfiable {
+		s.addResult(key, &cacheResult{})
+		s.unsat = append(s.unsat, keys)
+		if len(s.unsat) > s.CacheSize {
+			s.unsat = s.unsat[1:]
+		}
+		return false, nil, nil
+	}
+
+	sol := newCacheSolution(allArrays, allValues)
+	s.addResult(key, &cacheResult{satisfiable: true, solution: sol})
+	s.solutions = append(s.solutions, sol)
+	if len(s.solutions) > s.CacheSize {
+		s.solutions = s.solutions[1:]
+	}
+
+	values, _ = sol.values(arrays)
+	return true, values, nil
+}
+
+// lookup returns the result of the query from the cache. Returns false if the
+// result cannot be determined from the cache.
+func (s *CachingSolver) lookup(key string, keys []string, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, ok bool) {
+	// Check for an exact match on the constraint set.
+	if r := s.results[key]; r != nil {
+		if !r.satisfiable {
+			return false, nil, true
+		} else if values, ok := r.solution.values(arrays); ok {
+			return true, values, true
+		}
+	}
+
+	// A superset of an unsatisfiable set is also unsatisfiable.
+	for _, unsat := range s.unsat {
+		if isSortedSubset(unsat, keys) {
+			s.addResult(key, &cacheResult{})
+			return false, nil, true
+		}
+	}
+
+	// Reuse any previous solution that satisfies the query. Newer solutions
+	// are more likely to be similar so check those first.
+	for i := len(s.solutions) - 1; i >= 0; i-- {
+		sol := s.solutions[i]
+		if values, ok := sol.values(arrays); ok && sol.satisfies(constraints) {
+			return true, values, true
+		}
+	}
+	return false, nil, false
+}
+
+// addResult caches the result for key. The cache is cleared once full.
+func (s *CachingSolver) addResult(key string, r *cacheResult) {
+	if len(s.results) >= s.CacheSize {
+		s.results = make(map[string]*cacheResult)
+	}
+	s.results[key] = r
+}
+
+// constraintKeys returns a sorted, de-duplicated list of keys for constraints.
+// Constant true constraints are ignored. Returns false if any constraint is
+// constant false.
+func constraintKeys(constraints []Expr) ([]string, bool) {
+	m := make(map[string]struct{}, len(constraints))
+	keys := make([]string, 0, len(constraints))
+	for _, constraint := range constraints {
+		if IsConstantFalse(constraint) {
+			return nil, false
+		} else if IsConstantTrue(constraint) {
+			continue
+		}
+
+		key := constraintKey(constraint)
+		if _, ok := m[key]; !ok {
+			m[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
+// constraintKey returns a key which identifies constraint. Arrays only print
+// their ID & size so the key also includes the updates of each array read by
+// the constraint. Otherwise reads from the same array with different updates,
+// or from different anonymous arrays, would share a key.
+func constraintKey(constraint Expr) string {
+	v := &constraintKeyVisitor{chains: make(map[*ArrayUpdate]int)}
+	v.buf.WriteString(constraint.String())
+	WalkExpr(v, constraint)
+	return v.buf.String()
+}
+
+// constraintKeyVisitor writes a reference to the update chain of every select
+// expression in the order they are visited. Each chain is written in full the
+// first time it is referenced.
+type constraintKeyVisitor struct {
+	buf    bytes.Buffer
+	chains map[*ArrayUpdate]int
+}
+
+func (v *constraintKeyVisitor) Visit(expr Expr) (Expr, ExprVisitor) {
+	sel, ok := expr.(*SelectExpr)
+	if !ok {
+		return expr, v
+	}
+
+	if i, ok := v.chains[sel.Array.Updates]; ok {
+		fmt.Fprintf(&v.buf, "\n#%d", i)
+		return expr, v
+	}
+
+	i := len(v.chains)
+	v.chains[sel.Array.Updates] = i
+	fmt.Fprintf(&v.buf, "\n#%d=%s", i, sel.Array)
+	for upd := sel.Array.Updates; upd != nil; upd = upd.Next {
+		fmt.Fprintf(&v.buf, " [%s]=%s", upd.Index, upd.Value)
+	}
+	return expr, v
+}
+
+// isSortedSubset returns true if every element of a is in b. Both must be sorted.
+func isSortedSubset(a, b []string) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for i := 0; len(a) > 0; i++ {
+		if i >= len(b) || b[i] > a[0] {
+			return false
+		} else if b[i] == a[0] {
+			a = a[1:]
+		}
+	}
+	return true
+}
+
+// containsArray returns true if a contains an array with the same ID as array.
+func containsArray(a []*Array, array *Array) bool {
+	for _, other := range a {
+		if other.ID == array.ID {
+			return true
+		}
+	}
+	return false
+}
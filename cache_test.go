@@ -0,0 +1,126 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestCachingSolver_Solve(t *testing.T) {
+	a := glee.NewArray(1, 1)
+	x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+	gt5 := glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(5, 8))
+	lt20 := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(20, 8))
+	lt3 := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(3, 8))
+
+	t.Run("Exact", func(t *testing.T) {
+		var solver SolverMock
+		solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+			return true, [][]byte{{10}}, nil
+		}
+
+		s := glee.NewCachingSolver(&solver)
+		for _, constraints := range [][]glee.Expr{{gt5, lt20}, {lt20, gt5, gt5}} {
+			if satisfiable, values, err := s.Solve(constraints, []*glee.Array{a}); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if values[0][0] != 10 {
+				t.Fatalf("unexpected value: %d", values[0][0])
+			}
+		}
+		if solver.SolveN != 1 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		} else if stats := s.Stats(); stats.HitN != 1 || stats.MissN != 1 {
+			t.Fatalf("unexpected stats: %#v", stats)
+		}
+	})
+
+	t.Run("UnsatSubset", func(t *testing.T) {
+		var solver SolverMock
+		solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+			return false, nil, nil
+		}
+
+		s := glee.NewCachingSolver(&solver)
+		if satisfiable, _, err := s.Solve([]glee.Expr{gt5, lt3}, nil); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		}
+		if satisfiable, _, err := s.Solve([]glee.Expr{lt20, lt3, gt5}, nil); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		}
+		if solver.SolveN != 1 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		}
+	})
+
+	t.Run("Solution", func(t *testing.T) {
+		var solver SolverMock
+		solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+			if len(arrays) != 1 || arrays[0] != a {
+				t.Fatalf("unexpected arrays: %v", arrays)
+			}
+			return true, [][]byte{{10}}, nil
+		}
+
+		// The first query's solution also satisfies the second query.
+		s := glee.NewCachingSolver(&solver)
+		if satisfiable, _, err := s.Solve([]glee.Expr{gt5}, nil); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		}
+		if satisfiable, _, err := s.Solve([]glee.Expr{gt5, lt20}, nil); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		}
+		if solver.SolveN != 1 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		}
+
+		// The solution does not satisfy a contradicting query.
+		if _, _, err := s.Solve([]glee.Expr{lt3}, nil); err != nil {
+			t.Fatal(err)
+		} else if solver.SolveN != 2 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		}
+	})
+
+	// Arrays only print their ID so reads from different versions of the same
+	// array, or from different anonymous arrays, must not share results.
+	t.Run("Updates", func(t *testing.T) {
+		i := glee.NewArray(2, 8).Select(glee.NewConstantExpr(0, 32), 64, true)
+		b := a.Store(i, glee.NewConstantExpr(10, 8), false)
+		anon0 := glee.NewArray(0, 1).Store(i, glee.NewConstantExpr(10, 8), false)
+		anon1 := glee.NewArray(0, 1).Store(i, glee.NewConstantExpr(20, 8), false)
+		eq10 := func(array *glee.Array) glee.Expr {
+			return glee.NewBinaryExpr(glee.EQ, array.Select(glee.NewConstantExpr(0, 32), 8, false), glee.NewConstantExpr(10, 8))
+		}
+
+		for _, queries := range [][2][]glee.Expr{
+			{{eq10(a)}, {eq10(b)}},
+			{{eq10(a)}, {eq10(b), lt20}},
+			{{eq10(anon0)}, {eq10(anon1)}},
+		} {
+			var solver SolverMock
+			solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+				return false, nil, nil
+			}
+
+			s := glee.NewCachingSolver(&solver)
+			for _, constraints := range queries {
+				if _, _, err := s.Solve(constraints, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if solver.SolveN != 2 {
+				t.Fatalf("unexpected solve count: %d", solver.SolveN)
+			}
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// chanObject represents the contents of a channel allocated on a state.
+// Channels are referenced by address so the object is shared by every
+// binding of the channel.
+//
+// Unbuffered channels only accept a value once a receiver is blocked on the
+// channel. The value is held in the buffer until the receiver is scheduled.
+type chanObject struct {
+	buf    []Binding
+	cap    int
+	closed bool
+}
+
+// clone returns a copy of c which can be modified.
+func (c *chanObject) clone() *chanObject {
+	other := &chanObject{buf: make([]Binding, len(c.buf)), cap: c.cap, closed: c.closed}
+	copy(other.buf, c.buf)
+	return other
+}
+
+// chanWait represents a channel operation that a goroutine is blocked on.
+type chanWait struct {
+	addr uint64
+	send bool
+}
+
+// findChan returns the channel object at addr. Returns nil for a nil channel.
+func (s *ExecutionState) findChan(addr uint64) *chanObject {
+	if v, _ := s.chans.Get(addr); v != nil {
+		return v.(*chanObject)
+	}
+	return nil
+}
+
+// canSend returns true if a send to c at addr can proceed. Sends to a closed
+// channel can proceed so that they panic.
+func (s *ExecutionState) canSend(addr uint64, c *chanObject) bool {
+	if c.closed {
+		return true
+	} else if c.cap > 0 {
+		return len(c.buf) < c.cap
+	}
+	return len(c.buf) == 0 && s.hasWaiter(chanWait{addr: addr})
+}
+
+// canRecv returns true if a receive from c can proceed.
+func (s *ExecutionState) canRecv(c *chanObject) bool {
+	return len(c.buf) > 0 || c.closed
+}
+
+// send appends value to the channel at addr. The channel must not be closed.
+func (s *ExecutionState) send(addr uint64, c *chanObject, value Binding) {
+	other := c.clone()
+	other.buf = append(other.buf, value)
+	s.chans = s.chans.Set(addr, other)
+	s.wakeAll(addr)
+}
+
+// recv removes the next value from the channel at addr. Returns the zero value
+// of elem if the channel is closed & empty.
+func (s *ExecutionState) recv(addr uint64, c *chanObject, elem types.Type) (value Binding, ok bool) {
+	if len(c.buf) == 0 {
+		return s.zeroBinding(elem), false
+	}
+
+	other := c.clone()
+	value, other.buf = other.buf[0], other.buf[1:]
+	s.chans = s.chans.Set(addr, other)
+	s.wakeAll(addr)
+	return value, true
+}
+
+// hasWaiter returns true if a suspended goroutine is blocked on w.
+func (s *ExecutionState) hasWaiter(w chanWait) bool {
+	for _, g := range s.goroutines {
+		for _, other := range g.waits {
+			if other == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wake unblocks all suspended goroutines waiting on w. Woken goroutines
+// execute their blocked operation again once they are scheduled.
+func (s *ExecutionState) wake(w chanWait) {
+	for _, g := range s.goroutines {
+		for _, other := range g.waits {
+			if other == w {
+				g.waits = nil
+				break
+			}
+		}
+	}
+}
+
+// wakeAll unblocks all goroutines waiting on either direction of the channel.
+func (s *ExecutionState) wakeAll(addr uint64) {
+	s.wake(chanWait{addr: addr, send: true})
+	s.wake(chanWait{addr: addr, send: false})
+}
+
+// block suspends the current goroutine until one of the operations in waits
+// is able to proceed & schedules the remaining runnable goroutines. The
+// state panics with a deadlock if no goroutine is able to run.
+func (e *Executor) block(state *ExecutionState, waits []chanWait) error {
+	// Wake goroutines blocked on the opposite operation as they may now be
+	// able to proceed. Operations on nil channels can never proceed.
+	for _, w := range waits {
+		if w.addr != 0 {
+			state.wake(chanWait{addr: w.addr, send: !w.send})
+		}
+	}
+
+	ids := state.runnableGoroutineIDs()
+	if len(ids) == 0 {
+		state.status = ExecutionStatusPanicked
+		state.reason = "all goroutines are asleep - deadlock!"
+		return nil
+	}
+
+	// Rewind so the blocked instruction executes again once resumed.
+	e.infof("[goroutine] blocked: %d", state.gid)
+	state.Frame().pc--
+	state.waits = waits
+
+	return e.schedule(state, ids)
+}
+
+func (e *Executor) executeMakeChanInstr(state *ExecutionState, instr *ssa.MakeChan) error {
+	size, ok := state.Eval(instr.Size).(*ConstantExpr)
+	if !ok {
+		return fmt.Errorf("glee.Executor: channel size must be constant")
+	}
+
+	addr, _ := state.Alloc(e.PointerWidth() / 8)
+	state.chans = state.chans.Set(addr.Value, &chanObject{cap: int(size.Value)})
+	state.Frame().bind(instr, addr)
+	return nil
+}
+
+func (e *Executor) executeSendInstr(state *ExecutionState, instr *ssa.Send) error {
+	addr, err := state.objectAddr(state.Eval(instr.Chan))
+	if err != nil {
+		return err
+	}
+
+	c := state.findChan(addr)
+	if c == nil || !state.canSend(addr, c) {
+		return e.block(state, []chanWait{{addr: addr, send: true}})
+	} else if c.closed {
+		e.runtimePanic(state, "send on closed channel")
+		return nil
+	}
+
+	state.send(addr, c, state.Eval(instr.X))
+	return e.yield(state)
+}
+
+// executeUnOpArrowInstr receives a value from a channel.
+func (e *Executor) executeUnOpArrowInstr(state *ExecutionState, instr *ssa.UnOp) error {
+	addr, err := state.objectAddr(state.Eval(instr.X))
+	if err != nil {
+		return err
+	}
+
+	c := state.findChan(addr)
+	if c == nil || !state.canRecv(c) {
+		return e.block(state, []chanWait{{addr: addr}})
+	}
+
+	value, ok := state.recv(addr, c, instr.X.Type().Underlying().(*types.Chan).Elem())
+	if instr.CommaOk {
+		state.Frame().bind(instr, Tuple{value, NewBoolConstantExpr(ok)})
+	} else {
+		state.Frame().bind(instr, value)
+	}
+	return e.yield(state)
+}
+
+// executeSelectInstr forks a state for each case that is ready. If no cases
+// are ready then the default case is selected or, if there is none, the
+// goroutine blocks until one of the cases is ready.
+func (e *Executor) executeSelectInstr(state *ExecutionState, instr *ssa.Select) error {
+	var ready []int
+	var waits []chanWait
+	for i, st := range instr.States {
+		addr, err := state.objectAddr(state.Eval(st.Chan))
+		if err != nil {
+			return err
+		}
+		send := st.Dir == types.SendOnly
+		waits = append(waits, chanWait{addr: addr, send: send})
+
+		if c := state.findChan(addr); c == nil {
+			continue
+		} else if (send && state.canSend(addr, c)) || (!send && state.canRecv(c)) {
+			ready = append(ready, i)
+		}
+	}
+
+	switch {
+	case len(ready) == 0 && !instr.Blocking:
+		return e.selectCase(state, instr, -1)
+	case len(ready) == 0:
+		return e.block(state, waits)
+	case len(ready) == 1:
+		if err := e.selectCase(state, instr, ready[0]); err != nil {
+			return err
+		}
+		return e.yield(state)
+	}
+
+	for _, i := range ready {
+		e.infof("[fork] select case: %d", i)
+		newState := state.Fork(nil)
+		newState.id = e.nextStateID()
+		if err := e.selectCase(newState, instr, i); err != nil {
+			return err
+		}
+		e.addState(newState)
+	}
+	return nil
+}
+
+// selectCase performs the operation for the i-th select case & binds the
+// result tuple of (index, recvOk, r_0, ... r_n-1). An index of -1 selects
+// the default case.
+func (e *Executor) selectCase(state *ExecutionState, instr *ssa.Select, index int) error {
+	tuple := Tuple{NewConstantExpr(uint64(int64(index)), e.Sizeof(types.Typ[types.Int])), NewBoolConstantExpr(false)}
+
+	for i, st := range instr.States {
+		var elem types.Type
+		if st.Dir == types.RecvOnly {
+			elem = st.Chan.Type().Underlying().(*types.Chan).Elem()
+		}
+
+		if i != index {
+			if elem != nil {
+				tuple = append(tuple, state.zeroBinding(elem))
+			}
+			continue
+		}
+
+		addr, err := state.objectAddr(state.Eval(st.Chan))
+		if err != nil {
+			return err
+		}
+		c := state.findChan(addr)
+
+		if elem == nil {
+			if c.closed {
+				e.runtimePanic(state, "send on closed channel")
+				return nil
+			}
+			state.send(addr, c, state.Eval(st.Send))
+			continue
+		}
+
+		value, ok := state.recv(addr, c, elem)
+		tuple[1] = NewBoolConstantExpr(ok)
+		tuple = append(tuple, value)
+	}
+
+	state.Frame().bind(instr, tuple)
+	return nil
+}
+
+// execClose represents a function handler for the builtin close() function.
+func execClose(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+
+	addr, err := state.objectAddr(args[0])
+	if err != nil {
+		return err
+	}
+
+	c := state.findChan(addr)
+	if c == nil {
+		state.Executor().runtimePanic(state, "close of nil channel")
+		return nil
+	} else if c.closed {
+		state.Executor().runtimePanic(state, "close of closed channel")
+		return nil
+	}
+
+	other := c.clone()
+	other.closed = true
+	state.chans = state.chans.Set(addr, other)
+	state.wakeAll(addr)
+	return nil
+}
@@ -0,0 +1,137 @@
+package glee
+
+import (
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// closureObject represents a function value along with the bindings of its
+// captured free variables. Closures are referenced by address so they can be
+// stored in memory & passed around like any other function value.
+type closureObject struct {
+	fn       *ssa.Function
+	bindings []Binding
+}
+
+// findClosure returns the closure at addr. Returns nil if addr does not
+// reference a closure.
+func (s *ExecutionState) findClosure(addr uint64) *closureObject {
+	if v, _ := s.closures.Get(addr); v != nil {
+		return v.(*closureObject)
+	}
+	return nil
+}
+
+// freeVarBindings returns the captured free variable bindings for the function
+// called by instr. Returns nil if the callee is not a closure.
+func (s *ExecutionState) freeVarBindings(instr ssa.CallInstruction) []Binding {
+	common := instr.Common()
+	if common.IsInvoke() {
+		return nil
+	} else if _, ok := common.Value.(*ssa.Builtin); ok {
+		return nil
+	}
+
+	if addr, ok := s.Eval(common.Value).(*ConstantExpr); ok {
+		if c := s.findClosure(addr.Value); c != nil {
+			return c.bindings
+		}
+	}
+	return nil
+}
+
+// bindFreeVars binds captured variables to the free variables of the current frame.
+func (s *ExecutionState) bindFreeVars(bindings []Binding) {
+	frame := s.Frame()
+	for i, b := range bindings {
+		frame.bind(frame.fn.FreeVars[i], b)
+	}
+}
+
+func (e *Executor) executeMakeClosureInstr(state *ExecutionState, instr *ssa.MakeClosure) error {
+	// Free variables are captured at the point the closure is created.
+	c := &closureObject{fn: instr.Fn.(*ssa.Function), bindings: make([]Binding, len(instr.Bindings))}
+	for i, v := range instr.Bindings {
+		c.bindings[i] = state.Eval(v)
+	}
+
+	addr, _ := state.Alloc(e.PointerWidth() / 8)
+	state.closures = state.closures.Set(addr.Value, c)
+	state.Frame().bind(instr, addr)
+	return nil
+}
+
+// resolveCallee resolves the function value called by instr to the function
+// or closure it references. A nil or unknown function address panics. If the
+// address is symbolic then a child state is forked for each function & closure
+// with the call's signature that the address may equal. The function value is
+// rebound to the concrete address within the child & the call is executed
+// again. Interface receivers of invoke-mode calls are resolved by
+// resolveInvoke(). Returns true if state was handled & the call should not
+// proceed.
+func (e *Executor) resolveCallee(state *ExecutionState, instr ssa.CallInstruction) (bool, error) {
+	common := instr.Common()
+	if common.IsInvoke() {
+		return e.resolveInvoke(state, instr)
+	} else if common.StaticCallee() != nil {
+		return false, nil
+	} else if _, ok := common.Value.(*ssa.Builtin); ok {
+		return false, nil
+	}
+
+	addr := state.MustEvalAsExpr(common.Value)
+	if addr == nil {
+		addr = NewConstantExpr(0, e.PointerWidth())
+	}
+	if addr, ok := addr.(*ConstantExpr); ok {
+		if state.findClosure(addr.Value) == nil && e.funcsByAddr[addr.Value] == nil {
+			e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+			return true, nil
+		}
+		return false, nil
+	}
+
+	// Conditions for each possible target followed by the condition that the
+	// address references none of them, which includes nil.
+	targets := e.callTargets(state, common.Signature())
+	conds := make([]Expr, 0, len(targets)+1)
+	invalid := Expr(NewBoolConstantExpr(true))
+	for _, target := range targets {
+		cond := NewBinaryExpr(EQ, addr, NewConstantExpr(target, e.PointerWidth()))
+		conds = append(conds, cond)
+		invalid = newAndExpr(invalid, NewNotExpr(cond))
+	}
+	conds = append(conds, invalid)
+
+	return true, forkEach(state, conds, func(state *ExecutionState, i int) {
+		if i == len(conds)-1 {
+			e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+			return
+		}
+
+		e.infof("[fork] indirect call: addr=%d", targets[i])
+		state.Frame().bind(common.Value, NewConstantExpr(targets[i], e.PointerWidth()))
+		state.Frame().pc-- // execute call again with resolved callee
+	})
+}
+
+// callTargets returns the sorted addresses of every function value & closure
+// on state which has the signature sig.
+func (e *Executor) callTargets(state *ExecutionState, sig *types.Signature) []uint64 {
+	var addrs []uint64
+	for addr, fn := range e.funcsByAddr {
+		if types.Identical(fn.Signature, sig) {
+			addrs = append(addrs, addr)
+		}
+	}
+	for itr := state.closures.Iterator(); !itr.Done(); {
+		k, v := itr.Next()
+		if types.Identical(v.(*closureObject).fn.Signature, sig) {
+			addrs = append(addrs, k.(uint64))
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+	return addrs
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CompletionCommand represents a command for generating shell completion scripts.
+type CompletionCommand struct {
+	stdout io.Writer
+}
+
+// NewCompletionCommand returns a new instance of CompletionCommand.
+func NewCompletionCommand() *CompletionCommand {
+	return &CompletionCommand{
+		stdout: os.Stdout,
+	}
+}
+
+// Run executes the "completion" subcommand.
+func (cmd *CompletionCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-completion", flag.ContinueOnError)
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() != 1 {
+		return fmt.Errorf("shell required")
+	}
+
+	switch shell := fs.Arg(0); shell {
+	case "bash":
+		fmt.Fprint(cmd.stdout, bashCompletion)
+	case "zsh":
+		fmt.Fprint(cmd.stdout, zshCompletion)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+	return nil
+}
+
+func (cmd *CompletionCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee completion bash|zsh
+
+Prints a shell completion script. To enable completion, add the following
+to your shell's startup file:
+
+	source <(glee completion bash)
+`[1:])
+}
+
+const bashCompletion = `_glee() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=($(compgen -W "completion cover debug generate list replay run serve vet work help" -- "$cur"))
+		return
+	fi
+
+	case "${COMP_WORDS[1]}" in
+	completion)
+		COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
+		;;
+	cover)
+		COMPREPLY=($(compgen -W "-v -o -strlen -solver -smt -z3-timeout -z3-max-memory -z3-seed -z3-logic -z3-tactics -max-states -max-depth -max-instructions -max-time" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	debug)
+		COMPREPLY=($(compgen -W "-strlen -solver -smt -z3-timeout -z3-max-memory -z3-seed -z3-logic -z3-tactics -max-states -max-depth -max-instructions" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	generate)
+		COMPREPLY=($(compgen -W "-v -format -hotspots -func -fuzz -run -exported -o -corpus -checkpoint -resume -strlen -prefer -solver -smt -z3-timeout -z3-max-memory -z3-seed -z3-logic -z3-tactics -tags -goos -goarch -max-states -max-depth -max-instructions -max-time" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	list)
+		COMPREPLY=($(compgen -W "-format -json" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	replay)
+		COMPREPLY=($(compgen -W "-v" -- "$cur") $(compgen -f -- "$cur"))
+		;;
+	run)
+		COMPREPLY=($(compgen -W "-v -format -tree -strlen -prefer -solver -smt -z3-timeout -z3-max-memory -z3-seed -z3-logic -z3-tactics -max-states -max-depth -max-instructions -max-time -max-solver-time -max-memory -max-expr-depth -max-expr-size -deprioritize -query-timeout -query-retries -on-unknown" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	serve)
+		COMPREPLY=($(compgen -W "-addr -format -strlen -prefer -lease-timeout -max-depth -max-instructions -max-memory" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	vet)
+		COMPREPLY=($(compgen -W "-format -havoc" -- "$cur") $(compgen -d -- "$cur"))
+		;;
+	work)
+		COMPREPLY=($(compgen -W "-v -report-interval -solver -smt -z3-timeout -z3-max-memory -z3-seed -z3-logic -z3-tactics" -- "$cur"))
+		;;
+	esac
+}
+complete -F _glee glee
+`
+
+const zshCompletion = `#compdef glee
+
+_glee() {
+	local -a commands
+	commands=(
+		'completion:generate shell completion script'
+		'cover:write a coverage profile for a function'
+		'debug:interactively explore states of a function'
+		'generate:generate test cases'
+		'list:list analyzable functions'
+		'replay:execute stored corpus inputs concretely'
+		'run:report each path through a function'
+		'serve:coordinate exploration of a function by workers'
+		'vet:report unsupported constructs reachable from a function'
+		'work:explore states leased from a coordinator'
+		'help:show help'
+	)
+
+	if (( CURRENT == 2 )); then
+		_describe 'command' commands
+		return
+	fi
+
+	case "$words[2]" in
+	completion)
+		_values 'shell' bash zsh
+		;;
+	cover)
+		_arguments '-v[enable verbose logging]' '-o[coverage profile path]:file:_files' '-strlen[symbolic string length]:n' '-solver[solver name]:solver:(z3 bitwuzla smtlib)' '-smt[external SMT-LIB2 solver command]:command' '-z3-timeout[maximum time per Z3 query]:duration' '-z3-max-memory[maximum Z3 memory in megabytes]:n' '-z3-seed[Z3 random seed]:n' '-z3-logic[Z3 SMT-LIB2 logic]:logic' '-z3-tactics[comma-separated Z3 tactics]:tactics' '-max-states[maximum states]:n' '-max-depth[maximum branches per path]:n' '-max-instructions[maximum instructions per path]:n' '-max-time[maximum time]:duration' '-max-solver-time[maximum solver time per path]:duration' '-max-memory[maximum memory per path]:n' '-deprioritize[deprioritize paths exceeding budgets]' '1:package:_files -/' '2:function'
+		;;
+	debug)
+		_arguments '-strlen[symbolic string length]:n' '-solver[solver name]:solver:(z3 bitwuzla smtlib)' '-smt[external SMT-LIB2 solver command]:command' '-z3-timeout[maximum time per Z3 query]:duration' '-z3-max-memory[maximum Z3 memory in megabytes]:n' '-z3-seed[Z3 random seed]:n' '-z3-logic[Z3 SMT-LIB2 logic]:logic' '-z3-tactics[comma-separated Z3 tactics]:tactics' '-max-states[maximum states]:n' '-max-depth[maximum branches per path]:n' '-max-instructions[maximum instructions per path]:n' '1:package:_files -/' '2:function'
+		;;
+	generate)
+		_arguments '-v[enable verbose logging]' '-format[output format]:format:(text json)' '-hotspots[print top n fork hot spots]:n' '-func[generate a test file for function]:name' '-fuzz[generate a seed corpus for fuzz target]:name' '-run[explore functions matching regexp]:regexp' '-exported[generate a test file for every exported function]' '-o[output path]:file:_files' '-corpus[corpus directory]:dir:_files -/' '-checkpoint[write unexplored states to path when cancelled]:file:_files' '-resume[resume exploration from checkpoint path]:file:_files' '-strlen[symbolic string length]:n' '-prefer[preferred argument values]:preference:(none zero printable minimal)' '-solver[solver name]:solver:(z3 bitwuzla smtlib)' '-smt[external SMT-LIB2 solver command]:command' '-z3-timeout[maximum time per Z3 query]:duration' '-z3-max-memory[maximum Z3 memory in megabytes]:n' '-z3-seed[Z3 random seed]:n' '-z3-logic[Z3 SMT-LIB2 logic]:logic' '-z3-tactics[comma-separated Z3 tactics]:tactics' '-tags[build tags]:tags' '-goos[target operating system]:os' '-goarch[target architecture]:arch' '-max-states[maximum states per function]:n' '-max-depth[maximum branches per path]:n' '-max-instructions[maximum instructions per path]:n' '-max-time[maximum time per function]:duration' '*:package:_files -/'
+		;;
+	list)
+		_arguments '-format[output format]:format:(text json)' '-json[print output in JSON format]' '*:package:_files -/'
+		;;
+	replay)
+		_arguments '-v[print output of every replay]' '*:corpus:_files'
+		;;
+	run)
+		_arguments '-v[enable verbose logging]' '-format[output format]:format:(text json)' '-tree[state tree output path]:file:_files' '-strlen[symbolic string length]:n' '-prefer[preferred input values]:preference:(none zero printable minimal)' '-solver[solver name]:solver:(z3 bitwuzla smtlib)' '-smt[external SMT-LIB2 solver command]:command' '-z3-timeout[maximum time per Z3 query]:duration' '-z3-max-memory[maximum Z3 memory in megabytes]:n' '-z3-seed[Z3 random seed]:n' '-z3-logic[Z3 SMT-LIB2 logic]:logic' '-z3-tactics[comma-separated Z3 tactics]:tactics' '-max-states[maximum states]:n' '-max-depth[maximum branches per path]:n' '-max-instructions[maximum instructions per path]:n' '-max-time[maximum time]:duration' '-max-solver-time[maximum solver time per path]:duration' '-max-memory[maximum memory per path]:n' '-max-expr-depth[maximum expression depth]:n' '-max-expr-size[maximum expression size]:n' '-deprioritize[deprioritize paths exceeding budgets]' '-query-timeout[maximum time per solver query]:duration' '-query-retries[retries of timed out solver queries]:n' '-on-unknown[handling of undecided solver queries]:policy:(fail terminate skip)' '1:package:_files -/' '2:function'
+		;;
+	serve)
+		_arguments '-addr[listen address]:address' '-format[output format]:format:(text json)' '-strlen[symbolic string length]:n' '-prefer[preferred input values]:preference:(none zero printable minimal)' '-lease-timeout[time before an unreported lease is reassigned]:duration' '-max-depth[maximum branches per path]:n' '-max-instructions[maximum instructions per path]:n' '-max-memory[maximum memory per path]:n' '1:package:_files -/' '2:function'
+		;;
+	vet)
+		_arguments '-format[output format]:format:(text json)' '-havoc[approximate functions without a body]' '1:package:_files -/' '2:function'
+		;;
+	work)
+		_arguments '-v[enable verbose logging]' '-report-interval[time between progress reports]:duration' '-solver[solver name]:solver:(z3 bitwuzla smtlib)' '-smt[external SMT-LIB2 solver command]:command' '-z3-timeout[maximum time per Z3 query]:duration' '-z3-max-memory[maximum Z3 memory in megabytes]:n' '-z3-seed[Z3 random seed]:n' '-z3-logic[Z3 SMT-LIB2 logic]:logic' '-z3-tactics[comma-separated Z3 tactics]:tactics' '1:address'
+		;;
+	esac
+}
+
+compdef _glee glee
+`
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// commands is the list of subcommands dispatched by run().
+var commands = []string{"completion", "cover", "debug", "generate", "list", "replay", "run", "serve", "vet", "work"}
+
+func TestCompletionCommand_Run(t *testing.T) {
+	// Ensure every subcommand is completed & has its arguments completed.
+	t.Run("Bash", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := NewCompletionCommand()
+		cmd.stdout = &buf
+		if err := cmd.Run(context.Background(), []string{"bash"}); err != nil {
+			t.Fatal(err)
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, `compgen -W "`+strings.Join(commands, " ")+` help"`) {
+			t.Fatalf("commands not completed:\n%s", out)
+		}
+		for _, name := range commands {
+			if !strings.Contains(out, "\n\t"+name+")\n") {
+				t.Fatalf("arguments not completed: %s", name)
+			}
+		}
+		if !strings.HasSuffix(out, "complete -F _glee glee\n") {
+			t.Fatal("completion not registered")
+		}
+	})
+
+	t.Run("Zsh", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := NewCompletionCommand()
+		cmd.stdout = &buf
+		if err := cmd.Run(context.Background(), []string{"zsh"}); err != nil {
+			t.Fatal(err)
+		}
+
+		out := buf.String()
+		if !strings.HasPrefix(out, "#compdef glee\n") {
+			t.Fatalf("missing compdef header:\n%s", out)
+		}
+		for _, name := range commands {
+			if !strings.Contains(out, "\t\t'"+name+":") {
+				t.Fatalf("command not completed: %s", name)
+			} else if !strings.Contains(out, "\n\t"+name+")\n") {
+				t.Fatalf("arguments not completed: %s", name)
+			}
+		}
+	})
+
+	t.Run("ErrUnsupportedShell", func(t *testing.T) {
+		cmd := NewCompletionCommand()
+		cmd.stdout = &bytes.Buffer{}
+		if err := cmd.Run(context.Background(), []string{"fish"}); err == nil || err.Error() != "unsupported shell: fish" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
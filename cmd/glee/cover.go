@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+	"golang.org/x/tools/go/ssa"
+)
+
+// CoverCommand represents a command for reporting the coverage of a function.
+type CoverCommand struct {
+	// Solver backend & its configuration.
+	solver solverOptions
+
+	// Exploration limits for the function.
+	limits glee.Limits
+
+	// Receives executor log messages. Discards messages if nil.
+	logger glee.Logger
+}
+
+// NewCoverCommand returns a new instance of CoverCommand.
+func NewCoverCommand() *CoverCommand {
+	return &CoverCommand{}
+}
+
+// Run executes the "cover" subcommand.
+func (cmd *CoverCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-cover", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "verbose")
+	output := fs.String("o", "cover.out", "coverage profile path")
+	stringLen := fs.Int("strlen", testgen.DefaultStringLen, "symbolic string length")
+	cmd.solver.registerFlags(fs)
+	fs.IntVar(&cmd.limits.MaxStates, "max-states", 0, "maximum states")
+	fs.IntVar(&cmd.limits.MaxDepth, "max-depth", 0, "maximum branches per path")
+	fs.IntVar(&cmd.limits.MaxInstructions, "max-instructions", 0, "maximum instructions per path")
+	fs.DurationVar(&cmd.limits.MaxTime, "max-time", 0, "maximum time")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() < 2 {
+		return fmt.Errorf("package & function required")
+	} else if fs.NArg() > 2 {
+		return fmt.Errorf("too many arguments specified")
+	}
+
+	if *verbose {
+		cmd.logger = glee.NewLogger(os.Stderr, glee.LogLevelDebug)
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fn := findFunction(pkgs, fs.Arg(1))
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", fs.Arg(1))
+	}
+
+	coverage, err := cmd.coverFunction(ctx, fn, *stringLen)
+	if err != nil {
+		return err
+	}
+
+	// Write profile to stdout without a summary if requested.
+	if *output == "-" {
+		return coverage.WriteProfile(os.Stdout)
+	} else if err := writeProfile(*output, coverage); err != nil {
+		return err
+	}
+
+	lineN, lineTotal := coverage.LineN()
+	branchN, branchTotal := coverage.BranchN()
+	fmt.Printf("lines: %d/%d (%s)\n", lineN, lineTotal, percent(lineN, lineTotal))
+	fmt.Printf("branches: %d/%d (%s)\n", branchN, branchTotal, percent(branchN, branchTotal))
+	for _, bc := range coverage.Branches {
+		switch {
+		case !bc.True:
+			fmt.Printf("  %s: true branch not taken\n", bc.Pos)
+		case !bc.False:
+			fmt.Printf("  %s: false branch not taken\n", bc.Pos)
+		}
+	}
+	fmt.Printf("wrote coverage profile to %s\n", *output)
+	return nil
+}
+
+// coverFunction explores every path through fn with symbolic arguments and
+// returns the resulting coverage.
+func (cmd *CoverCommand) coverFunction(ctx context.Context, fn *ssa.Function, stringLen int) (*glee.Coverage, error) {
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return nil, err
+	}
+	defer closeSolver()
+
+	e := glee.NewExecutor(fn)
+	e.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	e.Limits = cmd.limits
+	if cmd.logger != nil {
+		e.Logger = cmd.logger
+	}
+
+	if _, err := e.BindSymbolicParams(stringLen); err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := e.ExecuteNextStateContext(ctx); err == glee.ErrNoStateAvailable {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return e.Coverage(), nil
+}
+
+func (cmd *CoverCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee cover [arguments] package function
+
+Symbolically executes the named function with symbolic arguments and writes
+the lines covered by all explored paths as a coverage profile. The profile
+can be viewed with:
+
+	go tool cover -html=cover.out
+
+Branches which were reached but could only be taken in one direction are
+printed along with a summary of line & branch coverage.
+
+Arguments:
+
+	-v
+	    Enable verbose logging.
+
+	-o path
+	    Output path for the coverage profile. Defaults to "cover.out".
+	    Use "-" to write the profile to stdout without a summary.
+
+	-strlen n
+	    Length of symbolic string & byte slice arguments.
+
+	-max-states n
+	    Stop branching once n states are created.
+
+	-max-depth n
+	    Stop exploring paths with more than n branches.
+
+	-max-instructions n
+	    Stop exploring paths after n instructions.
+
+	-max-time duration
+	    Stop exploring after the given duration.
+
+	-solver name
+	    Solver used for queries. One of "z3", "bitwuzla", or "smtlib".
+	    Defaults to "smtlib" if -smt is set & "z3" otherwise.
+
+	-smt command
+	    Run queries with an external SMT-LIB2 solver instead of the
+	    Z3 library. For example: "z3 -in -smt2".
+
+	-z3-timeout duration
+	    Stop each Z3 query after the given duration.
+
+	-z3-max-memory n
+	    Limit Z3 to n megabytes of memory.
+
+	-z3-seed n
+	    Seed for the randomized heuristics of Z3.
+
+	-z3-logic logic
+	    SMT-LIB2 logic used by Z3, such as "QF_ABV".
+
+	-z3-tactics tactics
+	    Comma-separated Z3 tactics applied in order instead of the
+	    default solver. For example: "simplify,solve-eqs,smt".
+`[1:])
+}
+
+// writeProfile writes the coverage profile to the file at path.
+func writeProfile(path string, coverage *glee.Coverage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := coverage.WriteProfile(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// percent returns n as a percentage of total.
+func percent(n, total int) string {
+	if total == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
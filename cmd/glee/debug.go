@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+	"golang.org/x/tools/go/ssa"
+)
+
+// DebugCommand represents a command for interactively exploring states.
+type DebugCommand struct {
+	// Solver backend & its configuration.
+	solver solverOptions
+
+	// Exploration limits for the function.
+	limits glee.Limits
+
+	e        *glee.Executor
+	searcher *debugSearcher
+	last     *glee.ExecutionState // most recently stopped state
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// NewDebugCommand returns a new instance of DebugCommand.
+func NewDebugCommand() *DebugCommand {
+	return &DebugCommand{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+	}
+}
+
+// Run executes the "debug" subcommand.
+func (cmd *DebugCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-debug", flag.ContinueOnError)
+	stringLen := fs.Int("strlen", testgen.DefaultStringLen, "symbolic string length")
+	cmd.solver.registerFlags(fs)
+	fs.IntVar(&cmd.limits.MaxStates, "max-states", 0, "maximum states")
+	fs.IntVar(&cmd.limits.MaxDepth, "max-depth", 0, "maximum branches per path")
+	fs.IntVar(&cmd.limits.MaxInstructions, "max-instructions", 0, "maximum instructions per path")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() < 2 {
+		return fmt.Errorf("package & function required")
+	} else if fs.NArg() > 2 {
+		return fmt.Errorf("too many arguments specified")
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fn := findFunction(pkgs, fs.Arg(1))
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", fs.Arg(1))
+	}
+	return cmd.debugFunction(ctx, fn, *stringLen)
+}
+
+// debugFunction reads & executes debugger commands until the input ends or
+// the user quits.
+func (cmd *DebugCommand) debugFunction(ctx context.Context, fn *ssa.Function, stringLen int) error {
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return err
+	}
+	defer closeSolver()
+
+	cmd.e = glee.NewExecutor(fn)
+	cmd.e.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	cmd.e.Limits = cmd.limits
+
+	// Replace the default searcher, which already holds the root state.
+	cmd.searcher = &debugSearcher{}
+	cmd.searcher.AddState(cmd.e.RootState())
+	cmd.e.Searcher = cmd.searcher
+
+	if _, err := cmd.e.BindSymbolicParams(stringLen); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stdout, "debugging %s. Type \"help\" for a list of commands.\n", fn.RelString(fn.Pkg.Pkg))
+
+	scanner := bufio.NewScanner(cmd.stdin)
+	for {
+		fmt.Fprint(cmd.stdout, "(glee) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(cmd.stdout, "")
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		} else if fields[0] == "quit" || fields[0] == "q" {
+			return nil
+		}
+
+		if err := cmd.exec(ctx, fields[0], fields[1:]); err == context.Canceled {
+			return err
+		} else if err != nil {
+			fmt.Fprintf(cmd.stdout, "error: %s\n", err)
+		}
+	}
+}
+
+// exec executes a single debugger command.
+func (cmd *DebugCommand) exec(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "help", "h":
+		fmt.Fprint(cmd.stdout, debugHelp)
+		return nil
+	case "step", "s":
+		return cmd.step(ctx)
+	case "next", "n":
+		return cmd.next(ctx)
+	case "continue", "c":
+		return cmd.cont(ctx)
+	case "states", "ls":
+		return cmd.states()
+	case "select", "sel":
+		return cmd.selectState(args)
+	case "constraints", "cs":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			for i, c := range state.Constraints() {
+				fmt.Fprintf(cmd.stdout, "%d. %s\n", i, state.FormatExpr(c))
+			}
+			return nil
+		})
+	case "stack", "bt":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			stack := state.Stack()
+			for i := len(stack) - 1; i >= 0; i-- {
+				fmt.Fprintf(cmd.stdout, "== FRAME #%d\n%s\n", i, stack[i].Dump())
+			}
+			return nil
+		})
+	case "heap":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			fmt.Fprint(cmd.stdout, state.DumpHeap())
+			return nil
+		})
+	case "diff", "hd":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			parent := state.Parent()
+			if parent == nil {
+				return fmt.Errorf("state#%d has no parent", state.ID())
+			}
+			diffs := state.DiffHeap(parent)
+			if len(diffs) == 0 {
+				fmt.Fprintf(cmd.stdout, "no heap changes since state#%d\n", parent.ID())
+			}
+			for _, diff := range diffs {
+				fmt.Fprintln(cmd.stdout, diff.String())
+			}
+			return nil
+		})
+	case "smt":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			return state.WriteConstraints(cmd.stdout, glee.FormatSMTLIB2)
+		})
+	case "kquery":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			return state.WriteConstraints(cmd.stdout, glee.FormatKQuery)
+		})
+	case "json":
+		return cmd.dump(args, func(state *glee.ExecutionState) error {
+			if err := state.WriteConstraints(cmd.stdout, glee.FormatJSON); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintln(cmd.stdout, "")
+			return err
+		})
+	default:
+		return fmt.Errorf("unknown command: %s", name)
+	}
+}
+
+// step executes a single instruction & prints it.
+func (cmd *DebugCommand) step(ctx context.Context) error {
+	state, done, err := cmd.e.StepContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if instr := state.Instr(); instr != nil {
+		fmt.Fprintf(cmd.stdout, "state#%d %s: %s\n", state.ID(), state.Position(), instr)
+	}
+	if done {
+		cmd.stopped(state)
+	}
+	return nil
+}
+
+// next executes the current or next state until it stops.
+func (cmd *DebugCommand) next(ctx context.Context) error {
+	state, err := cmd.e.ExecuteNextStateContext(ctx)
+	if err != nil {
+		return err
+	}
+	cmd.stopped(state)
+	return nil
+}
+
+// cont executes all remaining states.
+func (cmd *DebugCommand) cont(ctx context.Context) error {
+	for {
+		state, err := cmd.e.ExecuteNextStateContext(ctx)
+		if err == glee.ErrNoStateAvailable {
+			fmt.Fprintln(cmd.stdout, "no more states")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		cmd.stopped(state)
+	}
+}
+
+// stopped prints the result of a state which stopped executing.
+func (cmd *DebugCommand) stopped(state *glee.ExecutionState) {
+	cmd.last = state
+	if state.Terminated() {
+		fmt.Fprintf(cmd.stdout, "state#%d terminated: %s", state.ID(), state.Status())
+		if reason := state.Reason(); reason != "" {
+			fmt.Fprintf(cmd.stdout, " (%s)", reason)
+		}
+		fmt.Fprintln(cmd.stdout, "")
+		return
+	}
+
+	var ids []string
+	for _, child := range state.Children() {
+		ids = append(ids, "#"+strconv.Itoa(child.ID()))
+	}
+	fmt.Fprintf(cmd.stdout, "state#%d stopped at %s: forked %s\n", state.ID(), state.SourcePosition(), strings.Join(ids, ", "))
+}
+
+// states prints the state in progress & all pending states.
+func (cmd *DebugCommand) states() error {
+	if state := cmd.e.Current(); state != nil {
+		fmt.Fprintf(cmd.stdout, "* state#%d depth=%d %s (in progress)\n", state.ID(), state.Depth(), state.SourcePosition())
+	}
+
+	next := cmd.searcher.peek()
+	for _, state := range cmd.e.PendingStates() {
+		marker := " "
+		if state == next {
+			marker = ">"
+		}
+		fmt.Fprintf(cmd.stdout, "%s state#%d depth=%d %s", marker, state.ID(), state.Depth(), state.SourcePosition())
+		if state.Terminated() {
+			fmt.Fprintf(cmd.stdout, " (%s)", state.Status())
+		}
+		fmt.Fprintln(cmd.stdout, "")
+	}
+	return nil
+}
+
+// selectState schedules the pending state with the given ID to run next.
+func (cmd *DebugCommand) selectState(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: select ID")
+	} else if cmd.e.Current() != nil {
+		return fmt.Errorf("state#%d is in progress", cmd.e.Current().ID())
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid state id: %s", args[0])
+	}
+	for _, state := range cmd.e.PendingStates() {
+		if state.ID() == id {
+			cmd.searcher.next = state
+			return nil
+		}
+	}
+	return fmt.Errorf("pending state not found: %d", id)
+}
+
+// dump calls fn with the state matching the optional ID in args. Defaults to
+// the state in progress or, if none, the most recently stopped state.
+func (cmd *DebugCommand) dump(args []string, fn func(*glee.ExecutionState) error) error {
+	candidates := append([]*glee.ExecutionState{cmd.e.Current(), cmd.last}, cmd.e.PendingStates()...)
+
+	if len(args) == 0 {
+		for _, state := range candidates[:2] {
+			if state != nil {
+				return fn(state)
+			}
+		}
+		return fmt.Errorf("no state selected")
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid state id: %s", args[0])
+	}
+	for _, state := range candidates {
+		if state != nil && state.ID() == id {
+			return fn(state)
+		}
+	}
+	return fmt.Errorf("state not found: %d", id)
+}
+
+func (cmd *DebugCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee debug [arguments] package function
+
+Starts an interactive session which explores the named function with symbolic
+arguments one instruction or one state at a time. Type "help" within the
+session for a list of commands.
+
+Arguments:
+
+	-strlen n
+	    Length of symbolic string & byte slice arguments.
+
+	-max-states n
+	    Stop branching once n states are created.
+
+	-max-depth n
+	    Stop exploring paths with more than n branches.
+
+	-max-instructions n
+	    Stop exploring paths after n instructions.
+
+	-solver name
+	    Solver used for queries. One of "z3", "bitwuzla", or "smtlib".
+	    Defaults to "smtlib" if -smt is set & "z3" otherwise.
+
+	-smt command
+	    Run queries with an external SMT-LIB2 solver instead of the
+	    Z3 library. For example: "z3 -in -smt2".
+
+	-z3-timeout duration
+	    Stop each Z3 query after the given duration.
+
+	-z3-max-memory n
+	    Limit Z3 to n megabytes of memory.
+
+	-z3-seed n
+	    Seed for the randomized heuristics of Z3.
+
+	-z3-logic logic
+	    SMT-LIB2 logic used by Z3, such as "QF_ABV".
+
+	-z3-tactics tactics
+	    Comma-separated Z3 tactics applied in order instead of the
+	    default solver. For example: "simplify,solve-eqs,smt".
+`[1:])
+}
+
+const debugHelp = `Commands:
+
+	step, s               execute a single instruction
+	next, n               execute until the current state forks or terminates
+	continue, c           execute all remaining states
+	states, ls            list the state in progress & pending states
+	select, sel ID        schedule a pending state to execute next
+	constraints, cs [ID]  print the path constraints of a state
+	stack, bt [ID]        print the call stack of a state
+	heap [ID]             print the heap of a state
+	diff, hd [ID]         print the heap allocations changed since the parent state
+	smt [ID]              print the path constraints in SMT-LIB2 format
+	kquery [ID]           print the path constraints in KQuery format
+	json [ID]             print the path constraints in JSON format
+	help, h               this screen
+	quit, q               exit the debugger
+
+Commands accepting an ID default to the state in progress or, if none, the
+most recently stopped state.
+`
+
+// debugSearcher is a depth-first searcher which allows the user to choose
+// the next state to execute.
+type debugSearcher struct {
+	states []*glee.ExecutionState
+	next   *glee.ExecutionState // user selection, if any
+}
+
+// peek returns the state which will be selected next without removing it.
+func (s *debugSearcher) peek() *glee.ExecutionState {
+	if s.next != nil {
+		return s.next
+	} else if len(s.states) == 0 {
+		return nil
+	}
+	return s.states[len(s.states)-1]
+}
+
+// SelectState returns the user's selection or, if none, the last state added.
+func (s *debugSearcher) SelectState() *glee.ExecutionState {
+	state := s.peek()
+	if state == nil {
+		return nil
+	}
+	s.next = nil
+
+	for i := range s.states {
+		if s.states[i] == state {
+			s.states = append(s.states[:i], s.states[i+1:]...)
+			break
+		}
+	}
+	return state
+}
+
+// AddState adds a new state to the searcher.
+func (s *debugSearcher) AddState(state *glee.ExecutionState) {
+	s.states = append(s.states, state)
+}
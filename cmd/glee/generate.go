@@ -3,22 +3,25 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/benbjohnson/glee"
 	"github.com/benbjohnson/glee/go/ast/astutil"
-	"github.com/benbjohnson/glee/z3"
-	"golang.org/x/tools/go/packages"
+	"github.com/benbjohnson/glee/testgen"
 	"golang.org/x/tools/go/ssa"
-	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 var (
@@ -26,7 +29,28 @@ var (
 )
 
 // GenerateCommand represents a command for generating test cases.
-type GenerateCommand struct{}
+type GenerateCommand struct {
+	// Solver backend & its configuration.
+	solver solverOptions
+
+	// Exploration limits for each function.
+	limits glee.Limits
+
+	// Receives executor log messages. Discards messages if nil.
+	logger glee.Logger
+
+	// Output format. Either "text" or "json".
+	format string
+
+	// Values preferred for solved arguments.
+	preference glee.Preference
+
+	// Build configuration used to load packages & execute functions.
+	build buildOptions
+
+	// Path to write the unexplored states of a cancelled function, if set.
+	checkpoint string
+}
 
 // NewGenerateCommand returns a new instance of GenerateCommand.
 func NewGenerateCommand() *GenerateCommand {
@@ -37,88 +61,206 @@ func NewGenerateCommand() *GenerateCommand {
 func (cmd *GenerateCommand) Run(ctx context.Context, args []string) error {
 	fs := flag.NewFlagSet("glee-generate", flag.ContinueOnError)
 	verbose := fs.Bool("v", false, "verbose")
+	hotSpots := fs.Int("hotspots", 0, "print top n fork hot spots")
+	funcName := fs.String("func", "", "generate a test file for function")
+	fuzzName := fs.String("fuzz", "", "generate a seed corpus for fuzz target")
+	runPattern := fs.String("run", "", "explore functions matching regexp")
+	exported := fs.Bool("exported", false, "generate a test file for every exported function")
+	output := fs.String("o", "", "output path")
+	corpus := fs.String("corpus", "", "corpus directory")
+	fs.StringVar(&cmd.checkpoint, "checkpoint", "", "write unexplored states to path when cancelled")
+	resume := fs.String("resume", "", "resume exploration from checkpoint path")
+	stringLen := fs.Int("strlen", testgen.DefaultStringLen, "symbolic string length")
+	cmd.solver.registerFlags(fs)
+	prefer := fs.String("prefer", glee.PreferNone.String(), "preferred argument values")
+	fs.IntVar(&cmd.limits.MaxStates, "max-states", 0, "maximum states per function")
+	fs.IntVar(&cmd.limits.MaxDepth, "max-depth", 0, "maximum branches per path")
+	fs.IntVar(&cmd.limits.MaxInstructions, "max-instructions", 0, "maximum instructions per path")
+	fs.DurationVar(&cmd.limits.MaxTime, "max-time", 0, "maximum time per function")
+	fs.StringVar(&cmd.format, "format", formatText, "output format")
+	cmd.build.registerFlags(fs)
 	fs.Usage = cmd.usage
 	if err := fs.Parse(args); err != nil {
 		return err
+	} else if err := validateFormat(cmd.format); err != nil {
+		return err
+	} else if cmd.preference, err = glee.ParsePreference(*prefer); err != nil {
+		return err
 	} else if fs.NArg() == 0 {
 		return fmt.Errorf("package required")
-	} else if fs.NArg() > 1 {
-		return fmt.Errorf("too many packages specified")
+	}
+
+	var re *regexp.Regexp
+	if *runPattern != "" {
+		var err error
+		if re, err = regexp.Compile(*runPattern); err != nil {
+			return fmt.Errorf("invalid -run pattern: %w", err)
+		}
 	}
 
 	log.SetFlags(0)
-	if !*verbose {
+	if *verbose {
+		cmd.logger = glee.NewLogger(os.Stderr, glee.LogLevelDebug)
+	} else {
 		log.SetOutput(ioutil.Discard)
 	}
 
-	// Load the initial set of packages.
-	initial, err := packages.Load(&packages.Config{
-		Mode:  packages.LoadAllSyntax,
-		Tests: true,
-	}, fs.Args()...)
+	pkgs, err := buildProgram(cmd.build, fs.Args()...)
 	if err != nil {
 		return err
-	} else if packages.PrintErrors(initial) > 0 {
-		return fmt.Errorf("packages contain errors")
 	}
 
-	// Build program in SSA form.
-	prog, pkgs := ssautil.AllPackages(initial, ssa.BuilderMode(0))
-	for i, pkg := range pkgs {
-		if pkg == nil {
-			return fmt.Errorf("cannot build SSA for package %s", initial[i])
+	// Generate a table-driven test file for a single function, if specified.
+	if *funcName != "" {
+		fn := findMember(pkgs, *funcName)
+		if fn == nil {
+			return fmt.Errorf("function not found: %s", *funcName)
 		}
-		pkg.SetDebugMode(true)
+		return cmd.generateTestFile(ctx, fn, *output, *corpus, *stringLen)
 	}
-	prog.Build()
 
-	// Ensure program depends on runtime package.
-	if prog.ImportedPackage("runtime") == nil {
-		return fmt.Errorf("program does not depend on runtime")
+	// Generate a seed corpus for a fuzz target, if specified.
+	if *fuzzName != "" {
+		fn := findMember(pkgs, *fuzzName)
+		if fn == nil {
+			return fmt.Errorf("fuzz target not found: %s", *fuzzName)
+		}
+		return cmd.generateFuzzCorpus(ctx, fn, *output, *stringLen)
 	}
 
-	// TODO: Execute existing tests to determine test coverage.
+	// Generate a test file for every exported function, if specified.
+	if *exported {
+		if *output != "" {
+			return fmt.Errorf("-o cannot be used with -exported")
+		}
+		return cmd.generateExported(ctx, pkgs, *corpus, *stringLen)
+	}
 
-	// Find matching glee test cases.
-	var fns []*ssa.Function
-	for _, pkg := range pkgs {
-		for _, m := range pkg.Members {
-			if m, ok := m.(*ssa.Function); ok && strings.HasPrefix(m.Name(), SymbolicTestPrefix) {
-				fns = append(fns, m)
-			}
+	// Resume exploration of a cancelled function, if specified.
+	if *resume != "" {
+		cp, err := readGenerateCheckpoint(*resume)
+		if err != nil {
+			return err
+		}
+		fn := findFunction(pkgs, cp.Function)
+		if fn == nil {
+			return fmt.Errorf("function not found: %s", cp.Function)
+		} else if len(cp.ForkPaths) == 0 {
+			return nil // fully explored
 		}
+		return cmd.generateFunction(ctx, fn, *hotSpots, cp.ForkPaths)
 	}
-	sort.Slice(fns, func(i, j int) bool { return fns[i].Name() < fns[j].Name() })
+
+	// TODO: Execute existing tests to determine test coverage.
+
+	fns := matchFunctions(pkgs, re)
 
 	// Execute functions using the symbolic execution engine.
 	for _, fn := range fns {
-		if err := cmd.generateFunction(ctx, fn); err != nil {
+		if err := cmd.generateFunction(ctx, fn, *hotSpots, nil); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// findMember returns the package-level function in pkgs with the given name.
+// Test variants of packages are included so test functions are found.
+func findMember(pkgs []*ssa.Package, name string) *ssa.Function {
+	for _, pkg := range pkgs {
+		if fn, _ := pkg.Members[name].(*ssa.Function); fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// matchFunctions returns the functions in pkgs whose name matches re, sorted
+// by name. Returns glee test cases, by their prefix, if re is nil. Packages
+// are loaded along with their test variants so duplicates are skipped.
+func matchFunctions(pkgs []*ssa.Package, re *regexp.Regexp) []*ssa.Function {
+	var fns []*ssa.Function
+	m := make(map[string]struct{})
+	for _, pkg := range pkgs {
+		for _, fn := range packageFunctions(pkg) {
+			name := fn.RelString(fn.Pkg.Pkg)
+			if re == nil && (fn.Signature.Recv() != nil || !strings.HasPrefix(name, SymbolicTestPrefix)) {
+				continue
+			} else if re != nil && !re.MatchString(name) {
+				continue
+			} else if _, ok := m[fn.String()]; ok {
+				continue
+			}
+			m[fn.String()] = struct{}{}
+			fns = append(fns, fn)
+		}
+	}
+	sort.Slice(fns, func(i, j int) bool { return fns[i].String() < fns[j].String() })
+	return fns
+}
+
 // generateFunction performs symbolic execution over a function and generates test cases.
-func (cmd *GenerateCommand) generateFunction(ctx context.Context, fn *ssa.Function) error {
+// If hotSpots is non-zero then the top branches & functions by fork count are printed.
+// If prefixes are specified then only the subtree below each fork path is explored.
+func (cmd *GenerateCommand) generateFunction(ctx context.Context, fn *ssa.Function, hotSpots int, prefixes [][]int) error {
 	var buf bytes.Buffer
 	format.Node(&buf, token.NewFileSet(), fn.Syntax())
 
 	log.Printf("[begin]")
 	log.Print(buf.String())
 
-	z3Solver := z3.NewSolver()
-	defer z3Solver.Close()
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return err
+	}
+	defer closeSolver()
 
 	e := glee.NewExecutor(fn)
-	e.Solver = z3Solver
+	e.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	defer func() { log.Printf("[solver] %s", e.QueryStats()) }()
+	e.Limits = cmd.limits
+	cmd.build.configure(e)
+	if cmd.logger != nil {
+		e.Logger = cmd.logger
+	}
+	if hotSpots > 0 && cmd.format == formatText {
+		defer cmd.printHotSpots(e, hotSpots)
+	}
 
+	output := &generateOutput{Function: fn.RelString(fn.Pkg.Pkg), States: []*generateState{}}
+	var searcher *glee.ReplaySearcher
+	var n int
 	for {
-		state, err := e.ExecuteNextState()
-		if err == glee.ErrNoStateAvailable {
+		// Replay the next checkpointed subtree once the previous one is done.
+		if searcher == nil && len(prefixes) > 0 {
+			searcher, prefixes = glee.NewReplaySearcher(prefixes[0]), prefixes[1:]
+			e.Reset(searcher)
+		}
+
+		// Stop exploring new states once cancelled but report what we have.
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == glee.ErrNoStateAvailable && len(prefixes) > 0 {
+			searcher = nil
+			continue
+		} else if err == glee.ErrNoStateAvailable {
 			break
+		} else if err != nil && ctx.Err() != nil {
+			if err := cmd.writeCheckpoint(fn, append(e.Checkpoint(), prefixes...)); err != nil {
+				return err
+			}
+			return cmd.cancelled(fn, n, ctx.Err())
 		} else if err != nil {
 			return err
+		} else if searcher != nil && !searcher.Contains(state) {
+			continue // ancestor of a replayed subtree
+		}
+		n++
+
+		if cmd.format == formatJSON {
+			if state.Terminated() {
+				output.States = append(output.States, newGenerateState(state))
+			}
+			continue
 		}
 
 		// Report when a new state occurs.
@@ -138,6 +280,10 @@ func (cmd *GenerateCommand) generateFunction(ctx context.Context, fn *ssa.Functi
 		arrays, values, err := state.Values()
 		for i, array := range arrays {
 			value := values[i]
+			if name := state.ArrayName(array.ID); name != "" {
+				fmt.Printf("%s (%s) => %x\n", array.String(), name, value)
+				continue
+			}
 			fmt.Printf("%s => %x\n", array.String(), value)
 		}
 
@@ -148,16 +294,464 @@ func (cmd *GenerateCommand) generateFunction(ctx context.Context, fn *ssa.Functi
 	log.Print("[end]")
 	log.Print("")
 
+	if cmd.format == formatJSON {
+		return json.NewEncoder(os.Stdout).Encode(output)
+	}
+	return nil
+}
+
+// generateTestFile explores fn with symbolic arguments and writes a test file
+// with a test case for each path to path. Writes to stdout if path is "-" and
+// next to the function's source file if path is blank. The inputs of each
+// path are also added to the corpus in corpusDir, if set.
+func (cmd *GenerateCommand) generateTestFile(ctx context.Context, fn *ssa.Function, path, corpusDir string, stringLen int) error {
+	cases, err := cmd.generateCases(ctx, fn, corpusDir, stringLen)
+	if err != nil {
+		return err
+	} else if corpusDir != "" && cmd.format == formatText && path != "-" {
+		fmt.Printf("wrote %d inputs to corpus %s\n", len(cases), corpusDir)
+	}
+
+	// Print the test cases instead of a test file if JSON is requested.
+	if cmd.format == formatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(newGenerateCases(cases))
+	}
+	return writeTestFile(fn, cases, path)
+}
+
+// generateExported writes a test file for every exported function in pkgs
+// whose parameters can all be symbolic. Functions which fail are reported &
+// skipped so the remaining functions are still generated.
+func (cmd *GenerateCommand) generateExported(ctx context.Context, pkgs []*ssa.Package, corpusDir string, stringLen int) error {
+	// Packages are loaded along with their test variants so skip duplicates.
+	var fns []*ssa.Function
+	m := make(map[string]struct{})
+	for _, pkg := range pkgs {
+		for _, fn := range testgen.EntryPoints(pkg) {
+			if _, ok := m[fn.String()]; ok {
+				continue
+			}
+			m[fn.String()] = struct{}{}
+			fns = append(fns, fn)
+		}
+	}
+	if len(fns) == 0 {
+		return fmt.Errorf("no exported functions with symbolic parameters")
+	}
+
+	var outputs []*generateFunctionCases
+	var failed int
+	for _, fn := range fns {
+		name := fn.RelString(fn.Pkg.Pkg)
+		cases, err := cmd.generateCases(ctx, fn, corpusDir, stringLen)
+		if err != nil && ctx.Err() != nil {
+			return cmd.cancelled(fn, len(cases), ctx.Err())
+		} else if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			continue
+		}
+
+		if cmd.format == formatJSON {
+			outputs = append(outputs, &generateFunctionCases{Function: name, Cases: newGenerateCases(cases)})
+			continue
+		} else if err := writeTestFile(fn, cases, ""); err != nil {
+			return err
+		}
+	}
+
+	if cmd.format == formatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(outputs); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d functions failed", failed, len(fns))
+	}
+	return nil
+}
+
+// generateCases explores fn with symbolic arguments & returns a test case for
+// each path. The inputs of each path are also added to the corpus in
+// corpusDir, if set.
+func (cmd *GenerateCommand) generateCases(ctx context.Context, fn *ssa.Function, corpusDir string, stringLen int) ([]*testgen.TestCase, error) {
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return nil, err
+	}
+	defer closeSolver()
+
+	g := testgen.NewGenerator()
+	g.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	g.StringLen = stringLen
+	g.Limits = cmd.limits
+	g.Preference = cmd.preference
+	g.OS, g.Arch = cmd.build.goos, cmd.build.goarch
+
+	cases, err := g.Generate(ctx, fn)
+	if err != nil {
+		return cases, err
+	}
+
+	if corpusDir != "" {
+		for _, tc := range cases {
+			if _, err := testgen.WriteCorpusEntry(corpusDir, testgen.NewCorpusEntry(fn, tc)); err != nil {
+				return cases, err
+			}
+		}
+	}
+	return cases, nil
+}
+
+// writeTestFile writes a test file with cases for fn to path. Writes to stdout
+// if path is "-" and next to the function's source file if path is blank.
+func writeTestFile(fn *ssa.Function, cases []*testgen.TestCase, path string) error {
+	buf, err := testgen.FormatTestFile(fn, cases)
+	if err != nil {
+		return err
+	}
+
+	switch path {
+	case "-":
+		_, err = os.Stdout.Write(buf)
+		return err
+	case "":
+		filename := fn.Prog.Fset.Position(fn.Pos()).Filename
+		path = filepath.Join(filepath.Dir(filename), strings.ToLower(fn.Name())+"_glee_test.go")
+	}
+	if err := ioutil.WriteFile(path, buf, 0666); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d test cases to %s\n", len(cases), path)
+	return nil
+}
+
+// generateFuzzCorpus explores the callback of the fuzz target fn and writes
+// the arguments of each path as a seed corpus entry to dir. Defaults to the
+// corpus directory of the target used by "go test", which is
+// testdata/fuzz/<target> next to the target's source file.
+func (cmd *GenerateCommand) generateFuzzCorpus(ctx context.Context, fn *ssa.Function, dir string, stringLen int) error {
+	callback, err := testgen.FuzzCallback(fn)
+	if err != nil {
+		return err
+	}
+	typs := make([]types.Type, len(callback.Params)-1)
+	for i, param := range callback.Params[1:] {
+		typs[i] = param.Type()
+	}
+
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return err
+	}
+	defer closeSolver()
+
+	g := testgen.NewGenerator()
+	g.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	g.StringLen = stringLen
+	g.Limits = cmd.limits
+	g.Preference = cmd.preference
+	g.OS, g.Arch = cmd.build.goos, cmd.build.goarch
+
+	cases, err := g.GenerateFuzz(ctx, fn)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		filename := fn.Prog.Fset.Position(fn.Pos()).Filename
+		dir = filepath.Join(filepath.Dir(filename), "testdata", "fuzz", fn.Name())
+	}
+
+	for _, tc := range cases {
+		data, err := testgen.FormatFuzzCorpusEntry(typs, tc.Inputs, tc.LittleEndian)
+		if err != nil {
+			return err
+		}
+		path, err := testgen.WriteFuzzCorpusEntry(dir, data)
+		if err != nil {
+			return err
+		}
+		if cmd.format == formatJSON {
+			continue
+		}
+		fmt.Printf("%s: %s\n", path, tc.Status)
+	}
+
+	if cmd.format == formatJSON {
+		a := make([]*generateCase, len(cases))
+		for i, tc := range cases {
+			a[i] = &generateCase{Args: tc.Args, Status: string(tc.Status), Reason: tc.Reason}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(a)
+	}
+	fmt.Printf("wrote %d seed inputs to %s\n", len(cases), dir)
+	return nil
+}
+
+// writeCheckpoint writes the fork paths of the unexplored subtrees of fn to
+// the checkpoint path, if set, so exploration can be resumed with -resume.
+func (cmd *GenerateCommand) writeCheckpoint(fn *ssa.Function, paths [][]int) error {
+	if cmd.checkpoint == "" {
+		return nil
+	}
+
+	buf, err := json.MarshalIndent(&generateCheckpoint{Function: fn.RelString(fn.Pkg.Pkg), ForkPaths: paths}, "", "\t")
+	if err != nil {
+		return err
+	} else if err := ioutil.WriteFile(cmd.checkpoint, buf, 0666); err != nil {
+		return err
+	}
+	if cmd.format == formatText {
+		fmt.Printf("wrote checkpoint of %d subtrees to %s\n", len(paths), cmd.checkpoint)
+	}
 	return nil
 }
 
+// generateCheckpoint represents the unexplored subtrees of a cancelled
+// function. Each subtree is identified by the fork path of its root state.
+type generateCheckpoint struct {
+	Function  string  `json:"function"`
+	ForkPaths [][]int `json:"forkPaths"`
+}
+
+// readGenerateCheckpoint reads a checkpoint written by -checkpoint.
+func readGenerateCheckpoint(path string) (*generateCheckpoint, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp generateCheckpoint
+	if err := json.Unmarshal(buf, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// cancelled prints a marker for a partially explored function and returns err.
+func (cmd *GenerateCommand) cancelled(fn *ssa.Function, n int, err error) error {
+	if cmd.format == formatText {
+		fmt.Printf("cancelled %s after %d states\n", fn.Name(), n)
+	}
+	log.Print("[cancelled]")
+	return err
+}
+
+// printHotSpots prints a table of the top n branches & functions by the
+// number of states they produced.
+func (cmd *GenerateCommand) printHotSpots(e *glee.Executor, n int) {
+	branches, funcs := e.HotSpots()
+	if len(branches) > n {
+		branches = branches[:n]
+	}
+	if len(funcs) > n {
+		funcs = funcs[:n]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BRANCH\tFUNCTION\tFORKS\tSTATES")
+	for _, hs := range branches {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", hs.Pos, hs.Func.Name(), hs.ForkN, hs.StateN)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "FUNCTION\tFORKS\tSTATES")
+	for _, hs := range funcs {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", hs.Func, hs.ForkN, hs.StateN)
+	}
+	w.Flush()
+}
+
+// generateOutput represents the JSON output for a single function.
+type generateOutput struct {
+	Function string           `json:"function"`
+	States   []*generateState `json:"states"`
+}
+
+// generateState represents a terminal state in the JSON output.
+type generateState struct {
+	ID          int              `json:"id"`
+	Status      string           `json:"status"`
+	Reason      string           `json:"reason,omitempty"`
+	Constraints []string         `json:"constraints"`
+	Arrays      []*generateArray `json:"arrays,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// generateArray represents the solved value of a symbolic array. The value
+// is base64-encoded in JSON.
+type generateArray struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value []byte `json:"value"`
+}
+
+// generateCase represents a test case in the JSON output of -func.
+type generateCase struct {
+	Args   []string `json:"args"`
+	Status string   `json:"status"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// newGenerateCases returns the JSON representation of test cases.
+func newGenerateCases(cases []*testgen.TestCase) []*generateCase {
+	a := make([]*generateCase, len(cases))
+	for i, tc := range cases {
+		a[i] = &generateCase{Args: tc.Args, Status: string(tc.Status), Reason: tc.Reason}
+	}
+	return a
+}
+
+// generateFunctionCases represents the test cases of a function in the JSON
+// output of -exported.
+type generateFunctionCases struct {
+	Function string          `json:"function"`
+	Cases    []*generateCase `json:"cases"`
+}
+
+// newGenerateState returns the JSON representation of a terminal state.
+// Solver errors are reported on the state instead of failing the command.
+func newGenerateState(state *glee.ExecutionState) *generateState {
+	other := &generateState{
+		ID:          state.ID(),
+		Status:      string(state.Status()),
+		Reason:      state.Reason(),
+		Constraints: []string{},
+	}
+	for _, c := range state.Constraints() {
+		other.Constraints = append(other.Constraints, c.String())
+	}
+
+	arrays, values, err := state.Values()
+	if err != nil {
+		other.Error = err.Error()
+		return other
+	}
+	for i, array := range arrays {
+		other.Arrays = append(other.Arrays, &generateArray{ID: array.ID, Name: state.ArrayName(array.ID), Value: values[i]})
+	}
+	return other
+}
+
 func (cmd *GenerateCommand) usage() {
 	fmt.Fprintln(os.Stderr, `
-usage: glee generate [arguments] [package]
+usage: glee generate [arguments] [packages]
 
 Arguments:
 
 	-v
 	    Enable verbose logging.
+
+	-format format
+	    Output format. Either "text" or "json". JSON output is written
+	    as one object per function with each terminal state's
+	    constraints & base64-encoded array values. With -func or
+	    -exported, the test cases are printed instead of writing a
+	    test file.
+
+	-hotspots n
+	    Print the top n branches & functions by forked states.
+
+	-func name
+	    Generate a table-driven test file exercising each path
+	    through the named function.
+
+	-fuzz name
+	    Symbolically execute the f.Fuzz() callback of the named fuzz
+	    target & write the arguments of each path to its seed corpus.
+	    The callback must not capture variables.
+
+	-exported
+	    Generate a test file for every exported function whose
+	    parameters are booleans, numbers, strings, byte slices, or
+	    structs of booleans & numbers. Each file is written next to the
+	    function's source. Functions which cannot be explored are
+	    reported & skipped.
+
+	-run regexp
+	    Explore functions & methods whose name matches regexp instead
+	    of functions prefixed with SymbolicTest.
+
+	-o path
+	    Output path for the -func test file. Defaults to a file next
+	    to the function's source. Use "-" for stdout. With -fuzz, the
+	    corpus directory. Defaults to testdata/fuzz/<name> next to the
+	    fuzz target's source.
+
+	-corpus dir
+	    Store the solved inputs of each -func or -exported test case in
+	    the corpus directory dir along with the path's status &
+	    covered lines.
+	    Stored inputs can be executed with "glee replay".
+
+	-checkpoint path
+	    When exploration is cancelled, such as by an interrupt, write
+	    the fork paths of the function's unexplored states to path.
+
+	-resume path
+	    Resume exploring the function recorded in a checkpoint. Only
+	    the states which were unexplored when it was written are
+	    explored.
+
+	-strlen n
+	    Length of symbolic string & byte slice arguments.
+
+	-prefer preference
+	    Values preferred for solved test case arguments. One of "none", "zero",
+	    "printable", or "minimal". Preferences are only applied where
+	    they do not change the path taken.
+
+	-max-states n
+	    Stop branching once n states are created for a function.
+
+	-max-depth n
+	    Stop exploring paths with more than n branches.
+
+	-max-instructions n
+	    Stop exploring paths after n instructions.
+
+	-max-time duration
+	    Stop exploring a function after the given duration.
+
+	-solver name
+	    Solver used for queries. One of "z3", "bitwuzla", or "smtlib".
+	    Defaults to "smtlib" if -smt is set & "z3" otherwise.
+
+	-smt command
+	    Run queries with an external SMT-LIB2 solver instead of the
+	    Z3 library. For example: "z3 -in -smt2".
+
+	-z3-timeout duration
+	    Stop each Z3 query after the given duration.
+
+	-z3-max-memory n
+	    Limit Z3 to n megabytes of memory.
+
+	-z3-seed n
+	    Seed for the randomized heuristics of Z3.
+
+	-z3-logic logic
+	    SMT-LIB2 logic used by Z3, such as "QF_ABV".
+
+	-z3-tactics tactics
+	    Comma-separated Z3 tactics applied in order instead of the
+	    default solver. For example: "simplify,solve-eqs,smt".
+
+	-tags tags
+	    Comma-separated build tags used when loading packages.
+
+	-goos os
+	-goarch arch
+	    Target operating system & architecture. Packages are loaded &
+	    functions executed for the target. Defaults to the host.
+
+Packages may be given as import paths, directories, or patterns such as
+"./...".
 `[1:])
 }
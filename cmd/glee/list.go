@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ListCommand represents a command for listing analyzable functions.
+type ListCommand struct {
+	stdout io.Writer
+}
+
+// NewListCommand returns a new instance of ListCommand.
+func NewListCommand() *ListCommand {
+	return &ListCommand{
+		stdout: os.Stdout,
+	}
+}
+
+// Run executes the "list" subcommand.
+func (cmd *ListCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-list", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "json output")
+	format := fs.String("format", formatText, "output format")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if err := validateFormat(*format); err != nil {
+		return err
+	} else if fs.NArg() == 0 {
+		return fmt.Errorf("package required")
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, fs.Args()...)
+	if err != nil {
+		return err
+	}
+
+	// Collect all functions & methods declared in the given packages.
+	var items []*listItem
+	for _, pkg := range pkgs {
+		for _, fn := range packageFunctions(pkg) {
+			items = append(items, newListItem(fn))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	if *asJSON || *format == formatJSON {
+		enc := json.NewEncoder(cmd.stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(items)
+	}
+
+	w := tabwriter.NewWriter(cmd.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FUNCTION\tPARAMETERS\tSUPPORTED")
+	for _, item := range items {
+		supported := "yes"
+		if !item.Supported {
+			supported = "no (" + strings.Join(item.Unsupported, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.Name, strings.Join(item.Params, ", "), supported)
+	}
+	return w.Flush()
+}
+
+func (cmd *ListCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee list [arguments] [packages]
+
+Lists functions in the given packages along with whether they only contain
+instructions supported by the executor. Called functions are not scanned.
+
+Arguments:
+
+	-format format
+	    Output format. Either "text" or "json".
+
+	-json
+	    Print output in JSON format. Same as -format json.
+`[1:])
+}
+
+// listItem represents a single function in the "list" output.
+type listItem struct {
+	Name        string   `json:"name"`
+	Params      []string `json:"params"`
+	Supported   bool     `json:"supported"`
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// newListItem returns a list item for fn with its unsupported constructs.
+func newListItem(fn *ssa.Function) *listItem {
+	item := &listItem{Name: fn.RelString(fn.Pkg.Pkg), Params: []string{}}
+
+	params := fn.Signature.Params()
+	for i := 0; i < params.Len(); i++ {
+		item.Params = append(item.Params, params.At(i).Name()+" "+params.At(i).Type().String())
+	}
+
+	// De-duplicate reasons while maintaining order.
+	m := make(map[string]struct{})
+	for _, u := range glee.Unsupported(fn) {
+		if _, ok := m[u.Reason]; !ok {
+			m[u.Reason] = struct{}{}
+			item.Unsupported = append(item.Unsupported, u.Reason)
+		}
+	}
+	item.Supported = len(item.Unsupported) == 0
+
+	return item
+}
+
+// packageFunctions returns all non-synthetic functions & methods declared in pkg.
+func packageFunctions(pkg *ssa.Package) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, m := range pkg.Members {
+		switch m := m.(type) {
+		case *ssa.Function:
+			if m.Synthetic == "" {
+				fns = append(fns, m)
+			}
+		case *ssa.Type:
+			mset := pkg.Prog.MethodSets.MethodSet(types.NewPointer(m.Type()))
+			for i := 0; i < mset.Len(); i++ {
+				if fn := pkg.Prog.MethodValue(mset.At(i)); fn != nil && fn.Synthetic == "" && fn.Pkg == pkg {
+					fns = append(fns, fn)
+				}
+			}
+		}
+	}
+	return fns
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestListCommand_Run(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := NewListCommand()
+		cmd.stdout = &buf
+		if err := cmd.Run(context.Background(), []string{"./testdata/list"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, exp := buf.String(), ""+
+			"FUNCTION          PARAMETERS    SUPPORTED\n"+
+			"(*Switch).Toggle                no (not operator)\n"+
+			"Add               x int, y int  yes\n"+
+			"Scale             c complex128  no (complex number operations)\n"+
+			"main                            yes\n"; got != exp {
+			t.Fatalf("unexpected output:\n%s", got)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := NewListCommand()
+		cmd.stdout = &buf
+		if err := cmd.Run(context.Background(), []string{"-json", "./testdata/list"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var items []*listItem
+		if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+			t.Fatal(err)
+		} else if got, exp := items, []*listItem{
+			{Name: "(*Switch).Toggle", Params: []string{}, Unsupported: []string{"not operator"}},
+			{Name: "Add", Params: []string{"x int", "y int"}, Supported: true},
+			{Name: "Scale", Params: []string{"c complex128"}, Unsupported: []string{"complex number operations"}},
+			{Name: "main", Params: []string{}, Supported: true},
+		}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("unexpected items: %s", buf.String())
+		}
+	})
+
+	t.Run("ErrPackageRequired", func(t *testing.T) {
+		if err := NewListCommand().Run(context.Background(), nil); err == nil || err.Error() != "package required" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
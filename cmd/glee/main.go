@@ -5,10 +5,28 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/smtlib"
+	"github.com/benbjohnson/glee/z3"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 func main() {
-	if err := run(context.Background(), os.Args[1:]); err == flag.ErrHelp {
+	// Cancel the context on SIGINT so in-flight work can shutdown gracefully.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() { <-c; cancel() }()
+
+	if err := run(ctx, os.Args[1:]); err == flag.ErrHelp {
 		os.Exit(1)
 	} else if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -26,8 +44,26 @@ func run(ctx context.Context, args []string) error {
 	case "", "-h", "--help", "help":
 		usage()
 		return flag.ErrHelp
+	case "completion":
+		return NewCompletionCommand().Run(ctx, args)
+	case "cover":
+		return NewCoverCommand().Run(ctx, args)
+	case "debug":
+		return NewDebugCommand().Run(ctx, args)
 	case "generate":
 		return NewGenerateCommand().Run(ctx, args)
+	case "list":
+		return NewListCommand().Run(ctx, args)
+	case "replay":
+		return NewReplayCommand().Run(ctx, args)
+	case "run":
+		return NewRunCommand().Run(ctx, args)
+	case "serve":
+		return NewServeCommand().Run(ctx, args)
+	case "vet":
+		return NewVetCommand().Run(ctx, args)
+	case "work":
+		return NewWorkCommand().Run(ctx, args)
 	default:
 		return fmt.Errorf(`glee %s: unknown command`, cmd)
 	}
@@ -43,7 +79,184 @@ Usage:
 
 The commands are:
 
+	completion  generate shell completion script
+	cover       write a coverage profile for a function
+	debug       interactively explore states of a function
 	generate    generate test cases
+	list        list analyzable functions
+	replay      execute stored corpus inputs concretely
+	run         report each path through a function
+	serve       coordinate exploration of a function by workers
+	vet         report unsupported constructs reachable from a function
+	work        explore states leased from a coordinator
 	help        this screen
 `[1:])
 }
+
+// buildOptions represents the build configuration used to load packages.
+// Blank fields use the defaults of the go command.
+type buildOptions struct {
+	tags   string // comma-separated build tags
+	goos   string // target operating system
+	goarch string // target architecture
+}
+
+// registerFlags adds flags for each build option to fs.
+func (opt *buildOptions) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&opt.tags, "tags", "", "comma-separated build tags")
+	fs.StringVar(&opt.goos, "goos", "", "target operating system")
+	fs.StringVar(&opt.goarch, "goarch", "", "target architecture")
+}
+
+// configure sets the OS & architecture of e to the build target, if set.
+func (opt *buildOptions) configure(e *glee.Executor) {
+	if opt.goos != "" {
+		e.OS = opt.goos
+	}
+	if opt.goarch != "" {
+		e.Arch = opt.goarch
+	}
+}
+
+// buildProgram loads the packages matching patterns & builds them in SSA
+// form. Returns the SSA packages for the initial set of packages.
+func buildProgram(opt buildOptions, patterns ...string) ([]*ssa.Package, error) {
+	config := &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Tests: true,
+	}
+	if opt.tags != "" {
+		config.BuildFlags = append(config.BuildFlags, "-tags="+opt.tags)
+	}
+	if opt.goos != "" || opt.goarch != "" {
+		config.Env = os.Environ()
+		if opt.goos != "" {
+			config.Env = append(config.Env, "GOOS="+opt.goos)
+		}
+		if opt.goarch != "" {
+			config.Env = append(config.Env, "GOARCH="+opt.goarch)
+		}
+	}
+
+	// Load the initial set of packages.
+	initial, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, err
+	} else if packages.PrintErrors(initial) > 0 {
+		return nil, fmt.Errorf("packages contain errors")
+	} else if len(initial) == 0 {
+		return nil, fmt.Errorf("no packages match: %s", strings.Join(patterns, " "))
+	}
+
+	// Build program in SSA form.
+	prog, pkgs := ssautil.AllPackages(initial, ssa.BuilderMode(0))
+	for i, pkg := range pkgs {
+		if pkg == nil {
+			return nil, fmt.Errorf("cannot build SSA for package %s", initial[i])
+		}
+		pkg.SetDebugMode(true)
+	}
+	prog.Build()
+
+	// Ensure program depends on runtime package.
+	if prog.ImportedPackage("runtime") == nil {
+		return nil, fmt.Errorf("program does not depend on runtime")
+	}
+	return pkgs, nil
+}
+
+// Output formats supported by the -format flag.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// validateFormat returns an error if format is not a supported output format.
+func validateFormat(format string) error {
+	switch format {
+	case formatText, formatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %q", format)
+	}
+}
+
+// Names of solvers supported by the -solver flag.
+const (
+	solverZ3       = "z3"
+	solverBitwuzla = "bitwuzla"
+	solverSMTLIB   = "smtlib"
+)
+
+// solverOptions represents the solver backend used for queries & its
+// configuration.
+type solverOptions struct {
+	name       string // solver name, see -solver
+	smtCommand string // external SMT-LIB2 solver command
+
+	// Z3 library configuration.
+	z3Timeout   time.Duration // maximum time per query
+	z3MaxMemory int           // maximum memory, in megabytes
+	z3Seed      uint          // random seed
+	z3Logic     string        // SMT-LIB2 logic
+	z3Tactics   string        // comma-separated tactics
+}
+
+// registerFlags adds flags for each solver option to fs.
+func (opt *solverOptions) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&opt.name, "solver", "", "solver name")
+	fs.StringVar(&opt.smtCommand, "smt", "", "external SMT-LIB2 solver command")
+	fs.DurationVar(&opt.z3Timeout, "z3-timeout", 0, "maximum time per Z3 query")
+	fs.IntVar(&opt.z3MaxMemory, "z3-max-memory", 0, "maximum Z3 memory in megabytes")
+	fs.UintVar(&opt.z3Seed, "z3-seed", 0, "Z3 random seed")
+	fs.StringVar(&opt.z3Logic, "z3-logic", "", "Z3 SMT-LIB2 logic")
+	fs.StringVar(&opt.z3Tactics, "z3-tactics", "", "comma-separated Z3 tactics")
+}
+
+// z3Config returns the Z3 solver configuration for the options.
+func (opt *solverOptions) z3Config() z3.Config {
+	config := z3.Config{
+		Timeout:    opt.z3Timeout,
+		MaxMemory:  opt.z3MaxMemory,
+		RandomSeed: opt.z3Seed,
+		Logic:      opt.z3Logic,
+	}
+	for _, name := range strings.Split(opt.z3Tactics, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.Tactics = append(config.Tactics, name)
+		}
+	}
+	return config
+}
+
+// newSolver returns the solver used for execution & a function to release it.
+// Uses an external SMT-LIB2 solver if -smt is set & the Z3 library otherwise,
+// unless a solver is named explicitly.
+func (opt *solverOptions) newSolver() (glee.Solver, func() error, error) {
+	name := opt.name
+	if name == "" {
+		name = solverZ3
+		if opt.smtCommand != "" {
+			name = solverSMTLIB
+		}
+	}
+
+	switch name {
+	case solverZ3:
+		s, err := z3.NewSolverWithConfig(opt.z3Config())
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	case solverBitwuzla:
+		return newBitwuzlaSolver()
+	case solverSMTLIB:
+		command := strings.Fields(opt.smtCommand)
+		if len(command) == 0 {
+			return nil, nil, fmt.Errorf("smtlib solver requires -smt command")
+		}
+		return &smtlib.Solver{Command: command}, func() error { return nil }, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported solver: %q", name)
+	}
+}
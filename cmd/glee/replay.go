@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/benbjohnson/glee/testgen"
+)
+
+// ReplayCommand represents a command for executing stored corpus inputs
+// concretely to reproduce the paths they were solved for.
+type ReplayCommand struct {
+	// If true, the output of each replay is printed even if it passes.
+	verbose bool
+
+	// Source directory of each package, by import path.
+	dirs map[string]string
+}
+
+// NewReplayCommand returns a new instance of ReplayCommand.
+func NewReplayCommand() *ReplayCommand {
+	return &ReplayCommand{dirs: make(map[string]string)}
+}
+
+// Run executes the "replay" subcommand.
+func (cmd *ReplayCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-replay", flag.ContinueOnError)
+	fs.BoolVar(&cmd.verbose, "v", false, "verbose")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() == 0 {
+		return fmt.Errorf("corpus path required")
+	}
+
+	// Expand corpus directories into their entries.
+	var paths []string
+	for _, arg := range fs.Args() {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		} else if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		a, err := testgen.CorpusFiles(arg)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, a...)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no corpus entries found")
+	}
+
+	var failedN int
+	for _, path := range paths {
+		entry, err := testgen.ReadCorpusEntry(path)
+		if err != nil {
+			return err
+		}
+
+		failed, err := cmd.replay(ctx, path, entry)
+		if err != nil {
+			return err
+		} else if failed {
+			failedN++
+		}
+	}
+
+	if failedN > 0 {
+		return fmt.Errorf("%d of %d inputs failed", failedN, len(paths))
+	}
+	return nil
+}
+
+// replay executes the target of entry with its inputs using "go test" and
+// prints whether the outcome matches the status of the stored path. Returns
+// true if the target failed.
+func (cmd *ReplayCommand) replay(ctx context.Context, path string, entry *testgen.CorpusEntry) (failed bool, err error) {
+	dir, err := cmd.packageDir(ctx, entry.Package)
+	if err != nil {
+		return false, err
+	}
+
+	src, err := testgen.FormatReplayFile(entry)
+	if err != nil {
+		return false, err
+	}
+
+	// Write the replay test next to the target, without replacing a file.
+	filename := filepath.Join(dir, "glee_replay_test.go")
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(filename)
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return false, err
+	} else if err := f.Close(); err != nil {
+		return false, err
+	}
+
+	testArgs := []string{"test", "-count=1", "-run", "^" + testgen.ReplayTestName + "$"}
+	if cmd.verbose {
+		testArgs = append(testArgs, "-v")
+	}
+	testArgs = append(testArgs, ".")
+
+	var output bytes.Buffer
+	c := exec.CommandContext(ctx, "go", testArgs...)
+	c.Dir = dir
+	c.Stdout, c.Stderr = &output, &output
+	if err := c.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok || ctx.Err() != nil {
+			return false, err
+		}
+		failed = true
+	}
+
+	var result string
+	switch {
+	case failed && entry.Failed():
+		result = "reproduced"
+	case failed:
+		result = "unexpected failure"
+	case entry.Failed():
+		result = "not reproduced"
+	default:
+		result = "ok"
+	}
+	fmt.Printf("%s: %s(%s): %s: %s\n", path, entry.Function, strings.Join(entry.Args, ", "), entry.Status, result)
+
+	if failed || cmd.verbose {
+		os.Stdout.Write(output.Bytes())
+		fmt.Println("")
+	}
+	return failed, nil
+}
+
+// packageDir returns the source directory of the package with the given
+// import path. Directories are cached as multiple entries share a package.
+func (cmd *ReplayCommand) packageDir(ctx context.Context, pkgPath string) (string, error) {
+	if dir, ok := cmd.dirs[pkgPath]; ok {
+		return dir, nil
+	}
+
+	buf, err := exec.CommandContext(ctx, "go", "list", "-f", "{{.Dir}}", pkgPath).Output()
+	if err != nil {
+		if err, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("cannot find package %s: %s", pkgPath, bytes.TrimSpace(err.Stderr))
+		}
+		return "", err
+	}
+
+	dir := strings.TrimSpace(string(buf))
+	cmd.dirs[pkgPath] = dir
+	return dir, nil
+}
+
+func (cmd *ReplayCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee replay [arguments] path...
+
+Executes the target function of each corpus entry concretely with its stored
+inputs using "go test" & reports whether the outcome matches the status of the
+path the inputs were solved for. Paths may be entry files or corpus
+directories written by "glee generate -corpus". Output of failing replays,
+such as the stack trace of a panic, is printed.
+
+Paths which only fail under symbolic execution, such as a failed glee.Assert(),
+pass when replayed & are reported as not reproduced.
+
+Arguments:
+
+	-v
+	    Print the "go test" output of every replay.
+`[1:])
+}
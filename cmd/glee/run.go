@@ -0,0 +1,500 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+	"golang.org/x/tools/go/ssa"
+)
+
+// RunCommand represents a command for reporting each path through a function.
+type RunCommand struct {
+	// Solver backend & its configuration.
+	solver solverOptions
+
+	// Exploration limits for the function.
+	limits glee.Limits
+
+	// Receives executor log messages. Discards messages if nil.
+	logger glee.Logger
+
+	// Output format. Either "text" or "json".
+	format string
+
+	// Values preferred for solved inputs.
+	preference glee.Preference
+
+	// Path to write the state tree to in DOT format. Skipped if blank.
+	treePath string
+
+	// If true, paths exceeding their solver time or memory budget are
+	// deprioritized instead of exhausted.
+	deprioritize bool
+
+	// Per-query solver timeout, retries & handling of undecided queries.
+	queryTimeout  time.Duration
+	queryRetries  int
+	unknownPolicy glee.UnknownPolicy
+}
+
+// NewRunCommand returns a new instance of RunCommand.
+func NewRunCommand() *RunCommand {
+	return &RunCommand{}
+}
+
+// Run executes the "run" subcommand.
+func (cmd *RunCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-run", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "verbose")
+	stringLen := fs.Int("strlen", testgen.DefaultStringLen, "symbolic string length")
+	cmd.solver.registerFlags(fs)
+	prefer := fs.String("prefer", glee.PreferNone.String(), "preferred input values")
+	fs.IntVar(&cmd.limits.MaxStates, "max-states", 0, "maximum states")
+	fs.IntVar(&cmd.limits.MaxDepth, "max-depth", 0, "maximum branches per path")
+	fs.IntVar(&cmd.limits.MaxInstructions, "max-instructions", 0, "maximum instructions per path")
+	fs.DurationVar(&cmd.limits.MaxTime, "max-time", 0, "maximum time")
+	fs.DurationVar(&cmd.limits.MaxSolverTime, "max-solver-time", 0, "maximum solver time per path")
+	fs.IntVar(&cmd.limits.MaxMemory, "max-memory", 0, "maximum memory per path")
+	fs.IntVar(&cmd.limits.MaxExprDepth, "max-expr-depth", 0, "maximum expression depth")
+	fs.IntVar(&cmd.limits.MaxExprSize, "max-expr-size", 0, "maximum expression size")
+	fs.BoolVar(&cmd.deprioritize, "deprioritize", false, "deprioritize paths exceeding budgets")
+	fs.DurationVar(&cmd.queryTimeout, "query-timeout", 0, "maximum time per solver query")
+	fs.IntVar(&cmd.queryRetries, "query-retries", 0, "retries of timed out solver queries")
+	onUnknown := fs.String("on-unknown", glee.UnknownPolicyFailFast.String(), "handling of undecided solver queries")
+	fs.StringVar(&cmd.format, "format", formatText, "output format")
+	fs.StringVar(&cmd.treePath, "tree", "", "state tree output path")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if err := validateFormat(cmd.format); err != nil {
+		return err
+	} else if cmd.preference, err = glee.ParsePreference(*prefer); err != nil {
+		return err
+	} else if cmd.unknownPolicy, err = glee.ParseUnknownPolicy(*onUnknown); err != nil {
+		return err
+	} else if fs.NArg() < 2 {
+		return fmt.Errorf("package & function required")
+	} else if fs.NArg() > 2 {
+		return fmt.Errorf("too many arguments specified")
+	}
+
+	if *verbose {
+		cmd.logger = glee.NewLogger(os.Stderr, glee.LogLevelDebug)
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fn := findFunction(pkgs, fs.Arg(1))
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", fs.Arg(1))
+	}
+	return cmd.runFunction(ctx, fn, *stringLen)
+}
+
+// runFunction explores fn with symbolic arguments and prints a report for
+// each terminal state.
+func (cmd *RunCommand) runFunction(ctx context.Context, fn *ssa.Function, stringLen int) error {
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return err
+	}
+	defer closeSolver()
+
+	tracer := newPathTracer()
+
+	e := glee.NewExecutor(fn)
+	e.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	e.Limits = cmd.limits
+	e.Tracer = tracer
+	e.RecordStateTree = cmd.treePath != ""
+	if cmd.deprioritize {
+		e.BudgetPolicy = glee.BudgetPolicyDeprioritize
+	}
+	e.QueryTimeout, e.QueryRetries = cmd.queryTimeout, cmd.queryRetries
+	e.UnknownPolicy = cmd.unknownPolicy
+	if cmd.logger != nil {
+		e.Logger = cmd.logger
+	}
+
+	arrays, err := e.BindSymbolicParams(stringLen)
+	if err != nil {
+		return err
+	}
+
+	report := &runReport{Function: fn.RelString(fn.Pkg.Pkg), Paths: []*runPath{}}
+	for {
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == glee.ErrNoStateAvailable {
+			break
+		} else if err != nil && ctx.Err() != nil {
+			if cmd.format == formatText {
+				fmt.Printf("cancelled %s after %d paths\n", fn.Name(), len(report.Paths))
+			}
+			return ctx.Err()
+		} else if err != nil {
+			return err
+		} else if !state.Terminated() {
+			continue
+		}
+
+		path, err := cmd.newRunPath(ctx, e, fn, state, arrays, tracer.positions(state))
+		if err != nil {
+			return err
+		}
+		report.Paths = append(report.Paths, path)
+
+		// Text output is streamed as each path completes.
+		if cmd.format == formatText {
+			path.print()
+		}
+	}
+
+	if cmd.treePath != "" {
+		if err := writeStateTree(e, cmd.treePath); err != nil {
+			return err
+		}
+	}
+
+	if cmd.format == formatJSON {
+		report.Coverage = coveredLines(e.Coverage())
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(report)
+	}
+	fmt.Printf("%d paths explored\n", len(report.Paths))
+	return nil
+}
+
+// newRunPath returns the status, constraints, traversed positions & solved
+// inputs of a terminal state.
+func (cmd *RunCommand) newRunPath(ctx context.Context, e *glee.Executor, fn *ssa.Function, state *glee.ExecutionState, arrays []*glee.Array, positions []string) (*runPath, error) {
+	path := &runPath{
+		ID:          state.ID(),
+		Status:      string(state.Status()),
+		Reason:      state.Reason(),
+		Constraints: []string{},
+		Conditions:  []string{},
+		Trace:       positions,
+		Resources:   newRunResources(state),
+	}
+	if path.Trace == nil {
+		path.Trace = []string{}
+	}
+	for _, c := range state.Constraints() {
+		path.Constraints = append(path.Constraints, c.String())
+		path.Conditions = append(path.Conditions, state.FormatExpr(c))
+	}
+
+	// Exhausted & dropped states do not represent a feasible path.
+	switch state.Status() {
+	case glee.ExecutionStatusExhausted, glee.ExecutionStatusDropped:
+		return path, nil
+	}
+
+	solver := e.Solver
+	if cmd.preference != glee.PreferNone {
+		solver = glee.NewPreferringSolver(e.Solver, cmd.preference)
+	}
+
+	satisfiable, values, err := glee.SolveContext(ctx, solver, state.Constraints(), arrays)
+	if err != nil {
+		return nil, err
+	} else if !satisfiable {
+		return path, nil
+	}
+
+	path.Satisfiable = true
+	for i, param := range fn.Params {
+		lit, err := testgen.FormatValue(param.Type(), values[i], e.IsLittleEndian())
+		if err != nil {
+			lit = fmt.Sprintf("%x", values[i])
+		}
+		path.Inputs = append(path.Inputs, &runInput{
+			Name:  param.Name(),
+			Type:  param.Type().String(),
+			Value: lit,
+			Bytes: values[i],
+		})
+	}
+	return path, nil
+}
+
+func (cmd *RunCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee run [arguments] package function
+
+Symbolically executes the named function with symbolic arguments and prints
+each terminal path along with its status, the conditions on its inputs, the
+source lines it traversed, and a set of inputs which follow the path. Methods
+are named as in "glee list" & their receiver is symbolic. Pointer receivers
+reference a symbolic value of their element type.
+
+Arguments:
+
+	-v
+	    Enable verbose logging.
+
+	-format format
+	    Output format. Either "text" or "json". JSON output includes
+	    each path's raw constraints, base64-encoded input bytes, and the
+	    lines covered by all paths.
+
+	-strlen n
+	    Length of symbolic string & byte slice arguments.
+
+	-prefer preference
+	    Values preferred for solved inputs. One of "none", "zero",
+	    "printable", or "minimal". Preferences are only applied where
+	    they do not change the path taken.
+
+	-tree path
+	    Write the tree of forked states to path in the DOT format
+	    used by Graphviz. Render with "dot -Tsvg path".
+
+	-max-states n
+	    Stop branching once n states are created.
+
+	-max-depth n
+	    Stop exploring paths with more than n branches.
+
+	-max-instructions n
+	    Stop exploring paths after n instructions.
+
+	-max-time duration
+	    Stop exploring after the given duration.
+
+	-max-solver-time duration
+	    Stop exploring paths after the given solver time.
+
+	-max-memory n
+	    Stop exploring paths after n bytes are allocated.
+
+	-max-expr-depth n
+	-max-expr-size n
+	    Replace values whose expression is deeper than n, or has more
+	    than n distinct subexpressions, with a single feasible value.
+
+	-deprioritize
+	    Explore paths exceeding -max-solver-time or -max-memory once
+	    all other paths are explored instead of stopping them.
+
+	-query-timeout duration
+	    Stop a single solver query after the given duration.
+
+	-query-retries n
+	    Retry timed out queries up to n times, doubling the timeout
+	    each time. Requires -query-timeout.
+
+	-on-unknown policy
+	    Handling of queries the solver cannot decide, such as timeouts.
+	    One of "fail" to stop exploring, "terminate" to stop only the
+	    affected path, or "skip" to assume the query is unsatisfiable
+	    & not explore the branch.
+
+	-solver name
+	    Solver used for queries. One of "z3", "bitwuzla", or "smtlib".
+	    Defaults to "smtlib" if -smt is set & "z3" otherwise.
+
+	-smt command
+	    Run queries with an external SMT-LIB2 solver instead of the
+	    Z3 library. For example: "z3 -in -smt2".
+
+	-z3-timeout duration
+	    Stop each Z3 query after the given duration.
+
+	-z3-max-memory n
+	    Limit Z3 to n megabytes of memory.
+
+	-z3-seed n
+	    Seed for the randomized heuristics of Z3.
+
+	-z3-logic logic
+	    SMT-LIB2 logic used by Z3, such as "QF_ABV".
+
+	-z3-tactics tactics
+	    Comma-separated Z3 tactics applied in order instead of the
+	    default solver. For example: "simplify,solve-eqs,smt".
+`[1:])
+}
+
+// writeStateTree writes the state tree recorded by e to a file at path.
+func writeStateTree(e *glee.Executor, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := e.WriteStateTree(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// runReport represents the output of the "run" command.
+type runReport struct {
+	Function string           `json:"function"`
+	Paths    []*runPath       `json:"paths"`
+	Coverage map[string][]int `json:"coverage,omitempty"`
+}
+
+// runPath represents a single terminal state in the "run" output.
+type runPath struct {
+	ID          int           `json:"id"`
+	Status      string        `json:"status"`
+	Reason      string        `json:"reason,omitempty"`
+	Constraints []string      `json:"constraints"`
+	Conditions  []string      `json:"conditions"` // constraints in Go-like syntax
+	Satisfiable bool          `json:"satisfiable"`
+	Inputs      []*runInput   `json:"inputs,omitempty"`
+	Trace       []string      `json:"trace"`
+	Resources   *runResources `json:"resources"`
+}
+
+// runResources represents the resources used by a path.
+type runResources struct {
+	InstructionN  int    `json:"instructions"`
+	SolverTime    string `json:"solverTime"`
+	MemoryN       int    `json:"memory"`
+	Deprioritized bool   `json:"deprioritized,omitempty"`
+}
+
+// newRunResources returns the resource accounting of state.
+func newRunResources(state *glee.ExecutionState) *runResources {
+	return &runResources{
+		InstructionN:  state.InstructionN(),
+		SolverTime:    state.SolverTime().String(),
+		MemoryN:       state.MemoryN(),
+		Deprioritized: state.Deprioritized(),
+	}
+}
+
+// runInput represents a solved argument. Bytes are base64-encoded in JSON.
+type runInput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Bytes []byte `json:"bytes"`
+}
+
+// print writes the path in a human-readable format to stdout.
+func (p *runPath) print() {
+	fmt.Printf("path#%d: %s\n", p.ID, p.Status)
+	if p.Reason != "" {
+		fmt.Printf("  reason: %s\n", p.Reason)
+	}
+	if len(p.Conditions) > 0 {
+		fmt.Println("  conditions:")
+		for _, cond := range p.Conditions {
+			fmt.Printf("    %s\n", cond)
+		}
+	}
+
+	// Exhausted & dropped states are never solved.
+	switch glee.ExecutionStatus(p.Status) {
+	case glee.ExecutionStatusExhausted, glee.ExecutionStatusDropped:
+	default:
+		if !p.Satisfiable {
+			fmt.Println("  inputs: unsatisfiable")
+			break
+		}
+		fmt.Println("  inputs:")
+		for _, input := range p.Inputs {
+			fmt.Printf("    %s = %s\n", input.Name, input.Value)
+		}
+	}
+
+	r := p.Resources
+	fmt.Printf("  resources: instructions=%d solver=%s memory=%dB", r.InstructionN, r.SolverTime, r.MemoryN)
+	if r.Deprioritized {
+		fmt.Print(" deprioritized")
+	}
+	fmt.Println("")
+
+	fmt.Println("  trace:")
+	for _, pos := range p.Trace {
+		fmt.Printf("    %s\n", pos)
+	}
+	fmt.Println("")
+}
+
+// coveredLines returns the sorted lines executed by any state, by filename.
+func coveredLines(c *glee.Coverage) map[string][]int {
+	m := make(map[string][]int, len(c.Files))
+	for _, fc := range c.Files {
+		a := []int{}
+		for line, ok := range fc.Lines {
+			if ok {
+				a = append(a, line)
+			}
+		}
+		sort.Ints(a)
+		m[fc.Filename] = a
+	}
+	return m
+}
+
+// findFunction returns the function or method in pkgs matching name, as
+// formatted by the "list" command. Returns nil if no function matches.
+func findFunction(pkgs []*ssa.Package, name string) *ssa.Function {
+	for _, pkg := range pkgs {
+		for _, fn := range packageFunctions(pkg) {
+			if fn.RelString(fn.Pkg.Pkg) == name {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// pathTracer records the source lines traversed by each state. Children
+// inherit the lines of their parent at the point of the fork.
+type pathTracer struct {
+	m map[int][]string
+}
+
+// newPathTracer returns a new instance of pathTracer.
+func newPathTracer() *pathTracer {
+	return &pathTracer{m: make(map[int][]string)}
+}
+
+// positions returns the lines traversed by state & releases them.
+func (t *pathTracer) positions(state *glee.ExecutionState) []string {
+	a := t.m[state.ID()]
+	delete(t.m, state.ID())
+	return a
+}
+
+func (t *pathTracer) OnFork(parent, child *glee.ExecutionState) {
+	t.m[child.ID()] = append([]string(nil), t.m[parent.ID()]...)
+}
+
+func (t *pathTracer) OnInstruction(state *glee.ExecutionState, instr ssa.Instruction) {
+	pos := state.Position()
+	if !pos.IsValid() {
+		return
+	}
+
+	// Only record a line once per consecutive run of instructions.
+	line := fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+	if a := t.m[state.ID()]; len(a) > 0 && a[len(a)-1] == line {
+		return
+	}
+	t.m[state.ID()] = append(t.m[state.ID()], line)
+}
+
+func (t *pathTracer) OnTerminalState(state *glee.ExecutionState) {}
+
+func (t *pathTracer) OnSolverQuery(constraints []glee.Expr, satisfiable bool, d time.Duration, err error) {
+}
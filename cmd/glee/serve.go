@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+)
+
+// ServeCommand represents a command for coordinating the exploration of a
+// function by remote workers. See WorkCommand.
+//
+// Workers lease subtrees of the state tree, identified by fork path, and
+// replay the path from the entry state before exploring the subtree. Workers
+// report terminal states as they complete. When workers are idle, busy
+// workers are asked to offload unexplored states which are then leased to
+// the idle workers. Leases which are not reported within the lease timeout
+// are returned to the queue so the loss of a worker does not lose paths.
+type ServeCommand struct {
+	// Address to listen on.
+	addr string
+
+	// Configuration handed to workers.
+	config workConfig
+
+	// Time before an unreported lease is reassigned.
+	leaseTimeout time.Duration
+
+	// Output format. Either "text" or "json".
+	format string
+
+	mu        sync.Mutex
+	queue     [][]int             // fork paths not yet leased
+	leases    map[int]*workLease  // active leases, by ID
+	leaseSeq  int                 // last assigned lease ID
+	waitN     int                 // lease requests waiting for work
+	offloadN  int                 // states requested from busy workers
+	keys      map[string]struct{} // fork paths of reported states
+	paths     []*runPath          // reported terminal states
+	coverage  map[string]map[int]struct{}
+	changed   chan struct{} // closed & replaced when work is added or done
+	done      chan struct{} // closed once all states are explored
+	closeDone sync.Once
+}
+
+// NewServeCommand returns a new instance of ServeCommand.
+func NewServeCommand() *ServeCommand {
+	return &ServeCommand{
+		queue:    [][]int{{}},
+		leases:   make(map[int]*workLease),
+		keys:     make(map[string]struct{}),
+		coverage: make(map[string]map[int]struct{}),
+		changed:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run executes the "serve" subcommand.
+func (cmd *ServeCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-serve", flag.ContinueOnError)
+	fs.StringVar(&cmd.addr, "addr", DefaultServeAddr, "listen address")
+	fs.IntVar(&cmd.config.StringLen, "strlen", testgen.DefaultStringLen, "symbolic string length")
+	prefer := fs.String("prefer", glee.PreferNone.String(), "preferred input values")
+	fs.IntVar(&cmd.config.Limits.MaxDepth, "max-depth", 0, "maximum branches per path")
+	fs.IntVar(&cmd.config.Limits.MaxInstructions, "max-instructions", 0, "maximum instructions per path")
+	fs.IntVar(&cmd.config.Limits.MaxMemory, "max-memory", 0, "maximum memory per path")
+	fs.DurationVar(&cmd.leaseTimeout, "lease-timeout", DefaultLeaseTimeout, "time before an unreported lease is reassigned")
+	fs.StringVar(&cmd.format, "format", formatText, "output format")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if err := validateFormat(cmd.format); err != nil {
+		return err
+	} else if _, err := glee.ParsePreference(*prefer); err != nil {
+		return err
+	} else if fs.NArg() < 2 {
+		return fmt.Errorf("package & function required")
+	} else if fs.NArg() > 2 {
+		return fmt.Errorf("too many arguments specified")
+	}
+	cmd.config.Preference = *prefer
+
+	// Ensure the function exists before handing it to workers.
+	pkgs, err := buildProgram(buildOptions{}, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fn := findFunction(pkgs, fs.Arg(1))
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", fs.Arg(1))
+	}
+	cmd.config.Package, cmd.config.Function = fs.Arg(0), fs.Arg(1)
+
+	ln, err := net.Listen("tcp", cmd.addr)
+	if err != nil {
+		return err
+	}
+	if cmd.format == formatText {
+		fmt.Printf("serving %s on %s\n", fn.Name(), ln.Addr())
+	}
+
+	srv := &http.Server{Handler: cmd}
+	go srv.Serve(ln)
+
+	go cmd.monitorLeases(ctx)
+
+	select {
+	case <-ctx.Done():
+		srv.Close()
+		if cmd.format == formatText {
+			fmt.Printf("cancelled %s after %d paths\n", fn.Name(), len(cmd.reportedPaths()))
+		}
+		return ctx.Err()
+	case <-cmd.done:
+	}
+
+	// Allow waiting workers to receive the end of exploration.
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	paths := cmd.reportedPaths()
+	if cmd.format == formatJSON {
+		report := &runReport{Function: cmd.config.Function, Paths: paths, Coverage: cmd.coveredLines()}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		return enc.Encode(report)
+	}
+	fmt.Printf("%d paths explored\n", len(paths))
+	return nil
+}
+
+// ServeHTTP handles requests from workers.
+func (cmd *ServeCommand) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/config":
+		writeJSON(w, &cmd.config)
+	case r.Method == "POST" && r.URL.Path == "/lease":
+		cmd.handleLease(w, r)
+	case r.Method == "POST" && r.URL.Path == "/report":
+		cmd.handleReport(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleLease leases a subtree to a worker. Waits up to leaseWaitTime for
+// work to become available. Returns 204 No Content if none is available &
+// 410 Gone once all states are explored.
+func (cmd *ServeCommand) handleLease(w http.ResponseWriter, r *http.Request) {
+	timer := time.NewTimer(leaseWaitTime)
+	defer timer.Stop()
+
+	for {
+		lease, changed := cmd.nextLease()
+		if lease != nil {
+			writeJSON(w, lease)
+			return
+		}
+
+		select {
+		case <-cmd.done:
+			cmd.stopWaiting()
+			w.WriteHeader(http.StatusGone)
+			return
+		case <-changed:
+		case <-timer.C:
+			cmd.stopWaiting()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-r.Context().Done():
+			cmd.stopWaiting()
+			return
+		}
+		cmd.stopWaiting()
+	}
+}
+
+// nextLease returns a lease for the next queued fork path. Otherwise the
+// caller is counted as waiting & a channel is returned which is closed once
+// work is added.
+func (cmd *ServeCommand) nextLease() (*workLease, chan struct{}) {
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+
+	if len(cmd.queue) == 0 {
+		cmd.waitN++
+		return nil, cmd.changed
+	}
+
+	cmd.leaseSeq++
+	lease := &workLease{ID: cmd.leaseSeq, Prefix: cmd.queue[0], deadline: time.Now().Add(cmd.leaseTimeout)}
+	cmd.queue = cmd.queue[1:]
+	cmd.leases[lease.ID] = lease
+	return lease, nil
+}
+
+// stopWaiting removes a lease request from the waiting count.
+func (cmd *ServeCommand) stopWaiting() {
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+	cmd.waitN--
+}
+
+// handleReport records the terminal states, offloaded states, & coverage
+// reported by a worker for a lease. Returns 410 Gone if the lease has expired.
+func (cmd *ServeCommand) handleReport(w http.ResponseWriter, r *http.Request) {
+	var report workReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+
+	lease := cmd.leases[report.Lease]
+	if lease == nil {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	lease.deadline = time.Now().Add(cmd.leaseTimeout)
+
+	// States may be reported more than once if a lease expired while its
+	// worker was still running so each fork path is only recorded once.
+	for _, p := range report.Paths {
+		key := fmt.Sprint(p.ForkPath)
+		if _, ok := cmd.keys[key]; ok {
+			continue
+		}
+		cmd.keys[key] = struct{}{}
+
+		p.Path.ID = len(cmd.paths) + 1
+		cmd.paths = append(cmd.paths, p.Path)
+		if cmd.format == formatText {
+			p.Path.print()
+		}
+	}
+
+	for filename, lines := range report.Coverage {
+		m := cmd.coverage[filename]
+		if m == nil {
+			m = make(map[int]struct{})
+			cmd.coverage[filename] = m
+		}
+		for _, line := range lines {
+			m[line] = struct{}{}
+		}
+	}
+
+	// Requested states are considered delivered with the next report.
+	cmd.offloadN -= lease.offloadN
+	lease.offloadN = 0
+	if len(report.Offloaded) > 0 {
+		cmd.queue = append(cmd.queue, report.Offloaded...)
+		cmd.notify()
+	}
+
+	if report.Done {
+		delete(cmd.leases, lease.ID)
+		cmd.checkDone()
+		writeJSON(w, &workReportResponse{})
+		return
+	}
+
+	// Ask the worker to offload states for workers without work.
+	if n := cmd.waitN - len(cmd.queue) - cmd.offloadN; n > 0 {
+		lease.offloadN = n
+		cmd.offloadN += n
+	}
+	writeJSON(w, &workReportResponse{Offload: lease.offloadN})
+}
+
+// monitorLeases returns the fork paths of expired leases to the queue.
+func (cmd *ServeCommand) monitorLeases(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cmd.done:
+			return
+		case <-ticker.C:
+		}
+
+		cmd.mu.Lock()
+		now := time.Now()
+		for id, lease := range cmd.leases {
+			if now.Before(lease.deadline) {
+				continue
+			}
+			delete(cmd.leases, id)
+			cmd.offloadN -= lease.offloadN
+			cmd.queue = append(cmd.queue, lease.Prefix)
+			cmd.notify()
+		}
+		cmd.mu.Unlock()
+	}
+}
+
+// notify wakes lease requests waiting for work. Must hold mu.
+func (cmd *ServeCommand) notify() {
+	close(cmd.changed)
+	cmd.changed = make(chan struct{})
+}
+
+// checkDone closes the done channel if no work is queued or leased. Must hold mu.
+func (cmd *ServeCommand) checkDone() {
+	if len(cmd.queue) == 0 && len(cmd.leases) == 0 {
+		cmd.closeDone.Do(func() { close(cmd.done) })
+	}
+}
+
+// reportedPaths returns the terminal states reported so far.
+func (cmd *ServeCommand) reportedPaths() []*runPath {
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+	return append([]*runPath{}, cmd.paths...)
+}
+
+// coveredLines returns the sorted lines reported by any worker, by filename.
+func (cmd *ServeCommand) coveredLines() map[string][]int {
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+
+	m := make(map[string][]int, len(cmd.coverage))
+	for filename, lines := range cmd.coverage {
+		a := []int{}
+		for line := range lines {
+			a = append(a, line)
+		}
+		sort.Ints(a)
+		m[filename] = a
+	}
+	return m
+}
+
+func (cmd *ServeCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee serve [arguments] package function
+
+Coordinates the exploration of the named function by workers started with
+"glee work". Each worker must be able to load the same package from the
+same source. Terminal paths are printed as workers report them, in the same
+format as "glee run".
+
+Workers lease subtrees of the state tree & replay the branches leading to
+them. Idle workers are given states offloaded by busy workers. Exploration
+finishes once every subtree has been explored.
+
+Arguments:
+
+	-addr address
+	    Listen address for workers. Defaults to "`+DefaultServeAddr+`".
+
+	-format format
+	    Output format. Either "text" or "json".
+
+	-strlen n
+	    Length of symbolic string & byte slice arguments.
+
+	-prefer preference
+	    Values preferred for solved inputs. One of "none", "zero",
+	    "printable", or "minimal".
+
+	-lease-timeout duration
+	    Reassign a subtree if its worker does not report within the
+	    given duration.
+
+	-max-depth n
+	    Stop exploring paths with more than n branches.
+
+	-max-instructions n
+	    Stop exploring paths after n instructions.
+
+	-max-memory n
+	    Stop exploring paths after n bytes are allocated.
+
+Limits which depend on time are not supported as subtrees are replayed by
+re-executing the branches leading to them, which must fork identically.
+`[1:])
+}
+
+// DefaultServeAddr is the default address the coordinator listens on.
+const DefaultServeAddr = "localhost:7070"
+
+// DefaultLeaseTimeout is the default time before an unreported lease is reassigned.
+const DefaultLeaseTimeout = 5 * time.Minute
+
+// leaseWaitTime is the time a lease request waits for work to become available.
+const leaseWaitTime = 30 * time.Second
+
+// workConfig represents the exploration configuration handed to workers.
+type workConfig struct {
+	Package    string      `json:"package"`
+	Function   string      `json:"function"`
+	StringLen  int         `json:"strlen"`
+	Preference string      `json:"prefer"`
+	Limits     glee.Limits `json:"limits"`
+}
+
+// workLease represents a subtree leased to a worker. The subtree is the state
+// with the fork path prefix & its descendants.
+type workLease struct {
+	ID     int   `json:"id"`
+	Prefix []int `json:"prefix"`
+
+	deadline time.Time // time the lease expires unless reported
+	offloadN int       // states requested from the worker
+}
+
+// workReport represents the progress of a worker on a lease.
+type workReport struct {
+	Lease     int              `json:"lease"`
+	Paths     []*workPath      `json:"paths,omitempty"`
+	Offloaded [][]int          `json:"offloaded,omitempty"` // fork paths of offloaded states
+	Coverage  map[string][]int `json:"coverage,omitempty"`
+	Done      bool             `json:"done,omitempty"` // true if the subtree is explored
+}
+
+// workPath represents a terminal state reported by a worker.
+type workPath struct {
+	ForkPath []int    `json:"forkPath"`
+	Path     *runPath `json:"path"`
+}
+
+// workReportResponse represents the response to a report.
+type workReportResponse struct {
+	// Number of unexplored states the worker should offload.
+	Offload int `json:"offload,omitempty"`
+}
+
+// writeJSON writes v to w as JSON.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
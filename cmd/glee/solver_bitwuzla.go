@@ -0,0 +1,16 @@
+//go:build bitwuzla
+// +build bitwuzla
+
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/bitwuzla"
+)
+
+// newBitwuzlaSolver returns a solver using the Bitwuzla library & a function
+// to release it.
+func newBitwuzlaSolver() (glee.Solver, func() error, error) {
+	s := bitwuzla.NewSolver()
+	return s, s.Close, nil
+}
@@ -0,0 +1,16 @@
+//go:build !bitwuzla
+// +build !bitwuzla
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/benbjohnson/glee"
+)
+
+// newBitwuzlaSolver returns an error as Bitwuzla support requires building
+// with the "bitwuzla" tag.
+func newBitwuzlaSolver() (glee.Solver, func() error, error) {
+	return nil, nil, fmt.Errorf("bitwuzla solver not supported, rebuild with -tags bitwuzla")
+}
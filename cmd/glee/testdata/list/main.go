@@ -0,0 +1,17 @@
+package main
+
+import "runtime"
+
+// main depends on the runtime, which is required to build the program.
+func main() { runtime.GC() }
+
+// Add only contains supported instructions.
+func Add(x, y int) int { return x + y }
+
+// Scale multiplies a complex number, which is not supported.
+func Scale(c complex128) complex128 { return c * 2 }
+
+type Switch struct{ on bool }
+
+// Toggle uses the not operator, which is not supported.
+func (s *Switch) Toggle() bool { return !s.on }
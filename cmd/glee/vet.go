@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+// VetCommand represents a command for reporting the unsupported constructs
+// reachable from a function before it is executed.
+type VetCommand struct{}
+
+// NewVetCommand returns a new instance of VetCommand.
+func NewVetCommand() *VetCommand {
+	return &VetCommand{}
+}
+
+// Run executes the "vet" subcommand.
+func (cmd *VetCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-vet", flag.ContinueOnError)
+	format := fs.String("format", formatText, "output format")
+	havoc := fs.Bool("havoc", false, "approximate functions without a body")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if err := validateFormat(*format); err != nil {
+		return err
+	} else if fs.NArg() < 2 {
+		return fmt.Errorf("package & function required")
+	} else if fs.NArg() > 2 {
+		return fmt.Errorf("too many arguments specified")
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fn := findFunction(pkgs, fs.Arg(1))
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", fs.Arg(1))
+	}
+
+	e := glee.NewExecutor(fn)
+	e.Havoc = *havoc
+	a, err := e.Vet()
+	if err != nil {
+		return err
+	}
+
+	items := make([]*vetItem, len(a))
+	for i, u := range a {
+		items[i] = newVetItem(fn, u)
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(items); err != nil {
+			return err
+		}
+	} else if len(items) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "POSITION\tFUNCTION\tREASON\tSTUB")
+		for _, item := range items {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Pos, item.Func, item.Reason, item.Stub)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(items) > 0 {
+		return fmt.Errorf("%d unsupported instructions reachable from %s", len(items), fs.Arg(1))
+	}
+	return nil
+}
+
+func (cmd *VetCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee vet [arguments] package function
+
+Reports the instructions reachable from the named function which the executor
+cannot handle without running it. Calls are followed through static calls,
+function values & interface methods. Functions handled by the executor or
+annotated with //glee:skip are not followed. Each instruction is listed with
+a suggestion for how to stub it out. Exits with an error if any are found.
+Methods are named as in "glee list".
+
+Arguments:
+
+	-format format
+	    Output format. Either "text" or "json".
+
+	-havoc
+	    Do not report calls to functions without a body, such as
+	    assembly, as they are approximated during execution.
+`[1:])
+}
+
+// vetItem represents a single unsupported instruction in the "vet" output.
+type vetItem struct {
+	Pos    string `json:"pos"`
+	Func   string `json:"func"`
+	Reason string `json:"reason"`
+	Stub   string `json:"stub,omitempty"`
+}
+
+// newVetItem returns a vet item for an unsupported instruction reachable
+// from the entry function.
+func newVetItem(entry *ssa.Function, u glee.UnsupportedInstr) *vetItem {
+	fn := u.Instr.Parent()
+
+	pos := u.Instr.Pos()
+	if !pos.IsValid() {
+		pos = fn.Pos()
+	}
+	position := fn.Prog.Fset.Position(pos)
+	position.Filename = filepath.Base(position.Filename)
+
+	item := &vetItem{
+		Pos:    position.String(),
+		Func:   fn.String(),
+		Reason: u.Reason,
+		Stub:   vetStub(entry, fn),
+	}
+	if u.Callee != nil {
+		item.Reason = fmt.Sprintf("%s: %s", u.Reason, u.Callee)
+		item.Stub = "use -havoc or register a handler for " + u.Callee.String()
+	}
+	return item
+}
+
+// vetStub returns a suggestion for stubbing out fn so its unsupported
+// instructions are not executed. The entry function cannot be stubbed.
+func vetStub(entry, fn *ssa.Function) string {
+	if fn == entry {
+		return ""
+	} else if _, ok := fn.Syntax().(*ast.FuncDecl); ok {
+		return "annotate " + fn.Name() + " with //glee:skip"
+	}
+	return "register a handler for " + fn.String()
+}
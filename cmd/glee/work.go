@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+// WorkCommand represents a command for exploring subtrees of a function's
+// state tree leased from a coordinator. See ServeCommand.
+type WorkCommand struct {
+	// Base URL of the coordinator.
+	url string
+
+	// Solver backend & its configuration.
+	solver solverOptions
+
+	// Receives executor log messages. Discards messages if nil.
+	logger glee.Logger
+
+	// Time between progress reports to the coordinator.
+	reportInterval time.Duration
+
+	client *http.Client
+}
+
+// NewWorkCommand returns a new instance of WorkCommand.
+func NewWorkCommand() *WorkCommand {
+	return &WorkCommand{
+		reportInterval: DefaultReportInterval,
+		client:         &http.Client{Timeout: leaseWaitTime + time.Minute},
+	}
+}
+
+// Run executes the "work" subcommand.
+func (cmd *WorkCommand) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("glee-work", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "verbose")
+	cmd.solver.registerFlags(fs)
+	fs.DurationVar(&cmd.reportInterval, "report-interval", DefaultReportInterval, "time between progress reports")
+	fs.Usage = cmd.usage
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() == 0 {
+		return fmt.Errorf("coordinator address required")
+	} else if fs.NArg() > 1 {
+		return fmt.Errorf("too many arguments specified")
+	}
+
+	cmd.url = strings.TrimSuffix(fs.Arg(0), "/")
+	if !strings.Contains(cmd.url, "://") {
+		cmd.url = "http://" + cmd.url
+	}
+
+	if *verbose {
+		cmd.logger = glee.NewLogger(os.Stderr, glee.LogLevelDebug)
+	}
+
+	var config workConfig
+	if err := cmd.do(ctx, "GET", "/config", nil, &config); err != nil {
+		return err
+	}
+	preference, err := glee.ParsePreference(config.Preference)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := buildProgram(buildOptions{}, config.Package)
+	if err != nil {
+		return err
+	}
+	fn := findFunction(pkgs, config.Function)
+	if fn == nil {
+		return fmt.Errorf("function not found: %s", config.Function)
+	}
+
+	solver, closeSolver, err := cmd.solver.newSolver()
+	if err != nil {
+		return err
+	}
+	defer closeSolver()
+
+	e := glee.NewExecutor(fn)
+	e.Solver = glee.NewIndependenceSolver(glee.NewCachingSolver(solver))
+	e.Limits = config.Limits
+	if cmd.logger != nil {
+		e.Logger = cmd.logger
+	}
+
+	// Solved inputs are formatted the same as the "run" command.
+	runner := &RunCommand{preference: preference}
+
+	for {
+		lease, err := cmd.lease(ctx)
+		if err != nil {
+			return err
+		} else if lease == nil {
+			return nil
+		}
+
+		if err := cmd.explore(ctx, e, fn, runner, config.StringLen, lease); err != nil {
+			return err
+		}
+	}
+}
+
+// lease requests a subtree from the coordinator. Retries while no work is
+// available. Returns nil once the coordinator has finished exploration.
+func (cmd *WorkCommand) lease(ctx context.Context) (*workLease, error) {
+	for {
+		var lease workLease
+		if err := cmd.do(ctx, "POST", "/lease", nil, &lease); err == errNoContent {
+			continue
+		} else if err == errGone {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return &lease, nil
+	}
+}
+
+// explore replays the fork path of lease & explores the subtree below it.
+// Terminal states are reported periodically along with any states the
+// coordinator requests to offload. Abandons the lease if it has expired.
+func (cmd *WorkCommand) explore(ctx context.Context, e *glee.Executor, fn *ssa.Function, runner *RunCommand, stringLen int, lease *workLease) error {
+	tracer := newPathTracer()
+	searcher := glee.NewReplaySearcher(lease.Prefix)
+	e.Reset(searcher)
+	e.Tracer = tracer
+
+	arrays, err := e.BindSymbolicParams(stringLen)
+	if err != nil {
+		return err
+	}
+
+	report := &workReport{Lease: lease.ID}
+	reportTime := time.Now()
+	for {
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == glee.ErrNoStateAvailable {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// Only report states within the leased subtree. Ancestors of its root
+		// are replayed & terminate only if exploration is not deterministic.
+		if state.Terminated() && searcher.Contains(state) {
+			path, err := runner.newRunPath(ctx, e, fn, state, arrays, tracer.positions(state))
+			if err != nil {
+				return err
+			}
+			report.Paths = append(report.Paths, &workPath{ForkPath: state.ForkPath(), Path: path})
+		}
+
+		if time.Since(reportTime) < cmd.reportInterval {
+			continue
+		}
+
+		var resp workReportResponse
+		if err := cmd.do(ctx, "POST", "/report", report, &resp); err == errGone {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		report, reportTime = &workReport{Lease: lease.ID}, time.Now()
+
+		// Offloaded states are handed back with the next report.
+		for _, other := range searcher.Offload(resp.Offload) {
+			report.Offloaded = append(report.Offloaded, other.ForkPath())
+		}
+		if len(report.Offloaded) > 0 {
+			reportTime = time.Time{}
+		}
+	}
+
+	report.Coverage = coveredLines(e.Coverage())
+	report.Done = true
+	if err := cmd.do(ctx, "POST", "/report", report, &workReportResponse{}); err != nil && err != errGone {
+		return err
+	}
+	return nil
+}
+
+// Errors returned by do() for responses without a body.
+var (
+	errNoContent = errors.New("no content")
+	errGone      = errors.New("gone")
+)
+
+// do sends a request with in encoded as JSON, if non-nil, to the coordinator
+// & decodes the response body into out.
+func (cmd *WorkCommand) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, cmd.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := cmd.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNoContent:
+		return errNoContent
+	case http.StatusGone:
+		return errGone
+	default:
+		return fmt.Errorf("%s %s: unexpected status: %s", method, path, resp.Status)
+	}
+}
+
+func (cmd *WorkCommand) usage() {
+	fmt.Fprintln(os.Stderr, `
+usage: glee work [arguments] address
+
+Explores subtrees of a function's state tree leased from a coordinator started
+with "glee serve" at the given address. The function is loaded from the same
+package path as the coordinator so the worker must run against the same
+source. Exits once the coordinator finishes exploration.
+
+Arguments:
+
+	-v
+	    Enable verbose logging.
+
+	-report-interval duration
+	    Time between progress reports to the coordinator. Busy workers
+	    offload states to idle workers when reporting.
+
+	-solver name
+	    Solver used for queries. One of "z3", "bitwuzla", or "smtlib".
+	    Defaults to "smtlib" if -smt is set & "z3" otherwise.
+
+	-smt command
+	    Run queries with an external SMT-LIB2 solver instead of the
+	    Z3 library. For example: "z3 -in -smt2".
+
+	-z3-timeout duration
+	    Stop each Z3 query after the given duration.
+
+	-z3-max-memory n
+	    Limit Z3 to n megabytes of memory.
+
+	-z3-seed n
+	    Seed for the randomized heuristics of Z3.
+
+	-z3-logic logic
+	    SMT-LIB2 logic used by Z3, such as "QF_ABV".
+
+	-z3-tactics tactics
+	    Comma-separated Z3 tactics applied in order instead of the
+	    default solver. For example: "simplify,solve-eqs,smt".
+`[1:])
+}
+
+// DefaultReportInterval is the default time between progress reports.
+const DefaultReportInterval = time.Second
@@ -0,0 +1,323 @@
+package glee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Seed represents concrete values for symbolic arrays, keyed by array ID.
+// Bytes missing from the seed are zero.
+type Seed map[uint64][]byte
+
+// Clone returns a copy of the seed.
+func (seed Seed) Clone() Seed {
+	other := make(Seed, len(seed))
+	for id, value := range seed {
+		other[id] = append([]byte(nil), value...)
+	}
+	return other
+}
+
+// key returns a string which uniquely identifies the seed's values.
+func (seed Seed) key() string {
+	ids := make([]uint64, 0, len(seed))
+	for id := range seed {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var buf strings.Builder
+	for _, id := range ids {
+		// Trailing zeros are equivalent to missing bytes.
+		fmt.Fprintf(&buf, "%d=%x;", id, strings.TrimRight(string(seed[id]), "\x00"))
+	}
+	return buf.String()
+}
+
+// Eval returns the value of expr when each array holds its value in the seed.
+func (seed Seed) Eval(expr Expr) (*ConstantExpr, error) {
+	switch expr := expr.(type) {
+	case *ConstantExpr:
+		return expr, nil
+
+	case *NotOptimizedExpr:
+		return seed.Eval(expr.Src)
+
+	case *SelectExpr:
+		index, err := seed.Eval(expr.Index)
+		if err != nil {
+			return nil, err
+		}
+		return seed.selectByte(expr.Array, expr.Array.Updates, index)
+
+	case *ConcatExpr:
+		msb, err := seed.Eval(expr.MSB)
+		if err != nil {
+			return nil, err
+		}
+		lsb, err := seed.Eval(expr.LSB)
+		if err != nil {
+			return nil, err
+		}
+		return msb.Concat(lsb), nil
+
+	case *ExtractExpr:
+		src, err := seed.Eval(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return src.Extract(expr.Offset, expr.Width), nil
+
+	case *NotExpr:
+		src, err := seed.Eval(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return src.Not(), nil
+
+	case *CastExpr:
+		src, err := seed.Eval(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+		return seed.constant(NewCastExpr(src, expr.Width, expr.Signed))
+
+	case *BinaryExpr:
+		lhs, err := seed.Eval(expr.LHS)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := seed.Eval(expr.RHS)
+		if err != nil {
+			return nil, err
+		}
+		return seed.constant(NewBinaryExpr(expr.Op, lhs, rhs))
+
+	case *FPBinaryExpr:
+		lhs, err := seed.Eval(expr.LHS)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := seed.Eval(expr.RHS)
+		if err != nil {
+			return nil, err
+		}
+		return seed.constant(NewFPBinaryExpr(expr.Op, lhs, rhs))
+
+	case *FPCastExpr:
+		src, err := seed.Eval(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+		return seed.constant(NewFPCastExpr(expr.Op, src, expr.Width))
+
+	case *IteExpr:
+		cond, err := seed.Eval(expr.Cond)
+		if err != nil {
+			return nil, err
+		} else if cond.IsTrue() {
+			return seed.Eval(expr.Then)
+		}
+		return seed.Eval(expr.Else)
+
+	default:
+		return nil, fmt.Errorf("glee.Seed: unexpected expression type: %T", expr)
+	}
+}
+
+// selectByte returns the byte at index of array after applying upd & all
+// previous updates.
+func (seed Seed) selectByte(array *Array, upd *ArrayUpdate, index *ConstantExpr) (*ConstantExpr, error) {
+	for ; upd != nil; upd = upd.Next {
+		updIndex, err := seed.Eval(upd.Index)
+		if err != nil {
+			return nil, err
+		} else if updIndex.Value == index.Value {
+			return seed.Eval(upd.Value)
+		}
+	}
+
+	if value := seed[array.ID]; index.Value < uint64(len(value)) {
+		return NewConstantExpr8(uint64(value[index.Value])), nil
+	}
+	return NewConstantExpr8(0), nil
+}
+
+// constant returns expr as a constant. Constant operands always fold so a
+// non-constant expression is an error.
+func (seed Seed) constant(expr Expr) (*ConstantExpr, error) {
+	if expr, ok := expr.(*ConstantExpr); ok {
+		return expr, nil
+	}
+	return nil, fmt.Errorf("glee.Seed: cannot evaluate expression: %s", expr)
+}
+
+// Satisfies returns true if every constraint evaluates to true with the seed.
+func (seed Seed) Satisfies(constraints []Expr) (bool, error) {
+	for _, constraint := range constraints {
+		if value, err := seed.Eval(constraint); err != nil {
+			return false, err
+		} else if !value.IsTrue() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+var _ Searcher = (*ConcolicSearcher)(nil)
+
+// ConcolicSearcher represents a depth-first searcher which only selects
+// states whose constraints are satisfied by a concrete seed. Execution is
+// therefore restricted to the path taken by the seed.
+type ConcolicSearcher struct {
+	seed   Seed
+	states []*ExecutionState
+	err    error
+}
+
+// NewConcolicSearcher returns a new instance of ConcolicSearcher.
+func NewConcolicSearcher(seed Seed) *ConcolicSearcher {
+	return &ConcolicSearcher{seed: seed}
+}
+
+// Err returns the first error that occurred while evaluating constraints.
+// States which cannot be evaluated are not selected.
+func (s *ConcolicSearcher) Err() error { return s.err }
+
+// SelectState returns the most recently added state following the seed.
+func (s *ConcolicSearcher) SelectState() *ExecutionState {
+	if len(s.states) == 0 {
+		return nil
+	}
+	state := s.states[len(s.states)-1]
+	s.states = s.states[:len(s.states)-1]
+	return state
+}
+
+// AddState adds state to the searcher if its constraints are satisfied by
+// the seed. Otherwise the state is ignored.
+func (s *ConcolicSearcher) AddState(state *ExecutionState) {
+	if ok, err := s.seed.Satisfies(state.constraints); err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return
+	} else if !ok {
+		return
+	}
+	s.states = append(s.states, state)
+}
+
+// ConcolicDriver performs concolic execution in the style of DART & SAGE.
+// Each run executes the entry function on the concrete path of a seed while
+// recording the path constraints. Each constraint is then negated in turn to
+// solve for new seeds which follow a different path.
+type ConcolicDriver struct {
+	// Returns a new executor for each run. Executors must be configured
+	// identically, including symbolic parameters, so symbolic arrays are
+	// assigned the same IDs across runs. The executor's searcher is replaced.
+	NewExecutor func() (*Executor, error)
+
+	// Maximum number of runs. Zero is unlimited.
+	MaxRuns int
+}
+
+// ConcolicRun represents a single concrete execution of the entry function.
+type ConcolicRun struct {
+	Seed   Seed
+	States []*ExecutionState // terminal states on the seed's path
+}
+
+// Run executes seed & every seed generated from it. fn is called after each
+// run completes. Returns the number of runs executed.
+func (d *ConcolicDriver) Run(ctx context.Context, seed Seed, fn func(*ConcolicRun) error) (n int, err error) {
+	if d.NewExecutor == nil {
+		return 0, errors.New("glee.ConcolicDriver: executor constructor required")
+	}
+
+	// Each input records the index of the first constraint it may negate
+	// so paths are not regenerated by its children (SAGE generational search).
+	type input struct {
+		seed  Seed
+		bound int
+	}
+	queue := []input{{seed: seed.Clone()}}
+	seen := map[string]struct{}{seed.key(): {}}
+
+	for len(queue) > 0 {
+		if d.MaxRuns > 0 && n >= d.MaxRuns {
+			return n, nil
+		}
+		in := queue[0]
+		queue = queue[1:]
+
+		e, err := d.NewExecutor()
+		if err != nil {
+			return n, err
+		}
+
+		run, err := d.execute(ctx, e, in.seed)
+		if err != nil {
+			return n, err
+		}
+		n++
+
+		if err := fn(run); err != nil {
+			return n, err
+		}
+
+		// Negate each constraint after the bound to generate new inputs.
+		for _, state := range run.States {
+			constraints := state.Constraints()
+			for i := in.bound; i < len(constraints); i++ {
+				query := append(append([]Expr{}, constraints[:i]...), NewNotExpr(constraints[i]))
+				arrays := FindArrays(query...)
+
+				satisfiable, values, err := SolveContext(ctx, e.Solver, query, arrays)
+				if err != nil {
+					return n, err
+				} else if !satisfiable {
+					continue
+				}
+
+				other := in.seed.Clone()
+				for j, array := range arrays {
+					other[array.ID] = values[j]
+				}
+				if _, ok := seen[other.key()]; ok {
+					continue
+				}
+				seen[other.key()] = struct{}{}
+				queue = append(queue, input{seed: other, bound: i + 1})
+			}
+		}
+	}
+	return n, nil
+}
+
+// execute runs e on the path of seed & returns the terminal states.
+func (d *ConcolicDriver) execute(ctx context.Context, e *Executor, seed Seed) (*ConcolicRun, error) {
+	searcher := NewConcolicSearcher(seed)
+	searcher.AddState(e.RootState())
+	e.Searcher = searcher
+
+	run := &ConcolicRun{Seed: seed}
+	for {
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == ErrNoStateAvailable {
+			break
+		} else if err != nil {
+			return nil, err
+		} else if state.Terminated() {
+			run.States = append(run.States, state)
+		}
+	}
+
+	if err := searcher.Err(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
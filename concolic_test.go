@@ -0,0 +1,79 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestSeed_Eval(t *testing.T) {
+	a := glee.NewArray(1, 4)
+	x := a.Select(glee.NewConstantExpr64(0), 32, true)
+
+	t.Run("OK", func(t *testing.T) {
+		seed := glee.Seed{1: {0x0a, 0x00, 0x00, 0x00}}
+		expr := glee.NewBinaryExpr(glee.ADD, x, glee.NewConstantExpr32(5))
+		if value, err := seed.Eval(expr); err != nil {
+			t.Fatal(err)
+		} else if got, exp := value.Value, uint64(15); got != exp {
+			t.Fatalf("Eval()=%d, expected %d", got, exp)
+		}
+	})
+
+	// Missing arrays & bytes are treated as zero.
+	t.Run("Missing", func(t *testing.T) {
+		seed := glee.Seed{1: {0x01}}
+		if value, err := seed.Eval(x); err != nil {
+			t.Fatal(err)
+		} else if got, exp := value.Value, uint64(1); got != exp {
+			t.Fatalf("Eval()=%d, expected %d", got, exp)
+		}
+
+		if value, err := (glee.Seed{}).Eval(x); err != nil {
+			t.Fatal(err)
+		} else if got, exp := value.Value, uint64(0); got != exp {
+			t.Fatalf("Eval()=%d, expected %d", got, exp)
+		}
+	})
+
+	// Symbolic updates are resolved using the seed.
+	t.Run("Updates", func(t *testing.T) {
+		b := glee.NewArray(2, 2)
+		i := glee.NewCastExpr(a.Select(glee.NewConstantExpr64(0), 8, true), 64, false)
+		b = b.Store(i, glee.NewConstantExpr8(7), true)
+		expr := b.Select(glee.NewConstantExpr64(1), 8, true)
+
+		if value, err := (glee.Seed{1: {1}, 2: {3, 4}}).Eval(expr); err != nil {
+			t.Fatal(err)
+		} else if got, exp := value.Value, uint64(7); got != exp {
+			t.Fatalf("Eval()=%d, expected %d", got, exp)
+		}
+
+		if value, err := (glee.Seed{1: {0}, 2: {3, 4}}).Eval(expr); err != nil {
+			t.Fatal(err)
+		} else if got, exp := value.Value, uint64(4); got != exp {
+			t.Fatalf("Eval()=%d, expected %d", got, exp)
+		}
+	})
+}
+
+func TestSeed_Satisfies(t *testing.T) {
+	a := glee.NewArray(1, 1)
+	x := a.Select(glee.NewConstantExpr64(0), 8, true)
+	constraints := []glee.Expr{
+		glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr8(10)),
+		glee.NewNotExpr(glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr8(5))),
+	}
+
+	if ok, err := (glee.Seed{1: {4}}).Satisfies(constraints); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected satisfied")
+	}
+
+	if ok, err := (glee.Seed{1: {5}}).Satisfies(constraints); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected unsatisfied")
+	}
+}
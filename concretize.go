@@ -0,0 +1,173 @@
+package glee
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// limitExprs concretizes the expression bound by instr if it exceeds
+// Limits.MaxExprDepth or Limits.MaxExprSize. If instr forked state then the
+// binding of each child is checked instead.
+func (e *Executor) limitExprs(state *ExecutionState, instr ssa.Instruction) error {
+	if e.MaxExprDepth <= 0 && e.MaxExprSize <= 0 {
+		return nil
+	}
+	value, ok := instr.(ssa.Value)
+	if !ok {
+		return nil
+	}
+
+	states := []*ExecutionState{state}
+	if state.Forked() {
+		states = state.Children()
+	}
+
+	for _, state := range states {
+		frame := state.Frame()
+		if state.Terminated() || frame == nil {
+			continue
+		}
+
+		// Calls bind their result once the callee returns so the binding
+		// may not exist yet.
+		expr, ok := frame.Binding(value).(Expr)
+		if !ok || !exprExceeds(expr, e.MaxExprDepth, e.MaxExprSize) {
+			continue
+		}
+
+		constant, err := e.concretize(state, expr)
+		if err != nil {
+			return err
+		}
+		frame.bind(value, constant)
+	}
+	return nil
+}
+
+// concretize returns a value of expr which is feasible on state & constrains
+// expr to that value. The expression is wrapped in a NotOptimizedExpr within
+// the constraint so it is passed to the solver as-is rather than rewritten.
+func (e *Executor) concretize(state *ExecutionState, expr Expr) (*ConstantExpr, error) {
+	arrays := FindArrays(append(state.constraints[:len(state.constraints):len(state.constraints)], expr)...)
+	satisfiable, values, err := e.solve(state.constraints, arrays)
+	if err != nil {
+		return nil, err
+	} else if !satisfiable {
+		return nil, fmt.Errorf("glee.Executor: cannot concretize expression on unsatisfiable path")
+	}
+
+	value, err := NewExprEvaluator(arrays, values).Evaluate(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	e.infof("[concretize] expression limit exceeded: %s", value)
+	state.AddConstraint(NewBinaryExpr(EQ, NewNotOptimizedExpr(expr), value))
+	return value, nil
+}
+
+// exprExceeds returns true if the depth of expr is greater than maxDepth or
+// if expr has more than maxSize distinct subexpressions. Array updates read
+// by selects are included. A limit of zero is unlimited.
+func exprExceeds(expr Expr, maxDepth, maxSize int) bool {
+	m := exprMeasure{maxDepth: maxDepth, maxSize: maxSize, depths: make(map[interface{}]int)}
+	_, ok := m.expr(expr)
+	return !ok
+}
+
+// exprMeasure computes the depth of expression DAGs. Shared subexpressions
+// are measured once. Measurement stops as soon as a limit is exceeded.
+type exprMeasure struct {
+	maxDepth, maxSize int
+	depths            map[interface{}]int // by Expr or *ArrayUpdate
+}
+
+// expr returns the depth of expr. Returns false if a limit is exceeded.
+func (m *exprMeasure) expr(expr Expr) (int, bool) {
+	if depth, ok := m.depths[expr]; ok {
+		return depth, true
+	}
+
+	var children []Expr
+	switch expr := expr.(type) {
+	case *BinaryExpr:
+		children = []Expr{expr.LHS, expr.RHS}
+	case *CastExpr:
+		children = []Expr{expr.Src}
+	case *ConcatExpr:
+		children = []Expr{expr.MSB, expr.LSB}
+	case *ConstantExpr:
+		// nop
+	case *ExtractExpr:
+		children = []Expr{expr.Expr}
+	case *FPBinaryExpr:
+		children = []Expr{expr.LHS, expr.RHS}
+	case *FPCastExpr:
+		children = []Expr{expr.Src}
+	case *IteExpr:
+		children = []Expr{expr.Cond, expr.Then, expr.Else}
+	case *NotExpr:
+		children = []Expr{expr.Expr}
+	case *NotOptimizedExpr:
+		children = []Expr{expr.Src}
+	case *SelectExpr:
+		children = []Expr{expr.Index}
+	default:
+		panic("unreachable")
+	}
+
+	var depth int
+	for _, child := range children {
+		d, ok := m.expr(child)
+		if !ok {
+			return 0, false
+		} else if d > depth {
+			depth = d
+		}
+	}
+	if expr, ok := expr.(*SelectExpr); ok && expr.Array.Updates != nil {
+		d, ok := m.update(expr.Array.Updates)
+		if !ok {
+			return 0, false
+		} else if d > depth {
+			depth = d
+		}
+	}
+	return m.set(expr, depth+1)
+}
+
+// update returns the depth of an array update & all previous updates.
+func (m *exprMeasure) update(upd *ArrayUpdate) (int, bool) {
+	if depth, ok := m.depths[upd]; ok {
+		return depth, true
+	}
+
+	var depth int
+	for _, child := range []Expr{upd.Index, upd.Value} {
+		d, ok := m.expr(child)
+		if !ok {
+			return 0, false
+		} else if d > depth {
+			depth = d
+		}
+	}
+	if upd.Next != nil {
+		d, ok := m.update(upd.Next)
+		if !ok {
+			return 0, false
+		} else if d > depth {
+			depth = d
+		}
+	}
+	return m.set(upd, depth+1)
+}
+
+// set records the depth of a measured node. Returns false if a limit is exceeded.
+func (m *exprMeasure) set(key interface{}, depth int) (int, bool) {
+	m.depths[key] = depth
+	if (m.maxDepth > 0 && depth > m.maxDepth) || (m.maxSize > 0 && len(m.depths) > m.maxSize) {
+		return 0, false
+	}
+	return depth, true
+}
@@ -0,0 +1,199 @@
+package glee
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Coverage represents the source lines & conditional branches executed
+// across all states explored by an executor.
+type Coverage struct {
+	Files    []*FileCoverage   // sorted by filename
+	Branches []*BranchCoverage // sorted by position
+}
+
+// FileCoverage represents the executable lines of a source file. Only lines
+// within functions entered during execution are included.
+type FileCoverage struct {
+	Filename string
+	PkgPath  string       // import path of the file's package
+	Lines    map[int]bool // executable line to whether it was executed
+}
+
+// BranchCoverage represents the directions taken by a conditional branch.
+type BranchCoverage struct {
+	Pos   token.Position
+	True  bool
+	False bool
+}
+
+// LineN returns the number of executed lines & executable lines.
+func (c *Coverage) LineN() (covered, total int) {
+	for _, fc := range c.Files {
+		for _, ok := range fc.Lines {
+			if ok {
+				covered++
+			}
+			total++
+		}
+	}
+	return covered, total
+}
+
+// BranchN returns the number of branch directions taken & the total number
+// of directions for all branches reached.
+func (c *Coverage) BranchN() (covered, total int) {
+	for _, bc := range c.Branches {
+		if bc.True {
+			covered++
+		}
+		if bc.False {
+			covered++
+		}
+		total += 2
+	}
+	return covered, total
+}
+
+// WriteProfile writes the line coverage as a profile consumable by
+// "go tool cover". Each line is written as a separate block.
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "mode: set")
+	for _, fc := range c.Files {
+		name := fc.Filename
+		if fc.PkgPath != "" {
+			name = path.Join(fc.PkgPath, filepath.Base(fc.Filename))
+		}
+
+		lines := make([]int, 0, len(fc.Lines))
+		for line := range fc.Lines {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		for _, line := range lines {
+			var count int
+			if fc.Lines[line] {
+				count = 1
+			}
+			fmt.Fprintf(bw, "%s:%d.1,%d.1 1 %d\n", name, line, line+1, count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Coverage returns the lines & branches covered by all states explored so far.
+func (e *Executor) Coverage() *Coverage {
+	// Merge lines of released states with lines of retained states.
+	covered := make(map[string]map[uint]struct{})
+	mergeCoverage(covered, e.covered)
+	for state := range e.states {
+		mergeCoverage(covered, state.covered)
+	}
+
+	// Determine the executable lines of each function entered.
+	files := make(map[string]*FileCoverage)
+	for fn := range e.coverFuncs {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				pos, ok := e.coverPosition(instr)
+				if !ok {
+					continue
+				}
+
+				fc := files[pos.Filename]
+				if fc == nil {
+					fc = &FileCoverage{Filename: pos.Filename, Lines: make(map[int]bool)}
+					if fn.Pkg != nil {
+						fc.PkgPath = fn.Pkg.Pkg.Path()
+					}
+					files[pos.Filename] = fc
+				}
+				_, fc.Lines[pos.Line] = covered[pos.Filename][uint(pos.Line)]
+			}
+		}
+	}
+
+	c := &Coverage{}
+	for _, fc := range files {
+		c.Files = append(c.Files, fc)
+	}
+	sort.Slice(c.Files, func(i, j int) bool { return c.Files[i].Filename < c.Files[j].Filename })
+
+	for _, bc := range e.branches {
+		other := *bc
+		c.Branches = append(c.Branches, &other)
+	}
+	sort.Slice(c.Branches, func(i, j int) bool {
+		x, y := c.Branches[i].Pos, c.Branches[j].Pos
+		if x.Filename != y.Filename {
+			return x.Filename < y.Filename
+		} else if x.Line != y.Line {
+			return x.Line < y.Line
+		}
+		return x.Column < y.Column
+	})
+
+	return c
+}
+
+// recordCoverage marks the line of instr as executed by state.
+func (e *Executor) recordCoverage(state *ExecutionState, instr ssa.Instruction) {
+	if pos, ok := e.coverPosition(instr); ok {
+		e.coverFuncs[instr.Parent()] = struct{}{}
+		state.cover(pos)
+	}
+}
+
+// releaseCoverage retains the lines executed by state before it is removed.
+func (e *Executor) releaseCoverage(state *ExecutionState) {
+	mergeCoverage(e.covered, state.covered)
+}
+
+// branchCoverage returns the coverage for a conditional branch instruction.
+func (e *Executor) branchCoverage(instr *ssa.If) *BranchCoverage {
+	bc := e.branches[instr]
+	if bc == nil {
+		bc = &BranchCoverage{Pos: e.prog.Fset.Position(instrPos(instr))}
+		e.branches[instr] = bc
+	}
+	return bc
+}
+
+// coverPosition returns the position of instr used for line coverage.
+// Debug references & instructions without a position are not counted.
+func (e *Executor) coverPosition(instr ssa.Instruction) (token.Position, bool) {
+	if _, ok := instr.(*ssa.DebugRef); ok {
+		return token.Position{}, false
+	} else if fn := instr.Parent(); fn == nil || fn.Synthetic != "" {
+		return token.Position{}, false
+	}
+
+	pos := instrPos(instr)
+	if !pos.IsValid() {
+		return token.Position{}, false
+	}
+	return e.prog.Fset.Position(pos), true
+}
+
+// mergeCoverage adds the lines of src to dst.
+func mergeCoverage(dst, src map[string]map[uint]struct{}) {
+	for filename, lines := range src {
+		m := dst[filename]
+		if m == nil {
+			m = make(map[uint]struct{}, len(lines))
+			dst[filename] = m
+		}
+		for line := range lines {
+			m[line] = struct{}{}
+		}
+	}
+}
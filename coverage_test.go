@@ -0,0 +1,41 @@
+package glee_test
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestCoverage_WriteProfile(t *testing.T) {
+	c := &glee.Coverage{
+		Files: []*glee.FileCoverage{
+			{Filename: "/src/a/a.go", PkgPath: "example.com/a", Lines: map[int]bool{5: true, 3: true, 4: false}},
+			{Filename: "/tmp/b.go", Lines: map[int]bool{10: true}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteProfile(&buf); err != nil {
+		t.Fatal(err)
+	} else if got, exp := buf.String(), "mode: set\n"+
+		"example.com/a/a.go:3.1,4.1 1 1\n"+
+		"example.com/a/a.go:4.1,5.1 1 0\n"+
+		"example.com/a/a.go:5.1,6.1 1 1\n"+
+		"/tmp/b.go:10.1,11.1 1 1\n"; got != exp {
+		t.Fatalf("unexpected profile:\n%s", got)
+	}
+}
+
+func TestCoverage_BranchN(t *testing.T) {
+	c := &glee.Coverage{
+		Branches: []*glee.BranchCoverage{
+			{Pos: token.Position{Line: 1}, True: true, False: true},
+			{Pos: token.Position{Line: 2}, False: true},
+		},
+	}
+	if covered, total := c.BranchN(); covered != 3 || total != 4 {
+		t.Fatalf("BranchN()=%d/%d, expected 3/4", covered, total)
+	}
+}
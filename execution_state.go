@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"go/constant"
 	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
-	"unsafe"
+	"time"
 
 	"github.com/benbjohnson/immutable"
 	"golang.org/x/tools/go/ssa"
@@ -25,21 +28,86 @@ type ExecutionState struct {
 	// Execution hierarchy.
 	parent   *ExecutionState
 	children []*ExecutionState
+	explored bool // true once selected for execution
+	liveN    int  // unexplored states in subtree, see RandomPathSearcher
 
-	// Call stack
+	// Index of each fork on the path from the root state & the number of
+	// states forked from this state. See ForkPath().
+	forkPath []int
+	forkN    int
+
+	// Call stack of the current goroutine.
 	stack []*StackFrame
 
+	// Suspended goroutines & the ID of the current goroutine.
+	goroutines   []*goroutine
+	gid          int
+	waits        []chanWait // blocked operations of the current goroutine
+	goroutineSeq int        // last assigned goroutine ID
+	preemptN     int        // number of goroutine switches on this path
+
+	// Number of constrained forks & instructions executed on this path.
+	depth  int
+	instrN int
+
+	// Solver time & bytes allocated on this path. A deprioritized path has
+	// exceeded one of its budgets & is no longer checked against them.
+	solverTime    time.Duration
+	allocN        int
+	deprioritized bool
+
 	// Shows whether state is running, finished, or terminated by error state.
 	status ExecutionStatus
 	reason string
 
+	// Failed assertion & its counterexample if status is assert failed.
+	assertion *AssertionFailure
+
+	// Invalid access & its counterexample if status is memory error.
+	memoryError *MemoryError
+
+	// Value passed to panic() while unwinding. Cleared once recovered.
+	panicValue Binding
+
+	// Reason the test failed, set by t.Error() & similar methods. The state
+	// is reported as failed, instead of finished, once it completes.
+	testFailure string
+
+	// Values returned by the entry function. Only recorded while computing
+	// a function summary.
+	results Tuple
+
 	// Heap memory address space.
 	heap *immutable.SortedMap
 
+	// Map contents by the address allocated for each map.
+	maps *immutable.SortedMap
+
+	// Closures by the address allocated for each closure.
+	closures *immutable.SortedMap
+
+	// Channel contents by the address allocated for each channel.
+	chans *immutable.SortedMap
+
+	// Names assigned to symbolic arrays by glee.Name(), by array ID.
+	names *immutable.SortedMap
+
+	// Source-level origins of symbolic arrays, such as a parameter name or
+	// the call site of a glee intrinsic, by array ID. See ArrayOrigin().
+	origins *immutable.SortedMap
+
+	// Addresses of package-level variables, allocated on first use.
+	globals map[*ssa.Global]*ConstantExpr
+
+	// Symbolic environment variables & defined flags, by name.
+	env   *immutable.SortedMap
+	flags *immutable.SortedMap
+
 	// Constraints collected so far during execution.
 	constraints []Expr
 
-	// Line coverage
+	// Lines executed by this state, by filename. Lines executed before the
+	// state was forked are held by its ancestors.
 	covered map[string]map[uint]struct{}
 }
 
@@ -48,6 +116,15 @@ func NewExecutionState(executor *Executor, fn *ssa.Function) *ExecutionState {
 		executor: executor,
 		status:   ExecutionStatusRunning,
 		heap:     immutable.NewSortedMap(&uint64Comparer{}),
+		maps:     immutable.NewSortedMap(&uint64Comparer{}),
+		closures: immutable.NewSortedMap(&uint64Comparer{}),
+		chans:    immutable.NewSortedMap(&uint64Comparer{}),
+		names:    immutable.NewSortedMap(&uint64Comparer{}),
+		origins:  immutable.NewSortedMap(&uint64Comparer{}),
+		globals:  make(map[*ssa.Global]*ConstantExpr),
+		env:      immutable.NewSortedMap(nil),
+		flags:    immutable.NewSortedMap(nil),
+		covered:  make(map[string]map[uint]struct{}),
 	}
 	s.Push(fn)
 	return s
@@ -68,9 +145,11 @@ func (s *ExecutionState) Constraints() []Expr {
 // Clone returns a copy of the state and including deep copies of the stack
 // and constraints. However, this does not clone child states.
 func (s *ExecutionState) Clone() *ExecutionState {
-	stack := make([]*StackFrame, len(s.stack))
-	for i := range s.stack {
-		stack[i] = s.stack[i].Clone()
+	stack := cloneStack(s.stack)
+
+	goroutines := make([]*goroutine, len(s.goroutines))
+	for i := range s.goroutines {
+		goroutines[i] = s.goroutines[i].clone()
 	}
 
 	constraints := make([]Expr, len(s.constraints))
@@ -78,17 +157,60 @@ func (s *ExecutionState) Clone() *ExecutionState {
 		constraints[i] = s.constraints[i]
 	}
 
+	globals := make(map[*ssa.Global]*ConstantExpr, len(s.globals))
+	for g, addr := range s.globals {
+		globals[g] = addr
+	}
+
 	return &ExecutionState{
-		executor:    s.executor,
-		parent:      s.parent,
-		status:      s.status,
-		heap:        s.heap,
-		stack:       stack,
-		constraints: constraints,
-		covered:     make(map[string]map[uint]struct{}),
+		executor:      s.executor,
+		parent:        s.parent,
+		status:        s.status,
+		reason:        s.reason,
+		panicValue:    s.panicValue,
+		testFailure:   s.testFailure,
+		results:       s.results,
+		heap:          s.heap,
+		maps:          s.maps,
+		closures:      s.closures,
+		chans:         s.chans,
+		names:         s.names,
+		origins:       s.origins,
+		globals:       globals,
+		env:           s.env,
+		flags:         s.flags,
+		stack:         stack,
+		goroutines:    goroutines,
+		gid:           s.gid,
+		waits:         s.waits,
+		goroutineSeq:  s.goroutineSeq,
+		preemptN:      s.preemptN,
+		depth:         s.depth,
+		instrN:        s.instrN,
+		solverTime:    s.solverTime,
+		allocN:        s.allocN,
+		deprioritized: s.deprioritized,
+		constraints:   constraints,
+		covered:       make(map[string]map[uint]struct{}),
 	}
 }
 
+// Snapshot represents a saved copy of an execution state. A snapshot is not
+// affected by further execution of the state it was taken from.
+type Snapshot struct {
+	id    int
+	state *ExecutionState
+}
+
+// ID returns the ID of the state the snapshot was taken from.
+func (snap *Snapshot) ID() int { return snap.id }
+
+// Snapshot returns a copy of the state which can be restored later using
+// Executor.Restore(). Frames & memory are copy-on-write so this is cheap.
+func (s *ExecutionState) Snapshot() *Snapshot {
+	return &Snapshot{id: s.id, state: s.Clone()}
+}
+
 // Status returns the current status of the state.
 // See Reason() for additional information if status is in an error state.
 func (s *ExecutionState) Status() ExecutionStatus {
@@ -100,6 +222,18 @@ func (s *ExecutionState) Reason() string {
 	return s.reason
 }
 
+// AssertionFailure returns the failed assertion that terminated the state.
+// Returns nil if the status is not ExecutionStatusAssertFailed.
+func (s *ExecutionState) AssertionFailure() *AssertionFailure {
+	return s.assertion
+}
+
+// MemoryError returns the out-of-bounds access that terminated the state.
+// Returns nil if the status is not ExecutionStatusMemoryError.
+func (s *ExecutionState) MemoryError() *MemoryError {
+	return s.memoryError
+}
+
 // Terminated returns true if the state completes execution of a path.
 func (s *ExecutionState) Terminated() bool {
 	return s.status != ExecutionStatusRunning
@@ -111,12 +245,50 @@ func (s *ExecutionState) Position() token.Position {
 	if instr == nil {
 		return token.Position{}
 	}
-	switch instr := instr.(type) {
-	case *ssa.If:
-		return s.executor.prog.Fset.Position(instr.Cond.Pos())
-	default:
-		return s.executor.prog.Fset.Position(instr.Pos())
+	return s.executor.prog.Fset.Position(instrPos(instr))
+}
+
+// SourcePosition returns the position of the current instruction. Unlike
+// Position(), synthesized instructions without a position (such as phi nodes
+// & implicit jumps) are mapped to the nearest preceding instruction in the
+// block. If none exist then the last instruction of the previously executed
+// block is used and, finally, the position of the function itself.
+func (s *ExecutionState) SourcePosition() token.Position {
+	frame := s.Frame()
+	if frame == nil || frame.block == nil {
+		return token.Position{}
+	}
+	fset := s.executor.prog.Fset
+
+	// Search backwards from the current instruction in the current block.
+	pc := frame.pc
+	if pc >= len(frame.block.Instrs) {
+		pc = len(frame.block.Instrs) - 1
+	}
+	for i := pc; i >= 0; i-- {
+		if pos := instrPos(frame.block.Instrs[i]); pos.IsValid() {
+			return fset.Position(pos)
+		}
+	}
+
+	// Fallback to the block we jumped from, if any.
+	if frame.prev != nil {
+		for i := len(frame.prev.Instrs) - 1; i >= 0; i-- {
+			if pos := instrPos(frame.prev.Instrs[i]); pos.IsValid() {
+				return fset.Position(pos)
+			}
+		}
 	}
+	return fset.Position(frame.fn.Pos())
+}
+
+// instrPos returns the source position of instr. The position of an "if"
+// instruction is taken from its condition.
+func instrPos(instr ssa.Instruction) token.Pos {
+	if instr, ok := instr.(*ssa.If); ok {
+		return instr.Cond.Pos()
+	}
+	return instr.Pos()
 }
 
 // Frame returns the current stack frame.
@@ -127,6 +299,13 @@ func (s *ExecutionState) Frame() *StackFrame {
 	return s.stack[len(s.stack)-1]
 }
 
+// Stack returns a copy of the call stack. The current frame is last.
+func (s *ExecutionState) Stack() []*StackFrame {
+	a := make([]*StackFrame, len(s.stack))
+	copy(a, s.stack)
+	return a
+}
+
 // CallerFrame returns the parent of the current stack frame.
 func (s *ExecutionState) CallerFrame() *StackFrame {
 	if len(s.stack) <= 1 {
@@ -147,20 +326,36 @@ func (s *ExecutionState) Instr() ssa.Instruction {
 func (s *ExecutionState) Eval(value ssa.Value) Binding {
 	switch value := value.(type) {
 	case *ssa.Const:
-		if value.Value == nil {
+		// Nil pointers, maps, channels & functions are a zero address. Other
+		// nil values, such as slices & interfaces, are zeroed arrays.
+		if value.Value == nil && isScalarType(value.Type()) {
+			return NewConstantExpr(0, s.executor.PointerWidth())
+		} else if value.Value == nil {
 			size := s.executor.Sizeof(deref(value.Type())) / 8
 			_, array := s.Alloc(size)
 			array.zero()
 			return array
 		}
 
+		// Float typed constants may be stored as an exact int or rational.
+		if basic, ok := value.Type().Underlying().(*types.Basic); ok && basic.Info()&types.IsFloat != 0 {
+			f, _ := constant.Float64Val(constant.ToFloat(value.Value))
+			return NewFPConstantExpr(f, s.executor.Sizeof(basic))
+		}
+
 		switch value.Value.Kind() {
 		case constant.Bool:
 			return NewBoolConstantExpr(constant.BoolVal(value.Value))
 		case constant.Int:
+			// Negative constants are stored in two's complement at the width
+			// of their type.
+			width := s.executor.Sizeof(value.Type().Underlying())
+			if v64, isExact := constant.Int64Val(value.Value); isExact {
+				return NewConstantExpr(uint64(v64), width)
+			}
 			v64, isExact := constant.Uint64Val(value.Value)
 			assert(isExact, "inexact constant int")
-			return NewConstantExpr(v64, s.executor.Sizeof(value.Type().Underlying()))
+			return NewConstantExpr(v64, width)
 		case constant.String:
 			str := constant.StringVal(value.Value)
 			array := NewArray(0, uint(len(str)))
@@ -176,7 +371,9 @@ func (s *ExecutionState) Eval(value ssa.Value) Binding {
 			panic(fmt.Sprintf("unexpected const: %T", value.Value))
 		}
 	case *ssa.Function:
-		return NewConstantExpr(uint64(uintptr(unsafe.Pointer(value))), s.executor.PointerWidth())
+		return NewConstantExpr(s.executor.funcAddr(value), s.executor.PointerWidth())
+	case *ssa.Global:
+		return s.globalAddr(value)
 	default:
 		if f := s.Frame(); f != nil {
 			return f.bindings[value]
@@ -185,6 +382,19 @@ func (s *ExecutionState) Eval(value ssa.Value) Binding {
 	}
 }
 
+// globalAddr returns the address of a package-level variable. Variables are
+// allocated & zeroed on first use as package initializers are not executed.
+func (s *ExecutionState) globalAddr(g *ssa.Global) *ConstantExpr {
+	if addr := s.globals[g]; addr != nil {
+		return addr
+	}
+
+	addr, array := s.Alloc(s.executor.Sizeof(deref(g.Type())) / 8)
+	array.zero()
+	s.globals[g] = addr
+	return addr
+}
+
 // MustEvalAsExpr is the same as Eval() except that it returns an Expr type.
 // Panic if binding is Array or Tuple.
 func (s *ExecutionState) MustEvalAsExpr(value ssa.Value) Expr {
@@ -221,16 +431,19 @@ func (s *ExecutionState) ExtractCall(instr ssa.CallInstruction) (fn *ssa.Functio
 			if typ == nil {
 				panic(fmt.Sprintf("glee.Executor: type not found: id=%d", typeID))
 			}
-			data := s.selectIntAt(iface, 1)
 
 			fn = s.executor.prog.LookupMethod(typ, common.Method.Pkg(), common.Method.Name())
-			args = append(args, data) // add receiver
+			args = append(args, s.ifaceValue(iface, typ)) // add receiver
 		} else {
 			addr, ok := s.EvalAsConstantExpr(common.Value)
 			if !ok {
 				panic(fmt.Sprintf("glee.ExecutionState: expected constant function address"))
 			}
-			fn = (*ssa.Function)(unsafe.Pointer(uintptr(addr.Value)))
+			if c := s.findClosure(addr.Value); c != nil {
+				fn = c.fn
+			} else if fn = s.executor.funcsByAddr[addr.Value]; fn == nil {
+				panic(fmt.Sprintf("glee.ExecutionState: function not found: addr=%d", addr.Value))
+			}
 		}
 	}
 
@@ -241,9 +454,44 @@ func (s *ExecutionState) ExtractCall(instr ssa.CallInstruction) (fn *ssa.Functio
 	return fn, args
 }
 
+// ifaceValue returns the concrete value held by iface with the dynamic type typ.
+func (s *ExecutionState) ifaceValue(iface *Array, typ types.Type) Binding {
+	if isBoxedType(typ) {
+		addr, ok := s.selectIntAt(iface, 1).(*ConstantExpr)
+		if !ok {
+			panic(fmt.Sprintf("glee.ExecutionState: expected constant boxed value address"))
+		}
+		return s.unbox(addr)
+	}
+
+	e := s.executor
+	return iface.Select(NewConstantExpr32(uint64(e.PointerWidth()/8)), e.Sizeof(typ), e.IsLittleEndian())
+}
+
+// box copies value to the heap & returns its address. Zero-sized values are
+// not allocated and use a nil address.
+func (s *ExecutionState) box(value *Array) *ConstantExpr {
+	if value.Size == 0 {
+		return NewConstantExpr(0, s.executor.PointerWidth())
+	}
+
+	addr, array := s.Alloc(value.Size)
+	array.Updates = newArrayUpdates(value.bytes())
+	return addr
+}
+
+// unbox returns the value stored at addr by box().
+func (s *ExecutionState) unbox(addr *ConstantExpr) *Array {
+	if addr.Value == 0 {
+		return NewArray(0, 0)
+	}
+	return s.findAllocByAddr(addr)
+}
+
 // Push adds a frame to the top of the stack.
 func (s *ExecutionState) Push(fn *ssa.Function) {
 	f := NewStackFrame(s.Frame(), fn)
+	f.exprs = s.executor.exprs
 
 	f.locals = make([]*Array, len(fn.Locals))
 	for i, instr := range fn.Locals {
@@ -263,14 +511,63 @@ func (s *ExecutionState) Pop() {
 	f := s.Frame()
 	for _, array := range f.locals {
 		s.heap = s.heap.Delete(array.ID)
+		s.allocN -= int(array.Size)
 	}
 	s.stack[len(s.stack)-1] = nil
 	s.stack = s.stack[:len(s.stack)-1]
 
-	// Mark as finished if no more frames exist.
-	if len(s.stack) == 0 {
-		s.status = ExecutionStatusFinished
+	// Mark as finished if no more frames exist on the main goroutine.
+	// Remaining goroutines are discarded when the program exits.
+	if len(s.stack) == 0 && s.gid == mainGoroutineID {
+		s.finish()
+	}
+}
+
+// finish marks the state as finished. The state fails instead if the test
+// being executed reported a failure.
+func (s *ExecutionState) finish() {
+	s.status = ExecutionStatusFinished
+	if s.testFailure != "" {
+		s.status, s.reason = ExecutionStatusFailed, s.testFailure
+	}
+}
+
+// runDefers pushes a frame for each deferred call of the current frame so the
+// calls execute in last-in-first-out order. The deferred calls are removed
+// from the frame. Calls run while panicking are allowed to recover.
+func (s *ExecutionState) runDefers(panicking bool) {
+	frame := s.Frame()
+	defers := frame.defers
+	frame.defers = nil
+
+	for _, call := range defers {
+		s.Push(call.fn)
+		s.Frame().recoverable = panicking
+		for i, arg := range call.args {
+			s.Frame().bind(call.fn.Params[i], arg)
+		}
+		s.bindFreeVars(call.freeVars)
+	}
+}
+
+// hasDefers returns true if any frame on the stack has pending deferred calls.
+func (s *ExecutionState) hasDefers() bool {
+	for _, frame := range s.stack {
+		if len(frame.defers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// cover marks the line at pos as executed by the state.
+func (s *ExecutionState) cover(pos token.Position) {
+	lines := s.covered[pos.Filename]
+	if lines == nil {
+		lines = make(map[uint]struct{})
+		s.covered[pos.Filename] = lines
 	}
+	lines[uint(pos.Line)] = struct{}{}
 }
 
 // Fork returns a child copy of the given state with the additional constraint.
@@ -280,11 +577,59 @@ func (s *ExecutionState) Fork(constraint Expr) *ExecutionState {
 	child.covered = make(map[string]map[uint]struct{})
 	if constraint != nil {
 		child.AddConstraint(constraint)
+		child.depth++
 	}
 	s.children = append(s.children, child)
+
+	// The path is copied so siblings do not share a backing array.
+	child.forkPath = append(s.forkPath[:len(s.forkPath):len(s.forkPath)], s.forkN)
+	s.forkN++
+
 	return child
 }
 
+// ForkPath returns the index of each fork on the path from the root state to
+// this state, in the order forks were made from each ancestor. The root state
+// has an empty path. Paths identify states across executors configured
+// identically so long as exploration is deterministic. See ReplaySearcher.
+//
+// The returned slice must not be modified.
+func (s *ExecutionState) ForkPath() []int { return s.forkPath }
+
+// Parent returns the state this state was forked from.
+func (s *ExecutionState) Parent() *ExecutionState { return s.parent }
+
+// Children returns the states forked from this state that are still retained.
+func (s *ExecutionState) Children() []*ExecutionState { return s.children }
+
+// addLiveN adds delta to the number of unexplored states in the subtree of
+// the state & of each of its ancestors.
+func (s *ExecutionState) addLiveN(delta int) {
+	for ; s != nil; s = s.parent {
+		s.liveN += delta
+	}
+}
+
+// removeChild removes child from the state's list of children.
+func (s *ExecutionState) removeChild(child *ExecutionState) {
+	for i := range s.children {
+		if s.children[i] == child {
+			s.children = append(s.children[:i], s.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// replaceChild replaces child with other in the state's list of children.
+func (s *ExecutionState) replaceChild(child, other *ExecutionState) {
+	for i := range s.children {
+		if s.children[i] == child {
+			s.children[i] = other
+			return
+		}
+	}
+}
+
 // Done returns true if state encounters a finishing instruction (return, if, etc).
 func (s *ExecutionState) Done() bool {
 	if s.Terminated() || s.Forked() {
@@ -303,6 +648,24 @@ func (s *ExecutionState) Done() bool {
 	}
 }
 
+// Depth returns the number of forks on the path to the state which added a
+// constraint, such as branches. Calls & goroutine switches are not counted.
+func (s *ExecutionState) Depth() int { return s.depth }
+
+// InstructionN returns the number of instructions executed on the path to the state.
+func (s *ExecutionState) InstructionN() int { return s.instrN }
+
+// SolverTime returns the time spent in the solver on the path to the state.
+func (s *ExecutionState) SolverTime() time.Duration { return s.solverTime }
+
+// MemoryN returns an estimate of the memory used by the state, in bytes. This
+// is the size of its live heap allocations & excludes expressions.
+func (s *ExecutionState) MemoryN() int { return s.allocN }
+
+// Deprioritized returns true if the path exceeded its solver time or memory
+// budget & was deprioritized. See BudgetPolicyDeprioritize.
+func (s *ExecutionState) Deprioritized() bool { return s.deprioritized }
+
 // Forked returns true if state has a child state.
 func (s *ExecutionState) Forked() bool {
 	return len(s.children) > 0
@@ -312,7 +675,7 @@ func (s *ExecutionState) Forked() bool {
 func (s *ExecutionState) Values() ([]*Array, [][]byte, error) {
 	arrays := FindArrays(s.constraints...)
 
-	satisfiable, values, err := s.executor.Solver.Solve(s.constraints, arrays)
+	satisfiable, values, err := s.executor.solve(s.constraints, arrays)
 	if err != nil {
 		return nil, nil, err
 	} else if !satisfiable {
@@ -321,6 +684,133 @@ func (s *ExecutionState) Values() ([]*Array, [][]byte, error) {
 	return arrays, values, nil
 }
 
+// NamedValues computes initial values for each symbolic array named by
+// glee.Name(), keyed by name. Named arrays which are not constrained on the
+// path are zero.
+func (s *ExecutionState) NamedValues() (map[string][]byte, error) {
+	arrays, values, err := s.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string][]byte, s.names.Len())
+	for itr := s.names.Iterator(); !itr.Done(); {
+		k, v := itr.Next()
+		id, name := k.(uint64), v.(string)
+
+		m[name] = nil
+		if array := s.findAllocByAddr(NewConstantExpr64(id)); array != nil {
+			m[name] = make([]byte, array.Size)
+		}
+		for i, array := range arrays {
+			if array.ID == id {
+				m[name] = values[i]
+			}
+		}
+	}
+	return m, nil
+}
+
+// symbolicArrays returns the symbolic arrays that a value of type typ is
+// derived from. The bytes of strings & slices are checked but not their
+// lengths.
+func (s *ExecutionState) symbolicArrays(value Binding, typ types.Type) ([]*Array, error) {
+	switch value := value.(type) {
+	case Expr:
+		return FindArrays(value), nil
+
+	case *Array:
+		switch typ.Underlying().(type) {
+		case *types.Slice:
+			data, ok := s.selectIntAt(value, 0).(*ConstantExpr)
+			if !ok {
+				return nil, fmt.Errorf("glee.ExecutionState: expected constant slice data address")
+			} else if _, value = s.findAllocContainingAddr(data); value == nil {
+				return nil, nil // nil slice
+			}
+		case *types.Basic:
+		default:
+			return nil, fmt.Errorf("glee.ExecutionState: cannot find symbolic arrays of type: %s", typ)
+		}
+
+		exprs := make([]Expr, value.Size)
+		for i := range exprs {
+			exprs[i] = value.selectByte(NewConstantExpr64(uint64(i)))
+		}
+		return FindArrays(exprs...), nil
+
+	default:
+		return nil, fmt.Errorf("glee.ExecutionState: cannot find symbolic arrays of type: %s", typ)
+	}
+}
+
+// ArrayName returns the name assigned to the symbolic array with the given
+// ID by glee.Name(). Returns a blank string if the array is unnamed.
+func (s *ExecutionState) ArrayName(id uint64) string {
+	if v, _ := s.names.Get(id); v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// ArrayOrigin returns where the symbolic array with the given ID was created,
+// such as "x" for a parameter or "glee.Int@main.go:12" for an intrinsic call.
+// Returns a blank string if the array's origin is unknown.
+func (s *ExecutionState) ArrayOrigin(id uint64) string {
+	if v, _ := s.origins.Get(id); v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// setCallOrigin records the call site of a glee intrinsic as the origin of
+// the symbolic array. The origin is prefixed by prefix, if not blank.
+func (s *ExecutionState) setCallOrigin(array *Array, instr *ssa.Call, prefix string) {
+	fn, _ := s.ExtractCall(instr)
+	pos := s.executor.prog.Fset.Position(instr.Pos())
+	origin := fmt.Sprintf("glee.%s@%s:%d", fn.Name(), filepath.Base(pos.Filename), pos.Line)
+	if prefix != "" {
+		origin = prefix + "(" + origin + ")"
+	}
+	s.origins = s.origins.Set(array.ID, origin)
+}
+
+// FormatExpr returns expr in a Go-like syntax. Symbolic arrays are named by
+// glee.Name(), if named, or by their origin. See FormatExpr().
+func (s *ExecutionState) FormatExpr(expr Expr) string {
+	return FormatExpr(expr, func(array *Array) string {
+		if name := s.ArrayName(array.ID); name != "" {
+			return name
+		}
+		return s.ArrayOrigin(array.ID)
+	})
+}
+
+// WriteConstraints writes the state's path condition to w in the given
+// format. Every array referenced by a constraint is declared.
+func (s *ExecutionState) WriteConstraints(w io.Writer, format Format) error {
+	arrays := FindArrays(s.constraints...)
+
+	var buf []byte
+	var err error
+	switch format {
+	case FormatSMTLIB2:
+		buf, err = EncodeSMTLIB2(s.constraints, arrays)
+	case FormatKQuery:
+		buf, err = EncodeKQuery(s.constraints, arrays)
+	case FormatJSON:
+		buf, err = EncodeJSON(s.constraints, arrays)
+	default:
+		return fmt.Errorf("glee.ExecutionState: unsupported constraint format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf)
+	return err
+}
+
 // AddConstraint adds a constraint to the state. Panic if expr is a constant false.
 func (s *ExecutionState) AddConstraint(expr Expr) {
 	if expr, ok := expr.(*ConstantExpr); ok {
@@ -334,7 +824,7 @@ func (s *ExecutionState) AddConstraint(expr Expr) {
 		return
 	}
 
-	s.constraints = append(s.constraints, expr)
+	s.constraints = append(s.constraints, s.executor.exprs.Intern(expr))
 }
 
 // AddConstraint adds expr to constraints and returns the new constraint list.
@@ -354,18 +844,41 @@ func (s *ExecutionState) Alloc(width uint) (*ConstantExpr, *Array) {
 	addr := s.nextAddr()
 	array := NewArray(addr, width)
 	s.heap = s.heap.Set(addr, array)
+	s.allocN += int(width)
 	return NewConstantExpr(addr, s.executor.PointerWidth()), array
 }
 
 // nextAddr returns the next available address on the heap.
-// Ensures the address is always non-zero.
+// Ensures the address is always non-zero. Each allocation is followed by a
+// red zone so out-of-bounds accesses do not reach the next allocation.
 func (s *ExecutionState) nextAddr() uint64 {
 	itr := s.heap.Iterator()
 	itr.Last()
 	if k, v := itr.Prev(); k != nil {
-		return k.(uint64) + uint64(v.(*Array).Size)
+		return k.(uint64) + allocExtent(v.(*Array))
+	}
+	if base := s.executor.HeapBase; base != 0 {
+		return base
+	}
+	return DefaultHeapBase
+}
+
+// minRedZoneSize is the minimum number of unallocated bytes after each
+// allocation. Red zones are at least as large as their allocation.
+const minRedZoneSize = 16
+
+// allocAlign is the alignment of every allocation. It is fixed, rather than
+// based on the pointer width, so addresses do not vary by architecture.
+const allocAlign = 8
+
+// allocExtent returns the size of array plus its red zone, rounded up to
+// the next allocation boundary.
+func allocExtent(array *Array) uint64 {
+	n := 2 * uint64(array.Size)
+	if array.Size < minRedZoneSize {
+		n = uint64(array.Size) + minRedZoneSize
 	}
-	return uint64(s.executor.PointerWidth())
+	return (n + allocAlign - 1) &^ (allocAlign - 1)
 }
 
 func (s *ExecutionState) findAllocByAddr(addr *ConstantExpr) *Array {
@@ -396,17 +909,51 @@ func (s *ExecutionState) findAllocContainingAddr(addr *ConstantExpr) (base *Cons
 	return nil, nil
 }
 
+// findAllocNearAddr returns the allocation whose bytes or red zone contain
+// addr. Returns nil if addr is not near any allocation.
+func (s *ExecutionState) findAllocNearAddr(addr *ConstantExpr) (base *ConstantExpr, array *Array) {
+	itr := s.heap.Iterator()
+	if itr.Seek(addr.Value); itr.Done() {
+		itr.Last()
+	}
+
+	// Red zones never overlap so only the closest allocation at or below
+	// addr can contain it.
+	for !itr.Done() {
+		k, v := itr.Prev()
+		key, value := k.(uint64), v.(*Array)
+		if key > addr.Value {
+			continue
+		} else if addr.Value < key+allocExtent(value) {
+			return NewConstantExpr(key, s.executor.PointerWidth()), value
+		}
+		break
+	}
+	return nil, nil
+}
+
 // Copy copies the bytes in the value array to the given address.
 func (s *ExecutionState) Copy(addr *ConstantExpr, value *Array) {
 	base, array := s.findAllocContainingAddr(addr)
 	assert(array != nil, "copy: allocation not found: addr=%d", addr.Value)
+	s.copyAt(base, addr, value)
+}
 
-	newArray := array.Clone()
-	for i := uint64(0); i < uint64(value.Size); i++ {
-		index := newAddExpr(newSubExpr(addr, base), NewConstantExpr64(i))
-		newArray.storeByte(index, value.selectByte(NewConstantExpr64(i)))
-	}
-	s.heap = s.heap.Set(base.Value, newArray)
+// CopyValue copies value, a value of type typ, to the given address. Struct
+// fields & array elements are copied individually using the layout of typ so
+// padding at the destination is not overwritten.
+func (s *ExecutionState) CopyValue(addr *ConstantExpr, value *Array, typ types.Type) {
+	base, array := s.findAllocContainingAddr(addr)
+	assert(array != nil, "copy: allocation not found: addr=%d", addr.Value)
+	s.copyValueAt(base, addr, value, typ)
+}
+
+// LoadValue returns the value of type typ at the given address. Scalar types
+// are returned as expressions & all other types as arrays.
+func (s *ExecutionState) LoadValue(addr *ConstantExpr, typ types.Type) (Binding, error) {
+	base, array := s.findAllocContainingAddr(addr)
+	assert(array != nil, "load: allocation not found: addr=%d", addr.Value)
+	return s.load(base, addr, typ)
 }
 
 // Store updates the bytes at addr with value.
@@ -414,8 +961,7 @@ func (s *ExecutionState) Copy(addr *ConstantExpr, value *Array) {
 func (s *ExecutionState) Store(addr *ConstantExpr, value Expr) {
 	base, array := s.findAllocContainingAddr(addr)
 	assert(array != nil, "store: allocation not found: addr=%d", addr.Value)
-	newArray := array.Store(newSubExpr(addr, base), value, s.executor.IsLittleEndian())
-	s.heap = s.heap.Set(base.Value, newArray)
+	s.storeAt(base, addr, value)
 }
 
 // selectIntAt returns the i-th pointer-width expression selected from an array.
@@ -424,6 +970,28 @@ func (s *ExecutionState) selectIntAt(array *Array, i int) Expr {
 	return array.Select(NewConstantExpr32(uint64(i)*uint64(pointerWidth/8)), pointerWidth, s.executor.IsLittleEndian())
 }
 
+// sliceData returns the backing array of a slice header along with the byte
+// offset of the first element & the number of elements. The data pointer and
+// length of the header must be constant. Returns a nil array if len is zero.
+func (s *ExecutionState) sliceData(hdr *Array) (array *Array, offset, length uint64, err error) {
+	n, ok := s.selectIntAt(hdr, 1).(*ConstantExpr)
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("glee: expected constant slice len")
+	} else if n.Value == 0 {
+		return nil, 0, 0, nil
+	}
+
+	data, ok := s.selectIntAt(hdr, 0).(*ConstantExpr)
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("glee: expected constant slice data address")
+	}
+	base, array := s.findAllocContainingAddr(data)
+	if array == nil {
+		return nil, 0, 0, fmt.Errorf("glee: slice data not found: %d", data.Value)
+	}
+	return array, data.Value - base.Value, n.Value, nil
+}
+
 // storeIntAt returns a new array with the i-th pointer-width element updated.
 func (s *ExecutionState) storeIntAt(array *Array, i int, value Expr) *Array {
 	pointerWidth := uint64(s.executor.PointerWidth())
@@ -446,7 +1014,7 @@ func (s *ExecutionState) Dump() string {
 	fmt.Fprintln(&buf, "")
 
 	fmt.Fprintln(&buf, "== HEAP")
-	fmt.Fprintln(&buf, s.dumpHeap())
+	fmt.Fprintln(&buf, s.DumpHeap())
 	fmt.Fprintln(&buf, "")
 
 	fmt.Fprintln(&buf, "== CONSTRAINTS")
@@ -456,7 +1024,8 @@ func (s *ExecutionState) Dump() string {
 	return buf.String()
 }
 
-func (s *ExecutionState) dumpHeap() string {
+// DumpHeap returns the contents of each array on the heap as a string.
+func (s *ExecutionState) DumpHeap() string {
 	var buf bytes.Buffer
 	itr := s.heap.Iterator()
 	for {
@@ -483,18 +1052,63 @@ const (
 	ExecutionStatusPanicked = ExecutionStatus("panicked") // panic occurred
 	ExecutionStatusFailed   = ExecutionStatus("failed")   // test failed
 	ExecutionStatusExited   = ExecutionStatus("exited")   // process exited
+
+	ExecutionStatusOverflowed  = ExecutionStatus("overflowed")  // integer overflow detected
+	ExecutionStatusExhausted   = ExecutionStatus("exhausted")   // execution limit reached
+	ExecutionStatusDropped     = ExecutionStatus("dropped")     // assumption violated
+	ExecutionStatusUnsupported = ExecutionStatus("unsupported") // path could not be executed
+
+	ExecutionStatusAssertFailed = ExecutionStatus("assert_failed") // assertion can be false
+	ExecutionStatusMemoryError  = ExecutionStatus("memory_error")  // out-of-bounds access
 )
 
+// AssertionFailure represents a glee.Assert() call whose condition can be
+// false along with concrete inputs that cause it to fail.
+type AssertionFailure struct {
+	Pos token.Position // source position of the assertion
+
+	// Solved value for each symbolic array on the path.
+	Arrays []*Array
+	Values [][]byte
+}
+
+// MemoryError represents a load or store which can access bytes beyond the
+// end of an allocation along with concrete inputs that cause the access.
+type MemoryError struct {
+	Pos  token.Position // source position of the access
+	Addr uint64         // solved address of the access
+	Size uint           // bytes accessed
+
+	// Base address & size of the allocation containing Addr. Both are zero
+	// for an invalid pointer conversion which is not near any allocation.
+	Base      uint64
+	AllocSize uint
+
+	// Solved value for each symbolic array on the path.
+	Arrays []*Array
+	Values [][]byte
+}
+
 // StackFrame represents the state of a call into a function.
 type StackFrame struct {
 	fn       *ssa.Function
 	caller   *StackFrame
 	locals   []*Array
 	bindings map[ssa.Value]Binding
+	shared   bool // bindings are shared with another frame & must be copied before writing
+
+	// Deferred calls in the order they were registered.
+	defers []*deferredCall
+
+	unwinding   bool // panicking & waiting for deferred calls to return
+	recoverable bool // deferred call executed during a panic
 
 	block *ssa.BasicBlock
 	prev  *ssa.BasicBlock
 	pc    int
+
+	// If set, bound expressions are interned so identical values share nodes.
+	exprs *ExprBuilder
 }
 
 // NewStackFrame returns a new instance of StackFrame for a given function.
@@ -528,24 +1142,81 @@ func (f *StackFrame) jump(dst *ssa.BasicBlock) {
 	f.prev, f.block, f.pc = f.block, dst, -1
 }
 
+// jumpFrom moves execution to dst as if branching from src instead of the
+// current block. Phi nodes in dst select their value by src.
+func (f *StackFrame) jumpFrom(src, dst *ssa.BasicBlock) {
+	f.prev, f.block, f.pc = src, dst, -1
+}
+
 // bind assigns the expression or slice of expressions to a given SSA value.
 func (f *StackFrame) bind(value ssa.Value, b Binding) {
+	if f.exprs != nil {
+		b = f.exprs.InternBinding(b)
+	}
+
+	// Copy shared bindings on first write.
+	if f.shared {
+		bindings := make(map[ssa.Value]Binding, len(f.bindings)+1)
+		for k, v := range f.bindings {
+			bindings[k] = v
+		}
+		f.bindings, f.shared = bindings, false
+	}
 	f.bindings[value] = b
 }
 
-// Clone returns a copy of the stack frame.
+// Clone returns a copy of the stack frame. Bindings are shared with the
+// original frame until either frame binds a new value.
 func (f *StackFrame) Clone() *StackFrame {
+	f.shared = true
 	other := *f
 
-	other.bindings = make(map[ssa.Value]Binding, len(f.bindings))
-	for k := range f.bindings {
-		other.bindings[k] = f.bindings[k]
+	// Locals are only assigned when the frame is pushed so they are shared.
+	// Defers are capped so appending to either frame reallocates.
+	other.defers = f.defers[:len(f.defers):len(f.defers)]
+
+	return &other
+}
+
+// cloneStack returns a copy of each frame in stack. Callers are relinked to the
+// copied frames so that returning from a frame binds its results within the
+// new stack instead of the stack it was copied from.
+func cloneStack(stack []*StackFrame) []*StackFrame {
+	other := make([]*StackFrame, len(stack))
+	for i := range stack {
+		other[i] = stack[i].Clone()
+		if i > 0 {
+			other[i].caller = other[i-1]
+		}
 	}
+	return other
+}
 
-	other.locals = make([]*Array, len(f.locals))
-	copy(other.locals, f.locals)
+// Fn returns the function executing within the frame.
+func (f *StackFrame) Fn() *ssa.Function { return f.fn }
 
-	return &other
+// Caller returns the frame of the calling function, if any.
+func (f *StackFrame) Caller() *StackFrame { return f.caller }
+
+// Binding returns the binding for value within the frame. Returns nil if value
+// has not been bound. Unlike ExecutionState.Eval(), constants are not evaluated.
+func (f *StackFrame) Binding(value ssa.Value) Binding {
+	return f.bindings[value]
+}
+
+// Bindings returns all bound values, sorted by name.
+func (f *StackFrame) Bindings() []FrameBinding {
+	values := f.BoundValues()
+	a := make([]FrameBinding, len(values))
+	for i, value := range values {
+		a[i] = FrameBinding{
+			Value:   value,
+			Name:    value.Name(),
+			Type:    value.Type(),
+			Binding: f.bindings[value],
+		}
+	}
+	return a
 }
 
 // BoundValues returns all bound values, sorted by name.
@@ -576,6 +1247,14 @@ func (f *StackFrame) Dump() string {
 	return buf.String()
 }
 
+// FrameBinding represents an SSA value and its binding within a stack frame.
+type FrameBinding struct {
+	Value   ssa.Value
+	Name    string
+	Type    types.Type
+	Binding Binding
+}
+
 // Binding represents an object that can be bound to an SSA value.
 // This can be either an Expr or a Tuple.
 type Binding interface {
@@ -588,6 +1267,9 @@ func (*CastExpr) binding()         {}
 func (*ConcatExpr) binding()       {}
 func (*ConstantExpr) binding()     {}
 func (*ExtractExpr) binding()      {}
+func (*FPBinaryExpr) binding()     {}
+func (*FPCastExpr) binding()       {}
+func (*IteExpr) binding()          {}
 func (*NotExpr) binding()          {}
 func (*NotOptimizedExpr) binding() {}
 func (*SelectExpr) binding()       {}
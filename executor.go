@@ -1,15 +1,18 @@
 package glee
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"go/constant"
 	"go/token"
 	"go/types"
-	"log"
+	"math"
 	"math/rand"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"time"
 
 	"golang.org/x/tools/go/ssa"
 )
@@ -17,22 +20,75 @@ import (
 var (
 	ErrNoStateAvailable       = errors.New("glee: no state available")
 	ErrNoInstructionAvailable = errors.New("glee: no instruction available")
+	ErrNoValidUTF8Decoding    = errors.New("glee: no valid utf-8 decoding available")
+	ErrUnsatisfiable          = errors.New("glee: constraints unsatisfiable")
 )
 
 type Executor struct {
 	fn         *ssa.Function                // entry function
 	root       *ExecutionState              // initial state
 	states     map[*ExecutionState]struct{} // all states
-	globals    map[*ssa.Global]Expr         // global variables
 	stateIDSeq int                          // autoincrementing state ID
-
-	prog *ssa.Program                // entire program, ease-of-use var
-	fns  map[funcKey]FunctionHandler // registered function handlers
+	prev       *ExecutionState              // last executed state
+	current    *ExecutionState              // state in progress by StepContext()
+	interrupt  *ExecutionState              // state cancelled mid-path, see Checkpoint()
+	held       []*ExecutionState            // deprioritized states, see BudgetPolicy
+	ctx        context.Context              // context of the executing state
+	startTime  time.Time                    // time of first execution, used by MaxTime
+	exprs      *ExprBuilder                 // interns bound expressions
+
+	// Fork statistics by source branch & by function.
+	branchHotSpots map[token.Position]*HotSpot
+	funcHotSpots   map[*ssa.Function]*HotSpot
+
+	// Coverage of functions entered, lines executed by released states, and
+	// directions taken by each conditional branch.
+	coverFuncs map[*ssa.Function]struct{}
+	covered    map[string]map[uint]struct{}
+	branches   map[*ssa.If]*BranchCoverage
+
+	// Statistics for solver queries made by the executor.
+	queryStats QueryStats
+
+	// Every state created, by ID, if RecordStateTree is set.
+	tree map[int]*stateNode
+
+	prog  *ssa.Program                      // entire program, ease-of-use var
+	fns   map[funcKey]FunctionHandler       // handlers registered by name
+	funcs map[*ssa.Function]FunctionHandler // handlers registered by function
 
 	// Mapping of types to generated IDs and back.
 	// This is used for deterministically assigning pointer values.
-	typeIDs   map[types.Type]int
-	typesByID map[int]types.Type
+	// Identical types share an ID & are found by their string form.
+	typeIDs     map[types.Type]int
+	typesByID   map[int]types.Type
+	typesByName map[string][]int
+
+	// Addresses assigned to function values, in order of first use.
+	funcAddrs   map[*ssa.Function]uint64
+	funcsByAddr map[uint64]*ssa.Function
+
+	// Dynamic type of error values created by errors.New() & fmt.Errorf().
+	errorType types.Type
+
+	// Dynamic type of parse errors returned by modeled strconv functions.
+	numErrType types.Type
+
+	// Set by BindSymbolicArgs() along with the length of symbolic argument,
+	// environment variable & flag strings.
+	argsBound  bool
+	argsStrLen int
+
+	// Summaries of pure functions, computed on first call. A nil summary
+	// marks a function which cannot be summarized. Shared with the executors
+	// used to compute summaries, which set summarizing.
+	summaries   map[*ssa.Function]*summary
+	summarizing bool
+
+	// Annotations parsed from the doc comment of each function, along with
+	// the number of states forked within functions limited by maxpaths.
+	funcAnnotations map[*ssa.Function]*funcAnnotations
+	funcPathN       map[*ssa.Function]int
 
 	// OS & architecture settings for the executor.
 	// See `go tool dist list` for a list of valid combinations.
@@ -45,35 +101,239 @@ type Executor struct {
 
 	// Search strategy for the executor. Defaults to depth-first.
 	Searcher Searcher
+
+	// Address of the first heap allocation on each path. Allocations are
+	// placed at increasing addresses in the order they are made so the same
+	// path produces the same addresses on every run & architecture.
+	// Defaults to DefaultHeapBase. Must be set before execution.
+	HeapBase uint64
+
+	// If true, strings are assumed to be valid UTF-8 when decoding runes so
+	// states for invalid encodings are not generated.
+	AssumeValidUTF8 bool
+
+	// Maximum number of live goroutines on a path. Spawning additional
+	// goroutines returns an error.
+	MaxGoroutines int
+
+	// Maximum number of goroutine switches explored on a single path.
+	// Once reached, the current goroutine runs until it exits.
+	MaxPreemptions int
+
+	// If true, signed integer ADD, SUB, MUL, & DIV operations that may overflow
+	// fork an additional state with an ExecutionStatusOverflowed status.
+	CheckOverflow bool
+
+	// If true, constraints are passed through Simplify() before each query.
+	SimplifyConstraints bool
+
+	// If true, every state created is recorded so the fork tree can be
+	// written by WriteStateTree() once exploration completes. Must be set
+	// before execution.
+	RecordStateTree bool
+
+	// If true, calls to pure functions are replaced by a summary of the
+	// paths through the function, computed the first time it is called.
+	// Lines within summarized functions are not reported as covered.
+	Summarize bool
+
+	// If true, calls to functions without a body, or whose body fails to
+	// execute, bind fresh symbolic values to their results instead of
+	// returning an error. Each approximation is logged. Strings & byte slices
+	// returned are HavocStringLen bytes long.
+	Havoc          bool
+	HavocStringLen int
+
+	// Determines whether an error executing a path, such as an unsupported
+	// instruction, stops exploration or only terminates the path. Defaults
+	// to ErrorPolicyFailFast.
+	ErrorPolicy ErrorPolicy
+
+	// Determines whether a path exceeding MaxSolverTime or MaxMemory is
+	// terminated or deprioritized. Defaults to BudgetPolicyExhaust.
+	BudgetPolicy BudgetPolicy
+
+	// Maximum duration of a single solver query, passed to the solver as a
+	// context deadline. Queries exceeding it fail with ErrSolverTimeout &
+	// are retried up to QueryRetries times, doubling the timeout each time.
+	// Zero is unlimited & disables retries.
+	QueryTimeout time.Duration
+	QueryRetries int
+
+	// Determines how a query the solver cannot decide, such as a timeout
+	// or a resource limit, is handled once retries are exhausted. Defaults
+	// to UnknownPolicyFailFast.
+	UnknownPolicy UnknownPolicy
+
+	// Limits on exploration. States exceeding a limit are terminated with
+	// an ExecutionStatusExhausted status.
+	Limits
+
+	// If set, receives events for forks, instructions, terminal states, and
+	// solver queries.
+	Tracer Tracer
+
+	// Destination for log messages. Defaults to discarding all messages.
+	Logger Logger
+}
+
+// Limits represents limits on the amount of exploration performed by an
+// executor. A zero value for any limit means it is unlimited.
+type Limits struct {
+	// Maximum number of states created. States forked after the limit is
+	// reached are exhausted so running states can complete but not branch.
+	MaxStates int
+
+	// Maximum number of branches on a single path. See ExecutionState.Depth().
+	MaxDepth int
+
+	// Maximum number of instructions executed on a single path.
+	MaxInstructions int
+
+	// Maximum wall clock time since execution began. Once exceeded, each
+	// remaining state is exhausted when selected.
+	MaxTime time.Duration
+
+	// Maximum solver time spent on a single path. See ExecutionState.SolverTime().
+	MaxSolverTime time.Duration
+
+	// Maximum estimated memory, in bytes, used by a single path. See
+	// ExecutionState.MemoryN().
+	MaxMemory int
+
+	// Maximum size, in bytes, of an allocation with a symbolic size. Paths
+	// on which the size may exceed the limit are exhausted. Zero uses
+	// DefaultMaxSymbolicAllocSize as symbolic sizes are otherwise unbounded.
+	MaxSymbolicAllocSize int
+
+	// Maximum depth & number of distinct subexpressions of an expression
+	// bound by an instruction. Larger expressions, such as those built by
+	// loops over symbolic strings, are replaced by a single feasible value
+	// so later queries remain tractable. The path is constrained to that
+	// value rather than exhausted.
+	MaxExprDepth int
+	MaxExprSize  int
+}
+
+// ErrorPolicy represents how an executor handles an error on a single path.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyFailFast returns the error from ExecuteNextState() and
+	// the path is not explored further.
+	ErrorPolicyFailFast ErrorPolicy = iota
+
+	// ErrorPolicyTerminatePath terminates the path with an
+	// ExecutionStatusUnsupported status & the error as its reason so that
+	// exploration of other paths continues. Cancellation & solver errors
+	// are still returned as they are not specific to the path.
+	ErrorPolicyTerminatePath
+)
+
+// UnknownPolicy represents how an executor handles a solver query whose
+// satisfiability cannot be determined. See IsSolverUnknown().
+type UnknownPolicy int
+
+const (
+	// UnknownPolicyFailFast returns the solver error from ExecuteNextState()
+	// and the path is not explored further.
+	UnknownPolicyFailFast UnknownPolicy = iota
+
+	// UnknownPolicyTerminatePath terminates the path making the query with
+	// an ExecutionStatusExhausted status so that exploration of other paths
+	// continues.
+	UnknownPolicyTerminatePath
+
+	// UnknownPolicySkipBranch optimistically assumes the query is
+	// unsatisfiable, so a branch which cannot be decided is not explored,
+	// and execution of the path continues.
+	UnknownPolicySkipBranch
+)
+
+// String returns the name of the policy.
+func (p UnknownPolicy) String() string {
+	switch p {
+	case UnknownPolicyFailFast:
+		return "fail"
+	case UnknownPolicyTerminatePath:
+		return "terminate"
+	case UnknownPolicySkipBranch:
+		return "skip"
+	default:
+		return fmt.Sprintf("UnknownPolicy<%d>", int(p))
+	}
+}
+
+// ParseUnknownPolicy returns the policy with the given name.
+func ParseUnknownPolicy(s string) (UnknownPolicy, error) {
+	for _, p := range []UnknownPolicy{UnknownPolicyFailFast, UnknownPolicyTerminatePath, UnknownPolicySkipBranch} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return UnknownPolicyFailFast, fmt.Errorf("glee: invalid unknown policy: %q", s)
 }
 
+// BudgetPolicy represents how an executor handles a path which exceeds its
+// solver time or memory budget.
+type BudgetPolicy int
+
+const (
+	// BudgetPolicyExhaust terminates the path with an
+	// ExecutionStatusExhausted status.
+	BudgetPolicyExhaust BudgetPolicy = iota
+
+	// BudgetPolicyDeprioritize suspends the path until no other states
+	// are available from the searcher. The path then runs without a
+	// budget. Searchers which select from the state tree, such as
+	// RandomPathSearcher, may resume the path earlier.
+	BudgetPolicyDeprioritize
+)
+
+// DefaultMaxSymbolicAllocSize is the default maximum size of an allocation
+// with a symbolic size.
+const DefaultMaxSymbolicAllocSize = 1 << 10
+
 // NewExecutor returns a new instance of Executor.
 func NewExecutor(fn *ssa.Function) *Executor {
 	e := &Executor{
-		fn:      fn,
-		globals: make(map[*ssa.Global]Expr),
+		fn:    fn,
+		exprs: NewExprBuilder(),
+
+		prog:  fn.Prog,
+		fns:   make(map[funcKey]FunctionHandler),
+		funcs: make(map[*ssa.Function]FunctionHandler),
 
-		prog: fn.Prog,
-		fns:  make(map[funcKey]FunctionHandler),
+		typeIDs:     make(map[types.Type]int),
+		typesByID:   make(map[int]types.Type),
+		typesByName: make(map[string][]int),
 
-		typeIDs:   make(map[types.Type]int),
-		typesByID: make(map[int]types.Type),
+		funcAddrs:   make(map[*ssa.Function]uint64),
+		funcsByAddr: make(map[uint64]*ssa.Function),
+
+		summaries: make(map[*ssa.Function]*summary),
+
+		funcAnnotations: make(map[*ssa.Function]*funcAnnotations),
 
 		OS:       runtime.GOOS,
 		Arch:     runtime.GOARCH,
-		Searcher: NewDFSSearcher(),
+		HeapBase: DefaultHeapBase,
+		Logger:   nopLogger{},
+
+		MaxGoroutines:  DefaultMaxGoroutines,
+		MaxPreemptions: DefaultMaxPreemptions,
 	}
 
 	// Register all program types in deterministic order.
 	for _, typ := range programTypes(fn.Prog) {
-		typeID := len(e.typeIDs) + 1
-		e.typeIDs[typ] = typeID
-		e.typesByID[typeID] = typ
+		e.typeID(typ)
 	}
 
 	// Default registrations.
 	pkgName := "github.com/benbjohnson/glee"
 	e.Register(pkgName, "Assert", execAssert)
+	e.Register(pkgName, "Assume", execAssume)
+	e.Register(pkgName, "Name", execName)
 	e.Register(pkgName, "Byte", execInt)
 	e.Register(pkgName, "Int", execInt)
 	e.Register(pkgName, "Int8", execInt)
@@ -87,62 +347,705 @@ func NewExecutor(fn *ssa.Function) *Executor {
 	e.Register(pkgName, "Uint64", execInt)
 	e.Register(pkgName, "ByteSlice", execByteSlice)
 	e.Register(pkgName, "String", execString)
+	e.Register(pkgName, "StringMaxLen", execStringMaxLen)
+	e.Register(pkgName, "ByteSliceMaxLen", execByteSliceMaxLen)
+	e.Register("", "close", execClose)
 	e.Register("", "copy", execCopy)
+	e.Register("", "delete", execDelete)
 	e.Register("", "len", execLen)
-	e.Register("testing", "Fatal", execTestingFatal)
+	e.Register("", "cap", execCap)
+	e.Register("", "min", execMin)
+	e.Register("", "max", execMax)
+	e.Register("", "recover", execRecover)
+	e.Register("", "print", execPrint)
+	e.Register("", "println", execPrint)
+	e.Register("", "ssa:wrapnilchk", execWrapNilChk)
+	e.Register("testing", "Fail", execTestingFail)
+	e.Register("testing", "Error", execTestingFail)
+	e.Register("testing", "Errorf", execTestingFail)
+	e.Register("testing", "Failed", execTestingFailed)
+	e.Register("testing", "FailNow", execTestingFailNow)
+	e.Register("testing", "Fatal", execTestingFailNow)
+	e.Register("testing", "Fatalf", execTestingFailNow)
+	e.Register("testing", "Skip", execTestingSkip)
+	e.Register("testing", "Skipf", execTestingSkip)
+	e.Register("testing", "SkipNow", execTestingSkip)
+	e.Register("testing", "Log", execTestingNop)
+	e.Register("testing", "Logf", execTestingNop)
+	e.Register("testing", "Helper", execTestingNop)
+	e.Register("errors", "New", execErrorsNew)
+	e.Register("fmt", "Errorf", execFmtErrorf)
+	e.Register("fmt", "Print", execFmtPrint)
+	e.Register("fmt", "Printf", execFmtPrint)
+	e.Register("fmt", "Println", execFmtPrint)
+	e.Register("os", "Getenv", execOSGetenv)
+	e.Register("os", "LookupEnv", execOSLookupEnv)
+	e.Register("flag", "String", execFlagDefine)
+	e.Register("flag", "Int", execFlagDefine)
+	e.Register("flag", "Bool", execFlagDefine)
+	e.Register("flag", "Parse", execFlagParse)
+	e.Register("flag", "Args", execFlagArgs)
+	e.Register("flag", "NArg", execFlagNArg)
+	e.Register("flag", "Arg", execFlagArg)
+	e.Register("strconv", "Atoi", execStrconvAtoi)
+	e.Register("strconv", "ParseUint", execStrconvParseUint)
+	e.Register("unicode/utf8", "RuneLen", execUTF8RuneLen)
+	e.Register("unicode/utf8", "DecodeRune", execUTF8DecodeRune)
+	e.Register("unicode/utf8", "DecodeRuneInString", execUTF8DecodeRuneInString)
+
+	// Register handlers for specific methods of packages in the program.
+	if typ, err := e.errorStringType(); err == nil {
+		e.RegisterFunc(e.prog.LookupMethod(typ, nil, "Error"), execErrorsError)
+	}
 
 	// Initialize entry state.
-	e.root = NewExecutionState(e, fn)
+	e.Reset(NewDFSSearcher())
+
+	return e
+}
+
+// Reset discards all states & statistics and restarts exploration from a new
+// entry state, which is added to searcher. State IDs & heap addresses are
+// reassigned from the start so the same states are reproduced on each run.
+// BindSymbolicParams() must be called again if it was used.
+func (e *Executor) Reset(searcher Searcher) {
+	e.stateIDSeq = 0
+	e.prev, e.current, e.interrupt, e.held = nil, nil, nil, nil
+	e.startTime = time.Time{}
+	e.argsBound, e.argsStrLen = false, 0
+
+	e.branchHotSpots = make(map[token.Position]*HotSpot)
+	e.funcHotSpots = make(map[*ssa.Function]*HotSpot)
+
+	e.coverFuncs = make(map[*ssa.Function]struct{})
+	e.covered = make(map[string]map[uint]struct{})
+	e.branches = make(map[*ssa.If]*BranchCoverage)
+
+	e.queryStats = QueryStats{}
+	e.tree = nil
+	e.funcPathN = make(map[*ssa.Function]int)
+
+	e.root = NewExecutionState(e, e.fn)
 	e.root.id = e.nextStateID()
+	e.root.liveN = 1
 
-	// Add state to searcher.
+	e.Searcher = searcher
 	e.states = map[*ExecutionState]struct{}{e.root: struct{}{}}
 	e.Searcher.AddState(e.root)
-
-	return e
 }
 
 // RootState returns the initial state for the function execution.
 func (e *Executor) RootState() *ExecutionState { return e.root }
 
+// BindSymbolicParams binds a symbolic value to each parameter of the entry
+// function. Strings & byte slices are n bytes long. Structs which only contain
+// booleans & numbers are backed by a single array. The receiver of a method is
+// the first parameter & a pointer receiver references a symbolic value of its
+// element type. Returns the symbolic array backing each parameter in order.
+// Must be called before execution begins. See IsSymbolicParamType() for the
+// supported parameter types.
+func (e *Executor) BindSymbolicParams(n int) ([]*Array, error) {
+	state := e.root
+	frame := state.Frame()
+
+	arrays := make([]*Array, len(e.fn.Params))
+	for i, param := range e.fn.Params {
+		switch typ := param.Type().Underlying().(type) {
+		case *types.Basic:
+			switch {
+			case typ.Info()&types.IsString != 0:
+				_, arrays[i] = state.Alloc(uint(n))
+				frame.bind(param, arrays[i])
+			case typ.Info()&types.IsBoolean != 0:
+				_, arrays[i] = state.Alloc(1)
+				frame.bind(param, NewNotExpr(NewIsZeroExpr(arrays[i].Select(NewConstantExpr(0, 32), 8, e.IsLittleEndian()))))
+			case isExprType(typ):
+				width := e.Sizeof(typ)
+				_, arrays[i] = state.Alloc(width / 8)
+				frame.bind(param, arrays[i].Select(NewConstantExpr(0, 32), width, e.IsLittleEndian()))
+			default:
+				return nil, fmt.Errorf("glee.Executor: unsupported symbolic parameter type: %s", param.Type())
+			}
+
+		case *types.Pointer:
+			var addr *ConstantExpr
+			switch {
+			case isTestingType(typ.Elem()):
+				// Test functions receive a zero value so testing methods
+				// can be called on it. The value is not symbolic.
+				addr, arrays[i] = state.Alloc(e.Sizeof(typ.Elem()) / 8)
+				arrays[i].zero()
+			case i == 0 && e.fn.Signature.Recv() != nil && isSymbolicElem(typ.Elem()):
+				// Pointer receivers reference a symbolic value so methods
+				// can be executed without a wrapper function.
+				addr, arrays[i] = state.Alloc(e.Sizeof(typ.Elem()) / 8)
+			default:
+				return nil, fmt.Errorf("glee.Executor: unsupported symbolic parameter type: %s", param.Type())
+			}
+			frame.bind(param, addr)
+
+		case *types.Slice:
+			if elem, ok := typ.Elem().Underlying().(*types.Basic); !ok || elem.Kind() != types.Byte {
+				return nil, fmt.Errorf("glee.Executor: unsupported symbolic parameter type: %s", param.Type())
+			}
+
+			var addr *ConstantExpr
+			addr, arrays[i] = state.Alloc(uint(n))
+			length := NewConstantExpr(uint64(n), e.PointerWidth())
+			_, hdr := state.Alloc((e.PointerWidth() / 8) * 3)
+			hdr = state.storeIntAt(hdr, 0, addr)   // data
+			hdr = state.storeIntAt(hdr, 1, length) // len
+			hdr = state.storeIntAt(hdr, 2, length) // cap
+			state.heap = state.heap.Set(hdr.ID, hdr)
+			frame.bind(param, hdr)
+
+		case *types.Struct:
+			if !isSymbolicStruct(typ) {
+				return nil, fmt.Errorf("glee.Executor: unsupported symbolic parameter type: %s", param.Type())
+			}
+			_, arrays[i] = state.Alloc(e.Sizeof(typ) / 8)
+			frame.bind(param, arrays[i])
+
+		default:
+			return nil, fmt.Errorf("glee.Executor: unsupported symbolic parameter type: %s", param.Type())
+		}
+		state.origins = state.origins.Set(arrays[i].ID, param.Name())
+	}
+	return arrays, nil
+}
+
+// IsSymbolicParamType returns true if BindSymbolicParams() can bind a symbolic
+// value to a parameter of typ. These are booleans, numbers, strings, byte
+// slices & structs which only contain booleans & numbers. Pointers to
+// testing.T & testing.B are also supported but are not symbolic.
+func IsSymbolicParamType(typ types.Type) bool {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return typ.Info()&types.IsString != 0 || isExprType(typ)
+	case *types.Pointer:
+		return isTestingType(typ.Elem())
+	case *types.Slice:
+		elem, ok := typ.Elem().Underlying().(*types.Basic)
+		return ok && elem.Kind() == types.Byte
+	case *types.Struct:
+		return isSymbolicStruct(typ)
+	default:
+		return false
+	}
+}
+
+// isSymbolicElem returns true if typ is a boolean, a number, or a struct
+// which only contains booleans & numbers.
+func isSymbolicElem(typ types.Type) bool {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return isExprType(typ)
+	case *types.Struct:
+		return isSymbolicStruct(typ)
+	default:
+		return false
+	}
+}
+
+// isSymbolicStruct returns true if every field of typ is a boolean, a number,
+// or another such struct. Values of these types contain no references so
+// their bytes can be symbolic.
+func isSymbolicStruct(typ *types.Struct) bool {
+	for i := 0; i < typ.NumFields(); i++ {
+		switch field := typ.Field(i).Type().Underlying().(type) {
+		case *types.Basic:
+			if !isExprType(field) {
+				return false
+			}
+		case *types.Struct:
+			if !isSymbolicStruct(field) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // nextStateID returns the next autoincrementing state ID.
 func (e *Executor) nextStateID() int {
 	e.stateIDSeq++
 	return e.stateIDSeq
 }
 
+// StateN returns the number of states retained by the executor. Fully
+// explored states are released as execution progresses.
+func (e *Executor) StateN() int { return len(e.states) }
+
+// Restore adds a new state to the executor from a snapshot so it is explored
+// again from the point the snapshot was taken. The snapshot may be restored
+// multiple times. Restored states are attached to the root of the state tree.
+func (e *Executor) Restore(snap *Snapshot) *ExecutionState {
+	state := snap.state.Clone()
+	state.id = e.nextStateID()
+	state.parent = e.root
+	e.root.children = append(e.root.children, state)
+	e.addState(state)
+	return state
+}
+
+// addState adds a newly forked state to the executor & its searcher. States
+// exceeding the state or depth limits are exhausted so they are reported
+// without being executed.
+func (e *Executor) addState(state *ExecutionState) {
+	if e.MaxStates > 0 && e.stateIDSeq > e.MaxStates {
+		exhaust(state, "state limit reached")
+	} else if e.MaxDepth > 0 && state.depth > e.MaxDepth {
+		exhaust(state, "depth limit reached")
+	}
+	e.checkPathLimit(state)
+
+	// Continuations of deprioritized states are held from the searcher.
+	// States forked from them afterward are added as normal.
+	e.states[state] = struct{}{}
+	state.addLiveN(1)
+	if state.deprioritized && !state.parent.deprioritized {
+		e.held = append(e.held, state)
+	} else {
+		e.Searcher.AddState(state)
+	}
+
+	if e.RecordStateTree && state.parent != nil {
+		e.recordFork(state.parent, state)
+	}
+
+	if e.Tracer != nil && state.parent != nil {
+		e.Tracer.OnFork(state.parent, state)
+	}
+}
+
+// exhaust terminates a running state because an execution limit was reached.
+func exhaust(state *ExecutionState, reason string) {
+	if state.status == ExecutionStatusRunning {
+		state.status, state.reason = ExecutionStatusExhausted, reason
+	}
+}
+
+// prune removes state from the state tree if it is a leaf. A state is only
+// passed in after it has been executed so a leaf has no remaining work.
+//
+// Parents left without children are fully explored so they are removed
+// recursively. A parent left with a single child is spliced out of the tree
+// to compact chains of single-child states.
+func (e *Executor) prune(state *ExecutionState) {
+	for state != e.root && len(state.children) == 0 {
+		parent := state.parent
+		parent.removeChild(state)
+		state.parent = nil
+		e.releaseCoverage(state)
+		delete(e.states, state)
+		state = parent
+	}
+
+	if state != e.root && len(state.children) == 1 {
+		child, parent := state.children[0], state.parent
+		parent.replaceChild(state, child)
+		child.parent = parent
+		state.parent, state.children = nil, nil
+		e.releaseCoverage(state)
+		delete(e.states, state)
+	}
+}
+
+// typeID returns the ID assigned to typ. Types not referenced by the program
+// are assigned a new ID on first use.
+//
+// Type values are not canonical, such as pointer types, so an identical type
+// may already be registered under a different pointer. Its ID is reused so
+// type assertions & method lookups match and IDs do not depend on which
+// pointer is registered first.
+func (e *Executor) typeID(typ types.Type) int {
+	if id, ok := e.typeIDs[typ]; ok {
+		return id
+	}
+
+	name := typ.String()
+	for _, id := range e.typesByName[name] {
+		if types.Identical(typ, e.typesByID[id]) {
+			e.typeIDs[typ] = id
+			return id
+		}
+	}
+
+	id := len(e.typesByID) + 1
+	e.typeIDs[typ] = id
+	e.typesByID[id] = typ
+	e.typesByName[name] = append(e.typesByName[name], id)
+	return id
+}
+
+// funcAddr returns the address of fn when used as a function value.
+// Addresses are assigned on first use from funcSegment, which is above any
+// heap address, so they do not depend on where fn is loaded in memory.
+func (e *Executor) funcAddr(fn *ssa.Function) uint64 {
+	if addr, ok := e.funcAddrs[fn]; ok {
+		return addr
+	}
+	addr := funcSegment + uint64(len(e.funcAddrs))*funcAlign
+	e.funcAddrs[fn] = addr
+	e.funcsByAddr[addr] = fn
+	return addr
+}
+
+// DefaultHeapBase is the default address of the first heap allocation.
+const DefaultHeapBase = 0x1000
+
+// Function values are assigned addresses from funcSegment, spaced funcAlign
+// bytes apart. The segment fits in a 32-bit address space.
+const (
+	funcSegment = 0x80000000
+	funcAlign   = 8
+)
+
 // Register registers a function handler for a given function.
 // Every invocation of the given function will be delegated to the handler.
+// Methods are matched by name so the handler applies to every method in the
+// package with the same name, regardless of receiver.
 func (e *Executor) Register(path, name string, h FunctionHandler) {
 	e.fns[funcKey{path, name}] = h
 }
 
+// RegisterFunc registers a function handler for a specific function, such as
+// a method on a single receiver type. Handlers registered by function take
+// precedence over handlers registered by name.
+func (e *Executor) RegisterFunc(fn *ssa.Function, h FunctionHandler) {
+	e.funcs[fn] = h
+}
+
 // ExecuteNextState executes the next available state. This can be called
 // continually until ErrNoStateAvailable is returned.
 func (e *Executor) ExecuteNextState() (*ExecutionState, error) {
+	return e.ExecuteNextStateContext(context.Background())
+}
+
+// ExecuteNextStateContext executes the next available state, same as
+// ExecuteNextState(), but stops once ctx is done. Cancellation is checked
+// between instructions & is passed to the solver. If cancelled mid-path then
+// the partially executed state is returned with ctx.Err() and it is not
+// explored further.
+func (e *Executor) ExecuteNextStateContext(ctx context.Context) (*ExecutionState, error) {
+	for {
+		if state, done, err := e.StepContext(ctx); err != nil || done {
+			return state, err
+		}
+	}
+}
+
+// StepContext executes a single instruction of the state currently being
+// explored. If no state is in progress then the next state is selected from
+// the searcher first. Returns done as true once the state stops executing,
+// which is when ExecuteNextState() would have returned it.
+func (e *Executor) StepContext(ctx context.Context) (state *ExecutionState, done bool, err error) {
 	if !isValidOSArch(e.OS, e.Arch) {
-		return nil, errors.New("invalid os/arch combination")
+		return nil, false, errors.New("invalid os/arch combination")
 	}
 
-	state := e.Searcher.SelectState()
-	if state == nil {
-		return nil, ErrNoStateAvailable
+	// Cancellation of a state in progress is reported with the state below.
+	if e.current == nil {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		// Release the previously executed state now that it has been reported.
+		if e.prev != nil {
+			e.prune(e.prev)
+			e.prev = nil
+		}
+
+		if e.current = e.Searcher.SelectState(); e.current == nil {
+			if e.current = e.nextHeldState(); e.current == nil {
+				return nil, false, ErrNoStateAvailable
+			}
+		}
+		e.current.explored, e.prev = true, e.current
+		e.current.addLiveN(-1)
+
+		e.infof("[state] begin: %s", e.current.SourcePosition().String())
+
+		if e.startTime.IsZero() {
+			e.startTime = time.Now()
+		}
 	}
+	state = e.current
 
-	log.Printf("[state] begin: %s", state.Position().String())
-	defer log.Printf("")
+	e.ctx = ctx
+	defer func() { e.ctx = nil }()
 
-	// Loop until new states available or completion.
-	for {
-		if err := e.executeNextInstruction(state); err == ErrNoInstructionAvailable {
-			break
-		} else if err != nil {
-			return state, err
-		} else if state.Done() {
+	// States may be created as terminated (such as a forked panic path) so
+	// they are reported without being executed.
+	if done, err = e.step(ctx, state); err != nil {
+		e.current = nil
+		if ctx.Err() != nil {
+			e.interrupt = state
+		}
+		return state, true, err
+	} else if !done {
+		return state, false, nil
+	}
+	e.current = nil
+
+	e.recordHotSpots(state)
+
+	if e.RecordStateTree && state.Terminated() {
+		e.recordTerminalState(state)
+	}
+	if e.Tracer != nil && state.Terminated() {
+		e.Tracer.OnTerminalState(state)
+	}
+	return state, true, nil
+}
+
+// Current returns the state in progress by StepContext(), if any.
+func (e *Executor) Current() *ExecutionState { return e.current }
+
+// step executes the next instruction of state. Returns true if the state
+// has terminated or stopped on an instruction which created new states.
+func (e *Executor) step(ctx context.Context, state *ExecutionState) (done bool, err error) {
+	if state.Terminated() {
+		return true, nil
+	} else if err := ctx.Err(); err != nil {
+		return true, err
+	} else if e.MaxTime > 0 && time.Since(e.startTime) > e.MaxTime {
+		exhaust(state, "time limit reached")
+		return true, nil
+	} else if e.MaxInstructions > 0 && state.instrN >= e.MaxInstructions {
+		exhaust(state, "instruction limit reached")
+		return true, nil
+	} else if reason := e.overBudget(state); reason != "" {
+		if e.BudgetPolicy == BudgetPolicyDeprioritize {
+			e.deprioritize(state, reason)
+		} else {
+			exhaust(state, reason)
+		}
+		return true, nil
+	}
+	state.instrN++
+
+	if err := e.executeNextInstruction(state); err == ErrNoInstructionAvailable {
+		return true, nil
+	} else if err != nil && !(e.Havoc && e.havocFailedCall(ctx, state, err)) {
+		if IsSolverUnknown(err) && ctx.Err() == nil && e.UnknownPolicy == UnknownPolicyTerminatePath {
+			e.infof("[unknown] %s", err)
+			state.status, state.reason = ExecutionStatusExhausted, fmt.Sprintf("solver query undecided: %s", err)
+			return true, nil
+		} else if e.ErrorPolicy != ErrorPolicyTerminatePath || ctx.Err() != nil || IsSolverUnknown(err) {
+			return true, err
+		}
+		e.infof("[unsupported] %s", err)
+		state.status, state.reason = ExecutionStatusUnsupported, err.Error()
+	}
+	return state.Terminated() || state.Done(), nil
+}
+
+// overBudget returns the reason state has exceeded its solver time or memory
+// budget. Returns blank if within budget or if state has been deprioritized.
+func (e *Executor) overBudget(state *ExecutionState) string {
+	if state.deprioritized {
+		return ""
+	} else if e.MaxSolverTime > 0 && state.solverTime > e.MaxSolverTime {
+		return "solver time limit reached"
+	} else if e.MaxMemory > 0 && state.allocN > e.MaxMemory {
+		return "memory limit reached"
+	}
+	return ""
+}
+
+// deprioritize forks a continuation of state which is held from the searcher
+// until it has no other states available.
+func (e *Executor) deprioritize(state *ExecutionState, reason string) {
+	e.infof("[fork] deprioritize: %s", reason)
+	newState := state.Fork(nil)
+	newState.id = e.nextStateID()
+	newState.deprioritized = true
+	e.addState(newState)
+}
+
+// nextHeldState returns the oldest deprioritized state which has not been
+// explored. Returns nil if none remain.
+func (e *Executor) nextHeldState() *ExecutionState {
+	for len(e.held) > 0 {
+		state := e.held[0]
+		e.held = e.held[1:]
+		if !state.explored {
+			return state
+		}
+	}
+	return nil
+}
+
+// PendingStates returns all states which have not yet been selected for
+// execution, sorted by ID.
+func (e *Executor) PendingStates() []*ExecutionState {
+	var a []*ExecutionState
+	for state := range e.states {
+		if !state.explored {
+			a = append(a, state)
+		}
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].id < a[j].id })
+	return a
+}
+
+// Checkpoint returns the fork paths of the states which have not been fully
+// explored, such as after exploration is cancelled. These are the pending
+// states & the state which was cancelled mid-path, if any. Paths below another
+// returned path are omitted as the subtree of each path includes them. If the
+// searcher is a ReplaySearcher then only paths within its subtree are returned.
+//
+// Exploration can be resumed by another executor, configured identically, by
+// exploring each path with a ReplaySearcher.
+func (e *Executor) Checkpoint() [][]int {
+	states := e.PendingStates()
+	if e.interrupt != nil {
+		states = append(states, e.interrupt)
+	}
+
+	var candidates [][]int
+	for _, state := range states {
+		path := state.forkPath
+		if s, ok := e.Searcher.(*ReplaySearcher); ok {
+			// Ancestors of the replayed subtree resume from its root & states
+			// outside of it are not explored by this executor.
+			if hasForkPathPrefix(s.prefix, path) {
+				path = s.prefix
+			} else if !hasForkPathPrefix(path, s.prefix) {
+				continue
+			}
+		}
+		candidates = append(candidates, path)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return len(candidates[i]) < len(candidates[j]) })
+
+	var paths [][]int
+	for _, path := range candidates {
+		if !hasAnyForkPathPrefix(path, paths) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return compareForkPaths(paths[i], paths[j]) < 0 })
+	return paths
+}
+
+// solve solves the constraints using the executor's solver. The context of
+// the executing state is used, if any, so queries stop once it is cancelled.
+func (e *Executor) solve(constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if e.SimplifyConstraints {
+		constraints = Simplify(constraints)
+	}
+
+	// Timed out queries are retried with a longer timeout each time.
+	timeout := e.QueryTimeout
+	for i := 0; ; i++ {
+		satisfiable, values, err = e.solveOnce(ctx, constraints, arrays, timeout)
+		if err != ErrSolverTimeout || timeout == 0 || i >= e.QueryRetries {
 			break
 		}
+		timeout *= 2
+		e.infof("[solver] retrying query with %s timeout", timeout)
+	}
+
+	// Undecided queries are assumed to be unsatisfiable if requested.
+	if IsSolverUnknown(err) && ctx.Err() == nil && e.UnknownPolicy == UnknownPolicySkipBranch {
+		e.infof("[unknown] skipping: %s", err)
+		return false, nil, nil
 	}
-	return state, nil
+	return satisfiable, values, err
+}
+
+// solveOnce executes a single query with the given timeout, if non-zero.
+// Expiration of the timeout is reported as ErrSolverTimeout.
+func (e *Executor) solveOnce(ctx context.Context, constraints []Expr, arrays []*Array, timeout time.Duration) (satisfiable bool, values [][]byte, err error) {
+	queryCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		queryCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	t := time.Now()
+	satisfiable, values, err = SolveContext(queryCtx, e.Solver, constraints, arrays)
+	d := time.Since(t)
+
+	if err == context.DeadlineExceeded && ctx.Err() == nil {
+		err = ErrSolverTimeout
+	}
+
+	if e.current != nil {
+		e.current.solverTime += d
+	}
+	e.queryStats.Record(len(constraints), satisfiable, d, err)
+	if e.Tracer != nil {
+		e.Tracer.OnSolverQuery(constraints, satisfiable, d, err)
+	}
+	return satisfiable, values, err
+}
+
+// QueryStats returns statistics for the solver queries made by the executor.
+// Cache hits & misses are reported if the executor's solver provides them.
+func (e *Executor) QueryStats() QueryStats {
+	stats := e.queryStats
+	if solver, ok := e.Solver.(SolverStats); ok {
+		other := solver.QueryStats()
+		stats.CacheHitN, stats.CacheMissN = other.CacheHitN, other.CacheMissN
+	}
+	return stats
+}
+
+// recordHotSpots updates fork statistics for the instruction that state
+// stopped on. Only instructions producing multiple states count as branches.
+func (e *Executor) recordHotSpots(state *ExecutionState) {
+	frame := state.Frame()
+	if frame == nil || len(state.children) == 0 {
+		return
+	}
+
+	fs := e.funcHotSpots[frame.fn]
+	if fs == nil {
+		fs = &HotSpot{Func: frame.fn}
+		e.funcHotSpots[frame.fn] = fs
+	}
+	fs.ForkN++
+	fs.StateN += len(state.children)
+
+	if len(state.children) < 2 {
+		return
+	}
+
+	pos := state.SourcePosition()
+	bs := e.branchHotSpots[pos]
+	if bs == nil {
+		bs = &HotSpot{Pos: pos, Func: frame.fn}
+		e.branchHotSpots[pos] = bs
+	}
+	bs.ForkN++
+	bs.StateN += len(state.children)
+}
+
+// HotSpots returns fork statistics for each source branch and for each
+// function, sorted by the number of states produced in descending order.
+// These identify the loops & switches responsible for path explosion.
+func (e *Executor) HotSpots() (branches, funcs []*HotSpot) {
+	for _, hs := range e.branchHotSpots {
+		other := *hs
+		branches = append(branches, &other)
+	}
+	for _, hs := range e.funcHotSpots {
+		other := *hs
+		funcs = append(funcs, &other)
+	}
+	sortHotSpots(branches)
+	sortHotSpots(funcs)
+	return branches, funcs
 }
 
 func (e *Executor) executeNextInstruction(state *ExecutionState) (err error) {
@@ -152,10 +1055,21 @@ func (e *Executor) executeNextInstruction(state *ExecutionState) (err error) {
 	var frame *StackFrame
 	for {
 		frame = state.Frame()
-		if frame == nil {
+		if frame == nil && !state.Terminated() {
+			return e.exitGoroutine(state)
+		} else if frame == nil {
 			return ErrNoInstructionAvailable
 		}
 
+		// Continue unwinding a panic once the frame's deferred calls return.
+		if frame.unwinding {
+			e.unwind(state)
+			if state.Terminated() {
+				return nil
+			}
+			continue
+		}
+
 		// Continue if instruction exists.
 		state.Frame().NextInstr()
 		if state.Frame().Instr() != nil {
@@ -167,12 +1081,25 @@ func (e *Executor) executeNextInstruction(state *ExecutionState) (err error) {
 	// Log each non-debug line of execution.
 	instr := state.Instr()
 	if _, ok := instr.(*ssa.DebugRef); !ok {
-		pos := state.Position()
+		pos := state.SourcePosition()
 		pos.Filename = filepath.Base(pos.Filename)
 		pos.Column = 0
-		log.Printf("[exec] %s: %s (%T)", pos, instr.String(), instr)
+		e.debugf("[exec] %s: %s (%T)", pos, instr.String(), instr)
+	}
+
+	if e.Tracer != nil {
+		e.Tracer.OnInstruction(state, instr)
+	}
+	e.recordCoverage(state, instr)
+
+	if err := e.executeInstr(state, instr); err != nil {
+		return err
 	}
+	return e.limitExprs(state, instr)
+}
 
+// executeInstr dispatches instr to the function which executes its type.
+func (e *Executor) executeInstr(state *ExecutionState, instr ssa.Instruction) error {
 	switch instr := instr.(type) {
 	case *ssa.Alloc:
 		return e.executeAllocInstr(state, instr)
@@ -197,7 +1124,7 @@ func (e *Executor) executeNextInstruction(state *ExecutionState) (err error) {
 	case *ssa.FieldAddr:
 		return e.executeFieldAddrInstr(state, instr)
 	case *ssa.Go:
-		return errors.New("goroutines are not currently supported")
+		return e.executeGoInstr(state, instr)
 	case *ssa.If:
 		return e.executeIfInstr(state, instr)
 	case *ssa.Index:
@@ -261,7 +1188,7 @@ func (e *Executor) executeAllocInstr(state *ExecutionState, instr *ssa.Alloc) er
 	array.zero()
 	state.Frame().bind(instr, addr)
 
-	log.Printf("[alloc] type=%s addr=%d size=%d", instr.Type(), addr.Value, size)
+	e.debugf("[alloc] type=%s addr=%d size=%d", instr.Type(), addr.Value, size)
 
 	return nil
 }
@@ -270,12 +1197,16 @@ func (e *Executor) executeBinOpInstr(state *ExecutionState, instr *ssa.BinOp) er
 	switch typ := instr.X.Type().Underlying().(type) {
 	case *types.Interface:
 		return e.executeBinOpInstrInterface(state, instr)
+	case *types.Pointer, *types.Chan, *types.Map, *types.Signature:
+		return e.executeBinOpInstrPointer(state, instr)
 	case *types.Basic:
 		info := typ.Info()
-		if info&types.IsBoolean != 0 {
+		if typ.Kind() == types.UnsafePointer {
+			return e.executeBinOpInstrPointer(state, instr)
+		} else if info&types.IsBoolean != 0 {
 			return e.executeBinOpInstrBoolean(state, instr)
 		} else if info&types.IsInteger != 0 {
-			return e.executeBinOpInstrInteger(state, instr, types.IsUnsigned == 0)
+			return e.executeBinOpInstrInteger(state, instr, info&types.IsUnsigned == 0)
 		} else if info&types.IsFloat != 0 {
 			return e.executeBinOpInstrFloat(state, instr)
 		} else if info&types.IsComplex != 0 {
@@ -303,6 +1234,112 @@ func (e *Executor) executeBinOpInstrInterface(state *ExecutionState, instr *ssa.
 	}
 }
 
+// executeBinOpInstrPointer compares two pointer, channel, map, or function
+// values by address. Only equality comparisons are valid for these types.
+func (e *Executor) executeBinOpInstrPointer(state *ExecutionState, instr *ssa.BinOp) error {
+	x, y := state.MustEvalAsExpr(instr.X), state.MustEvalAsExpr(instr.Y)
+	switch instr.Op {
+	case token.EQL:
+		state.Frame().bind(instr, newEqExpr(x, y))
+		return nil
+	case token.NEQ:
+		state.Frame().bind(instr, NewNotExpr(newEqExpr(x, y)))
+		return nil
+	default:
+		return errors.New("invalid pointer binop operator")
+	}
+}
+
+// executeBinOpInstrDivision binds the quotient or remainder of x & y. If the
+// divisor may be zero then a panicked state is forked and the continuing
+// state is constrained to a non-zero divisor.
+func (e *Executor) executeBinOpInstrDivision(state *ExecutionState, instr *ssa.BinOp, op BinaryOp, x, y Expr) error {
+	// MinInt / -1 wraps to MinInt in Go so the overflow is only reported on an
+	// additional state, the same as ADD, SUB, & MUL. Remainders cannot overflow.
+	if op == SDIV && e.CheckOverflow {
+		newState, err := forkIfSatisfiable(state, signedOverflowCond(op, x, y, nil))
+		if err != nil {
+			return err
+		} else if newState != nil {
+			e.infof("[fork] integer overflow")
+			newState.status = ExecutionStatusOverflowed
+			newState.reason = fmt.Sprintf("integer overflow: %s", op)
+			e.addState(newState)
+		}
+	}
+
+	isZero := newEqExpr(y, NewConstantExpr(0, ExprWidth(y)))
+	return forkEach(state, []Expr{NewNotExpr(isZero), isZero}, func(state *ExecutionState, i int) {
+		if i == 0 {
+			state.Frame().bind(instr, NewBinaryExpr(op, x, y))
+			return
+		}
+		e.runtimePanic(state, "integer divide by zero")
+	})
+}
+
+// executeBinOpInstrOverflow binds the result of a signed ADD, SUB, or MUL. If
+// CheckOverflow is enabled & the operation may overflow then an additional
+// state is forked that reports the overflow. The continuing state is not
+// constrained since overflow wraps around in Go.
+func (e *Executor) executeBinOpInstrOverflow(state *ExecutionState, instr *ssa.BinOp, op BinaryOp, x, y Expr, signed bool) error {
+	result := NewBinaryExpr(op, x, y)
+	if !e.CheckOverflow || !signed {
+		state.Frame().bind(instr, result)
+		return nil
+	}
+
+	cond := signedOverflowCond(op, x, y, result)
+	if satisfiable, err := isSatisfiable(state, cond); err != nil {
+		return err
+	} else if !satisfiable {
+		state.Frame().bind(instr, result)
+		return nil
+	}
+
+	e.infof("[fork] integer overflow")
+	newState := state.Fork(cond)
+	newState.id = e.nextStateID()
+	newState.status = ExecutionStatusOverflowed
+	newState.reason = fmt.Sprintf("integer overflow: %s", op)
+	e.addState(newState)
+
+	newState = state.Fork(nil)
+	newState.id = e.nextStateID()
+	newState.Frame().bind(instr, result)
+	e.addState(newState)
+
+	return nil
+}
+
+// signedOverflowCond returns an expression that is true if the signed
+// operation of x & y overflows. The result of the operation is passed in and
+// is unused for SDIV, whose only overflow is MinInt / -1.
+func signedOverflowCond(op BinaryOp, x, y, result Expr) Expr {
+	width := ExprWidth(x)
+	zero := NewConstantExpr(0, width)
+
+	switch op {
+	case ADD: // operands have the same sign & result sign differs
+		return newSltExpr(newAndExpr(newXorExpr(result, x), newXorExpr(result, y)), zero)
+	case SUB: // operands have different signs & result sign differs from x
+		return newSltExpr(newAndExpr(newXorExpr(x, y), newXorExpr(x, result)), zero)
+	case MUL: // result does not divide back to y, or -1 * MinInt
+		minInt := NewConstantExpr(1<<(width-1), width)
+		negOne := NewConstantExpr(^uint64(0), width)
+		return newOrExpr(
+			newAndExpr(NewNotExpr(newEqExpr(x, zero)), NewNotExpr(newEqExpr(NewBinaryExpr(SDIV, result, x), y))),
+			newAndExpr(newEqExpr(x, negOne), newEqExpr(y, minInt)),
+		)
+	case SDIV: // MinInt / -1
+		minInt := NewConstantExpr(1<<(width-1), width)
+		negOne := NewConstantExpr(^uint64(0), width)
+		return newAndExpr(newEqExpr(x, minInt), newEqExpr(y, negOne))
+	default:
+		panic(fmt.Sprintf("glee: invalid overflow operation: %s", op))
+	}
+}
+
 func (e *Executor) executeBinOpInstrBoolean(state *ExecutionState, instr *ssa.BinOp) error {
 	x, y := state.Eval(instr.X).(Expr), state.Eval(instr.Y).(Expr)
 	switch instr.Op {
@@ -312,6 +1349,12 @@ func (e *Executor) executeBinOpInstrBoolean(state *ExecutionState, instr *ssa.Bi
 	case token.OR:
 		state.Frame().bind(instr, NewBinaryExpr(OR, x, y))
 		return nil
+	case token.EQL:
+		state.Frame().bind(instr, NewBinaryExpr(EQ, x, y))
+		return nil
+	case token.NEQ:
+		state.Frame().bind(instr, NewBinaryExpr(NE, x, y))
+		return nil
 	default:
 		return errors.New("invalid boolean binop operator")
 	}
@@ -322,28 +1365,21 @@ func (e *Executor) executeBinOpInstrInteger(state *ExecutionState, instr *ssa.Bi
 
 	switch instr.Op {
 	case token.ADD:
-		state.Frame().bind(instr, NewBinaryExpr(ADD, x, y))
-		return nil
+		return e.executeBinOpInstrOverflow(state, instr, ADD, x, y, signed)
 	case token.SUB:
-		state.Frame().bind(instr, NewBinaryExpr(SUB, x, y))
-		return nil
+		return e.executeBinOpInstrOverflow(state, instr, SUB, x, y, signed)
 	case token.MUL:
-		state.Frame().bind(instr, NewBinaryExpr(MUL, x, y))
-		return nil
+		return e.executeBinOpInstrOverflow(state, instr, MUL, x, y, signed)
 	case token.QUO:
 		if signed {
-			state.Frame().bind(instr, NewBinaryExpr(SDIV, x, y))
-		} else {
-			state.Frame().bind(instr, NewBinaryExpr(UDIV, x, y))
+			return e.executeBinOpInstrDivision(state, instr, SDIV, x, y)
 		}
-		return nil
+		return e.executeBinOpInstrDivision(state, instr, UDIV, x, y)
 	case token.REM: // unsigned vs signed
 		if signed {
-			state.Frame().bind(instr, NewBinaryExpr(SREM, x, y))
-		} else {
-			state.Frame().bind(instr, NewBinaryExpr(UREM, x, y))
+			return e.executeBinOpInstrDivision(state, instr, SREM, x, y)
 		}
-		return nil
+		return e.executeBinOpInstrDivision(state, instr, UREM, x, y)
 	case token.AND:
 		state.Frame().bind(instr, NewBinaryExpr(AND, x, y))
 		return nil
@@ -406,7 +1442,33 @@ func (e *Executor) executeBinOpInstrInteger(state *ExecutionState, instr *ssa.Bi
 }
 
 func (e *Executor) executeBinOpInstrFloat(state *ExecutionState, instr *ssa.BinOp) error {
-	return errors.New("floating-point operations are not supported")
+	x, y := state.MustEvalAsExpr(instr.X), state.MustEvalAsExpr(instr.Y)
+
+	switch instr.Op {
+	case token.ADD:
+		state.Frame().bind(instr, NewFPBinaryExpr(FADD, x, y))
+	case token.SUB:
+		state.Frame().bind(instr, NewFPBinaryExpr(FSUB, x, y))
+	case token.MUL:
+		state.Frame().bind(instr, NewFPBinaryExpr(FMUL, x, y))
+	case token.QUO:
+		state.Frame().bind(instr, NewFPBinaryExpr(FDIV, x, y))
+	case token.EQL:
+		state.Frame().bind(instr, NewFPBinaryExpr(FEQ, x, y))
+	case token.NEQ:
+		state.Frame().bind(instr, NewNotExpr(NewFPBinaryExpr(FEQ, x, y)))
+	case token.LSS:
+		state.Frame().bind(instr, NewFPBinaryExpr(FLT, x, y))
+	case token.LEQ:
+		state.Frame().bind(instr, NewFPBinaryExpr(FLE, x, y))
+	case token.GTR:
+		state.Frame().bind(instr, NewFPBinaryExpr(FLT, y, x))
+	case token.GEQ:
+		state.Frame().bind(instr, NewFPBinaryExpr(FLE, y, x))
+	default:
+		return errors.New("invalid floating-point binop operator")
+	}
+	return nil
 }
 
 func (e *Executor) executeBinOpInstrComplex(state *ExecutionState, instr *ssa.BinOp) error {
@@ -435,30 +1497,11 @@ func (e *Executor) executeBinOpInstrString(state *ExecutionState, instr *ssa.Bin
 func (e *Executor) executeBinOpInstrStringADD(state *ExecutionState, instr *ssa.BinOp) error {
 	x, y := state.Eval(instr.X).(*Array), state.Eval(instr.Y).(*Array)
 
-	log.Printf("[binop] str-add x=%s y=%s", x, y)
-
-	// Return either x or y if the other is zero length.
-	if x.Size == 0 {
-		state.Frame().bind(instr, y)
-		return nil
-	} else if y.Size == 0 {
-		state.Frame().bind(instr, x)
-		return nil
-	}
-
-	// If x & y are non-blank then create a new array and copy all bytes.
-	array := NewArray(0, x.Size+y.Size)
-	for i := uint(0); i < x.Size; i++ {
-		index := NewConstantExpr64(uint64(i))
-		array.storeByte(index, x.selectByte(index))
-	}
-	for i := uint(0); i < y.Size; i++ {
-		array.storeByte(NewConstantExpr64(uint64(x.Size+i)), y.selectByte(NewConstantExpr64(uint64(i))))
-	}
-
-	// Bind new array to instruction.
-	state.Frame().bind(instr, array)
+	e.debugf("[binop] str-add x=%s y=%s", x, y)
 
+	// Bytes are only copied once the result is accessed so strings built in
+	// a loop are not copied on every iteration.
+	state.Frame().bind(instr, concatArray(x, y))
 	return nil
 }
 
@@ -551,22 +1594,56 @@ func (e *Executor) executeCallInstr(state *ExecutionState, instr *ssa.Call) erro
 		return registered(state, instr)
 	}
 
+	// Resolve function values & interface receivers which are nil or symbolic.
+	if ok, err := e.resolveCallee(state, instr); err != nil || ok {
+		return err
+	}
+
 	// Lookup if function is registered with executor and defer execution.
 	fn, args := state.ExtractCall(instr)
-	path, name := fn.Pkg.Pkg.Path(), fn.Name()
-	if registered, ok := e.fns[funcKey{path, name}]; ok {
+	if registered := e.registeredFn(fn); registered != nil {
 		return registered(state, instr)
 	}
 
+	// Approximate calls to functions annotated to be skipped.
+	if a, err := e.annotations(fn); err != nil {
+		return err
+	} else if a != nil && a.skip {
+		e.havoc(state, instr, fn, "skipped by annotation")
+		return nil
+	}
+
+	// Instantiate the summary of pure functions instead of executing them.
+	if e.Summarize {
+		if summary, err := e.summary(fn); err != nil {
+			return err
+		} else if summary != nil {
+			return summary.apply(state, instr, args)
+		}
+	}
+
+	// Approximate calls to functions without a body, such as assembly.
+	if e.Havoc && fn.Blocks == nil {
+		e.havoc(state, instr, fn, "function body unavailable")
+		return nil
+	}
+
+	return e.callFunction(state, instr, fn, args)
+}
+
+// callFunction forks a child of state which executes fn with args. Execution
+// continues after instr once fn returns.
+func (e *Executor) callFunction(state *ExecutionState, instr *ssa.Call, fn *ssa.Function, args []Binding) error {
 	// Move execution to the new frame & bind arguments.
-	log.Printf("[fork] call: %s %s", path, name)
+	e.infof("[fork] call: %s", fn)
 	newState := state.Fork(nil)
 	newState.id = e.nextStateID()
 	newState.Push(fn)
 	for i, arg := range args {
 		newState.Frame().bind(fn.Params[i], arg)
 	}
-	e.Searcher.AddState(newState)
+	newState.bindFreeVars(state.freeVarBindings(instr))
+	e.addState(newState)
 
 	return nil
 }
@@ -598,7 +1675,7 @@ func (e *Executor) executeConvertInstr(state *ExecutionState, instr *ssa.Convert
 		case types.Byte:
 			return e.executeConvertInstrByteSliceToString(state, instr)
 		case types.Rune:
-			return fmt.Errorf("glee.Executor: rune-to-string conversion is not supported")
+			return e.executeConvertInstrRuneSliceToString(state, instr)
 		default:
 			return fmt.Errorf("glee.Executor: unsupported slice conversion: %s", srcType.Elem())
 		}
@@ -606,7 +1683,7 @@ func (e *Executor) executeConvertInstr(state *ExecutionState, instr *ssa.Convert
 	case *types.Basic:
 		if srcType.Info()&types.IsInteger != 0 {
 			if dstType, ok := dstType.(*types.Basic); ok && dstType.Kind() == types.String {
-				return fmt.Errorf("glee.Executor: int-to-string conversion is not supported")
+				return e.executeConvertInstrIntToString(state, instr)
 			}
 		}
 
@@ -615,7 +1692,7 @@ func (e *Executor) executeConvertInstr(state *ExecutionState, instr *ssa.Convert
 			case *types.Slice:
 				switch dstType.Elem().(*types.Basic).Kind() {
 				case types.Rune:
-					return fmt.Errorf("glee.Executor: string-to-rune conversion is not supported")
+					return e.executeConvertInstrStringToRuneSlice(state, instr)
 				case types.Byte:
 					return e.executeConvertInstrStringToByteSlice(state, instr)
 				}
@@ -628,14 +1705,32 @@ func (e *Executor) executeConvertInstr(state *ExecutionState, instr *ssa.Convert
 			return fmt.Errorf("glee.Executor: unsupported string conversion: %s", dstType)
 		}
 
+		// An unsafe.Pointer is represented by its address so converting it to
+		// a uintptr or to another pointer type does not change its value.
+		// Addresses converted back to pointers are validated first.
 		if srcType.Kind() == types.UnsafePointer {
-			return fmt.Errorf("glee.Executor: unsafe.Pointer conversion is not supported")
+			state.Frame().bind(instr, state.MustEvalAsExpr(instr.X))
+			return nil
+		} else if dstType, ok := dstType.(*types.Basic); ok && dstType.Kind() == types.UnsafePointer {
+			return e.executeConvertInstrUintptrToPointer(state, instr)
+		}
+
+		// Booleans are zero-extended to integers & integers are compared
+		// against zero to produce booleans.
+		if dstType, ok := dstType.(*types.Basic); ok {
+			if srcType.Info()&types.IsBoolean != 0 && dstType.Info()&types.IsInteger != 0 {
+				state.Frame().bind(instr, newZExtExpr(state.MustEvalAsExpr(instr.X), e.Sizeof(dstType)))
+				return nil
+			} else if srcType.Info()&types.IsInteger != 0 && dstType.Info()&types.IsBoolean != 0 {
+				state.Frame().bind(instr, NewNotExpr(NewIsZeroExpr(state.MustEvalAsExpr(instr.X))))
+				return nil
+			}
 		}
 
 		if srcType.Info()&types.IsComplex != 0 {
 			return fmt.Errorf("glee.Executor: complex type conversion is not supported")
-		} else if srcType.Info()&types.IsFloat != 0 {
-			return fmt.Errorf("glee.Executor: floating point type conversion is not supported")
+		} else if srcType.Info()&types.IsFloat != 0 || isFloatType(dstType) {
+			return e.executeConvertInstrFloat(state, instr)
 		} else if (srcType.Info()&types.IsInteger == 0) && (srcType.Info()&types.IsUnsigned == 0) {
 			return fmt.Errorf("glee.Executor: unsupported basic type conversion: %s", srcType)
 		}
@@ -653,36 +1748,12 @@ func (e *Executor) executeConvertInstr(state *ExecutionState, instr *ssa.Convert
 func (e *Executor) executeConvertInstrByteSliceToString(state *ExecutionState, instr *ssa.Convert) error {
 	hdr := state.Eval(instr.X).(*Array)
 
-	log.Printf("[convert] []byte-to-string: %s", hdr)
-
-	// Find data using slice header pointer. Must be a constant expression.
-	ptr, ok := state.selectIntAt(hdr, 0).(*ConstantExpr)
-	if !ok {
-		return fmt.Errorf("glee.Executor: cannot read non-constant SliceHeader.Data field")
-	}
-
-	// Find length of slice.
-	length, ok := state.selectIntAt(hdr, 1).(*ConstantExpr)
-	if !ok {
-		return fmt.Errorf("glee.Executor: cannot read non-constant SliceHeader.Len field")
-	}
-
-	// Find the array at the given address.
-	base, src := state.findAllocContainingAddr(ptr)
-	if src == nil {
-		return fmt.Errorf("glee.Executor: byte slice data allocation not found: %d", ptr.Value)
-	}
-	offset := ptr.Value - base.Value
-
-	// Copy values from byte slice data to new array.
-	dst := NewArray(0, uint(length.Value))
-	for i := uint64(0); i < length.Value; i++ {
-		dst.storeByte(NewConstantExpr64(i), src.selectByte(NewConstantExpr64(offset+i)))
-	}
+	e.debugf("[convert] []byte-to-string: %s", hdr)
 
 	// Bind new array to instruction.
-	state.Frame().bind(instr, dst)
-	return nil
+	return e.forkSliceBytes(state, hdr, func(state *ExecutionState, p *Array) {
+		state.Frame().bind(instr, p)
+	})
 }
 
 func (e *Executor) executeConvertInstrStringToByteSlice(state *ExecutionState, instr *ssa.Convert) error {
@@ -691,13 +1762,10 @@ func (e *Executor) executeConvertInstrStringToByteSlice(state *ExecutionState, i
 
 	// Build underlying array and copy bytes.
 	addr, array := state.Alloc(x.Size)
-	for i := uint64(0); i < uint64(x.Size); i++ {
-		index := NewConstantExpr64(i)
-		array.storeByte(index, x.selectByte(index))
-	}
+	array.Updates = newArrayUpdates(x.bytes())
 
 	// Build slice header.
-	_, hdr := state.Alloc(e.PointerWidth() * 3)
+	_, hdr := state.Alloc((e.PointerWidth() / 8) * 3)
 	hdr = state.storeIntAt(hdr, 0, addr)   // data
 	hdr = state.storeIntAt(hdr, 1, length) // len
 	hdr = state.storeIntAt(hdr, 2, length) // cap
@@ -709,8 +1777,172 @@ func (e *Executor) executeConvertInstrStringToByteSlice(state *ExecutionState, i
 	return nil
 }
 
-func (e *Executor) executeDeferInstr(state *ExecutionState, instr *ssa.Defer) error {
-	return fmt.Errorf("glee.Executor: defer is not supported")
+// executeConvertInstrFloat converts to or from a floating-point type.
+func (e *Executor) executeConvertInstrFloat(state *ExecutionState, instr *ssa.Convert) error {
+	srcType := instr.X.Type().Underlying().(*types.Basic)
+	dstType, ok := instr.Type().Underlying().(*types.Basic)
+	if !ok || dstType.Info()&types.IsComplex != 0 {
+		return fmt.Errorf("glee.Executor: unsupported floating point conversion: %s", instr.Type())
+	}
+
+	x, width := state.MustEvalAsExpr(instr.X), e.Sizeof(dstType)
+	switch {
+	case srcType.Info()&types.IsFloat != 0 && dstType.Info()&types.IsFloat != 0:
+		if ExprWidth(x) != width {
+			x = NewFPCastExpr(FPEXT, x, width)
+		}
+		state.Frame().bind(instr, x)
+	case srcType.Info()&types.IsFloat != 0 && dstType.Info()&types.IsUnsigned != 0:
+		state.Frame().bind(instr, NewFPCastExpr(FPTOUI, x, width))
+	case srcType.Info()&types.IsFloat != 0:
+		state.Frame().bind(instr, NewFPCastExpr(FPTOSI, x, width))
+	case srcType.Info()&types.IsUnsigned != 0:
+		state.Frame().bind(instr, NewFPCastExpr(UITOFP, x, width))
+	default:
+		state.Frame().bind(instr, NewFPCastExpr(SITOFP, x, width))
+	}
+	return nil
+}
+
+// executeConvertInstrIntToString converts a rune to its UTF-8 encoded string.
+// A state is forked for each encoded length. Invalid runes encode to U+FFFD.
+func (e *Executor) executeConvertInstrIntToString(state *ExecutionState, instr *ssa.Convert) error {
+	signed := instr.X.Type().Underlying().(*types.Basic).Info()&types.IsUnsigned == 0
+	r := NewCastExpr(state.MustEvalAsExpr(instr.X), Width64, signed)
+
+	for _, c := range encodeRuneCases(r) {
+		newState, err := forkIfSatisfiable(state, c.cond)
+		if err != nil {
+			return err
+		} else if newState == nil {
+			continue
+		}
+
+		bytes := c.bytes
+		if c.size == 0 {
+			bytes = runeErrorBytes()
+		}
+
+		dst := NewArray(0, uint(len(bytes)))
+		for i, b := range bytes {
+			dst.storeByte(NewConstantExpr64(uint64(i)), b)
+		}
+		newState.Frame().bind(instr, dst)
+		e.addState(newState)
+	}
+	return nil
+}
+
+// executeConvertInstrRuneSliceToString encodes a rune slice as a UTF-8 string.
+// A state is forked for every satisfiable combination of encoded lengths.
+// Invalid runes encode to U+FFFD.
+func (e *Executor) executeConvertInstrRuneSliceToString(state *ExecutionState, instr *ssa.Convert) error {
+	src, offset, n, err := state.sliceData(state.Eval(instr.X).(*Array))
+	if err != nil {
+		return fmt.Errorf("glee.Executor: %s", err)
+	}
+
+	runes := make([]Expr, n)
+	for i := range runes {
+		index := NewConstantExpr64(offset + uint64(i)*4)
+		runes[i] = src.Select(index, Width32, e.IsLittleEndian())
+	}
+	return e.forkRuneString(state, instr, runes, NewBoolConstantExpr(true), nil)
+}
+
+// forkRuneString recursively encodes the first rune in runes. Unsatisfiable
+// encodings are discarded early. Once all runes are encoded, a state is forked
+// with the combined condition and the encoded bytes are bound as a string.
+func (e *Executor) forkRuneString(state *ExecutionState, instr *ssa.Convert, runes []Expr, cond Expr, bytes []Expr) error {
+	if len(runes) > 0 {
+		for _, c := range encodeRuneCases(runes[0]) {
+			cond := newAndExpr(cond, c.cond)
+			if satisfiable, err := isSatisfiable(state, cond); err != nil {
+				return err
+			} else if !satisfiable {
+				continue
+			}
+
+			encoded := c.bytes
+			if c.size == 0 {
+				encoded = runeErrorBytes()
+			}
+			if err := e.forkRuneString(state, instr, runes[1:], cond, append(bytes[:len(bytes):len(bytes)], encoded...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	newState := state.Fork(cond)
+	newState.id = e.nextStateID()
+
+	dst := NewArray(0, uint(len(bytes)))
+	for i, b := range bytes {
+		dst.storeByte(NewConstantExpr64(uint64(i)), b)
+	}
+	newState.Frame().bind(instr, dst)
+	e.addState(newState)
+	return nil
+}
+
+// executeConvertInstrStringToRuneSlice decodes a string into a rune slice.
+// A state is forked for every satisfiable decoding of the string's bytes.
+func (e *Executor) executeConvertInstrStringToRuneSlice(state *ExecutionState, instr *ssa.Convert) error {
+	x := state.Eval(instr.X).(*Array)
+	if err := e.forkRuneSlice(state, instr, x, 0, NewBoolConstantExpr(true), nil); err != nil {
+		return err
+	} else if !state.Forked() {
+		return ErrNoValidUTF8Decoding
+	}
+	return nil
+}
+
+// forkRuneSlice recursively decodes the rune at offset in s. Unsatisfiable
+// decodings are discarded early. Once all bytes are decoded, a state is forked
+// with the combined condition and the decoded runes are bound as a slice.
+func (e *Executor) forkRuneSlice(state *ExecutionState, instr *ssa.Convert, s *Array, offset uint, cond Expr, runes []Expr) error {
+	if offset < s.Size {
+		for _, c := range decodeRuneCases(s, offset, e.AssumeValidUTF8) {
+			cond := newAndExpr(cond, c.cond)
+			if satisfiable, err := isSatisfiable(state, cond); err != nil {
+				return err
+			} else if !satisfiable {
+				continue
+			}
+
+			if err := e.forkRuneSlice(state, instr, s, offset+c.size, cond, append(runes[:len(runes):len(runes)], c.r)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	newState := state.Fork(cond)
+	newState.id = e.nextStateID()
+
+	// Build underlying array of 32-bit runes. Empty slices have a nil data pointer.
+	addr := NewConstantExpr(0, e.PointerWidth())
+	if len(runes) > 0 {
+		var array *Array
+		addr, array = newState.Alloc(uint(len(runes)) * 4)
+		for i, r := range runes {
+			array = array.Store(NewConstantExpr64(uint64(i)*4), r, e.IsLittleEndian())
+		}
+		newState.heap = newState.heap.Set(array.ID, array)
+	}
+
+	// Build slice header.
+	length := NewConstantExpr(uint64(len(runes)), e.PointerWidth())
+	_, hdr := newState.Alloc((e.PointerWidth() * 3) / 8)
+	hdr = newState.storeIntAt(hdr, 0, addr)   // data
+	hdr = newState.storeIntAt(hdr, 1, length) // len
+	hdr = newState.storeIntAt(hdr, 2, length) // cap
+	newState.heap = newState.heap.Set(hdr.ID, hdr)
+
+	newState.Frame().bind(instr, hdr)
+	e.addState(newState)
+	return nil
 }
 
 func (e *Executor) executeExtractInstr(state *ExecutionState, instr *ssa.Extract) error {
@@ -720,7 +1952,17 @@ func (e *Executor) executeExtractInstr(state *ExecutionState, instr *ssa.Extract
 }
 
 func (e *Executor) executeFieldInstr(state *ExecutionState, instr *ssa.Field) error {
-	return fmt.Errorf("glee.Executor: *ssa.Field instruction not supported")
+	structType := instr.X.Type().Underlying().(*types.Struct)
+	offsets := e.Sizes().Offsetsof(structFields(structType))
+	fieldOffset := NewConstantExpr(uint64(offsets[instr.Field]), e.PointerWidth())
+
+	x := state.Eval(instr.X).(*Array)
+	value, err := state.extract(x, fieldOffset, instr.Type())
+	if err != nil {
+		return err
+	}
+	state.Frame().bind(instr, value)
+	return nil
 }
 
 func (e *Executor) executeFieldAddrInstr(state *ExecutionState, instr *ssa.FieldAddr) error {
@@ -732,10 +1974,11 @@ func (e *Executor) executeFieldAddrInstr(state *ExecutionState, instr *ssa.Field
 	offsets := e.Sizes().Offsetsof(structFields(structType))
 	fieldOffset := offsets[instr.Field]
 
-	// Find base address of the structure. Must be a constrant address currently.
-	base := state.Eval(instr.X).(*ConstantExpr)
+	// Find base address of the structure. This may be symbolic in which case
+	// it is resolved when the field is loaded or stored.
+	base := state.MustEvalAsExpr(instr.X)
 
-	log.Printf("[field] base=%d offset=%d", base.Value, fieldOffset)
+	e.debugf("[field] base=%s offset=%d", base, fieldOffset)
 
 	// Compute offset from base address to field address.
 	expr := NewBinaryExpr(ADD, base, NewConstantExpr(uint64(fieldOffset), e.PointerWidth()))
@@ -745,36 +1988,103 @@ func (e *Executor) executeFieldAddrInstr(state *ExecutionState, instr *ssa.Field
 }
 
 func (e *Executor) executeIndexInstr(state *ExecutionState, instr *ssa.Index) error {
-	return fmt.Errorf("glee.Executor: *ssa.Index instruction not supported")
+	typ, ok := instr.X.Type().Underlying().(*types.Array)
+	if !ok {
+		return fmt.Errorf("glee.Executor: unexpected Index.X type: %T", instr.X.Type().Underlying())
+	}
+
+	x := state.Eval(instr.X).(*Array)
+	index := state.MustEvalAsExpr(instr.Index)
+	length := NewConstantExpr(uint64(typ.Len()), e.PointerWidth())
+
+	var extractErr error
+	if err := e.forkIndexInRange(state, instr.Index, index, length, func(state *ExecutionState) {
+		offset := NewCastExpr(index, e.PointerWidth(), isSignedIndex(instr.Index))
+		indexBytes := newMulExpr(offset, NewConstantExpr(uint64(e.Sizeof(typ.Elem())/8), e.PointerWidth()))
+		value, err := state.extract(x, indexBytes, instr.Type())
+		if err != nil {
+			extractErr = err
+			return
+		}
+		state.Frame().bind(instr, value)
+	}); err != nil {
+		return err
+	}
+	return extractErr
 }
 
 func (e *Executor) executeIndexAddrInstr(state *ExecutionState, instr *ssa.IndexAddr) error {
 	switch typ := instr.X.Type().(type) {
-	case *types.Array:
-		return e.executeIndexAddrInstrArray(state, instr, typ)
+	case *types.Pointer:
+		if typ, ok := typ.Elem().Underlying().(*types.Array); ok {
+			return e.executeIndexAddrInstrArray(state, instr, typ)
+		}
 	case *types.Slice:
 		return e.executeIndexAddrInstrSlice(state, instr, typ)
-	default:
-		return fmt.Errorf("glee.Executor: unexpected IndexAddr.X type: %T", typ)
 	}
+	return fmt.Errorf("glee.Executor: unexpected IndexAddr.X type: %T", instr.X.Type())
 }
 
+// executeIndexAddrInstrArray computes the address of an element from a pointer
+// to an array, such as an array allocated for a composite literal.
 func (e *Executor) executeIndexAddrInstrArray(state *ExecutionState, instr *ssa.IndexAddr, typ *types.Array) error {
-	x := state.Eval(instr.X).(*Array)
+	base := state.MustEvalAsExpr(instr.X)
 	index := state.MustEvalAsExpr(instr.Index)
+	length := NewConstantExpr(uint64(typ.Len()), e.PointerWidth())
 
-	indexBytes := newMulExpr(index, NewConstantExpr(uint64(e.Sizeof(typ.Elem())/8), e.PointerWidth()))
-	state.Frame().bind(instr, newAddExpr(NewConstantExpr(x.ID, e.PointerWidth()), indexBytes))
-	return nil
+	return e.forkIndexInRange(state, instr.Index, index, length, func(state *ExecutionState) {
+		indexBytes := newMulExpr(newZExtExpr(index, e.PointerWidth()), NewConstantExpr(uint64(e.Sizeof(typ.Elem())/8), e.PointerWidth()))
+		state.Frame().bind(instr, newAddExpr(base, indexBytes))
+	})
 }
 
 func (e *Executor) executeIndexAddrInstrSlice(state *ExecutionState, instr *ssa.IndexAddr, typ *types.Slice) error {
 	x := state.Eval(instr.X).(*Array)
 	index := state.MustEvalAsExpr(instr.Index)
 
-	indexBytes := newMulExpr(index, NewConstantExpr(uint64(e.Sizeof(typ.Elem())/8), e.PointerWidth()))
-	state.Frame().bind(instr, newAddExpr(state.selectIntAt(x, 0), indexBytes))
-	return nil
+	return e.forkIndexInRange(state, instr.Index, index, state.selectIntAt(x, 1), func(state *ExecutionState) {
+		indexBytes := newMulExpr(newZExtExpr(index, e.PointerWidth()), NewConstantExpr(uint64(e.Sizeof(typ.Elem())/8), e.PointerWidth()))
+		state.Frame().bind(instr, newAddExpr(state.selectIntAt(x, 0), indexBytes))
+	})
+}
+
+// forkIndexInRange calls fn on the state in which index is within [0, length).
+// If index may be out of range then state, or a forked child state, panics
+// the same as the Go runtime. Otherwise, fn is called on state directly.
+//
+// The index may be of any integer type so it is compared at the wider of its
+// width & the width of length. Within fn, index fits within length's width.
+func (e *Executor) forkIndexInRange(state *ExecutionState, v ssa.Value, index, length Expr, fn func(state *ExecutionState)) error {
+	// Negative indices are out of range once compared as unsigned.
+	width := ExprWidth(length)
+	if w := ExprWidth(index); w > width {
+		width = w
+	}
+	inRange := newUltExpr(NewCastExpr(index, width, isSignedIndex(v)), newZExtExpr(length, width))
+
+	if IsConstantTrue(inRange) {
+		fn(state)
+		return nil
+	} else if outOfRange, err := isSatisfiable(state, NewNotExpr(inRange)); err != nil {
+		return err
+	} else if !outOfRange {
+		fn(state)
+		return nil
+	}
+
+	return forkEach(state, []Expr{inRange, NewNotExpr(inRange)}, func(state *ExecutionState, i int) {
+		if i == 0 {
+			fn(state)
+			return
+		}
+		e.runtimePanic(state, "index out of range")
+	})
+}
+
+// isSignedIndex returns true if v is an index of a signed integer type.
+func isSignedIndex(v ssa.Value) bool {
+	basic, ok := v.Type().Underlying().(*types.Basic)
+	return !ok || basic.Info()&types.IsUnsigned == 0
 }
 
 func (e *Executor) executeLookupInstr(state *ExecutionState, instr *ssa.Lookup) error {
@@ -796,50 +2106,134 @@ func (e *Executor) executeLookupInstrString(state *ExecutionState, instr *ssa.Lo
 	return nil
 }
 
-func (e *Executor) executeLookupInstrMap(state *ExecutionState, instr *ssa.Lookup) error {
-	return fmt.Errorf("glee.Executor: map lookup is not supported")
-}
-
-func (e *Executor) executeMakeChanInstr(state *ExecutionState, instr *ssa.MakeChan) error {
-	return fmt.Errorf("glee.Executor: channels are not supported")
-}
-
-func (e *Executor) executeMakeClosureInstr(state *ExecutionState, instr *ssa.MakeClosure) error {
-	return fmt.Errorf("glee.Executor: closures are not supported")
-}
-
 func (e *Executor) executeMakeInterfaceInstr(state *ExecutionState, instr *ssa.MakeInterface) error {
-	typeID := uint64(e.typeIDs[instr.X.Type()])
+	typeID := uint64(e.typeID(instr.X.Type()))
 
 	// Build interface element that contains two pointers.
 	// One pointer to the type and one to the data.
+	// Values which are not expressions, such as structs & strings, are
+	// copied to the heap and referenced by address.
+	var data Expr
+	if isBoxedType(instr.X.Type()) {
+		data = state.box(state.Eval(instr.X).(*Array))
+	} else {
+		data = state.MustEvalAsExpr(instr.X)
+	}
+
 	_, iface := state.Alloc((e.PointerWidth() * 2) / 8)
 	iface = state.storeIntAt(iface, 0, NewConstantExpr(typeID, e.PointerWidth()))
-	iface = state.storeIntAt(iface, 1, state.MustEvalAsExpr(instr.X))
+	iface = state.storeIntAt(iface, 1, data)
 	state.heap = state.heap.Set(iface.ID, iface)
 
 	state.Frame().bind(instr, iface)
 	return nil
 }
 
-func (e *Executor) executeMakeMapInstr(state *ExecutionState, instr *ssa.MakeMap) error {
-	return fmt.Errorf("glee.Executor: map instantiation is not supported")
+func (e *Executor) executeMakeSliceInstr(state *ExecutionState, instr *ssa.MakeSlice) error {
+	// Evaluate arguments. Symbolic sizes are concretized first.
+	length, lok := state.EvalAsConstantExpr(instr.Len)
+	capacity, cok := state.EvalAsConstantExpr(instr.Cap)
+	if !lok || !cok {
+		return e.executeMakeSliceInstrSymbolic(state, instr)
+	} else if capacity == nil {
+		capacity = length
+	}
+	e.makeSlice(state, instr, length, capacity)
+	return nil
 }
 
-func (e *Executor) executeMakeSliceInstr(state *ExecutionState, instr *ssa.MakeSlice) error {
+// executeMakeSliceInstrSymbolic forks a panicking state for symbolic sizes
+// which are out of range. Otherwise, the underlying array is allocated to the
+// largest feasible capacity while the slice header keeps the symbolic length
+// & capacity.
+func (e *Executor) executeMakeSliceInstrSymbolic(state *ExecutionState, instr *ssa.MakeSlice) error {
+	length := state.MustEvalAsExpr(instr.Len)
+	capacity := state.MustEvalAsExpr(instr.Cap)
+	if capacity == nil {
+		capacity = length
+	}
+	zero := NewConstantExpr(0, ExprWidth(length))
+
+	// Fork states for a negative length or a capacity less than the length.
+	var valid Expr = NewBoolConstantExpr(true)
+	for _, c := range []struct {
+		cond   Expr
+		reason string
+	}{
+		{NewBinaryExpr(SLT, length, zero), "makeslice: len out of range"},
+		{NewBinaryExpr(SLT, capacity, length), "makeslice: cap out of range"},
+	} {
+		newState, err := forkIfSatisfiable(state, NewBinaryExpr(AND, valid, c.cond))
+		if err != nil {
+			return err
+		} else if newState != nil {
+			e.runtimePanic(newState, c.reason)
+			e.addState(newState)
+		}
+		valid = NewBinaryExpr(AND, valid, NewNotExpr(c.cond))
+	}
+
 	typ := instr.Type().(*types.Slice)
+	elemSize := e.Sizeof(typ.Elem()) / 8
+	return e.forkSymbolicAlloc(state, valid, capacity, elemSize, func(state *ExecutionState, max uint64) {
+		addr, array := state.Alloc(uint(max) * elemSize)
+		array.zero()
+
+		_, hdr := state.Alloc((e.PointerWidth() / 8) * 3)
+		hdr = state.storeIntAt(hdr, 0, addr)     // data
+		hdr = state.storeIntAt(hdr, 1, length)   // len
+		hdr = state.storeIntAt(hdr, 2, capacity) // cap
+		state.Frame().bind(instr, hdr)
+	})
+}
+
+// forkSymbolicAlloc forks a state for allocating n elements of elemSize bytes
+// where n is symbolic. The constraint valid must ensure n is non-negative.
+// A state is exhausted if n may exceed Limits.MaxSymbolicAllocSize or
+// MaxAllocSize(). Otherwise, fn is called on a state where n is within the
+// limit with the largest feasible value of n so the allocation can hold
+// every possible size.
+func (e *Executor) forkSymbolicAlloc(state *ExecutionState, valid Expr, n Expr, elemSize uint, fn func(state *ExecutionState, max uint64)) error {
+	limit := uint64(e.MaxSymbolicAllocSize)
+	if limit == 0 {
+		limit = DefaultMaxSymbolicAllocSize
+	}
+	if max := uint64(e.MaxAllocSize()); limit > max {
+		limit = max
+	}
+	if elemSize > 0 {
+		limit /= uint64(elemSize)
+	}
+	tooLarge := NewBinaryExpr(UGT, n, NewConstantExpr(limit, ExprWidth(n)))
+
+	// Exhaust paths which require an allocation larger than the limit.
+	if newState, err := forkIfSatisfiable(state, NewBinaryExpr(AND, valid, tooLarge)); err != nil {
+		return err
+	} else if newState != nil {
+		exhaust(newState, "allocation size limit reached")
+		e.addState(newState)
+	}
 
-	// Evaluate arguments.
-	length, ok := state.EvalAsConstantExpr(instr.Len)
-	if !ok {
-		return fmt.Errorf("glee.Executor: make slice len must be a constant")
+	newState, err := forkIfSatisfiable(state, NewBinaryExpr(AND, valid, NewNotExpr(tooLarge)))
+	if err != nil || newState == nil {
+		return err
 	}
-	capacity, ok := state.EvalAsConstantExpr(instr.Cap)
-	if !ok {
-		return fmt.Errorf("glee.Executor: make slice cap must be a constant")
-	} else if capacity == nil {
-		capacity = length
+
+	_, max, err := e.GetRange(newState, n)
+	if err != nil {
+		return err
 	}
+	e.debugf("[alloc] symbolic size %s, max=%d", n, max)
+
+	fn(newState, max)
+	e.addState(newState)
+	return nil
+}
+
+// makeSlice allocates a zeroed slice with a concrete length & capacity and
+// binds its header to instr.
+func (e *Executor) makeSlice(state *ExecutionState, instr *ssa.MakeSlice, length, capacity *ConstantExpr) {
+	typ := instr.Type().(*types.Slice)
 
 	// Build underlying array & initialize to zero value.
 	elemSizeBytes := (e.Sizeof(typ.Elem()) / 8)
@@ -847,43 +2241,59 @@ func (e *Executor) executeMakeSliceInstr(state *ExecutionState, instr *ssa.MakeS
 	array.zero()
 
 	// Build slice header.
-	_, hdr := state.Alloc(e.PointerWidth() * 3)
+	_, hdr := state.Alloc((e.PointerWidth() / 8) * 3)
 	hdr = state.storeIntAt(hdr, 0, addr)     // data
 	hdr = state.storeIntAt(hdr, 1, length)   // len
 	hdr = state.storeIntAt(hdr, 2, capacity) // cap
 
 	// Bind header to instruction.
 	state.Frame().bind(instr, hdr)
-
-	return nil
-}
-
-func (e *Executor) executeMapUpdateInstr(state *ExecutionState, instr *ssa.MapUpdate) error {
-	return fmt.Errorf("glee.Executor: map update is not supported")
 }
 
+// executeNextInstr advances a range iterator. For strings, a state is forked
+// for each possible decoding of the rune at the iterator's offset.
 func (e *Executor) executeNextInstr(state *ExecutionState, instr *ssa.Next) error {
-	return fmt.Errorf("glee.Executor: range next is not supported")
-}
+	if !instr.IsString {
+		return e.executeNextInstrMap(state, instr)
+	}
 
-func (e *Executor) executePanicInstr(state *ExecutionState, instr *ssa.Panic) error {
-	return fmt.Errorf("glee.Executor: panic is not supported")
-}
+	iter := state.Eval(instr.Iter).(Tuple)
+	s, offset := iter[0].(*Array), uint(iter[1].(*ConstantExpr).Value)
+	width := e.Sizeof(types.Typ[types.Int])
 
-func (e *Executor) executeRangeInstr(state *ExecutionState, instr *ssa.Range) error {
-	return fmt.Errorf("glee.Executor: range is not supported")
-}
+	// Mark iterator as done once all bytes are consumed.
+	if offset >= s.Size {
+		state.Frame().bind(instr, Tuple{NewBoolConstantExpr(false), NewConstantExpr(0, width), NewConstantExpr32(0)})
+		return nil
+	}
 
-func (e *Executor) executeRunDefersInstr(state *ExecutionState, instr *ssa.RunDefers) error {
-	return fmt.Errorf("glee.Executor: defer is not supported")
-}
+	for _, c := range decodeRuneCases(s, offset, e.AssumeValidUTF8) {
+		newState, err := forkIfSatisfiable(state, c.cond)
+		if err != nil {
+			return err
+		} else if newState == nil {
+			continue
+		}
+
+		newState.Frame().bind(instr.Iter, Tuple{s, NewConstantExpr64(uint64(offset + c.size))})
+		newState.Frame().bind(instr, Tuple{NewBoolConstantExpr(true), NewConstantExpr(uint64(offset), width), c.r})
+		e.addState(newState)
+	}
 
-func (e *Executor) executeSelectInstr(state *ExecutionState, instr *ssa.Select) error {
-	return fmt.Errorf("glee.Executor: select is not supported")
+	if !state.Forked() {
+		return ErrNoValidUTF8Decoding
+	}
+	return nil
 }
 
-func (e *Executor) executeSendInstr(state *ExecutionState, instr *ssa.Send) error {
-	return fmt.Errorf("glee.Executor: send is not supported")
+// executeRangeInstr binds an iterator over a string or map. A string iterator
+// is a tuple of the string and the byte offset of the next rune.
+func (e *Executor) executeRangeInstr(state *ExecutionState, instr *ssa.Range) error {
+	if _, ok := instr.X.Type().Underlying().(*types.Map); ok {
+		return e.executeRangeInstrMap(state, instr)
+	}
+	state.Frame().bind(instr, Tuple{state.Eval(instr.X).(*Array), NewConstantExpr64(0)})
+	return nil
 }
 
 func (e *Executor) executeSliceInstr(state *ExecutionState, instr *ssa.Slice) error {
@@ -914,22 +2324,24 @@ func (e *Executor) executeSliceInstrArray(state *ExecutionState, instr *ssa.Slic
 	hi := state.MustEvalAsExpr(instr.High)
 	max := state.MustEvalAsExpr(instr.Max)
 
-	log.Printf("[slice] array low=%v high=%v max=%v", lo, hi, max)
+	e.debugf("[slice] array low=%v high=%v max=%v", lo, hi, max)
 
 	// Determine element width.
 	pointerWidth := e.PointerWidth()
 	typ := instr.Type().(*types.Slice)
 	elemWidth := NewConstantExpr(uint64(e.Sizeof(typ.Elem()))/8, pointerWidth)
 
-	// Set index defaults.
+	// Set index defaults. The length is the number of elements in the array
+	// type rather than the size of its allocation in bytes.
+	length := NewConstantExpr(uint64(deref(instr.X.Type()).(*types.Array).Len()), pointerWidth)
 	if lo == nil {
 		lo = NewConstantExpr(0, pointerWidth)
 	}
 	if hi == nil {
-		hi = NewConstantExpr(uint64(array.Size), pointerWidth)
+		hi = length
 	}
 	if max == nil {
-		max = NewConstantExpr(uint64(array.Size), pointerWidth)
+		max = length
 	}
 
 	// Copy to new header with updated data/len/cap.
@@ -964,12 +2376,11 @@ func (e *Executor) executeSliceInstrString(state *ExecutionState, instr *ssa.Sli
 		hi = NewConstantExpr64(uint64(x.Size))
 	}
 
-	log.Printf("[slice] string low=%v high=%v", lo, hi)
+	e.debugf("[slice] string low=%v high=%v", lo, hi)
 
 	// Verify low & high are inbounds.
 	if hi.Value > uint64(x.Size) || lo.Value > uint64(x.Size) {
-		state.status = ExecutionStatusPanicked
-		state.reason = "slice bounds out of range"
+		e.runtimePanic(state, "slice bounds out of range")
 		return nil
 	}
 
@@ -991,16 +2402,17 @@ func (e *Executor) executeSliceInstrSlice(state *ExecutionState, instr *ssa.Slic
 	hi := state.MustEvalAsExpr(instr.High)
 	max := state.MustEvalAsExpr(instr.Max)
 
-	log.Printf("[slice] slice low=%v high=%v max=%v, id=#%d", lo, hi, max, x.ID)
+	e.debugf("[slice] slice low=%v high=%v max=%v, id=#%d", lo, hi, max, x.ID)
 
 	// Determine element width.
 	pointerWidth := e.PointerWidth()
 	typ := instr.Type().(*types.Slice)
 	elemWidth := NewConstantExpr(uint64(e.Sizeof(typ.Elem()))/8, pointerWidth)
 
-	// Set index defaults.
+	// Set index defaults. Indices may be of any integer type so they are
+	// extended or truncated to the width of the slice header fields.
 	if lo == nil {
-		lo = NewConstantExpr64(0)
+		lo = NewConstantExpr(0, pointerWidth)
 	}
 	if hi == nil {
 		hi = state.selectIntAt(x, 1)
@@ -1008,6 +2420,7 @@ func (e *Executor) executeSliceInstrSlice(state *ExecutionState, instr *ssa.Slic
 	if max == nil {
 		max = state.selectIntAt(x, 2)
 	}
+	lo, hi, max = newZExtExpr(lo, pointerWidth), newZExtExpr(hi, pointerWidth), newZExtExpr(max, pointerWidth)
 
 	// Data is offset based on element width and low value.
 	prevData := state.selectIntAt(x, 0)
@@ -1032,17 +2445,102 @@ func (e *Executor) executeSliceInstrSlice(state *ExecutionState, instr *ssa.Slic
 	return nil
 }
 
+// executeTypeAssertInstr forks into states where the dynamic type of the
+// interface matches the asserted type & where it does not. A failed assertion
+// panics unless the comma-ok form is used.
 func (e *Executor) executeTypeAssertInstr(state *ExecutionState, instr *ssa.TypeAssert) error {
-	return fmt.Errorf("glee.Executor: type assertion is not supported")
+	iface := state.Eval(instr.X).(*Array)
+	typeID := state.selectIntAt(iface, 0)
+
+	// Asserting to an interface returns the interface value itself. Otherwise
+	// the concrete value is extracted from the data word.
+	isIface := types.IsInterface(instr.AssertedType)
+	if !isIface && !isBoxedType(instr.AssertedType) && e.Sizeof(instr.AssertedType) > e.PointerWidth() {
+		return fmt.Errorf("glee.Executor: unsupported type assertion to %s", instr.AssertedType)
+	}
+
+	// value returns the asserted value. Boxed values are only loaded once the
+	// dynamic type is known to match.
+	value := func(state *ExecutionState) Binding {
+		if isIface {
+			return iface
+		}
+		return state.ifaceValue(iface, instr.AssertedType)
+	}
+
+	cond := e.typeAssertCond(typeID, instr.AssertedType)
+	return forkEach(state, []Expr{cond, NewNotExpr(cond)}, func(state *ExecutionState, i int) {
+		ok := i == 0
+		switch {
+		case instr.CommaOk && ok:
+			state.Frame().bind(instr, Tuple{value(state), NewBoolConstantExpr(true)})
+		case instr.CommaOk:
+			state.Frame().bind(instr, Tuple{state.zeroBinding(instr.AssertedType), NewBoolConstantExpr(false)})
+		case ok:
+			state.Frame().bind(instr, value(state))
+		default:
+			e.runtimePanic(state, e.typeAssertReason(typeID, instr))
+		}
+	})
+}
+
+// typeAssertCond returns an expression that is true if the type ID of an
+// interface satisfies the asserted type. Concrete types must be identical and
+// interface types must be implemented. A nil interface never satisfies.
+func (e *Executor) typeAssertCond(typeID Expr, asserted types.Type) Expr {
+	ids := make([]int, 0, len(e.typesByID))
+	for id := range e.typesByID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	cond := Expr(NewBoolConstantExpr(false))
+	for _, id := range ids {
+		typ := e.typesByID[id]
+		if iface, ok := asserted.Underlying().(*types.Interface); ok {
+			if types.IsInterface(typ) || !types.Implements(typ, iface) {
+				continue
+			}
+		} else if !types.Identical(typ, asserted) {
+			continue
+		}
+		cond = newOrExpr(cond, newEqExpr(typeID, NewConstantExpr(uint64(id), ExprWidth(typeID))))
+	}
+	return cond
+}
+
+// typeAssertReason returns the panic message for a failed type assertion.
+// The dynamic type is only included if it is known.
+func (e *Executor) typeAssertReason(typeID Expr, instr *ssa.TypeAssert) string {
+	if typeID, ok := typeID.(*ConstantExpr); ok {
+		if typeID.Value == 0 {
+			return fmt.Sprintf("interface conversion: interface is nil, not %s", instr.AssertedType)
+		} else if typ := e.typesByID[int(typeID.Value)]; typ != nil && types.IsInterface(instr.AssertedType) {
+			return fmt.Sprintf("interface conversion: %s is not %s", typ, instr.AssertedType)
+		} else if typ != nil {
+			return fmt.Sprintf("interface conversion: %s is %s, not %s", instr.X.Type(), typ, instr.AssertedType)
+		}
+	}
+	return fmt.Sprintf("interface conversion: %s is not %s", instr.X.Type(), instr.AssertedType)
 }
 
+// executeReturnInstr binds the results to the caller's call instruction and
+// pops the frame in place. No new state is forked as the path is unchanged.
 func (e *Executor) executeReturnInstr(state *ExecutionState, instr *ssa.Return) error {
+	// Record the results of a summarized function.
+	frame := state.CallerFrame()
+	if frame == nil && e.summarizing {
+		state.results = make(Tuple, len(instr.Results))
+		for i := range state.results {
+			state.results[i] = state.Eval(instr.Results[i])
+		}
+	}
+
 	// Assign return values to call instruction results.
-	if frame := state.CallerFrame(); frame != nil {
+	if frame != nil {
 		// Retrieve results from this frame.
 		results := make(Tuple, len(instr.Results))
 		for i := range results {
-			fmt.Printf("dbg/ret %#v\n", instr.Results[i])
 			results[i] = state.Eval(instr.Results[i])
 		}
 
@@ -1058,41 +2556,54 @@ func (e *Executor) executeReturnInstr(state *ExecutionState, instr *ssa.Return)
 			}
 		}
 
-		// Split off new state with same constraints so we can maintain position.
-		log.Print("[fork] return")
-		newState := state.Fork(nil)
-		newState.id = e.nextStateID()
-		newState.Pop()
-		e.Searcher.AddState(newState)
+		// Continue execution in the caller.
+		e.debugf("[return]")
+		state.Pop()
+		return nil
 	}
 
+	// Returning from the entry function of the main goroutine finishes the
+	// state. The frame is kept so the state reports the position it returned
+	// from. Other goroutines exit once their frame is popped.
+	if state.gid == mainGoroutineID {
+		state.finish()
+	} else {
+		state.Pop()
+	}
 	return nil
 }
 
 func (e *Executor) executeIfInstr(state *ExecutionState, instr *ssa.If) error {
+	// Fork every case of a switch at once instead of one branch at a time.
+	if ok, err := e.executeIfInstrSwitch(state, instr); ok || err != nil {
+		return err
+	}
+
 	cond := state.Eval(instr.Cond).(Expr)
 	block := instr.Block()
 
 	// Add the false branch if it is valid.
-	if satisfiable, _, err := e.Solver.Solve(append(state.constraints, NewNotExpr(cond)), nil); err != nil {
+	if satisfiable, _, err := e.solve(append(state.constraints, NewNotExpr(cond)), nil); err != nil {
 		return err
 	} else if satisfiable {
-		log.Print("[fork] condition false")
+		e.infof("[fork] condition false")
+		e.branchCoverage(instr).False = true
 		newState := state.Fork(NewNotExpr(cond))
 		newState.id = e.nextStateID()
 		newState.Frame().jump(block.Succs[1])
-		e.Searcher.AddState(newState)
+		e.addState(newState)
 	}
 
 	// Add the true branch if it is satisfiable.
-	if satisfiable, _, err := e.Solver.Solve(append(state.constraints, cond), nil); err != nil {
+	if satisfiable, _, err := e.solve(append(state.constraints, cond), nil); err != nil {
 		return err
 	} else if satisfiable {
-		log.Print("[fork] condition true")
+		e.infof("[fork] condition true")
+		e.branchCoverage(instr).True = true
 		newState := state.Fork(cond)
 		newState.id = e.nextStateID()
 		newState.Frame().jump(block.Succs[0])
-		e.Searcher.AddState(newState)
+		e.addState(newState)
 	}
 
 	return nil
@@ -1120,39 +2631,35 @@ func (e *Executor) executeUnOpNotInstr(state *ExecutionState, instr *ssa.UnOp) e
 }
 
 func (e *Executor) executeUnOpSubInstr(state *ExecutionState, instr *ssa.UnOp) error {
-	return fmt.Errorf("glee.Executor: negation operator is not supported")
-}
+	x := state.MustEvalAsExpr(instr.X)
+	width := ExprWidth(x)
 
-func (e *Executor) executeUnOpArrowInstr(state *ExecutionState, instr *ssa.UnOp) error {
-	return fmt.Errorf("glee.Executor: arrow operator is not supported")
+	// Floats are negated by flipping the sign bit of their IEEE 754 encoding,
+	// the same as Go. This includes zero & NaN values.
+	if isFloatType(instr.X.Type()) {
+		state.Frame().bind(instr, newXorExpr(x, NewConstantExpr(1<<(width-1), width)))
+		return nil
+	}
+
+	// Integers are negated in two's complement so negating the minimum value
+	// of a signed type wraps around to itself, the same as Go.
+	state.Frame().bind(instr, NewBinaryExpr(SUB, NewConstantExpr(0, width), x))
+	return nil
 }
 
 func (e *Executor) executeUnOpMulInstr(state *ExecutionState, instr *ssa.UnOp) error {
-	width := e.Sizeof(instr.Type())
-
-	// Find allocation by address.
-	addr := state.Eval(instr.X).(*ConstantExpr)
-	base, array := state.findAllocContainingAddr(addr)
-	assert(array != nil, "UnOp(MUL): allocation not found: addr=%d", addr.Value)
-
-	// Extract value from the allocation and bind it to the instruction.
-	// Simple data types (such as ints) are extracted as expressions.
-	// Complex data types such as interfaces are extracted as arrays.
-	if isExprType(instr.Type()) {
-		state.Frame().bind(instr, array.Select(newSubExpr(addr, base), width, e.IsLittleEndian()))
-	} else {
-		indexExpr := newSubExpr(addr, base)
-		_, dst := state.Alloc(width / 8)
-		for i := uint64(0); i < uint64(dst.Size); i++ {
-			arrayIndex := newAddExpr(indexExpr, NewConstantExpr(i, e.PointerWidth()))
-			dst.storeByte(NewConstantExpr64(i), array.selectByte(arrayIndex))
+	// Extract value from each allocation the address may point into and
+	// bind it to the instruction.
+	addr := state.MustEvalAsExpr(instr.X)
+	size := e.Sizeof(instr.Type()) / 8
+	return e.forkResolve(state, addr, size, func(state *ExecutionState, base *ConstantExpr) error {
+		value, err := state.load(base, addr, instr.Type())
+		if err != nil {
+			return err
 		}
-		state.heap = state.heap.Set(dst.ID, dst)
-
-		state.Frame().bind(instr, dst)
-	}
-
-	return nil
+		state.Frame().bind(instr, value)
+		return nil
+	})
 }
 
 func (e *Executor) executeUnOpXorInstr(state *ExecutionState, instr *ssa.UnOp) error {
@@ -1174,22 +2681,27 @@ func (e *Executor) executePhiInstr(state *ExecutionState, instr *ssa.Phi) error
 
 func (e *Executor) executeStoreInstr(state *ExecutionState, instr *ssa.Store) error {
 	// Retrieve address from stack frame.
-	addr, ok := state.EvalAsConstantExpr(instr.Addr)
-	if !ok {
-		return fmt.Errorf("cannot store using symbolic addresses")
-	}
-
-	// Copy value if it is an array.
-	switch val := state.Eval(instr.Val).(type) {
-	case *Array:
-		state.Copy(addr, val)
-		return nil
-	case Expr:
-		state.Store(addr, val)
-		return nil
-	default:
-		return fmt.Errorf("unexpected store value: %#v", val)
-	}
+	addr := state.MustEvalAsExpr(instr.Addr)
+
+	// Copy value using the layout of its type if it is an array.
+	val := state.Eval(instr.Val)
+	size := e.Sizeof(instr.Val.Type()) / 8
+	return e.forkResolve(state, addr, size, func(state *ExecutionState, base *ConstantExpr) error {
+		switch val := val.(type) {
+		case *Array:
+			if isStringType(instr.Val.Type()) {
+				state.storeStringAt(base, addr, val)
+				return nil
+			}
+			state.copyValueAt(base, addr, val, instr.Val.Type())
+			return nil
+		case Expr:
+			state.storeAt(base, addr, val)
+			return nil
+		default:
+			return fmt.Errorf("unexpected store value: %#v", val)
+		}
+	})
 }
 
 func (e *Executor) Sizes() types.Sizes {
@@ -1215,7 +2727,7 @@ func (e *Executor) MaxAllocSize() uint {
 // IsLittleEndian returns true if the target architecture is little endian.
 func (e *Executor) IsLittleEndian() bool {
 	switch e.Arch {
-	case "ppc64", "mips", "mips64":
+	case "ppc64", "mips", "mips64", "s390x":
 		return false
 	default:
 		return true
@@ -1228,16 +2740,76 @@ func (e *Executor) IsLittleEndian() bool {
 // delegated to the FunctionHandler.
 type FunctionHandler func(state *ExecutionState, instr *ssa.Call) error
 
+// HotSpot represents fork statistics for a source branch or a function.
+type HotSpot struct {
+	Pos    token.Position // branch position; zero for function statistics
+	Func   *ssa.Function  // containing function
+	ForkN  int            // number of times execution forked
+	StateN int            // number of states produced
+}
+
+// sortHotSpots sorts a by state count descending, then by function & position.
+func sortHotSpots(a []*HotSpot) {
+	sort.Slice(a, func(i, j int) bool {
+		if a[i].StateN != a[j].StateN {
+			return a[i].StateN > a[j].StateN
+		} else if x, y := a[i].Func.String(), a[j].Func.String(); x != y {
+			return x < y
+		} else if a[i].Pos.Filename != a[j].Pos.Filename {
+			return a[i].Pos.Filename < a[j].Pos.Filename
+		}
+		return a[i].Pos.Offset < a[j].Pos.Offset
+	})
+}
+
+// registeredFn returns the handler registered for fn. Returns nil if fn is
+// not registered. Synthetic wrappers, such as bound methods & promoted
+// methods, have no package and are never registered by name.
+func (e *Executor) registeredFn(fn *ssa.Function) FunctionHandler {
+	if h := e.funcs[fn]; h != nil {
+		return h
+	} else if fn.Pkg == nil {
+		return nil
+	}
+	return e.fns[funcKey{fn.Pkg.Pkg.Path(), fn.Name()}]
+}
+
 // funcKey represents a key for registering a FunctionHandler with the Executor.
 type funcKey struct {
 	path string // package name
 	name string // function name
 }
 
-// Assert adds a constraint to the current execution state.
+// Assume adds a precondition to the current execution state. Paths where
+// cond cannot hold are dropped.
+func Assume(cond bool) {}
+
+// execAssume represents a function handler for adding a precondition to the current state.
+func execAssume(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+
+	cond, ok := args[0].(Expr)
+	if !ok {
+		return fmt.Errorf("glee.Assume(): unable to assume non-expression: %T", args[0])
+	}
+
+	if satisfiable, err := isSatisfiable(state, cond); err != nil {
+		return err
+	} else if !satisfiable {
+		state.status = ExecutionStatusDropped
+		state.reason = "assumption violated"
+		return nil
+	}
+
+	state.AddConstraint(cond)
+	return nil
+}
+
+// Assert checks that cond holds for the current execution state. A failed
+// state is reported for any inputs where cond can be false.
 func Assert(cond bool) {}
 
-// execAssert represents a function handler for adding an assertion to the current state.
+// execAssert represents a function handler for checking an assertion on the current state.
 func execAssert(state *ExecutionState, instr *ssa.Call) error {
 	_, args := state.ExtractCall(instr)
 
@@ -1246,7 +2818,63 @@ func execAssert(state *ExecutionState, instr *ssa.Call) error {
 		return fmt.Errorf("glee.Assert(): unable to assert non-expression: %T", args[0])
 	}
 
-	state.AddConstraint(cond)
+	// Solve for a counterexample on the failing path so the inputs are
+	// reported along with the position of the assertion.
+	pos := state.Position()
+	var solveErr error
+	if err := forkEach(state, []Expr{cond, NewNotExpr(cond)}, func(state *ExecutionState, i int) {
+		if i == 0 || solveErr != nil {
+			return
+		}
+
+		arrays, values, err := state.Values()
+		if err != nil {
+			solveErr = err
+			return
+		}
+		state.status = ExecutionStatusAssertFailed
+		state.reason = fmt.Sprintf("assertion failed: %s", pos)
+		state.assertion = &AssertionFailure{Pos: pos, Arrays: arrays, Values: values}
+	}); err != nil {
+		return err
+	}
+	return solveErr
+}
+
+// Name assigns name to the symbolic input that v is derived from. Solved
+// values can then be retrieved by name with ExecutionState.NamedValues(). The
+// name must be a constant.
+func Name(name string, v interface{}) {}
+
+// execName represents a function handler for naming a symbolic input.
+func execName(state *ExecutionState, instr *ssa.Call) error {
+	c, ok := instr.Call.Args[0].(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return fmt.Errorf("glee.Name(): only constant names allowed")
+	}
+	name := constant.StringVal(c.Value)
+
+	mi, ok := instr.Call.Args[1].(*ssa.MakeInterface)
+	if !ok {
+		return fmt.Errorf("glee.Name(): interface values cannot be named")
+	}
+
+	arrays, err := state.symbolicArrays(state.Eval(mi.X), mi.X.Type())
+	if err != nil {
+		return err
+	} else if len(arrays) == 0 {
+		return fmt.Errorf("glee.Name(): %q is not symbolic", name)
+	} else if len(arrays) > 1 {
+		return fmt.Errorf("glee.Name(): %q is derived from multiple symbolic inputs", name)
+	}
+
+	// Names must be unique within a path.
+	for itr := state.names.Iterator(); !itr.Done(); {
+		if k, v := itr.Next(); v.(string) == name && k.(uint64) != arrays[0].ID {
+			return fmt.Errorf("glee.Name(): duplicate name: %q", name)
+		}
+	}
+	state.names = state.names.Set(arrays[0].ID, name)
 	return nil
 }
 
@@ -1278,6 +2906,7 @@ func Uint64() uint64 { return 0 }
 func execInt(state *ExecutionState, instr *ssa.Call) error {
 	width := state.Executor().Sizeof(instr.Type())
 	_, array := state.Alloc(width / 8)
+	state.setCallOrigin(array, instr, "")
 	state.Frame().bind(instr, array.Select(NewConstantExpr(0, 32), width, state.Executor().IsLittleEndian()))
 	return nil
 }
@@ -1296,39 +2925,125 @@ func execString(state *ExecutionState, instr *ssa.Call) error {
 
 	// Allocate underlying bytes.
 	_, array := state.Alloc(uint(n.Value))
+	state.setCallOrigin(array, instr, "")
 
 	// Bind array to instruction.
 	state.Frame().bind(instr, array)
 	return nil
 }
 
-// ByteSlice returns a symbolic byte slice that is n bytes long.
+// ByteSlice returns a symbolic byte slice that is n bytes long. If n is
+// symbolic then the length of the slice is also symbolic.
 func ByteSlice(n int) []byte { return nil }
 
 // execByteSlice represents a function handler for the ByteSlice() function.
 func execByteSlice(state *ExecutionState, instr *ssa.Call) error {
 	_, args := state.ExtractCall(instr)
 
-	n, ok := args[0].(*ConstantExpr)
-	if !ok {
-		return fmt.Errorf("glee.ByteSlice(): only constant size allowed")
+	n := args[0].(Expr)
+
+	// bind allocates max bytes of symbolic data & binds a slice of n bytes.
+	bind := func(state *ExecutionState, max uint64) {
+		// Allocate underlying byte array.
+		addr, data := state.Alloc(uint(max))
+		state.setCallOrigin(data, instr, "")
+
+		// Allocate slice header array.
+		pointerWidth := state.Executor().PointerWidth()
+		_, hdr := state.Alloc((pointerWidth / 8) * 3)
+		hdr = state.storeIntAt(hdr, 0, addr) // data
+		hdr = state.storeIntAt(hdr, 1, n)    // len
+		hdr = state.storeIntAt(hdr, 2, n)    // cap
+		state.heap = state.heap.Set(hdr.ID, hdr)
+
+		// Bind header to instruction.
+		state.Frame().bind(instr, hdr)
 	}
 
-	// Allocate underlying byte array.
-	addr, _ := state.Alloc(uint(n.Value))
+	if n, ok := n.(*ConstantExpr); ok {
+		bind(state, n.Value)
+		return nil
+	}
 
-	// Allocate slice header array.
-	pointerWidth := state.Executor().PointerWidth()
-	_, hdr := state.Alloc((pointerWidth / 8) * 3)
-	hdr = state.storeIntAt(hdr, 0, addr) // data
-	hdr = state.storeIntAt(hdr, 1, n)    // len
-	hdr = state.storeIntAt(hdr, 2, n)    // cap
-	state.heap = state.heap.Set(hdr.ID, hdr)
+	// Symbolic sizes panic if negative, the same as make().
+	e := state.Executor()
+	negative := NewBinaryExpr(SLT, n, NewConstantExpr(0, ExprWidth(n)))
+	if newState, err := forkIfSatisfiable(state, negative); err != nil {
+		return err
+	} else if newState != nil {
+		e.runtimePanic(newState, "makeslice: len out of range")
+		e.addState(newState)
+	}
+	return e.forkSymbolicAlloc(state, NewNotExpr(negative), n, 1, bind)
+}
 
-	// Bind header to instruction.
-	state.Frame().bind(instr, hdr)
+// StringMaxLen returns a symbolic string that is at most max bytes long.
+func StringMaxLen(max int) string { return "" }
 
-	return nil
+// execStringMaxLen represents a function handler for the StringMaxLen() function.
+func execStringMaxLen(state *ExecutionState, instr *ssa.Call) error {
+	return forkMaxLen(state, instr, "glee.StringMaxLen()", func(state *ExecutionState, data *Array, addr, n *ConstantExpr) {
+		// Strings are represented by their bytes so copy the prefix if shorter.
+		if n.Value == uint64(data.Size) {
+			state.Frame().bind(instr, data)
+			return
+		}
+
+		array := NewArray(0, uint(n.Value))
+		for i := uint64(0); i < n.Value; i++ {
+			index := NewConstantExpr64(i)
+			array.storeByte(index, data.selectByte(index))
+		}
+		state.Frame().bind(instr, array)
+	})
+}
+
+// ByteSliceMaxLen returns a symbolic byte slice that is at most max bytes long.
+func ByteSliceMaxLen(max int) []byte { return nil }
+
+// execByteSliceMaxLen represents a function handler for the ByteSliceMaxLen() function.
+func execByteSliceMaxLen(state *ExecutionState, instr *ssa.Call) error {
+	return forkMaxLen(state, instr, "glee.ByteSliceMaxLen()", func(state *ExecutionState, data *Array, addr, n *ConstantExpr) {
+		pointerWidth := state.Executor().PointerWidth()
+		_, hdr := state.Alloc((pointerWidth / 8) * 3)
+		hdr = state.storeIntAt(hdr, 0, addr) // data
+		hdr = state.storeIntAt(hdr, 1, n)    // len
+		hdr = state.storeIntAt(hdr, 2, n)    // cap
+		state.heap = state.heap.Set(hdr.ID, hdr)
+
+		state.Frame().bind(instr, hdr)
+	})
+}
+
+// forkMaxLen allocates a symbolic length & max bytes of symbolic data for a
+// bounded-length value. The length is a separate symbolic int so its solved
+// value is reported along with the data. A state is forked for each length
+// from zero to max and fn binds the value of length n within that state.
+func forkMaxLen(state *ExecutionState, instr *ssa.Call, name string, fn func(state *ExecutionState, data *Array, addr, n *ConstantExpr)) error {
+	_, args := state.ExtractCall(instr)
+
+	max, ok := args[0].(*ConstantExpr)
+	if !ok {
+		return fmt.Errorf("%s: only constant max size allowed", name)
+	} else if int64(max.Value) < 0 {
+		return fmt.Errorf("%s: negative max size", name)
+	}
+
+	e := state.Executor()
+	intWidth := e.Sizeof(types.Typ[types.Int])
+	_, lenArray := state.Alloc(intWidth / 8)
+	length := lenArray.Select(NewConstantExpr(0, 32), intWidth, e.IsLittleEndian())
+	addr, data := state.Alloc(uint(max.Value))
+	state.setCallOrigin(lenArray, instr, "len")
+	state.setCallOrigin(data, instr, "")
+
+	conds := make([]Expr, max.Value+1)
+	for i := range conds {
+		conds[i] = NewBinaryExpr(EQ, length, NewConstantExpr(uint64(i), intWidth))
+	}
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		fn(state, data, addr, NewConstantExpr(uint64(i), intWidth))
+	})
 }
 
 // execCopy represents a function handler for the builtin copy() function.
@@ -1385,8 +3100,7 @@ func execCopy(state *ExecutionState, instr *ssa.Call) error {
 
 	// Validate that source size not larger than destination size.
 	if srcSize > dstSize {
-		state.status = ExecutionStatusPanicked
-		state.reason = "copy out of range"
+		state.Executor().runtimePanic(state, "copy out of range")
 		return nil
 	}
 
@@ -1407,76 +3121,180 @@ func execCopy(state *ExecutionState, instr *ssa.Call) error {
 // execLen represents a function handler for the builtin len() function.
 func execLen(state *ExecutionState, instr *ssa.Call) error {
 	_, args := state.ExtractCall(instr)
-	arg := args[0].(*Array)
 
-	switch typ := instr.Call.Args[0].Type().(type) {
-	case *types.Slice:
-		v, ok := state.selectIntAt(arg, 1).(*ConstantExpr)
-		if !ok {
-			return fmt.Errorf("glee: len() expects constant slice len")
+	// Maps are referenced by address so handle before expecting an array.
+	if _, ok := instr.Call.Args[0].Type().Underlying().(*types.Map); ok {
+		addr, err := state.objectAddr(args[0])
+		if err != nil {
+			return err
+		}
+
+		var n int
+		if m := state.findMap(addr); m != nil {
+			n = len(m.entries)
+		}
+		state.Frame().bind(instr, NewConstantExpr(uint64(n), state.Executor().Sizeof(types.Typ[types.Int])))
+		return nil
+	}
+
+	// Channels report the number of buffered values.
+	if _, ok := instr.Call.Args[0].Type().Underlying().(*types.Chan); ok {
+		addr, err := state.objectAddr(args[0])
+		if err != nil {
+			return err
+		}
+
+		var n int
+		if c := state.findChan(addr); c != nil {
+			n = len(c.buf)
 		}
-		state.Frame().bind(instr, v)
+		state.Frame().bind(instr, NewConstantExpr(uint64(n), state.Executor().Sizeof(types.Typ[types.Int])))
+		return nil
+	}
+
+	arg := args[0].(*Array)
+	switch typ := instr.Call.Args[0].Type().Underlying().(type) {
+	case *types.Slice:
+		state.Frame().bind(instr, state.selectIntAt(arg, 1))
 		return nil
 	case *types.Basic:
-		state.Frame().bind(instr, NewConstantExpr64(uint64(arg.Size)))
+		state.Frame().bind(instr, NewConstantExpr(uint64(arg.Size), state.Executor().Sizeof(types.Typ[types.Int])))
 		return nil
 	default:
 		return fmt.Errorf("glee: invalid len() arg type: %s", typ)
 	}
 }
 
-// execTestingFatal represents a function handler for the testing.Fatal() function.
-func execTestingFatal(state *ExecutionState, instr *ssa.Call) error {
-	panic("TODO")
+// execCap represents a function handler for the builtin cap() function.
+// The capacity of arrays is determined by their type so the SSA builder
+// folds those calls into constants.
+func execCap(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+
+	switch typ := instr.Call.Args[0].Type().Underlying().(type) {
+	case *types.Slice:
+		state.Frame().bind(instr, state.selectIntAt(args[0].(*Array), 2))
+		return nil
+	case *types.Chan:
+		addr, err := state.objectAddr(args[0])
+		if err != nil {
+			return err
+		}
+
+		var n int
+		if c := state.findChan(addr); c != nil {
+			n = c.cap
+		}
+		state.Frame().bind(instr, NewConstantExpr(uint64(n), state.Executor().Sizeof(types.Typ[types.Int])))
+		return nil
+	default:
+		return fmt.Errorf("glee: invalid cap() arg type: %s", typ)
+	}
+}
+
+// execMin represents a function handler for the builtin min() function.
+func execMin(state *ExecutionState, instr *ssa.Call) error {
+	return execMinMax(state, instr, false)
+}
+
+// execMax represents a function handler for the builtin max() function.
+func execMax(state *ExecutionState, instr *ssa.Call) error {
+	return execMinMax(state, instr, true)
+}
+
+// execMinMax binds the smallest or largest argument of a min() or max() call.
+// A state is forked for each argument that can be the result. An argument is
+// the result if it is strictly better than all earlier arguments and at least
+// as good as all later arguments so exactly one condition holds.
+func execMinMax(state *ExecutionState, instr *ssa.Call, max bool) error {
+	_, args := state.ExtractCall(instr)
+
+	basic, ok := instr.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&(types.IsInteger|types.IsFloat) == 0 {
+		return fmt.Errorf("glee: unsupported %s() arg type: %s", instr.Call.Value.Name(), instr.Type())
+	}
+	isFloat := basic.Info()&types.IsFloat != 0
+	signed := basic.Info()&types.IsUnsigned == 0
+
+	exprs := make([]Expr, len(args))
+	for i := range args {
+		exprs[i] = args[i].(Expr)
+	}
+
+	// less returns the condition that x is less than y, or less than or equal
+	// to y if orEqual is set.
+	less := func(x, y Expr, orEqual bool) Expr {
+		switch {
+		case isFloat && orEqual:
+			return NewFPBinaryExpr(FLE, x, y)
+		case isFloat:
+			return NewFPBinaryExpr(FLT, x, y)
+		case signed && orEqual:
+			return NewBinaryExpr(SLE, x, y)
+		case signed:
+			return NewBinaryExpr(SLT, x, y)
+		case orEqual:
+			return NewBinaryExpr(ULE, x, y)
+		default:
+			return NewBinaryExpr(ULT, x, y)
+		}
+	}
+
+	conds := make([]Expr, len(exprs))
+	for i := range exprs {
+		conds[i] = NewBoolConstantExpr(true)
+		for j := range exprs {
+			if i == j {
+				continue
+			}
+
+			// Swap operands to find the largest value.
+			x, y := exprs[i], exprs[j]
+			if max {
+				x, y = y, x
+			}
+			conds[i] = newAndExpr(conds[i], less(x, y, j > i))
+		}
+	}
+
+	// Floating-point comparisons with NaN are always false so none of the
+	// conditions above hold. Any NaN argument results in NaN.
+	if isFloat {
+		nan := Expr(NewBoolConstantExpr(false))
+		for _, expr := range exprs {
+			nan = newOrExpr(nan, NewNotExpr(NewFPBinaryExpr(FEQ, expr, expr)))
+		}
+		conds = append(conds, nan)
+	}
+
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		if i < len(exprs) {
+			state.Frame().bind(instr, exprs[i])
+		} else {
+			state.Frame().bind(instr, NewFPConstantExpr(math.NaN(), state.Executor().Sizeof(instr.Type())))
+		}
+	})
 }
 
-// isValidOSArch returns true if the OS & architecture combination are valid.
+//go:generate go run gen_osarch.go
+
+// isValidOSArch returns true if the OS & architecture combination are valid
+// and the type sizes for the architecture are known. See osarch.go.
 func isValidOSArch(os, arch string) bool {
-	switch fmt.Sprintf("%s/%s", os, arch) {
-	case "android/386",
-		"android/amd64",
-		"android/arm",
-		"android/arm64",
-		"darwin/386",
-		"darwin/amd64",
-		"darwin/arm",
-		"darwin/arm64",
-		"dragonfly/amd64",
-		"freebsd/386",
-		"freebsd/amd64",
-		"freebsd/arm",
-		"js/wasm",
-		"linux/386",
-		"linux/amd64",
-		"linux/arm",
-		"linux/arm64",
-		"linux/mips",
-		"linux/mips64",
-		"linux/mips64le",
-		"linux/mipsle",
-		"linux/ppc64",
-		"linux/ppc64le",
-		"linux/riscv64",
-		"linux/s390x",
-		"nacl/386",
-		"nacl/amd64p32",
-		"nacl/arm",
-		"netbsd/386",
-		"netbsd/amd64",
-		"netbsd/arm",
-		"openbsd/386",
-		"openbsd/amd64",
-		"openbsd/arm",
-		"plan9/386",
-		"plan9/amd64",
-		"plan9/arm",
-		"solaris/amd64",
-		"windows/386",
-		"windows/amd64":
-		return true
-	default:
+	if _, ok := osArchs[os+"/"+arch]; !ok {
 		return false
 	}
+	return types.SizesFor("gc", arch) != nil
+}
+
+// OSArchs returns a sorted list of all supported "os/arch" combinations.
+func OSArchs() []string {
+	a := make([]string, 0, len(osArchs))
+	for k := range osArchs {
+		a = append(a, k)
+	}
+	sort.Strings(a)
+	return a
 }
 
 func structFields(typ *types.Struct) []*types.Var {
@@ -1505,6 +3323,12 @@ func deref(typ types.Type) types.Type {
 	return typ
 }
 
+// isFloatType returns true if typ is a floating-point basic type.
+func isFloatType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
+
 // isPointerType returns true if typ is a pointer type.
 func isPointerType(typ types.Type) bool {
 	_, ok := typ.Underlying().(*types.Pointer)
@@ -1526,14 +3350,45 @@ func programTypes(prog *ssa.Program) []types.Type {
 
 	// Convert to a slice sorted by name.
 	a := make([]types.Type, 0, len(m))
+	keys := make(map[types.Type]string, len(m))
 	for typ := range m {
 		a = append(a, typ)
+		keys[typ] = typ.String()
 	}
-	sort.Slice(a, func(i, j int) bool { return a[i].String() < a[j].String() })
+
+	// Distinct types may share a name, such as types declared within
+	// different functions, so ties are ordered by declaration position.
+	sort.Slice(a, func(i, j int) bool {
+		if ki, kj := keys[a[i]], keys[a[j]]; ki != kj {
+			return ki < kj
+		}
+		return typeDeclPosition(prog.Fset, a[i]) < typeDeclPosition(prog.Fset, a[j])
+	})
 
 	return a
 }
 
+// typeDeclPosition returns the declaration position of the named type within
+// typ, if any. Returns a blank string for unnamed types.
+func typeDeclPosition(fset *token.FileSet, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.Named:
+		return fset.Position(typ.Obj().Pos()).String()
+	case *types.Pointer:
+		return typeDeclPosition(fset, typ.Elem())
+	case *types.Slice:
+		return typeDeclPosition(fset, typ.Elem())
+	case *types.Array:
+		return typeDeclPosition(fset, typ.Elem())
+	case *types.Chan:
+		return typeDeclPosition(fset, typ.Elem())
+	case *types.Map:
+		return typeDeclPosition(fset, typ.Key()) + " " + typeDeclPosition(fset, typ.Elem())
+	default:
+		return ""
+	}
+}
+
 // addFunctionTypes adds all types referred to in fn to the map.
 // Recursively adds anonymous functions.
 func addFunctionTypes(fn *ssa.Function, m map[types.Type]struct{}) {
@@ -1555,14 +3410,27 @@ func addFunctionTypes(fn *ssa.Function, m map[types.Type]struct{}) {
 }
 
 // isExprType returns true if typ is stored as an Expr.
-// Only applies to boolean and integer values.
+// Only applies to boolean, integer and float values.
 func isExprType(typ types.Type) bool {
 	if typ, ok := typ.(*types.Basic); ok {
-		return typ.Info()&types.IsBoolean != 0 || typ.Info()&types.IsInteger != 0
+		return typ.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat) != 0
 	}
 	return false
 }
 
+// isBoxedType returns true if values of typ are not expressions and must be
+// stored on the heap when converted to an interface.
+func isBoxedType(typ types.Type) bool {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return typ.Info()&types.IsString != 0
+	case *types.Array, *types.Slice, *types.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
 // Solver represents a logical constraint solver.
 type Solver interface {
 	// Returns the satisfiability of the set of constraints. If the formula
@@ -1570,6 +3438,28 @@ type Solver interface {
 	Solve(contraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error)
 }
 
+// ContextSolver represents a solver that can stop a query early once its
+// context is cancelled or its deadline is exceeded.
+type ContextSolver interface {
+	Solver
+
+	// Same as Solve() but returns ctx.Err() if ctx is done before solving
+	// is complete.
+	SolveContext(ctx context.Context, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error)
+}
+
+// SolveContext solves the constraints with solver using ctx, if supported.
+// Solvers which do not implement ContextSolver only have ctx checked before
+// the query begins.
+func SolveContext(ctx context.Context, solver Solver, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	if solver, ok := solver.(ContextSolver); ok {
+		return solver.SolveContext(ctx, constraints, arrays)
+	} else if err := ctx.Err(); err != nil {
+		return false, nil, err
+	}
+	return solver.Solve(constraints, arrays)
+}
+
 // Searcher represents a strategy for finding the next execution state to execute.
 type Searcher interface {
 	// Returns the next state to explore.
@@ -1658,6 +3548,73 @@ func (s *BFSSearcher) AddState(state *ExecutionState) {
 	s.states = append(s.states, state)
 }
 
+// BatchingSearcher represents a searcher which continues down the path of the
+// previously selected state until an instruction or time budget is spent
+// before deferring to its underlying searcher. This improves locality on deep
+// paths & reduces solver context churn compared to switching at every fork.
+type BatchingSearcher struct {
+	base            Searcher
+	maxInstructions int
+	maxDuration     time.Duration
+
+	current    *ExecutionState   // last selected state
+	batch      []*ExecutionState // states forked from current, held from base
+	startInstr int               // instruction count at start of batch
+	startTime  time.Time         // time at start of batch
+}
+
+// NewBatchingSearcher returns a new instance of BatchingSearcher that wraps
+// base. A batch ends after maxInstructions instructions or maxDuration time,
+// whichever comes first. A zero value for either is unlimited.
+func NewBatchingSearcher(base Searcher, maxInstructions int, maxDuration time.Duration) *BatchingSearcher {
+	return &BatchingSearcher{
+		base:            base,
+		maxInstructions: maxInstructions,
+		maxDuration:     maxDuration,
+	}
+}
+
+// SelectState returns the last state forked from the previously selected
+// state if the batch has budget remaining. Otherwise, held states are passed
+// to the underlying searcher & a new batch begins with its selection.
+func (s *BatchingSearcher) SelectState() *ExecutionState {
+	if len(s.batch) > 0 && !s.expired() {
+		s.current = s.batch[len(s.batch)-1]
+		s.batch = s.batch[:len(s.batch)-1]
+		return s.current
+	}
+
+	for _, state := range s.batch {
+		s.base.AddState(state)
+	}
+	s.batch = nil
+
+	if s.current = s.base.SelectState(); s.current != nil {
+		s.startInstr, s.startTime = s.current.instrN, time.Now()
+	}
+	return s.current
+}
+
+// expired returns true if the current batch has exhausted its budget.
+func (s *BatchingSearcher) expired() bool {
+	if s.maxInstructions > 0 && s.current.instrN-s.startInstr >= s.maxInstructions {
+		return true
+	} else if s.maxDuration > 0 && time.Since(s.startTime) >= s.maxDuration {
+		return true
+	}
+	return false
+}
+
+// AddState holds states forked from the current state for the batch. All
+// other states are added to the underlying searcher.
+func (s *BatchingSearcher) AddState(state *ExecutionState) {
+	if s.current != nil && state.parent == s.current {
+		s.batch = append(s.batch, state)
+		return
+	}
+	s.base.AddState(state)
+}
+
 type RandomSearcher struct {
 	states []*ExecutionState
 	rand   *rand.Rand
@@ -1686,6 +3643,8 @@ func (s *RandomSearcher) AddState(state *ExecutionState) {
 }
 
 // RandomPathSearcher randomly selects a path from the executor's state tree.
+// Each child is weighted by the number of unexplored states in its subtree so
+// fully explored subtrees are never descended into.
 type RandomPathSearcher struct {
 	executor *Executor
 	rand     *rand.Rand
@@ -1702,19 +3661,27 @@ func NewRandomPathSearcher(executor *Executor, rand *rand.Rand) *RandomPathSearc
 // SelectState returns a random leaf execution state from the executor.
 func (s *RandomPathSearcher) SelectState() *ExecutionState {
 	state := s.executor.root
-	if state == nil {
+	if state == nil || state.liveN == 0 {
 		return nil
 	}
 
-	for {
-		// Return if leaf node.
-		if len(state.children) == 0 {
-			return state
+	// Unexplored states are always leaves as execution continues in children.
+	for len(state.children) > 0 {
+		var next *ExecutionState
+		n := s.rand.Intn(state.liveN)
+		for _, child := range state.children {
+			if n < child.liveN {
+				next = child
+				break
+			}
+			n -= child.liveN
 		}
-
-		// Otherwise randomly choose child.
-		state = state.children[s.rand.Intn(len(state.children))]
+		if next == nil {
+			return nil
+		}
+		state = next
 	}
+	return state
 }
 
 // AddState is a no-op. Searcher finds states from the executor.
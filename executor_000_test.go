@@ -2,6 +2,7 @@ package glee_test
 
 import (
 	"encoding/hex"
+	"math/rand"
 	"testing"
 
 	"github.com/benbjohnson/glee"
@@ -60,4 +61,100 @@ func TestExecutor_Pkg000_If(t *testing.T) {
 			t.Fatalf("values[0]=%s, expected any other value", got)
 		}
 	})
+	t.Run("Bindings", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "simple")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state, err := e.ExecuteNextState()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Symbolic 'x' value should be listed in the frame's bindings.
+		frame := state.Frame()
+		x := MustVarValue(fn, "x")
+		if frame.Fn() != fn {
+			t.Fatalf("unexpected frame function: %s", frame.Fn())
+		} else if frame.Binding(x) == nil {
+			t.Fatal("expected 'x' binding")
+		}
+
+		var found bool
+		for _, b := range frame.Bindings() {
+			if b.Value == x {
+				if got, exp := b.Type.String(), "int"; got != exp {
+					t.Fatalf("Type=%s, expected %s", got, exp)
+				} else if b.Name != x.Name() {
+					t.Fatalf("Name=%s, expected %s", b.Name, x.Name())
+				}
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("expected 'x' in Bindings()")
+		} else if got, exp := len(state.Stack()), 1; got != exp {
+			t.Fatalf("len(Stack())=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "simple")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Execute all states.
+		MustExecuteAll(t, e)
+
+		// Only the root state should be retained once fully explored.
+		if got, exp := e.StateN(), 1; got != exp {
+			t.Fatalf("StateN()=%d, expected %d", got, exp)
+		} else if got, exp := len(e.RootState().Children()), 0; got != exp {
+			t.Fatalf("len(Children())=%d, expected %d", got, exp)
+		}
+	})
+
+	// Fully explored subtrees have no weight so every state is selected once.
+	t.Run("RandomPath", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "simple")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// terminated returns the number of terminal states once all states
+		// are explored with searcher.
+		terminated := func(searcher glee.Searcher) int {
+			e.Reset(searcher)
+			return len(TerminalStates(MustExecuteAll(t, e)))
+		}
+
+		exp := terminated(glee.NewDFSSearcher())
+		if got := terminated(glee.NewRandomPathSearcher(e.Executor, rand.New(rand.NewSource(0)))); got != exp {
+			t.Fatalf("terminal states=%d, expected %d", got, exp)
+		} else if got, exp := e.StateN(), 1; got != exp {
+			t.Fatalf("StateN()=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("HotSpots", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "simple")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Execute all states.
+		MustExecuteAll(t, e)
+
+		// The single 'if' should fork into both branches.
+		branches, funcs := e.HotSpots()
+		if got, exp := len(branches), 1; got != exp {
+			t.Fatalf("len(branches)=%d, expected %d", got, exp)
+		} else if got, exp := TrimPosition(branches[0].Pos).String(), `simple.go:9`; got != exp {
+			t.Fatalf("unexpected position: %s", got)
+		} else if got, exp := branches[0].StateN, 2; got != exp {
+			t.Fatalf("StateN=%d, expected %d", got, exp)
+		} else if got, exp := len(funcs), 1; got != exp {
+			t.Fatalf("len(funcs)=%d, expected %d", got, exp)
+		} else if funcs[0].Func != fn {
+			t.Fatalf("unexpected function: %s", funcs[0].Func)
+		}
+	})
 }
@@ -1,15 +1,12 @@
 package glee_test
 
-import (
-	"testing"
-)
+import "testing"
 
 func TestExecutor_Pkg001_Call(t *testing.T) {
 	prog := MustBuildProgram(t, "./testdata/pkg001_call")
 
 	t.Run("Simple", func(t *testing.T) {
 		caller := MustFindFunction(t, prog, "caller")
-		callee := MustFindFunction(t, prog, "callee")
 		e := NewExecutor(caller)
 		defer e.Close()
 
@@ -27,24 +24,19 @@ func TestExecutor_Pkg001_Call(t *testing.T) {
 			t.Fatalf("unexpected position: %s", got)
 		}
 
-		// Next state should run from callee() true to end of callee().
+		// Next state should run from callee() true, return, and run until caller() 'if'.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:19`; got != exp {
+		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:11`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
 		} else if arrays, values, err := state.Values(); err != nil {
 			t.Fatal(err)
-		} else if x, err := EvalVar(state, arrays, values, callee, "x"); err != nil {
+		} else if x, err := EvalVar(state, arrays, values, caller, "x"); err != nil {
 			t.Fatal(err)
-		} else if x.Value <= 10 {
-			t.Fatalf("unexpected 'x': %d", x.Value)
-		}
-
-		// Next state should run until caller() 'if'
-		if state, err := e.ExecuteNextState(); err != nil {
+		} else if y, err := EvalVar(state, arrays, values, caller, "y"); err != nil {
 			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:11`; got != exp {
-			t.Fatalf("unexpected position: %s", got)
+		} else if x8, y16 := int8(x.Value), int16(y.Value); int32(x8)*int32(y16) <= 10 {
+			t.Fatalf("unexpected 'x' & 'y': %d, %d", x8, y16)
 		}
 
 		// Next state should execute caller() 'if': true condition.
@@ -67,6 +59,8 @@ func TestExecutor_Pkg001_Call(t *testing.T) {
 			t.Fatal(err)
 		} else if got, exp := TrimPosition(state.Position()).String(), `-`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
+		} else if got, exp := TrimPosition(state.SourcePosition()).String(), `simple.go:11`; got != exp {
+			t.Fatalf("unexpected source position: %s", got)
 		} else if arrays, values, err := state.Values(); err != nil {
 			t.Fatal(err)
 		} else if x, err := EvalVar(state, arrays, values, caller, "x"); err != nil {
@@ -77,24 +71,19 @@ func TestExecutor_Pkg001_Call(t *testing.T) {
 			t.Fatalf("unexpected 'x' & 'y': %d, %d", x8, y16)
 		}
 
-		// Next state should execute callee() false until end of callee()
+		// Next state should execute callee() false, return, and run until caller() 'if'.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:21`; got != exp {
+		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:11`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
 		} else if arrays, values, err := state.Values(); err != nil {
 			t.Fatal(err)
-		} else if x, err := EvalVar(state, arrays, values, callee, "x"); err != nil {
+		} else if x, err := EvalVar(state, arrays, values, caller, "x"); err != nil {
 			t.Fatal(err)
-		} else if x32 := int32(x.Value); x32 > 10 {
-			t.Fatalf("unexpected 'x': %d", x32)
-		}
-
-		// Next state should run until caller() 'if'
-		if state, err := e.ExecuteNextState(); err != nil {
+		} else if y, err := EvalVar(state, arrays, values, caller, "y"); err != nil {
 			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `simple.go:11`; got != exp {
-			t.Fatalf("unexpected position: %s", got)
+		} else if x8, y16 := int8(x.Value), int16(y.Value); int32(x8)*int32(y16) > 10 {
+			t.Fatalf("unexpected 'x' & 'y': %d, %d", x8, y16)
 		}
 
 		// Next state should execute caller() false. The true condition is impossible.
@@ -114,4 +103,16 @@ func TestExecutor_Pkg001_Call(t *testing.T) {
 			t.Fatalf("unexpected 'x' & 'y': %d, %d", x8, y16)
 		}
 	})
+
+	// Returns should not fork so state counts grow linearly with calls.
+	t.Run("Chain", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "chain")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Root, one state per call, and both branches of the final 'if'.
+		if got, exp := len(MustExecuteAll(t, e)), 1+4+2; got != exp {
+			t.Fatalf("state count=%d, expected %d", got, exp)
+		}
+	})
 }
@@ -42,4 +42,21 @@ func TestExecutor_Pkg002_Struct(t *testing.T) {
 			t.Fatalf("values[0]=%s, expected NOT %s", got, exp)
 		}
 	})
+
+	t.Run("FieldValue", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "fieldValue")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `field.go:13`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := hex.EncodeToString(values[0]), "0900000000000000"; got != exp { // 64-bit litte-endian
+			t.Fatalf("values[0]=%s, expected %s", got, exp)
+		}
+	})
 }
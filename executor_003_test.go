@@ -1,6 +1,7 @@
 package glee_test
 
 import (
+	"encoding/binary"
 	"testing"
 )
 
@@ -115,4 +116,24 @@ func TestExecutor_Pkg003_Slice(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("ByteSliceMaxLen", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "byteSliceMaxLen")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `byte_slice.max_len.go:10`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		// Length is solved separately from the data.
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := binary.LittleEndian.Uint64(values[0]), uint64(3); got != exp {
+			t.Fatalf("len=%d, expected %d", got, exp)
+		} else if got, exp := string(values[1][2:3]), "z"; got != exp {
+			t.Fatalf("data=%q, expected %q", got, exp)
+		}
+	})
 }
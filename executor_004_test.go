@@ -1,6 +1,7 @@
 package glee_test
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/benbjohnson/glee"
@@ -688,4 +689,24 @@ func TestExecutor_Pkg004_String(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("MaxLen", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "stringMaxLen")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `max_len.go:10`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		// Length is solved separately from the data.
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := binary.LittleEndian.Uint64(values[0]), uint64(2); got != exp {
+			t.Fatalf("len=%d, expected %d", got, exp)
+		} else if got, exp := string(values[1][:2]), "ab"; got != exp {
+			t.Fatalf("data=%q, expected %q", got, exp)
+		}
+	})
 }
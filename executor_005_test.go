@@ -1,7 +1,11 @@
 package glee_test
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"testing"
+
+	"github.com/benbjohnson/glee"
 )
 
 func TestExecutor_Pkg005_Array(t *testing.T) {
@@ -41,4 +45,42 @@ func TestExecutor_Pkg005_Array(t *testing.T) {
 			t.Fatalf("values[0]=%s, expected NOT contains %s", got, exp)
 		}
 	})
+
+	t.Run("IndexValue", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "indexValue")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `index.go:14`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := hex.EncodeToString(values[0]), "0100000000000000"; got != exp { // 64-bit litte-endian
+			t.Fatalf("values[0]=%s, expected %s", got, exp)
+		}
+	})
+	// Indexes outside the array panic the same as the Go runtime.
+	t.Run("IndexValueOutOfRange", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "indexValueOutOfRange"))
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if m := CountStatus(states); m[glee.ExecutionStatusFinished] != 1 || m[glee.ExecutionStatusPanicked] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+		for _, state := range states {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			} else if got, exp := state.Reason(), "index out of range"; got != exp {
+				t.Fatalf("Reason=%q, expected %q", got, exp)
+			} else if _, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if i := int64(binary.LittleEndian.Uint64(values[0])); i >= 0 && i < 3 {
+				t.Fatalf("index=%d, expected out of range", i)
+			}
+		}
+	})
 }
@@ -14,14 +14,14 @@ func TestExecutor_Pkg006_Interface(t *testing.T) {
 		e := NewExecutor(fn)
 		defer e.Close()
 
-		// Initial state should run until the 'if' statement.
+		// Initial state should run until the Add() invocation.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `interface.go:21`; got != exp {
+		} else if got, exp := TrimPosition(state.Position()).String(), `interface.go:12`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
 		}
 
-		// After returning it should end on the  the 'if' statement.
+		// After returning it should end on the 'if' statement.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
 		} else if got, exp := TrimPosition(state.Position()).String(), `interface.go:12`; got != exp {
@@ -60,29 +60,21 @@ func TestExecutor_Pkg006_Interface(t *testing.T) {
 		e := NewExecutor(fn)
 		defer e.Close()
 
-		// Initial states should run until X1.Val() invocation and then stop on return.
+		// Initial state should run until X1.Val() invocation.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
 		} else if got, exp := TrimPosition(state.Position()).String(), `interface.slice.go:13`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
-		} else if state, err := e.ExecuteNextState(); err != nil {
-			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `interface.slice.go:22`; got != exp {
-			t.Fatalf("unexpected position: %s", got)
 		}
 
-		// Initial states should run Y1.Val() invocation and then stop on return.
+		// Next state should return from X1.Val() and run until Y1.Val() invocation.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
 		} else if got, exp := TrimPosition(state.Position()).String(), `interface.slice.go:13`; got != exp {
 			t.Fatalf("unexpected position: %s", got)
-		} else if state, err := e.ExecuteNextState(); err != nil {
-			t.Fatal(err)
-		} else if got, exp := TrimPosition(state.Position()).String(), `interface.slice.go:28`; got != exp {
-			t.Fatalf("unexpected position: %s", got)
 		}
 
-		// Next state should stop at the 'if' block.
+		// Next state should return from Y1.Val() and stop at the 'if' block.
 		if state, err := e.ExecuteNextState(); err != nil {
 			t.Fatal(err)
 		} else if got, exp := TrimPosition(state.Position()).String(), `interface.slice.go:13`; got != exp {
@@ -0,0 +1,106 @@
+package glee_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg007_Strconv(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg007_strconv")
+
+	t.Run("Atoi", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "atoiNegative")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Execute all states and find the state where the parsed value matched.
+		state := StateAt(MustExecuteAll(t, e), `atoi.go:17`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		// The string must be solved to the decimal representation.
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := string(values[0]), "-12"; got != exp {
+			t.Fatalf("values[0]=%q, expected %q", got, exp)
+		}
+	})
+
+	// Long strings parse with leading zeros or fail with strconv.ErrRange.
+	t.Run("LongString", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "atoiLong"))
+		defer e.Close()
+
+		states := MustExecuteAll(t, e)
+		for _, state := range states {
+			if state.Status() == glee.ExecutionStatusPanicked {
+				t.Fatalf("unexpected panic: %s", state.Reason())
+			}
+		}
+
+		if s := MustSolveString(t, StateAt(states, `atoi.go:26`)); len(s) != 20 || s[0] != '0' {
+			t.Fatalf("unexpected string: %q", s)
+		} else if _, err := strconv.Atoi(s); err != nil {
+			t.Fatalf("strconv.Atoi(%q): %s", s, err)
+		}
+
+		if s := MustSolveString(t, StateAt(states, `atoi.go:37`)); len(s) != 20 {
+			t.Fatalf("unexpected string: %q", s)
+		} else if x, err := strconv.Atoi(s); !IsErrRange(err) || x != math.MaxInt64 {
+			t.Fatalf("strconv.Atoi(%q)=<%d,%v>, expected out of range", s, x, err)
+		}
+	})
+
+	// Strings of the full width of the bit size parse up to the maximum value.
+	t.Run("ParseUint", func(t *testing.T) {
+		for _, tt := range []struct {
+			fn             string
+			bitSize        int
+			ok, outOfRange string
+		}{
+			{"parseUint8", 8, `parseuint.go:19`, `parseuint.go:14`},
+			{"parseUint32", 32, `parseuint.go:33`, `parseuint.go:28`},
+		} {
+			t.Run(tt.fn, func(t *testing.T) {
+				e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+				defer e.Close()
+				states := MustExecuteAll(t, e)
+				max := uint64(1)<<uint(tt.bitSize) - 1
+
+				s := MustSolveString(t, StateAt(states, tt.ok))
+				if x, err := strconv.ParseUint(s, 10, tt.bitSize); err != nil || x != max {
+					t.Fatalf("strconv.ParseUint(%q)=<%d,%v>, expected %d", s, x, err, max)
+				}
+
+				s = MustSolveString(t, StateAt(states, tt.outOfRange))
+				if x, err := strconv.ParseUint(s, 10, tt.bitSize); !IsErrRange(err) || x != max {
+					t.Fatalf("strconv.ParseUint(%q)=<%d,%v>, expected out of range", s, x, err)
+				}
+			})
+		}
+	})
+}
+
+// MustSolveString returns the solved value of the first symbolic string in
+// state. Fatal on error or if state is nil.
+func MustSolveString(tb testing.TB, state *glee.ExecutionState) string {
+	tb.Helper()
+	if state == nil {
+		tb.Fatal("expected state")
+	}
+	_, values, err := state.Values()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(values[0])
+}
+
+// IsErrRange returns true if err is a *strconv.NumError for a value out of range.
+func IsErrRange(err error) bool {
+	e, ok := err.(*strconv.NumError)
+	return ok && e.Err == strconv.ErrRange
+}
@@ -0,0 +1,79 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg008_UTF8(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg008_utf8")
+
+	t.Run("DecodeRuneInString", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "decodeRuneInString")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `utf8.go:13`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := string(values[0]), "é"; got != exp {
+			t.Fatalf("values[0]=%q, expected %q", got, exp)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "rangeString")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `utf8.go:26`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if _, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if got, exp := string(values[0]), "☃"; got != exp {
+			t.Fatalf("values[0]=%q, expected %q", got, exp)
+		}
+	})
+
+	t.Run("RuneToString", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "runeToString")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `utf8.go:33`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if r, err := EvalVar(state, arrays, values, fn, "r"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := rune(r.Value), 'ß'; got != exp {
+			t.Fatalf("r=%q, expected %q", got, exp)
+		}
+	})
+
+	t.Run("RuneSliceToString", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "runeSliceToString")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `utf8.go:41`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if r, err := EvalVar(state, arrays, values, fn, "r"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := rune(r.Value), 'ñ'; got != exp {
+			t.Fatalf("r=%q, expected %q", got, exp)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg009_Float(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg009_float")
+
+	t.Run("Scale", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "scale")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `float.go:11`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int32(x.Value), int32(21); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Negate", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "negate")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `float.go:19`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int32(x.Value), int32(-3); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+}
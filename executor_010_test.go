@@ -0,0 +1,106 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg010_Map(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg010_map")
+
+	t.Run("Lookup", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "lookup")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `map.go:11`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if k, err := EvalVar(state, arrays, values, fn, "k"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(k.Value), int64(20); got != exp {
+			t.Fatalf("k=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "update")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `map.go:21`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if k1, err := EvalVar(state, arrays, values, fn, "k1"); err != nil {
+			t.Fatal(err)
+		} else if k2, err := EvalVar(state, arrays, values, fn, "k2"); err != nil {
+			t.Fatal(err)
+		} else if k1.Value != k2.Value {
+			t.Fatalf("expected equal keys: %d != %d", k1.Value, k2.Value)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "rangeSum")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `map.go:33`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(9); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	// Ensure a missed lookup of a pointer element binds a nil pointer.
+	t.Run("MissingPointer", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "missingPointer")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `map.go:43`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if k, err := EvalVar(state, arrays, values, fn, "k"); err != nil {
+			t.Fatal(err)
+		} else if k.Value == 1 {
+			t.Fatalf("unexpected k: %d", k.Value)
+		}
+	})
+
+	// Ensure a missed lookup of a named integer element binds zero.
+	t.Run("MissingNamed", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "missingNamed")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `map.go:53`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if k, err := EvalVar(state, arrays, values, fn, "k"); err != nil {
+			t.Fatal(err)
+		} else if k.Value == 1 {
+			t.Fatalf("unexpected k: %d", k.Value)
+		}
+	})
+}
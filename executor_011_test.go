@@ -0,0 +1,99 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg011_Panic(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg011_panic")
+
+	t.Run("Panic", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "panics")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			}
+			found = true
+
+			if got, exp := TrimPosition(state.Position()).String(), `panic.go:10`; got != exp {
+				t.Fatalf("Position()=%s, expected %s", got, exp)
+			} else if got, exp := state.Reason(), "boom"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+				t.Fatal(err)
+			} else if got, exp := int64(x.Value), int64(10); got != exp {
+				t.Fatalf("x=%d, expected %d", got, exp)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected panicked state")
+		}
+	})
+
+	t.Run("Recover", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "recovers")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() == glee.ExecutionStatusPanicked {
+				t.Fatalf("unexpected panic: %s", state.Reason())
+			} else if instr := state.Instr(); instr == nil || instr.Block() != fn.Recover {
+				continue
+			}
+			found = true
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+				t.Fatal(err)
+			} else if got, exp := int64(x.Value), int64(10); got != exp {
+				t.Fatalf("x=%d, expected %d", got, exp)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected recovered state")
+		}
+	})
+
+	// Ensure run-time panics run deferred calls which may recover.
+	for _, tt := range []struct{ name, fn string }{
+		{"RecoverDivide", "recoversDivide"},
+		{"RecoverIndex", "recoversIndex"},
+		{"RecoverNilMap", "recoversNilMap"},
+		{"RecoverCaller", "recoversCaller"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			fn := MustFindFunction(t, prog, tt.fn)
+			e := NewExecutor(fn)
+			defer e.Close()
+
+			var found bool
+			for _, state := range MustExecuteAll(t, e) {
+				if state.Status() == glee.ExecutionStatusPanicked {
+					t.Fatalf("unexpected panic: %s", state.Reason())
+				} else if instr := state.Instr(); instr != nil && instr.Block() == fn.Recover {
+					found = true
+				}
+			}
+
+			if !found {
+				t.Fatal("expected recovered state")
+			}
+		})
+	}
+}
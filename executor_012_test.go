@@ -0,0 +1,45 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg012_Closure(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg012_closure")
+
+	t.Run("Capture", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "capture")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `closure.go:12`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(5); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Return", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "returned")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `closure.go:25`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(2); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+}
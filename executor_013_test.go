@@ -0,0 +1,35 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg013_Goroutine(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg013_goroutine")
+
+	t.Run("Interleave", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "interleave")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Value written by the goroutine is only seen if it runs first.
+		m := make(map[int64]struct{})
+		for _, state := range MustExecuteAll(t, e) {
+			if got := TrimPosition(state.Position()).String(); got != `goroutine.go:14` {
+				continue
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if v, err := EvalVar(state, arrays, values, fn, "v"); err != nil {
+				t.Fatal(err)
+			} else {
+				m[int64(v.Value)] = struct{}{}
+			}
+		}
+
+		if _, ok := m[0]; !ok {
+			t.Fatal("expected state where goroutine runs last")
+		} else if _, ok := m[1]; !ok {
+			t.Fatal("expected state where goroutine runs first")
+		}
+	})
+}
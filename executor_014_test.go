@@ -0,0 +1,101 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg014_Chan(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg014_chan")
+
+	t.Run("Buffered", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "buffered")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `chan.go:12`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(5); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Unbuffered", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "unbuffered")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `chan.go:21`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(4); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Deadlock", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "deadlock")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			}
+			found = true
+
+			if got, exp := TrimPosition(state.Position()).String(), `chan.go:31`; got != exp {
+				t.Fatalf("Position()=%s, expected %s", got, exp)
+			} else if got, exp := state.Reason(), "all goroutines are asleep - deadlock!"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected deadlocked state")
+		}
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "selectReady")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		// Each ready case should be explored.
+		m := make(map[int64]struct{})
+		for _, state := range MustExecuteAll(t, e) {
+			if got := TrimPosition(state.Position()).String(); got != `chan.go:46` {
+				continue
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+				t.Fatal(err)
+			} else {
+				m[int64(x.Value)] = struct{}{}
+			}
+		}
+
+		if _, ok := m[1]; !ok {
+			t.Fatal("expected state for first case")
+		} else if _, ok := m[2]; !ok {
+			t.Fatal("expected state for second case")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package glee_test
+
+import "testing"
+
+func TestExecutor_Pkg015_Pointer(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg015_pointer")
+
+	t.Run("StoreIndex", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "storeIndex")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `pointer.go:16`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if i, err := EvalVar(state, arrays, values, fn, "i"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(i.Value), int64(2); got != exp {
+			t.Fatalf("i=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("StorePointer", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "storePointer")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `pointer.go:30`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if i, err := EvalVar(state, arrays, values, fn, "i"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(i.Value), int64(0); got != exp {
+			t.Fatalf("i=%d, expected %d", got, exp)
+		}
+	})
+}
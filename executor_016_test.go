@@ -0,0 +1,73 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg016_TypeAssert(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg016_typeassert")
+
+	t.Run("CommaOk", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "commaOk")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `typeassert.go:11`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(x.Value), int64(7); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Switch", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "typeSwitch")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		state := StateAt(MustExecuteAll(t, e), `typeassert.go:25`)
+		if state == nil {
+			t.Fatal("expected matching state")
+		}
+
+		if arrays, values, err := state.Values(); err != nil {
+			t.Fatal(err)
+		} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+			t.Fatal(err)
+		} else if got := int64(x.Value); got <= 10 {
+			t.Fatalf("x=%d, expected greater than 10", got)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "mustAssert")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			}
+			found = true
+
+			if got, exp := TrimPosition(state.Position()).String(), `typeassert.go:31`; got != exp {
+				t.Fatalf("Position()=%s, expected %s", got, exp)
+			} else if got, exp := state.Reason(), "interface conversion: interface{} is int8, not uint16"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected panicked state")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package glee_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg017_Arith(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg017_arith")
+
+	t.Run("DivideByZero", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "divide")
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			}
+			found = true
+
+			if got, exp := TrimPosition(state.Position()).String(), `arith.go:9`; got != exp {
+				t.Fatalf("Position()=%s, expected %s", got, exp)
+			} else if got, exp := state.Reason(), "integer divide by zero"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if y, err := EvalVar(state, arrays, values, fn, "y"); err != nil {
+				t.Fatal(err)
+			} else if y.Value != 0 {
+				t.Fatalf("y=%d, expected 0", y.Value)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected panicked state")
+		}
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "overflow")
+		e := NewExecutor(fn)
+		e.CheckOverflow = true
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusOverflowed {
+				continue
+			}
+			found = true
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+				t.Fatal(err)
+			} else if got := int8(x.Value); got < 28 {
+				t.Fatalf("x=%d, expected at least 28", got)
+			}
+		}
+
+		if !found {
+			t.Fatal("expected overflowed state")
+		}
+	})
+
+	// Ensure MinInt / -1 is reported as an overflow rather than a panic.
+	t.Run("DivideOverflow", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "divideOverflow")
+		e := NewExecutor(fn)
+		e.CheckOverflow = true
+		defer e.Close()
+
+		var found bool
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() == glee.ExecutionStatusPanicked {
+				t.Fatalf("unexpected panic: %s", state.Reason())
+			} else if state.Status() != glee.ExecutionStatusOverflowed {
+				continue
+			}
+			found = true
+
+			if got, exp := state.Reason(), "integer overflow: sdiv"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if x, err := EvalVar(state, arrays, values, fn, "x"); err != nil {
+				t.Fatal(err)
+			} else if y, err := EvalVar(state, arrays, values, fn, "y"); err != nil {
+				t.Fatal(err)
+			} else if int8(x.Value) != math.MinInt8 || int8(y.Value) != -1 {
+				t.Fatalf("x=%d, y=%d, expected MinInt8 / -1", int8(x.Value), int8(y.Value))
+			}
+		}
+
+		if !found {
+			t.Fatal("expected overflowed state")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package glee_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg018_Limits(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg018_limits")
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "loop"))
+		e.MaxDepth = 3
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := len(m[glee.ExecutionStatusFinished]), 3; got != exp {
+			t.Fatalf("finished=%d, expected %d", got, exp)
+		} else if len(m[glee.ExecutionStatusExhausted]) == 0 {
+			t.Fatal("expected exhausted state")
+		}
+		for _, state := range m[glee.ExecutionStatusExhausted] {
+			if got, exp := state.Reason(), "depth limit reached"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			} else if state.Depth() != 4 {
+				t.Fatalf("unexpected depth: %d", state.Depth())
+			}
+		}
+	})
+
+	t.Run("MaxStates", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "loop"))
+		e.MaxStates = 10
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if len(m[glee.ExecutionStatusExhausted]) == 0 {
+			t.Fatal("expected exhausted state")
+		} else if got, exp := m[glee.ExecutionStatusExhausted][0].Reason(), "state limit reached"; got != exp {
+			t.Fatalf("Reason()=%q, expected %q", got, exp)
+		}
+	})
+
+	t.Run("MaxInstructions", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "spin"))
+		e.MaxInstructions = 100
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := len(m[glee.ExecutionStatusExhausted]), 1; got != exp {
+			t.Fatalf("exhausted=%d, expected %d", got, exp)
+		} else if state := m[glee.ExecutionStatusExhausted][0]; state.Reason() != "instruction limit reached" {
+			t.Fatalf("unexpected reason: %s", state.Reason())
+		} else if state.InstructionN() != 100 {
+			t.Fatalf("unexpected instruction count: %d", state.InstructionN())
+		}
+	})
+
+	t.Run("MaxTime", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "spin"))
+		e.MaxTime = time.Nanosecond
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := len(m[glee.ExecutionStatusExhausted]), 1; got != exp {
+			t.Fatalf("exhausted=%d, expected %d", got, exp)
+		} else if got, exp := m[glee.ExecutionStatusExhausted][0].Reason(), "time limit reached"; got != exp {
+			t.Fatalf("Reason()=%q, expected %q", got, exp)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg019_Builtins(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg019_builtins")
+
+	for _, tt := range []struct {
+		name     string
+		fn       string
+		position string // position a state must reach, if set
+	}{
+		{"CapSlice", "capSlice", ""},
+		{"CapChan", "capChan", ""},
+		{"New", "newInt", "builtins.go:25"},
+		{"Delete", "deleteKey", "builtins.go:32"},
+		{"MinInt", "minInt", "builtins.go:39"},
+		{"MaxUint", "maxUint", "builtins.go:46"},
+		{"MinFloat", "minFloat", "builtins.go:53"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			defer e.Close()
+
+			states := MustExecuteAll(t, e)
+			if n := CountStatus(states)[glee.ExecutionStatusPanicked]; n != 0 {
+				t.Fatalf("unexpected panics: %d", n)
+			} else if tt.position != "" && StateAt(states, tt.position) == nil {
+				t.Fatalf("expected state at %s", tt.position)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg020_Methods(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg020_methods")
+
+	for _, tt := range []struct {
+		name     string
+		fn       string
+		position string // position a state must reach, if set
+	}{
+		{"PointerMethod", "pointerMethod", "methods.go:28"},
+		{"MethodValue", "methodValue", "methods.go:36"},
+		{"MethodExpr", "methodExpr", "methods.go:43"},
+		{"Promoted", "promoted", "methods.go:51"},
+		{"BoxedReceiver", "boxedReceiver", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			defer e.Close()
+
+			states := MustExecuteAll(t, e)
+			if n := CountStatus(states)[glee.ExecutionStatusPanicked]; n != 0 {
+				t.Fatalf("unexpected panics: %d", n)
+			} else if tt.position != "" && StateAt(states, tt.position) == nil {
+				t.Fatalf("expected state at %s", tt.position)
+			}
+		})
+	}
+}
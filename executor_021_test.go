@@ -0,0 +1,45 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg021_Assert(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg021_assert")
+
+	t.Run("Assume", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "assume"))
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := len(m[glee.ExecutionStatusDropped]), 1; got != exp {
+			t.Fatalf("dropped=%d, expected %d", got, exp)
+		} else if got, exp := len(m[glee.ExecutionStatusFinished]), 0; got != exp {
+			t.Fatalf("finished=%d, expected %d", got, exp)
+		}
+	})
+
+	t.Run("Assert", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "assert"))
+		defer e.Close()
+
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := len(m[glee.ExecutionStatusFinished]), 1; got != exp {
+			t.Fatalf("finished=%d, expected %d", got, exp)
+		} else if got, exp := len(m[glee.ExecutionStatusAssertFailed]), 1; got != exp {
+			t.Fatalf("assert failed=%d, expected %d", got, exp)
+		}
+
+		// Failed state should include the counterexample & assertion position.
+		failure := m[glee.ExecutionStatusAssertFailed][0].AssertionFailure()
+		if failure == nil {
+			t.Fatal("expected assertion failure")
+		} else if got, exp := TrimPosition(failure.Pos).String(), "assert.go:16"; got != exp {
+			t.Fatalf("Pos=%s, expected %s", got, exp)
+		} else if got, exp := failure.Values[0][0], byte(15); got != exp {
+			t.Fatalf("x=%d, expected %d", got, exp)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package glee_test
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExecutor_Pkg022_Coverage(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg022_coverage")
+
+	t.Run("Branch", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "branch"))
+		defer e.Close()
+
+		MustExecuteAll(t, e)
+		c := e.Coverage()
+		if got, exp := len(c.Files), 1; got != exp {
+			t.Fatalf("len(Files)=%d, expected %d", got, exp)
+		} else if got, exp := filepath.Base(c.Files[0].Filename), "coverage.go"; got != exp {
+			t.Fatalf("Filename=%s, expected %s", got, exp)
+		}
+
+		if covered, total := c.LineN(); covered != total {
+			t.Fatalf("lines=%d/%d, expected full coverage", covered, total)
+		} else if covered, total := c.BranchN(); covered != 2 || total != 2 {
+			t.Fatalf("branches=%d/%d, expected 2/2", covered, total)
+		}
+	})
+
+	t.Run("DeadBranch", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "deadBranch"))
+		defer e.Close()
+
+		MustExecuteAll(t, e)
+		c := e.Coverage()
+		if got, exp := c.Files[0].Lines[18], false; got != exp {
+			t.Fatalf("line 18 covered=%v, expected %v", got, exp)
+		} else if got, exp := c.Files[0].Lines[20], true; got != exp {
+			t.Fatalf("line 20 covered=%v, expected %v", got, exp)
+		}
+
+		if got, exp := len(c.Branches), 1; got != exp {
+			t.Fatalf("len(Branches)=%d, expected %d", got, exp)
+		} else if bc := c.Branches[0]; bc.True || !bc.False {
+			t.Fatalf("unexpected branch coverage: %+v", bc)
+		} else if got, exp := TrimPosition(bc.Pos).String(), "coverage.go:17"; got != exp {
+			t.Fatalf("Pos=%s, expected %s", got, exp)
+		}
+	})
+}
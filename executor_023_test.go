@@ -0,0 +1,56 @@
+package glee_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg023_Concolic(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg023_concolic")
+	fn := MustFindFunction(t, prog, "magic")
+
+	var executors []*Executor
+	defer func() {
+		for _, e := range executors {
+			e.Close()
+		}
+	}()
+
+	d := &glee.ConcolicDriver{
+		NewExecutor: func() (*glee.Executor, error) {
+			e := NewExecutor(fn)
+			executors = append(executors, e)
+			return e.Executor, nil
+		},
+	}
+
+	// Starting from a zero seed, every path should be found by negation.
+	m := make(map[glee.ExecutionStatus]int)
+	n, err := d.Run(context.Background(), glee.Seed{}, func(run *glee.ConcolicRun) error {
+		if got, exp := len(run.States), 1; got != exp {
+			t.Fatalf("len(States)=%d, expected %d", got, exp)
+		}
+		m[run.States[0].Status()]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := n, 3; got != exp {
+		t.Fatalf("runs=%d, expected %d", got, exp)
+	} else if got, exp := m[glee.ExecutionStatusFinished], 2; got != exp {
+		t.Fatalf("finished=%d, expected %d", got, exp)
+	} else if got, exp := m[glee.ExecutionStatusPanicked], 1; got != exp {
+		t.Fatalf("panicked=%d, expected %d", got, exp)
+	}
+
+	t.Run("MaxRuns", func(t *testing.T) {
+		d.MaxRuns = 1
+		if n, err := d.Run(context.Background(), glee.Seed{}, func(*glee.ConcolicRun) error { return nil }); err != nil {
+			t.Fatal(err)
+		} else if n != 1 {
+			t.Fatalf("runs=%d, expected 1", n)
+		}
+	})
+}
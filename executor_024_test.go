@@ -0,0 +1,53 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg024_MakeSlice(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg024_makeslice")
+
+	// Lengths larger than the allocation limit are exhausted.
+	t.Run("SymbolicLen", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "symbolicLen"))
+		defer e.Close()
+
+		m := CountStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got, exp := m[glee.ExecutionStatusFinished], 2; got != exp {
+			t.Fatalf("finished=%d, expected %d", got, exp)
+		} else if got, exp := m[glee.ExecutionStatusExhausted], 1; got != exp {
+			t.Fatalf("exhausted=%d, expected %d", got, exp)
+		}
+	})
+
+	// A negative length panics.
+	t.Run("NegativeLen", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "negativeLen"))
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if m := CountStatus(states); m[glee.ExecutionStatusPanicked] != 1 || m[glee.ExecutionStatusFinished] != 1 || m[glee.ExecutionStatusExhausted] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+		for _, state := range states {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			} else if got, exp := state.Reason(), "makeslice: len out of range"; got != exp {
+				t.Fatalf("Reason=%q, expected %q", got, exp)
+			}
+		}
+	})
+
+	// The length of the slice & its contents are both symbolic.
+	t.Run("SymbolicByteSlice", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "symbolicByteSlice"))
+		defer e.Close()
+
+		m := CountStatus(TerminalStates(MustExecuteAll(t, e)))
+		if m[glee.ExecutionStatusFinished] != 3 || m[glee.ExecutionStatusPanicked] != 1 || m[glee.ExecutionStatusExhausted] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg025_Bounds(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg025_bounds")
+
+	// Reading past the capacity of a slice reports the access.
+	t.Run("ConstantIndex", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "constantIndex"))
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if len(states) != 1 {
+			t.Fatalf("unexpected state count: %d", len(states))
+		} else if got, exp := states[0].Status(), glee.ExecutionStatusMemoryError; got != exp {
+			t.Fatalf("Status=%s, expected %s", got, exp)
+		}
+
+		merr := states[0].MemoryError()
+		if merr == nil {
+			t.Fatal("expected memory error")
+		} else if got, exp := merr.Addr-merr.Base, uint64(6); got != exp {
+			t.Fatalf("offset=%d, expected %d", got, exp)
+		} else if got, exp := merr.Size, uint(1); got != exp {
+			t.Fatalf("Size=%d, expected %d", got, exp)
+		} else if got, exp := merr.AllocSize, uint(4); got != exp {
+			t.Fatalf("AllocSize=%d, expected %d", got, exp)
+		} else if merr.Pos.Line != 10 {
+			t.Fatalf("unexpected position: %s", merr.Pos)
+		}
+	})
+
+	// Only indexes beyond the allocation are reported.
+	t.Run("SymbolicIndex", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "symbolicIndex"))
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if m := CountStatus(states); m[glee.ExecutionStatusFinished] != 3 || m[glee.ExecutionStatusMemoryError] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+		for _, state := range states {
+			if state.Status() != glee.ExecutionStatusMemoryError {
+				continue
+			} else if merr := state.MemoryError(); merr.Addr < merr.Base+4 || merr.Addr >= merr.Base+8 {
+				t.Fatalf("unexpected address: base=%d addr=%d", merr.Base, merr.Addr)
+			}
+		}
+	})
+
+	// Stores are checked the same as loads.
+	t.Run("SymbolicStore", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "symbolicStore"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); m[glee.ExecutionStatusMemoryError] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
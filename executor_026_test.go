@@ -0,0 +1,56 @@
+package glee_test
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestExecutor_Pkg026_Named(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg026_named")
+
+	// Solved values are keyed by the name of each symbolic input.
+	t.Run("OK", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "price"))
+		defer e.Close()
+
+		var n int
+		for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+			m, err := state.NamedValues()
+			if err != nil {
+				t.Fatal(err)
+			} else if len(m) != 3 {
+				t.Fatalf("unexpected names: %v", m)
+			} else if len(m["qty"]) != 1 {
+				t.Fatalf("unexpected qty: %x", m["qty"])
+			}
+
+			if price := int64(binary.LittleEndian.Uint64(m["price"])); price > 100 && string(m["code"]) == "ab" {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Fatalf("unexpected matching state count: %d", n)
+		}
+	})
+
+	// Reusing a name for another input is an error.
+	t.Run("ErrDuplicate", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "duplicate"))
+		defer e.Close()
+
+		if _, err := e.ExecuteNextState(); err == nil || !strings.Contains(err.Error(), `duplicate name: "x"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// Only symbolic values can be named.
+	t.Run("ErrConcrete", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "concrete"))
+		defer e.Close()
+
+		if _, err := e.ExecuteNextState(); err == nil || !strings.Contains(err.Error(), `"x" is not symbolic`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
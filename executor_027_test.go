@@ -0,0 +1,31 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg027_StructCopy(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg027_structcopy")
+
+	// Nested structs & nil pointers are copied without overwriting other fields.
+	t.Run("Nested", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "copyNested"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 2 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+
+	// Arrays of structs are copied element by element.
+	t.Run("Array", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "copyArray"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 2 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg028_Strings(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg028_strings")
+
+	for _, tt := range []struct {
+		fn string
+		n  int
+	}{
+		{fn: "contains", n: 2},
+		{fn: "hasPrefix", n: 2},
+		{fn: "index", n: 4}, // 0, 1, 2, -1
+		{fn: "bytesEqual", n: 2},
+	} {
+		t.Run(tt.fn, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			defer e.Close()
+			glee.RegisterStringsModels(e.Executor)
+
+			if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != tt.n {
+				t.Fatalf("statuses=%v, expected %d finished", m, tt.n)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg029_Errors(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg029_errors")
+
+	// Error values fork on nil checks & return their message.
+	t.Run("Branch", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "branch"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 3 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+
+	// Error values are only equal to themselves.
+	t.Run("Identity", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "identity"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg030_Main(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg030_main")
+
+	// Arguments, flags & environment variables are symbolic.
+	t.Run("Symbolic", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "main"))
+		defer e.Close()
+
+		if arrays, err := e.BindSymbolicArgs(2, 2); err != nil {
+			t.Fatal(err)
+		} else if len(arrays) != 2 {
+			t.Fatalf("unexpected array count: %d", len(arrays))
+		}
+
+		// Forks on the first arg, second arg, -v flag & $HOME being set.
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 16 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+
+	// Flags keep their defaults & the environment is empty if unbound.
+	t.Run("Defaults", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "defaults"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
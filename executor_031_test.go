@@ -0,0 +1,47 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg031_Summary(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg031_summary")
+
+	// Summarized calls produce the same terminal states as executing the body.
+	for _, fn := range []string{"repeated", "nested", "fallback"} {
+		t.Run(fn, func(t *testing.T) {
+			var m [2]map[glee.ExecutionStatus]int
+			for i, summarize := range []bool{false, true} {
+				e := NewExecutor(MustFindFunction(t, prog, fn))
+				e.Summarize = summarize
+				m[i] = CountStatus(TerminalStates(MustExecuteAll(t, e)))
+				e.Close()
+			}
+
+			exp, got := m[0], m[1]
+			if exp[glee.ExecutionStatusAssertFailed] != 0 || got[glee.ExecutionStatusAssertFailed] != 0 {
+				t.Fatalf("unexpected assertion failure: %v", got)
+			} else if len(got) != len(exp) {
+				t.Fatalf("statuses=%v, expected %v", got, exp)
+			}
+			for status, n := range exp {
+				if got[status] != n {
+					t.Fatalf("statuses=%v, expected %v", got, exp)
+				}
+			}
+		})
+	}
+
+	// Functions which may panic are executed normally.
+	t.Run("Panic", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "fallback"))
+		e.Summarize = true
+		defer e.Close()
+
+		if got := CountStatus(TerminalStates(MustExecuteAll(t, e))); got[glee.ExecutionStatusPanicked] != 1 || got[glee.ExecutionStatusFinished] != 1 {
+			t.Fatalf("unexpected statuses: %v", got)
+		}
+	})
+}
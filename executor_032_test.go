@@ -0,0 +1,39 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg032_Havoc(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg032_havoc")
+
+	// Results of functions without a body are unconstrained.
+	t.Run("Body", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "body"))
+		e.Havoc = true
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 2 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+
+	// Functions which fail to execute return an error unless havoc is enabled.
+	t.Run("Unsupported", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "unsupported"))
+		defer e.Close()
+		if _, err := ExecuteAll(e.Executor); err == nil {
+			t.Fatal("expected error")
+		}
+
+		e = NewExecutor(MustFindFunction(t, prog, "unsupported"))
+		e.Havoc = true
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 2 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+func TestExecutor_Pkg033_Methods(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg033_methods")
+	pkg := MustFindFunction(t, prog, "callA").Pkg
+
+	// nop is a handler which skips the call.
+	nop := func(state *glee.ExecutionState, instr *ssa.Call) error { return nil }
+
+	// Handlers registered by function only apply to that method.
+	t.Run("Func", func(t *testing.T) {
+		for _, tt := range []struct {
+			fn  string
+			exp glee.ExecutionStatus
+		}{
+			{"callA", glee.ExecutionStatusFinished},
+			{"callIface", glee.ExecutionStatusFinished},
+			{"callB", glee.ExecutionStatusPanicked},
+		} {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			e.RegisterFunc(prog.LookupMethod(pkg.Type("A").Type(), nil, "Fail"), nop)
+			states := TerminalStates(MustExecuteAll(t, e))
+			e.Close()
+
+			if len(states) != 1 || states[0].Status() != tt.exp {
+				t.Fatalf("%s: statuses=%v, expected %s", tt.fn, CountStatus(states), tt.exp)
+			}
+		}
+	})
+
+	// Handlers registered by name apply to methods on every receiver.
+	t.Run("Name", func(t *testing.T) {
+		for _, fn := range []string{"callA", "callB"} {
+			e := NewExecutor(MustFindFunction(t, prog, fn))
+			e.Register(pkg.Pkg.Path(), "Fail", nop)
+			states := TerminalStates(MustExecuteAll(t, e))
+			e.Close()
+
+			if len(states) != 1 || states[0].Status() != glee.ExecutionStatusFinished {
+				t.Fatalf("%s: statuses=%v, expected %s", fn, CountStatus(states), glee.ExecutionStatusFinished)
+			}
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg034_Testing(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg034_testing")
+
+	// Errors fail the path once it completes, fatal errors fail it
+	// immediately, & skipped paths are dropped.
+	t.Run("Range", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "TestRange"))
+		defer e.Close()
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got := len(m[glee.ExecutionStatusFinished]); got != 1 {
+			t.Fatalf("finished=%d, expected 1", got)
+		} else if got := m[glee.ExecutionStatusDropped]; len(got) != 1 || got[0].Reason() != "test skipped" {
+			t.Fatalf("unexpected dropped states: %d", len(got))
+		}
+
+		failed := m[glee.ExecutionStatusFailed]
+		if len(failed) != 2 {
+			t.Fatalf("failed=%d, expected 2", len(failed))
+		}
+		for _, state := range failed {
+			if reason := state.Reason(); reason != "too large: %d" && reason != "t.Fatal() called" {
+				t.Fatalf("unexpected reason: %q", reason)
+			}
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "TestFailed"))
+		defer e.Close()
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		m := StatesByStatus(TerminalStates(MustExecuteAll(t, e)))
+		if got := m[glee.ExecutionStatusFailed]; len(got) != 1 || got[0].Reason() != "t.Error() called" {
+			t.Fatalf("unexpected failed states: %d", len(got))
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg035_Print(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg035_print")
+
+	e := NewExecutor(MustFindFunction(t, prog, "printing"))
+	defer e.Close()
+
+	// Printing does not fork so only the final branch creates paths.
+	var n int
+	for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+		if state.Status() != glee.ExecutionStatusFinished {
+			t.Fatalf("unexpected state: status=%s reason=%q", state.Status(), state.Reason())
+		}
+		n++
+	}
+	if n != 2 {
+		t.Fatalf("finished=%d, expected 2", n)
+	}
+}
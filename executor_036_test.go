@@ -0,0 +1,48 @@
+package glee_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExecutor_WriteStateTree(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg036_tree")
+
+	e := NewExecutor(MustFindFunction(t, prog, "classify"))
+	e.RecordStateTree = true
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+	MustExecuteAll(t, e)
+
+	var buf bytes.Buffer
+	if err := e.WriteStateTree(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+
+	if !strings.HasPrefix(s, "digraph states {") {
+		t.Fatalf("unexpected output: %s", s)
+	} else if n := strings.Count(s, "->"); n < 2 {
+		t.Fatalf("edges=%d, expected at least 2:\n%s", n, s)
+	} else if !strings.Contains(s, "panicked") {
+		t.Fatalf("expected panicked state:\n%s", s)
+	} else if !strings.Contains(s, "finished") {
+		t.Fatalf("expected finished state:\n%s", s)
+	}
+}
+
+func TestExecutor_WriteStateTree_ErrNotRecorded(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg036_tree")
+
+	e := NewExecutor(MustFindFunction(t, prog, "classify"))
+	defer e.Close()
+
+	var buf bytes.Buffer
+	if err := e.WriteStateTree(&buf); err == nil || err.Error() != "glee.Executor: state tree not recorded" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package glee_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Reset(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg037_determinism")
+	fn := MustFindFunction(t, prog, "allocating")
+
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	// Resetting the executor should reproduce the same states.
+	a := StateStrings(TerminalStates(MustExecuteAll(t, e)))
+	e.Reset(glee.NewDFSSearcher())
+	if b := StateStrings(TerminalStates(MustExecuteAll(t, e))); fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Fatalf("unexpected states after reset:\n%v\n%v", a, b)
+	}
+
+	// A new executor should also reproduce the same states.
+	other := NewExecutor(fn)
+	defer other.Close()
+	if b := StateStrings(TerminalStates(MustExecuteAll(t, other))); fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Fatalf("unexpected states on new executor:\n%v\n%v", a, b)
+	}
+}
+
+func TestExecutor_HeapBase(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg037_determinism")
+
+	e := NewExecutor(MustFindFunction(t, prog, "allocating"))
+	e.HeapBase = 0x10000
+	defer e.Close()
+
+	a := StateStrings(TerminalStates(MustExecuteAll(t, e)))
+	if len(a) == 0 {
+		t.Fatal("expected states")
+	} else if s := fmt.Sprint(a); !strings.Contains(s, fmt.Sprintf("(array #%d ", e.HeapBase)) {
+		t.Fatalf("expected allocation at heap base: %s", s)
+	}
+}
+
+// StateStrings returns a description of each state within a.
+func StateStrings(a []*glee.ExecutionState) []string {
+	other := make([]string, len(a))
+	for i, state := range a {
+		other[i] = fmt.Sprintf("#%d %s %q %v", state.ID(), state.Status(), state.Reason(), state.Constraints())
+	}
+	return other
+}
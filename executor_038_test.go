@@ -0,0 +1,53 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg038_Unsafe(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg038_unsafe")
+
+	// Offsets within the array are valid. Others are either an invalid
+	// pointer conversion or an out of bounds read at the end of the array.
+	t.Run("Index", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "index"))
+		defer e.Close()
+
+		m := CountStatus(TerminalStates(MustExecuteAll(t, e)))
+		if m[glee.ExecutionStatusFinished] == 0 {
+			t.Fatalf("expected finished states: %v", m)
+		} else if m[glee.ExecutionStatusMemoryError] == 0 {
+			t.Fatalf("expected memory error states: %v", m)
+		}
+	})
+
+	t.Run("Roundtrip", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "roundtrip"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 1 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "compare"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 3 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+
+	// Every address in the low page is below the heap so is invalid.
+	t.Run("Invalid", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "invalid"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusMemoryError] != 1 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg039_Concat(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg039_concat")
+
+	for _, fn := range []string{"build", "compare"} {
+		t.Run(fn, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, fn))
+			defer e.Close()
+
+			// Each function should only fork on its final condition.
+			var n int
+			for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+				if state.Status() != glee.ExecutionStatusFinished {
+					t.Fatalf("unexpected state: status=%s reason=%q", state.Status(), state.Reason())
+				}
+				n++
+			}
+			if n != 2 {
+				t.Fatalf("finished=%d, expected 2", n)
+			}
+		})
+	}
+}
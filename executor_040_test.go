@@ -0,0 +1,37 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg040_Tuple(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg040_tuple")
+
+	for _, tt := range []struct {
+		fn string
+		n  int
+	}{
+		{"pointers", 2},
+		{"interfaces", 2},
+		{"structs", 3},
+	} {
+		t.Run(tt.fn, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			defer e.Close()
+
+			// Every path should finish with all results bound in the caller.
+			var n int
+			for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+				if state.Status() != glee.ExecutionStatusFinished {
+					t.Fatalf("unexpected state: status=%s reason=%q", state.Status(), state.Reason())
+				}
+				n++
+			}
+			if n != tt.n {
+				t.Fatalf("finished=%d, expected %d", n, tt.n)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package glee_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg041_Unsupported(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg041_unsupported")
+
+	// Ensure the error is returned & exploration stops by default.
+	t.Run("FailFast", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "complement"))
+		defer e.Close()
+
+		if _, err := ExecuteAll(e.Executor); err == nil {
+			t.Fatal("expected error")
+		} else if !strings.Contains(err.Error(), "not supported") {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	// Ensure only the path with the error is terminated.
+	t.Run("TerminatePath", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "complement"))
+		e.ErrorPolicy = glee.ErrorPolicyTerminatePath
+		defer e.Close()
+
+		m := make(map[glee.ExecutionStatus]int)
+		for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+			m[state.Status()]++
+			if state.Status() == glee.ExecutionStatusUnsupported && !strings.Contains(state.Reason(), "not supported") {
+				t.Fatalf("unexpected reason: %s", state.Reason())
+			}
+		}
+		if len(m) != 2 || m[glee.ExecutionStatusFinished] != 1 || m[glee.ExecutionStatusUnsupported] != 1 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg042_Budget(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg042_budget")
+
+	// Ensure resources used by each path are tracked.
+	t.Run("Accounting", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "alloc"))
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if len(states) != 2 {
+			t.Fatalf("unexpected state count: %d", len(states))
+		}
+		for _, state := range states {
+			if state.InstructionN() == 0 {
+				t.Fatal("expected instruction count")
+			} else if state.SolverTime() <= 0 {
+				t.Fatal("expected solver time")
+			}
+		}
+
+		// The larger allocation is on the first path explored.
+		if n := states[0].MemoryN(); n < 4096 {
+			t.Fatalf("unexpected memory: %d", n)
+		} else if n := states[1].MemoryN(); n >= 4096 {
+			t.Fatalf("unexpected memory: %d", n)
+		}
+	})
+
+	// Ensure a path exceeding its memory budget is exhausted by default.
+	t.Run("Exhaust", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "alloc"))
+		e.MaxMemory = 1024
+		defer e.Close()
+
+		m := make(map[glee.ExecutionStatus]int)
+		for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+			m[state.Status()]++
+		}
+		if len(m) != 2 || m[glee.ExecutionStatusFinished] != 1 || m[glee.ExecutionStatusExhausted] != 1 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+
+	// Ensure a path exceeding its memory budget completes after all other paths.
+	t.Run("Deprioritize", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "alloc"))
+		e.MaxMemory = 1024
+		e.BudgetPolicy = glee.BudgetPolicyDeprioritize
+		defer e.Close()
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if len(states) != 2 {
+			t.Fatalf("unexpected state count: %d", len(states))
+		}
+		for _, state := range states {
+			if state.Status() != glee.ExecutionStatusFinished {
+				t.Fatalf("unexpected status: %s", state.Status())
+			}
+		}
+		if states[0].Deprioritized() {
+			t.Fatal("expected first path to not be deprioritized")
+		} else if !states[1].Deprioritized() {
+			t.Fatal("expected last path to be deprioritized")
+		}
+	})
+}
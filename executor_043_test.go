@@ -0,0 +1,41 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg043_Switch(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg043_switch")
+
+	e := NewExecutor(MustFindFunction(t, prog, "classify"))
+	defer e.Close()
+
+	// Each case & the default should be forked from the first comparison.
+	var n int
+	for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+		if state.Status() != glee.ExecutionStatusFinished {
+			t.Fatalf("unexpected state: status=%s reason=%q", state.Status(), state.Reason())
+		} else if state.Depth() != 1 {
+			t.Fatalf("unexpected depth: %d", state.Depth())
+		}
+		n++
+	}
+	if n != 9 {
+		t.Fatalf("finished=%d, expected 9", n)
+	}
+
+	// A chain of branches requires two queries per case. One query is
+	// required per case when forked together.
+	if stats := e.QueryStats(); stats.QueryN > 9 {
+		t.Fatalf("unexpected query count: %d", stats.QueryN)
+	}
+
+	// Every branch of the chain is covered in both directions.
+	for _, bc := range e.Coverage().Branches {
+		if !bc.True || !bc.False {
+			t.Fatalf("expected full branch coverage: %s", bc.Pos)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package glee_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg044_Annotation(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg044_annotation")
+
+	// Ensure a skipped function is approximated instead of executed.
+	t.Run("Skip", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "skip"))
+		defer e.Close()
+
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 2 {
+			t.Fatalf("unexpected states: %v", m)
+		}
+	})
+
+	// Ensure states forked after the limit are exhausted.
+	t.Run("MaxPaths", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "limited"))
+		defer e.Close()
+
+		m := CountStatus(TerminalStates(MustExecuteAll(t, e)))
+		if m[glee.ExecutionStatusExhausted] == 0 {
+			t.Fatalf("expected exhausted states: %v", m)
+		} else if n := m[glee.ExecutionStatusFinished] + m[glee.ExecutionStatusExhausted]; n >= 256 {
+			t.Fatalf("unexpected state count: %d", n)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "invalid"))
+		defer e.Close()
+
+		if _, err := ExecuteAll(e.Executor); err == nil {
+			t.Fatal("expected error")
+		} else if !strings.Contains(err.Error(), "invalid maxpaths annotation") {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+}
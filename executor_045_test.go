@@ -0,0 +1,95 @@
+package glee_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+func TestExecutor_Pkg045_Arch(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg045_arch")
+
+	for _, tt := range []struct {
+		osArch string
+		is32   bool
+	}{
+		{"linux/amd64", false},
+		{"linux/arm64", false},
+		{"linux/386", true},
+		{"linux/arm", true},
+		{"js/wasm", false},
+	} {
+		t.Run(tt.osArch, func(t *testing.T) {
+			a := strings.Split(tt.osArch, "/")
+
+			// Ensure int arithmetic is performed at the width of the target.
+			t.Run("Wrap", func(t *testing.T) {
+				e := NewExecutor(MustFindFunction(t, prog, "wrap"))
+				e.OS, e.Arch = a[0], a[1]
+				defer e.Close()
+
+				if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); (m[glee.ExecutionStatusAssertFailed] > 0) != tt.is32 {
+					t.Fatalf("unexpected assertion failure: %v", m)
+				}
+			})
+
+			// Ensure lengths & narrow indices match the width of the target.
+			t.Run("Length", func(t *testing.T) {
+				e := NewExecutor(MustFindFunction(t, prog, "length"))
+				e.OS, e.Arch = a[0], a[1]
+				defer e.Close()
+
+				if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] == 0 {
+					t.Fatalf("unexpected states: %v", m)
+				}
+			})
+
+			// Ensure slice headers are sized by the pointer width of the target.
+			t.Run("SliceHeader", func(t *testing.T) {
+				e := NewExecutor(MustFindFunction(t, prog, "header"))
+				e.OS, e.Arch = a[0], a[1]
+				defer e.Close()
+
+				var tracer SliceHeaderTracer
+				e.Tracer = &tracer
+				MustExecuteAll(t, e)
+
+				exp := uint(24)
+				if tt.is32 {
+					exp = 12
+				}
+				if len(tracer.Sizes) != 2 {
+					t.Fatalf("unexpected headers: %v", tracer.Sizes)
+				}
+				for name, size := range tracer.Sizes {
+					if size != exp {
+						t.Fatalf("%s: header size=%d, expected %d", name, size, exp)
+					}
+				}
+			})
+		})
+	}
+}
+
+// SliceHeaderTracer records the size of each slice header created by a
+// conversion or make instruction once it is used as an operand.
+type SliceHeaderTracer struct {
+	Tracer
+	Sizes map[string]uint
+}
+
+func (t *SliceHeaderTracer) OnInstruction(state *glee.ExecutionState, instr ssa.Instruction) {
+	for _, op := range instr.Operands(nil) {
+		switch v := (*op).(type) {
+		case *ssa.Convert, *ssa.MakeSlice:
+			if hdr, ok := state.Eval(v).(*glee.Array); ok {
+				if t.Sizes == nil {
+					t.Sizes = make(map[string]uint)
+				}
+				t.Sizes[v.String()] = hdr.Size
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package glee_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecutor_Pkg046_Format(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg046_format")
+	e := NewExecutor(MustFindFunction(t, prog, "compare"))
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure arrays are named by the parameter & the intrinsic call site.
+	var n int
+	for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+		for _, c := range state.Constraints() {
+			s := state.FormatExpr(c)
+			if !strings.Contains(s, "x") || !strings.Contains(s, "glee.Int@main.go:11") {
+				t.Fatalf("unexpected condition: %s", s)
+			}
+			n++
+		}
+	}
+	if n != 2 {
+		t.Fatalf("unexpected condition count: %d", n)
+	}
+}
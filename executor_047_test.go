@@ -0,0 +1,83 @@
+package glee_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg047_Replay(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg047_replay")
+	fn := MustFindFunction(t, prog, "classify")
+
+	// explore returns a description of each terminal state in the subtree
+	// explored by searcher along with the fork paths of offloaded states.
+	explore := func(t *testing.T, searcher *glee.ReplaySearcher, offloadN int) (terminal []string, offloaded [][]int) {
+		t.Helper()
+
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.Reset(searcher)
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+
+		for {
+			state, err := e.ExecuteNextState()
+			if err == glee.ErrNoStateAvailable {
+				sort.Strings(terminal)
+				return terminal, offloaded
+			} else if err != nil {
+				t.Fatal(err)
+			} else if state.Terminated() {
+				if !searcher.Contains(state) {
+					t.Fatalf("terminal state outside of subtree: %v", state.ForkPath())
+				}
+				terminal = append(terminal, fmt.Sprintf("%v %s %v", state.ForkPath(), state.Status(), state.Constraints()))
+			}
+
+			if len(offloaded) < offloadN {
+				for _, other := range searcher.Offload(offloadN - len(offloaded)) {
+					offloaded = append(offloaded, other.ForkPath())
+				}
+			}
+		}
+	}
+
+	all, _ := explore(t, glee.NewReplaySearcher(nil), 0)
+	if len(all) < 4 {
+		t.Fatalf("expected multiple paths: %v", all)
+	}
+
+	// Ensure subtrees explored separately reproduce the same paths.
+	t.Run("Subtrees", func(t *testing.T) {
+		var a []string
+		for _, prefix := range [][]int{{0}, {1}} {
+			terminal, _ := explore(t, glee.NewReplaySearcher(prefix), 0)
+			if len(terminal) == 0 {
+				t.Fatalf("expected paths below %v", prefix)
+			}
+			a = append(a, terminal...)
+		}
+		if sort.Strings(a); fmt.Sprint(a) != fmt.Sprint(all) {
+			t.Fatalf("unexpected paths:\n%v\n%v", a, all)
+		}
+	})
+
+	// Ensure offloaded states are not explored & can be explored separately.
+	t.Run("Offload", func(t *testing.T) {
+		a, offloaded := explore(t, glee.NewReplaySearcher(nil), 2)
+		if len(offloaded) == 0 {
+			t.Fatal("expected offloaded states")
+		}
+		for len(offloaded) > 0 {
+			terminal, other := explore(t, glee.NewReplaySearcher(offloaded[0]), 1)
+			a, offloaded = append(a, terminal...), append(offloaded[1:], other...)
+		}
+		if sort.Strings(a); fmt.Sprint(a) != fmt.Sprint(all) {
+			t.Fatalf("unexpected paths:\n%v\n%v", a, all)
+		}
+	})
+}
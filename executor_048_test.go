@@ -0,0 +1,114 @@
+package glee_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg048_Unknown(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg048_unknown")
+	fn := MustFindFunction(t, prog, "sign")
+
+	t.Run("FailFast", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.Executor.Solver = &timeoutSolver{Solver: e.Solver, n: 1}
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ExecuteAll(e.Executor); err != glee.ErrSolverTimeout {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// Ensure timed out queries are retried with a doubled timeout.
+	t.Run("Retry", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		solver := &timeoutSolver{Solver: e.Solver, n: 2}
+		e.Executor.Solver = solver
+		e.QueryTimeout, e.QueryRetries = time.Second, 2
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		if states := TerminalStates(MustExecuteAll(t, e)); len(states) != 3 {
+			t.Fatalf("unexpected path count: %d", len(states))
+		} else if len(solver.timeouts) < 3 {
+			t.Fatalf("unexpected timeouts: %v", solver.timeouts)
+		} else if solver.timeouts[1] <= time.Second || solver.timeouts[2] <= 2*time.Second {
+			t.Fatalf("expected doubled timeouts: %v", solver.timeouts)
+		}
+	})
+
+	// Ensure undecided branches are not explored but the path continues. The
+	// first query decides the x >= 0 branch so only the x < 0 path finishes.
+	t.Run("SkipBranch", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.Executor.Solver = &timeoutSolver{Solver: e.Solver, n: 1}
+		e.UnknownPolicy = glee.UnknownPolicySkipBranch
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		if m := CountStatus(TerminalStates(MustExecuteAll(t, e))); len(m) != 1 || m[glee.ExecutionStatusFinished] != 1 {
+			t.Fatalf("unexpected statuses: %v", m)
+		}
+	})
+
+	// Ensure only the path making an undecided query is terminated.
+	t.Run("TerminatePath", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.Executor.Solver = &timeoutSolver{Solver: e.Solver, n: 1}
+		e.UnknownPolicy = glee.UnknownPolicyTerminatePath
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+
+		if exhausted := CountStatus(MustExecuteAll(t, e))[glee.ExecutionStatusExhausted]; exhausted != 1 {
+			t.Fatalf("unexpected exhausted path count: %d", exhausted)
+		}
+	})
+}
+
+func TestParseUnknownPolicy(t *testing.T) {
+	for _, p := range []glee.UnknownPolicy{glee.UnknownPolicyFailFast, glee.UnknownPolicyTerminatePath, glee.UnknownPolicySkipBranch} {
+		if other, err := glee.ParseUnknownPolicy(p.String()); err != nil {
+			t.Fatal(err)
+		} else if other != p {
+			t.Fatalf("unexpected policy: %s", other)
+		}
+	}
+	if _, err := glee.ParseUnknownPolicy("foo"); err == nil || err.Error() != `glee: invalid unknown policy: "foo"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// timeoutSolver wraps a solver & fails the first n queries with
+// ErrSolverTimeout. Records the timeout of each query's context.
+type timeoutSolver struct {
+	glee.Solver
+	n        int
+	timeouts []time.Duration
+}
+
+func (s *timeoutSolver) SolveContext(ctx context.Context, constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	s.timeouts = append(s.timeouts, timeout)
+
+	if s.n > 0 {
+		s.n--
+		return false, nil, glee.ErrSolverTimeout
+	}
+	return glee.SolveContext(ctx, s.Solver, constraints, arrays)
+}
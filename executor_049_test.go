@@ -0,0 +1,82 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg049_ExprLimit(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg049_exprlimit")
+	fn := MustFindFunction(t, prog, "hash")
+
+	t.Run("Unlimited", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		if _, err := e.BindSymbolicParams(8); err != nil {
+			t.Fatal(err)
+		}
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if len(states) != 2 {
+			t.Fatalf("unexpected path count: %d", len(states))
+		}
+		for _, state := range states {
+			if hasConcretizedConstraint(state) {
+				t.Fatal("unexpected concretized constraint")
+			}
+		}
+	})
+
+	// Ensure deep expressions are replaced by a value consistent with the path.
+	t.Run("MaxExprDepth", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.MaxExprDepth = 8
+		if _, err := e.BindSymbolicParams(8); err != nil {
+			t.Fatal(err)
+		}
+
+		states := TerminalStates(MustExecuteAll(t, e))
+		if len(states) == 0 {
+			t.Fatal("expected terminal states")
+		}
+		for _, state := range states {
+			if state.Status() != glee.ExecutionStatusFinished {
+				t.Fatalf("unexpected status: %s (%s)", state.Status(), state.Reason())
+			} else if !hasConcretizedConstraint(state) {
+				t.Fatal("expected concretized constraint")
+			} else if _, _, err := state.Values(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("MaxExprSize", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+		e.MaxExprSize = 16
+		if _, err := e.BindSymbolicParams(8); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+			if !hasConcretizedConstraint(state) {
+				t.Fatal("expected concretized constraint")
+			}
+		}
+	})
+}
+
+// hasConcretizedConstraint returns true if state constrains an opaque
+// expression to a single value.
+func hasConcretizedConstraint(state *glee.ExecutionState) bool {
+	for _, constraint := range state.Constraints() {
+		if expr, ok := constraint.(*glee.BinaryExpr); ok && expr.Op == glee.EQ {
+			if _, ok := expr.RHS.(*glee.NotOptimizedExpr); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
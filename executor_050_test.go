@@ -0,0 +1,65 @@
+package glee_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutionState_DiffHeap(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg050_heapdiff")
+
+	e := NewExecutor(MustFindFunction(t, prog, "update"))
+	e.RecordStateTree = true
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// States are checked as they are returned since fully explored parents are
+	// pruned from the tree once the next state is executed.
+	var n int
+	for {
+		state, err := e.ExecuteNextState()
+		if err == glee.ErrNoStateAvailable {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		} else if !state.Terminated() {
+			continue
+		}
+		n++
+
+		if diffs := state.DiffHeap(state); len(diffs) != 0 {
+			t.Fatalf("unexpected self diff: %v", diffs)
+		}
+
+		parent := state.Parent()
+		if parent == nil {
+			t.Fatal("expected parent state")
+		}
+
+		// Each branch writes a single int element of the slice.
+		diffs := state.DiffHeap(parent)
+		if len(diffs) != 1 {
+			t.Fatalf("diffs=%v, expected one", diffs)
+		} else if diff := diffs[0]; diff.Old == nil || diff.New == nil {
+			t.Fatalf("expected changed allocation: %s", diff)
+		} else if got, want := diff.Bytes(), uint(8); got != want {
+			t.Fatalf("Bytes()=%d, want %d", got, want)
+		}
+	}
+	if n != 2 {
+		t.Fatalf("terminated states=%d, expected 2", n)
+	}
+
+	var buf bytes.Buffer
+	if err := e.WriteStateTree(&buf); err != nil {
+		t.Fatal(err)
+	} else if s := buf.String(); strings.Count(s, "heap ~") != 2 {
+		t.Fatalf("expected a heap change on each branch:\n%s", s)
+	}
+}
@@ -0,0 +1,48 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg051_Indirect(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg051_indirect")
+
+	fn := MustFindFunction(t, prog, "apply")
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Out of range indexes return early, each function in the table is called
+	// once & the nil entry panics.
+	var finished, panicked int
+	for _, state := range MustExecuteAll(t, e) {
+		switch state.Status() {
+		case glee.ExecutionStatusFinished:
+			finished++
+		case glee.ExecutionStatusPanicked:
+			panicked++
+			if got, exp := state.Reason(), "invalid memory address or nil pointer dereference"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+
+			if arrays, values, err := state.Values(); err != nil {
+				t.Fatal(err)
+			} else if i, err := EvalVar(state, arrays, values, fn, "i"); err != nil {
+				t.Fatal(err)
+			} else if i.Value != 3 {
+				t.Fatalf("i=%d, expected 3", i.Value)
+			}
+		}
+	}
+
+	if finished != 5 {
+		t.Fatalf("finished=%d, expected 5", finished)
+	} else if panicked != 1 {
+		t.Fatalf("panicked=%d, expected 1", panicked)
+	}
+}
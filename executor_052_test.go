@@ -0,0 +1,69 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg052_Embed(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg052_embed")
+
+	// Each function branches on the result of the method so both paths are
+	// only feasible if the method was dispatched & executed symbolically.
+	for _, fn := range []string{"promoted", "promotedPointer", "pointerReceiver", "embeddedInterface"} {
+		t.Run(fn, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, fn))
+			defer e.Close()
+
+			if _, err := e.BindSymbolicParams(0); err != nil {
+				t.Fatal(err)
+			}
+			m := StatesByStatus(MustExecuteAll(t, e))
+			finished, panicked := m[glee.ExecutionStatusFinished], m[glee.ExecutionStatusPanicked]
+			if len(finished) != 2 {
+				t.Fatalf("finished=%d, expected 2", len(finished))
+			} else if len(panicked) != 0 {
+				t.Fatalf("unexpected panic: %s", panicked[0].Reason())
+			}
+		})
+	}
+
+	t.Run("NilPointerReceiver", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "nilPointerReceiver"))
+		defer e.Close()
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		m := StatesByStatus(MustExecuteAll(t, e))
+		finished, panicked := m[glee.ExecutionStatusFinished], m[glee.ExecutionStatusPanicked]
+		if len(finished) != 0 {
+			t.Fatalf("finished=%d, expected 0", len(finished))
+		} else if len(panicked) != 1 {
+			t.Fatalf("panicked=%d, expected 1", len(panicked))
+		} else if got, exp := panicked[0].Reason(), "value method main.Square.Area called using nil *Square pointer"; got != exp {
+			t.Fatalf("Reason()=%q, expected %q", got, exp)
+		}
+	})
+
+	// Out of range indexes return early, each shape is dispatched to its own
+	// method & the nil shape panics.
+	t.Run("Dispatch", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "dispatch"))
+		defer e.Close()
+
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+		m := StatesByStatus(MustExecuteAll(t, e))
+		finished, panicked := m[glee.ExecutionStatusFinished], m[glee.ExecutionStatusPanicked]
+		if len(finished) != 5 {
+			t.Fatalf("finished=%d, expected 5", len(finished))
+		} else if len(panicked) != 1 {
+			t.Fatalf("panicked=%d, expected 1", len(panicked))
+		} else if got, exp := panicked[0].Reason(), "invalid memory address or nil pointer dereference"; got != exp {
+			t.Fatalf("Reason()=%q, expected %q", got, exp)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package glee_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Vet(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg053_vet")
+
+	e := NewExecutor(MustFindFunction(t, prog, "check"))
+	defer e.Close()
+
+	a, err := e.Vet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := make(map[string]string)
+	for _, u := range a {
+		m[u.Instr.Parent().Name()] = u.Reason
+	}
+	if exp := map[string]string{
+		"On":      "not operator",
+		"negate":  "negation operator",
+		"check$1": "xor operator",
+	}; !reflect.DeepEqual(m, exp) {
+		t.Fatalf("unexpected unsupported instructions: %v", m)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg053_vet")
+
+	// Ensure only instructions within the function body are scanned.
+	t.Run("CallsNotFollowed", func(t *testing.T) {
+		if a := glee.Unsupported(MustFindFunction(t, prog, "check")); len(a) != 0 {
+			t.Fatalf("unexpected unsupported instructions: %d", len(a))
+		}
+	})
+
+	t.Run("Reason", func(t *testing.T) {
+		fn := MustFindFunction(t, prog, "check").AnonFuncs[0]
+		if a := glee.Unsupported(fn); len(a) != 1 {
+			t.Fatalf("unexpected unsupported instructions: %d", len(a))
+		} else if got, exp := a[0].Reason, "xor operator"; got != exp {
+			t.Fatalf("Reason=%q, expected %q", got, exp)
+		} else if a[0].Instr.Parent() != fn {
+			t.Fatalf("unexpected parent: %s", a[0].Instr.Parent())
+		} else if a[0].Callee != nil {
+			t.Fatalf("unexpected callee: %s", a[0].Callee)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package glee_test
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg054_Receiver(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg054_receiver")
+
+	// Look up the method on the pointer receiver type.
+	pkg := MustFindFunction(t, prog, "main").Pkg
+	typ := pkg.Type("Account").Type()
+	fn := prog.MethodValue(prog.MethodSets.MethodSet(types.NewPointer(typ)).Lookup(pkg.Pkg, "Withdraw"))
+
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Frozen accounts return early, withdrawals over the limit panic & the
+	// remaining withdrawals finish.
+	var finished, panicked int
+	for _, state := range MustExecuteAll(t, e) {
+		switch state.Status() {
+		case glee.ExecutionStatusFinished:
+			finished++
+		case glee.ExecutionStatusPanicked:
+			panicked++
+			if got, exp := state.Reason(), "over limit"; got != exp {
+				t.Fatalf("Reason()=%q, expected %q", got, exp)
+			}
+		}
+	}
+
+	if finished != 2 {
+		t.Fatalf("finished=%d, expected 2", finished)
+	} else if panicked != 1 {
+		t.Fatalf("panicked=%d, expected 1", panicked)
+	}
+}
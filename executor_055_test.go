@@ -0,0 +1,62 @@
+package glee_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg055_Checkpoint(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg055_checkpoint")
+
+	e := NewExecutor(MustFindFunction(t, prog, "classify"))
+	defer e.Close()
+
+	if _, err := e.BindSymbolicParams(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel exploration after the first two terminal states.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	for {
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == context.Canceled {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		} else if state.Terminated() {
+			if n++; n == 2 {
+				cancel()
+			}
+		}
+	}
+
+	paths := e.Checkpoint()
+	if len(paths) == 0 {
+		t.Fatal("expected unexplored states")
+	}
+
+	// Resume each checkpointed subtree with a replay from the root. Together
+	// they must produce the remaining terminal states exactly once.
+	for _, path := range paths {
+		searcher := glee.NewReplaySearcher(path)
+		e.Reset(searcher)
+		if _, err := e.BindSymbolicParams(0); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, state := range TerminalStates(MustExecuteAll(t, e)) {
+			if searcher.Contains(state) {
+				n++
+			}
+		}
+	}
+
+	if n != 6 {
+		t.Fatalf("terminal states=%d, expected 6", n)
+	}
+}
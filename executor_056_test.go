@@ -0,0 +1,68 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExecutor_Pkg056_SliceBytes(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg056_slicebytes")
+
+	// Ensure subslices with a symbolic offset or length select the bytes of
+	// their backing array. Only the expected offset or length can match.
+	for _, tt := range []struct{ name, fn string }{{"Offset", "offset"}, {"Length", "length"}} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(MustFindFunction(t, prog, tt.fn))
+			defer e.Close()
+
+			if _, err := e.BindSymbolicParams(0); err != nil {
+				t.Fatal(err)
+			}
+
+			var found int
+			for _, state := range MustExecuteAll(t, e) {
+				if state.Status() != glee.ExecutionStatusPanicked {
+					continue
+				} else if got, exp := state.Reason(), "found"; got != exp {
+					t.Fatalf("Reason()=%q, expected %q", got, exp)
+				}
+				found++
+			}
+			if found != 1 {
+				t.Fatalf("found=%d, expected 1", found)
+			}
+		})
+	}
+
+	// Ensure a subslice aliases the symbolic bytes of its backing array.
+	t.Run("Content", func(t *testing.T) {
+		e := NewExecutor(MustFindFunction(t, prog, "content"))
+		defer e.Close()
+
+		if _, err := e.BindSymbolicParams(4); err != nil {
+			t.Fatal(err)
+		}
+
+		var found int
+		for _, state := range MustExecuteAll(t, e) {
+			if state.Status() != glee.ExecutionStatusPanicked {
+				continue
+			}
+			found++
+
+			_, values, err := state.Values()
+			if err != nil {
+				t.Fatal(err)
+			} else if len(values) != 1 {
+				t.Fatalf("len(values)=%d, expected 1", len(values))
+			} else if got, exp := string(values[0][1:3]), "hi"; got != exp {
+				t.Fatalf("b[1:3]=%q, expected %q", got, exp)
+			}
+		}
+		if found != 1 {
+			t.Fatalf("found=%d, expected 1", found)
+		}
+	})
+}
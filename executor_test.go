@@ -2,11 +2,17 @@ package glee_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/benbjohnson/glee"
 	"github.com/benbjohnson/glee/z3"
@@ -77,10 +83,16 @@ func MustBuildProgram(tb testing.TB, path string) *ssa.Program {
 }
 
 // MustFindFunction returns a function from any package in the program with the given name.
+// The main package is searched first as dependencies may have an unexported
+// function with the same name as a fixture.
 func MustFindFunction(tb testing.TB, prog *ssa.Program, name string) *ssa.Function {
 	tb.Helper()
 
-	for _, pkg := range prog.AllPackages() {
+	pkgs := prog.AllPackages()
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return pkgs[i].Pkg.Name() == "main" && pkgs[j].Pkg.Name() != "main"
+	})
+	for _, pkg := range pkgs {
 		if m := pkg.Members[name]; m == nil {
 			continue
 		} else if fn, ok := m.(*ssa.Function); !ok {
@@ -93,6 +105,73 @@ func MustFindFunction(tb testing.TB, prog *ssa.Program, name string) *ssa.Functi
 	return nil
 }
 
+// ExecuteAll executes e until no states remain. Returns every state in the
+// order returned by ExecuteNextState(), including states stopped on a branch
+// or return, along with the first error.
+func ExecuteAll(e *glee.Executor) ([]*glee.ExecutionState, error) {
+	var a []*glee.ExecutionState
+	for {
+		state, err := e.ExecuteNextState()
+		if err == glee.ErrNoStateAvailable {
+			return a, nil
+		} else if err != nil {
+			return a, err
+		}
+		a = append(a, state)
+	}
+}
+
+// MustExecuteAll executes e until no states remain & returns every state.
+// Fatal on error.
+func MustExecuteAll(tb testing.TB, e *Executor) []*glee.ExecutionState {
+	tb.Helper()
+	a, err := ExecuteAll(e.Executor)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return a
+}
+
+// TerminalStates returns the states within a which have terminated.
+func TerminalStates(a []*glee.ExecutionState) []*glee.ExecutionState {
+	var other []*glee.ExecutionState
+	for _, state := range a {
+		if state.Terminated() {
+			other = append(other, state)
+		}
+	}
+	return other
+}
+
+// StateAt returns the first state within a stopped at position, as formatted
+// by TrimPosition(). Returns nil if no state stopped at position.
+func StateAt(a []*glee.ExecutionState, position string) *glee.ExecutionState {
+	for _, state := range a {
+		if TrimPosition(state.Position()).String() == position {
+			return state
+		}
+	}
+	return nil
+}
+
+// StatesByStatus returns the states within a grouped by status.
+func StatesByStatus(a []*glee.ExecutionState) map[glee.ExecutionStatus][]*glee.ExecutionState {
+	m := make(map[glee.ExecutionStatus][]*glee.ExecutionState)
+	for _, state := range a {
+		m[state.Status()] = append(m[state.Status()], state)
+	}
+	return m
+}
+
+// CountStatus returns the number of states within a with each status.
+func CountStatus(a []*glee.ExecutionState) map[glee.ExecutionStatus]int {
+	m := make(map[glee.ExecutionStatus]int)
+	for _, state := range a {
+		m[state.Status()]++
+	}
+	return m
+}
+
 // VarValue returns the ssa.Value for a given variable name.
 func VarValue(fn *ssa.Function, name string) ssa.Value {
 	for _, blk := range fn.Blocks {
@@ -131,3 +210,221 @@ func fn2str(fn *ssa.Function) string {
 	fn.WriteTo(&buf)
 	return buf.String()
 }
+
+// Ensure the generated OS/arch table matches the ports of the Go toolchain.
+func TestOSArchs(t *testing.T) {
+	out, err := exec.Command("go", "tool", "dist", "list").Output()
+	if err != nil {
+		t.Skipf("go tool dist list: %s", err)
+	}
+	if got, exp := glee.OSArchs(), strings.Fields(string(out)); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("OSArchs()=%v, expected %v; run go generate", got, exp)
+	}
+}
+
+func TestExecutor_OSArch(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+
+	for _, osArch := range glee.OSArchs() {
+		t.Run(osArch, func(t *testing.T) {
+			e := NewExecutor(fn)
+			defer e.Close()
+
+			a := strings.Split(osArch, "/")
+			e.OS, e.Arch = a[0], a[1]
+
+			if e.Sizes() == nil {
+				t.Fatal("expected sizes")
+			} else if w := e.PointerWidth(); w != 32 && w != 64 {
+				t.Fatalf("unexpected pointer width: %d", w)
+			} else if _, err := e.ExecuteNextState(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		e.OS, e.Arch = "nacl", "386"
+		if _, err := e.ExecuteNextState(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestExecutor_ExecuteNextStateContext(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+
+	t.Run("Canceled", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := e.ExecuteNextStateContext(ctx); err != context.Canceled {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		e := NewExecutor(fn)
+		defer e.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := e.ExecuteNextStateContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestExecutor_StepContext(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	// Step the root state until it forks.
+	var stepN int
+	for {
+		state, done, err := e.StepContext(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		} else if state != e.RootState() {
+			t.Fatalf("unexpected state: #%d", state.ID())
+		}
+		stepN++
+
+		if done {
+			break
+		} else if e.Current() != state {
+			t.Fatal("expected state in progress")
+		}
+	}
+
+	if got, exp := stepN, e.RootState().InstructionN(); got != exp {
+		t.Fatalf("steps=%d, expected %d", got, exp)
+	} else if e.Current() != nil {
+		t.Fatal("expected no state in progress")
+	} else if got, exp := len(e.PendingStates()), 2; got != exp {
+		t.Fatalf("len(PendingStates())=%d, expected %d", got, exp)
+	}
+}
+
+func TestBatchingSearcher(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	// Wrap the default searcher which already holds the root state.
+	e.Searcher = glee.NewBatchingSearcher(e.Searcher, 1000, time.Minute)
+
+	if got, exp := len(TerminalStates(MustExecuteAll(t, e))), 2; got != exp {
+		t.Fatalf("terminated=%d, expected %d", got, exp)
+	}
+}
+
+func TestExecutor_QueryStats(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	MustExecuteAll(t, e)
+
+	stats := e.QueryStats()
+	if stats.QueryN == 0 {
+		t.Fatal("expected queries")
+	} else if stats.SatN+stats.UnsatN != stats.QueryN {
+		t.Fatalf("unexpected results: %s", stats)
+	}
+
+	var n int
+	for _, v := range stats.Durations {
+		n += v
+	}
+	if n != stats.QueryN {
+		t.Fatalf("histogram count=%d, expected %d", n, stats.QueryN)
+	}
+}
+
+func TestExecutor_Restore(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	// Execute up to the branch & snapshot the first child.
+	if _, err := e.ExecuteNextState(); err != nil {
+		t.Fatal(err)
+	}
+	state, err := e.ExecuteNextState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := state.Snapshot()
+
+	// Exhaust remaining states.
+	MustExecuteAll(t, e)
+
+	// Restoring adds a new state with the same path condition.
+	restored := e.Restore(snap)
+	if restored.ID() == snap.ID() {
+		t.Fatal("expected new state id")
+	} else if got, exp := fmt.Sprint(restored.Constraints()), fmt.Sprint(state.Constraints()); got != exp {
+		t.Fatalf("Constraints()=%s, expected %s", got, exp)
+	}
+
+	if other, err := e.ExecuteNextState(); err != nil {
+		t.Fatal(err)
+	} else if other != restored {
+		t.Fatalf("expected restored state, got state#%d", other.ID())
+	} else if _, err := e.ExecuteNextState(); err != glee.ErrNoStateAvailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecutor_Tracer(t *testing.T) {
+	prog := MustBuildProgram(t, "./testdata/pkg000_if")
+	fn := MustFindFunction(t, prog, "simple")
+	e := NewExecutor(fn)
+	defer e.Close()
+
+	var tracer Tracer
+	e.Tracer = &tracer
+
+	terminated := len(TerminalStates(MustExecuteAll(t, e)))
+
+	// The branch forks two children which are both terminal.
+	if got, exp := tracer.ForkN, 2; got != exp {
+		t.Fatalf("ForkN=%d, expected %d", got, exp)
+	} else if got, exp := tracer.TerminalN, terminated; got != exp {
+		t.Fatalf("TerminalN=%d, expected %d", got, exp)
+	} else if tracer.InstructionN == 0 {
+		t.Fatal("expected instructions")
+	} else if tracer.SolverQueryN == 0 {
+		t.Fatal("expected solver queries")
+	}
+}
+
+// Tracer is a test glee.Tracer that counts each event.
+type Tracer struct {
+	ForkN        int
+	InstructionN int
+	TerminalN    int
+	SolverQueryN int
+}
+
+func (t *Tracer) OnFork(parent, child *glee.ExecutionState)                       { t.ForkN++ }
+func (t *Tracer) OnInstruction(state *glee.ExecutionState, instr ssa.Instruction) { t.InstructionN++ }
+func (t *Tracer) OnTerminalState(state *glee.ExecutionState)                      { t.TerminalN++ }
+func (t *Tracer) OnSolverQuery(constraints []glee.Expr, satisfiable bool, d time.Duration, err error) {
+	t.SolverQueryN++
+}
@@ -3,6 +3,7 @@ package glee
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"sort"
 )
 
@@ -17,6 +18,9 @@ func (*CastExpr) expr()         {}
 func (*ConcatExpr) expr()       {}
 func (*ConstantExpr) expr()     {}
 func (*ExtractExpr) expr()      {}
+func (*FPBinaryExpr) expr()     {}
+func (*FPCastExpr) expr()       {}
+func (*IteExpr) expr()          {}
 func (*NotExpr) expr()          {}
 func (*NotOptimizedExpr) expr() {}
 func (*SelectExpr) expr()       {}
@@ -43,6 +47,15 @@ func ExprWidth(expr Expr) uint {
 			return WidthBool
 		}
 		return ExprWidth(expr.LHS)
+	case *FPBinaryExpr:
+		if expr.Op.IsCompare() {
+			return WidthBool
+		}
+		return ExprWidth(expr.LHS)
+	case *FPCastExpr:
+		return expr.Width
+	case *IteExpr:
+		return ExprWidth(expr.Then)
 	default:
 		panic("unreachable")
 	}
@@ -209,7 +222,7 @@ func newAddExpr(lhs, rhs Expr) Expr {
 
 	// Compute constant if both sides are constant.
 	if lhs, ok := lhs.(*ConstantExpr); ok {
-		if lhs.Value == 0 {
+		if lhs.IsZero() {
 			return rhs
 		} else if rhs, ok := rhs.(*ConstantExpr); ok {
 			return lhs.Add(rhs)
@@ -325,9 +338,9 @@ func newMulExpr(lhs, rhs Expr) Expr {
 
 	// Optimize for multiplication with a constant 1 or 0.
 	if lhs, ok := lhs.(*ConstantExpr); ok {
-		if lhs.Value == 1 {
+		if lhs.IsOne() {
 			return rhs
-		} else if lhs.Value == 0 {
+		} else if lhs.IsZero() {
 			return lhs
 		}
 	}
@@ -388,7 +401,7 @@ func newAndExpr(lhs, rhs Expr) Expr {
 	if rhs, ok := rhs.(*ConstantExpr); ok {
 		if rhs.IsAllOnes() {
 			return lhs
-		} else if rhs.Value == 0 {
+		} else if rhs.IsZero() {
 			return rhs
 		}
 	}
@@ -413,7 +426,7 @@ func newOrExpr(lhs, rhs Expr) Expr {
 	if rhs, ok := rhs.(*ConstantExpr); ok {
 		if rhs.IsAllOnes() {
 			return rhs
-		} else if rhs.Value == 0 {
+		} else if rhs.IsZero() {
 			return lhs
 		}
 	}
@@ -429,7 +442,7 @@ func newXorExpr(lhs, rhs Expr) Expr {
 
 	// Compute constant if both sides are constant.
 	if lhs, ok := lhs.(*ConstantExpr); ok {
-		if lhs.Value == 0 {
+		if lhs.IsZero() {
 			return rhs
 		} else if rhs, ok := rhs.(*ConstantExpr); ok {
 			return lhs.Xor(rhs)
@@ -478,6 +491,19 @@ func newAShrExpr(lhs, rhs Expr) Expr {
 	return &BinaryExpr{Op: ASHR, LHS: lhs, RHS: rhs}
 }
 
+// ShiftAmountExpr returns the shift amount expression resized to width so it
+// can be used as the operand of a shift of a width-bit value. Amounts wider
+// than width saturate at width so oversized shifts keep Go semantics instead
+// of wrapping when truncated.
+func ShiftAmountExpr(amount Expr, width uint) Expr {
+	w := ExprWidth(amount)
+	if w <= width {
+		return NewCastExpr(amount, width, false)
+	}
+	limit := NewConstantExpr(uint64(width), w)
+	return NewIteExpr(newUltExpr(amount, limit), NewExtractExpr(amount, 0, width), NewConstantExpr(uint64(width), width))
+}
+
 // newEqExpr returns an expression that represents the equality of lhs and rhs.
 func newEqExpr(lhs, rhs Expr) Expr {
 	// If constant is on right side, swap to left side.
@@ -718,6 +744,35 @@ func (e *NotExpr) String() string {
 	return fmt.Sprintf("(not %s)", e.Expr)
 }
 
+// IteExpr represents a conditional expression that evaluates to Then if Cond
+// is true and to Else otherwise. Both branches must have the same width.
+type IteExpr struct {
+	Cond Expr
+	Then Expr
+	Else Expr
+}
+
+// NewIteExpr returns a new instance of IteExpr. The expression is folded to
+// a single branch if cond is constant or if both branches are identical.
+func NewIteExpr(cond, then, els Expr) Expr {
+	assert(ExprWidth(cond) == WidthBool, "ite condition must be boolean: width=%d", ExprWidth(cond))
+	assert(ExprWidth(then) == ExprWidth(els), "ite branch width mismatch: %d != %d", ExprWidth(then), ExprWidth(els))
+
+	if IsConstantTrue(cond) {
+		return then
+	} else if IsConstantFalse(cond) {
+		return els
+	} else if CompareExpr(then, els) == 0 {
+		return then
+	}
+	return &IteExpr{Cond: cond, Then: then, Else: els}
+}
+
+// String returns the string representation of the expression.
+func (e *IteExpr) String() string {
+	return fmt.Sprintf("(ite %s %s %s)", e.Cond, e.Then, e.Else)
+}
+
 // CastExpr represents an expression that casts an expression to a new width.
 type CastExpr struct {
 	Src    Expr
@@ -768,19 +823,36 @@ func (e *CastExpr) String() string {
 }
 
 // ConstantExpr represents an arbitrary precision integer.
+//
+// Values up to 64 bits are stored in Value. Wider values are also stored in
+// Big, in which case Value holds only the low 64 bits.
 type ConstantExpr struct {
 	Value uint64
 	Width uint
+	Big   *big.Int
 }
 
 // NewConstantExpr returns a new instance of ConstantExpr.
 func NewConstantExpr(value uint64, width uint) *ConstantExpr {
+	if width > Width64 {
+		return NewBigConstantExpr(new(big.Int).SetUint64(value), width)
+	}
 	return &ConstantExpr{
 		Value: value & ((1 << width) - 1),
 		Width: width,
 	}
 }
 
+// NewBigConstantExpr returns a constant expression of any width. The value is
+// truncated to width bits & negative values are stored in two's complement.
+func NewBigConstantExpr(value *big.Int, width uint) *ConstantExpr {
+	v := new(big.Int).And(value, bigmask(width))
+	if width <= Width64 {
+		return NewConstantExpr(v.Uint64(), width)
+	}
+	return &ConstantExpr{Value: v.Uint64(), Width: width, Big: v}
+}
+
 // NewConstantExpr8 returns a 8-bit constant expression.
 func NewConstantExpr8(value uint64) *ConstantExpr {
 	return NewConstantExpr(value, 8)
@@ -809,11 +881,51 @@ func NewBoolConstantExpr(value bool) *ConstantExpr {
 	return &ConstantExpr{Value: 0, Width: WidthBool}
 }
 
+// BigInt returns the unsigned value of the expression.
+func (e *ConstantExpr) BigInt() *big.Int {
+	if e.Big != nil {
+		return new(big.Int).Set(e.Big)
+	}
+	return new(big.Int).SetUint64(e.Value)
+}
+
+// signedBigInt returns the value of the expression as a two's complement integer.
+func (e *ConstantExpr) signedBigInt() *big.Int {
+	v := e.BigInt()
+	if e.Width > 0 && v.Bit(int(e.Width-1)) == 1 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), e.Width))
+	}
+	return v
+}
+
+// shift returns the shift amount of other, capped at the width of e. Go
+// defines shifts by the width or more to produce zero, or all sign bits for
+// signed right shifts, so the cap preserves the result for any larger amount.
+func (e *ConstantExpr) shift(other *ConstantExpr) uint {
+	if v := other.BigInt(); !v.IsUint64() || v.Uint64() > uint64(e.Width) {
+		return e.Width
+	}
+	return uint(other.Value)
+}
+
 // String returns the string representation of the expression.
 func (e *ConstantExpr) String() string {
+	if e.Big != nil {
+		return fmt.Sprintf("(const %s %d)", e.Big, e.Width)
+	}
 	return fmt.Sprintf("(const %d %d)", e.Value, e.Width)
 }
 
+// IsZero returns true if all bits in the value are zero.
+func (e *ConstantExpr) IsZero() bool {
+	return e.Value == 0 && (e.Big == nil || e.Big.Sign() == 0)
+}
+
+// IsOne returns true if the value is one.
+func (e *ConstantExpr) IsOne() bool {
+	return e.Value == 1 && (e.Big == nil || e.Big.IsUint64())
+}
+
 // IsTrue returns true if this is a boolean true expression.
 func (e *ConstantExpr) IsTrue() bool {
 	return e.Width == WidthBool && e.Value != 0
@@ -826,24 +938,36 @@ func (e *ConstantExpr) IsFalse() bool {
 
 // IsAllOnes returns true if all bits in the value are one.
 func (e *ConstantExpr) IsAllOnes() bool {
+	if e.Width > Width64 {
+		return e.BigInt().Cmp(bigmask(e.Width)) == 0
+	}
 	return e.Value == bitmask(e.Width)
 }
 
 // Add returns the sum of e and other.
 func (e *ConstantExpr) Add(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "add: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Add(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr(e.Value+other.Value, e.Width)
 }
 
 // Sub returns the difference of e and other.
 func (e *ConstantExpr) Sub(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "sub: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Sub(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr(e.Value-other.Value, e.Width)
 }
 
 // Mul returns the product of e and other.
 func (e *ConstantExpr) Mul(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "mul: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Mul(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr((e.Value*other.Value)&bitmask(e.Width), e.Width)
 }
 
@@ -860,7 +984,7 @@ func (e *ConstantExpr) UDiv(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewConstantExpr(uint64(uint64(e.Value)/uint64(other.Value)), e.Width)
 	default:
-		panic(fmt.Sprintf("udiv: non-standard width: %d", e.Width))
+		return NewBigConstantExpr(new(big.Int).Quo(e.BigInt(), other.BigInt()), e.Width)
 	}
 }
 
@@ -877,7 +1001,7 @@ func (e *ConstantExpr) SDiv(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewConstantExpr(uint64(int64(e.Value)/int64(other.Value)), e.Width)
 	default:
-		panic(fmt.Sprintf("sdiv: non-standard width: %d", e.Width))
+		return NewBigConstantExpr(new(big.Int).Quo(e.signedBigInt(), other.signedBigInt()), e.Width)
 	}
 }
 
@@ -894,7 +1018,7 @@ func (e *ConstantExpr) URem(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewConstantExpr(uint64(uint64(e.Value)%uint64(other.Value)), e.Width)
 	default:
-		panic(fmt.Sprintf("urem: non-standard width: %d", e.Width))
+		return NewBigConstantExpr(new(big.Int).Rem(e.BigInt(), other.BigInt()), e.Width)
 	}
 }
 
@@ -911,25 +1035,73 @@ func (e *ConstantExpr) SRem(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewConstantExpr(uint64(int64(e.Value)%int64(other.Value)), e.Width)
 	default:
-		panic(fmt.Sprintf("srem: non-standard width: %d", e.Width))
+		return NewBigConstantExpr(new(big.Int).Rem(e.signedBigInt(), other.signedBigInt()), e.Width)
+	}
+}
+
+// AddOverflows returns true if the signed sum of e and other does not fit in
+// the width of e.
+func (e *ConstantExpr) AddOverflows(other *ConstantExpr) bool {
+	assert(e.Width == other.Width, "add: width mismatch: %d != %d", e.Width, other.Width)
+	return !fitsSigned(new(big.Int).Add(e.signedBigInt(), other.signedBigInt()), e.Width)
+}
+
+// SubOverflows returns true if the signed difference of e and other does not
+// fit in the width of e.
+func (e *ConstantExpr) SubOverflows(other *ConstantExpr) bool {
+	assert(e.Width == other.Width, "sub: width mismatch: %d != %d", e.Width, other.Width)
+	return !fitsSigned(new(big.Int).Sub(e.signedBigInt(), other.signedBigInt()), e.Width)
+}
+
+// MulOverflows returns true if the signed product of e and other does not fit
+// in the width of e.
+func (e *ConstantExpr) MulOverflows(other *ConstantExpr) bool {
+	assert(e.Width == other.Width, "mul: width mismatch: %d != %d", e.Width, other.Width)
+	return !fitsSigned(new(big.Int).Mul(e.signedBigInt(), other.signedBigInt()), e.Width)
+}
+
+// SDivOverflows returns true if the signed quotient of e and other does not
+// fit in the width of e. This only occurs for the minimum integer divided by
+// -1, which SDiv() wraps back to the minimum integer. Division by zero is not
+// considered an overflow.
+func (e *ConstantExpr) SDivOverflows(other *ConstantExpr) bool {
+	assert(e.Width == other.Width, "sdiv: width mismatch: %d != %d", e.Width, other.Width)
+	if other.IsZero() {
+		return false
 	}
+	return !fitsSigned(new(big.Int).Quo(e.signedBigInt(), other.signedBigInt()), e.Width)
+}
+
+// fitsSigned returns true if v is representable as a signed integer of width bits.
+func fitsSigned(v *big.Int, width uint) bool {
+	limit := new(big.Int).Lsh(big.NewInt(1), width-1)
+	return v.Cmp(new(big.Int).Neg(limit)) >= 0 && v.Cmp(limit) < 0
 }
 
 // And returns the bitwise AND of e and other.
 func (e *ConstantExpr) And(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "and: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).And(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr(e.Value&other.Value, e.Width)
 }
 
 // Or returns the bitwise OR of e and other.
 func (e *ConstantExpr) Or(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "or: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Or(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr(e.Value|other.Value, e.Width)
 }
 
 // Xor returns the bitwise XOR of e and other.
 func (e *ConstantExpr) Xor(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "xor: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Xor(e.BigInt(), other.BigInt()), e.Width)
+	}
 	return NewConstantExpr(e.Value^other.Value, e.Width)
 }
 
@@ -937,15 +1109,15 @@ func (e *ConstantExpr) Xor(other *ConstantExpr) *ConstantExpr {
 func (e *ConstantExpr) Shl(other *ConstantExpr) *ConstantExpr {
 	switch e.Width {
 	case Width8:
-		return NewConstantExpr(uint64(uint8(e.Value)<<other.Value), e.Width)
+		return NewConstantExpr(uint64(uint8(e.Value)<<e.shift(other)), e.Width)
 	case Width16:
-		return NewConstantExpr(uint64(uint16(e.Value)<<other.Value), e.Width)
+		return NewConstantExpr(uint64(uint16(e.Value)<<e.shift(other)), e.Width)
 	case Width32:
-		return NewConstantExpr(uint64(uint32(e.Value)<<other.Value), e.Width)
+		return NewConstantExpr(uint64(uint32(e.Value)<<e.shift(other)), e.Width)
 	case Width64:
-		return NewConstantExpr(uint64(e.Value)<<other.Value, e.Width)
+		return NewConstantExpr(uint64(e.Value)<<e.shift(other), e.Width)
 	default:
-		panic("shl: non-standard width")
+		return NewBigConstantExpr(new(big.Int).Lsh(e.BigInt(), e.shift(other)), e.Width)
 	}
 }
 
@@ -953,15 +1125,15 @@ func (e *ConstantExpr) Shl(other *ConstantExpr) *ConstantExpr {
 func (e *ConstantExpr) LShr(other *ConstantExpr) *ConstantExpr {
 	switch e.Width {
 	case Width8:
-		return NewConstantExpr(uint64(uint8(e.Value)>>other.Value), e.Width)
+		return NewConstantExpr(uint64(uint8(e.Value)>>e.shift(other)), e.Width)
 	case Width16:
-		return NewConstantExpr(uint64(uint16(e.Value)>>other.Value), e.Width)
+		return NewConstantExpr(uint64(uint16(e.Value)>>e.shift(other)), e.Width)
 	case Width32:
-		return NewConstantExpr(uint64(uint32(e.Value)>>other.Value), e.Width)
+		return NewConstantExpr(uint64(uint32(e.Value)>>e.shift(other)), e.Width)
 	case Width64:
-		return NewConstantExpr(uint64(e.Value)>>other.Value, e.Width)
+		return NewConstantExpr(uint64(e.Value)>>e.shift(other), e.Width)
 	default:
-		panic("lshr: non-standard width")
+		return NewBigConstantExpr(new(big.Int).Rsh(e.BigInt(), e.shift(other)), e.Width)
 	}
 }
 
@@ -969,21 +1141,24 @@ func (e *ConstantExpr) LShr(other *ConstantExpr) *ConstantExpr {
 func (e *ConstantExpr) AShr(other *ConstantExpr) *ConstantExpr {
 	switch e.Width {
 	case Width8:
-		return NewConstantExpr(uint64(uint8(int8(e.Value)>>other.Value)), e.Width)
+		return NewConstantExpr(uint64(uint8(int8(e.Value)>>e.shift(other))), e.Width)
 	case Width16:
-		return NewConstantExpr(uint64(uint16(int16(e.Value)>>other.Value)), e.Width)
+		return NewConstantExpr(uint64(uint16(int16(e.Value)>>e.shift(other))), e.Width)
 	case Width32:
-		return NewConstantExpr(uint64(uint32(int32(e.Value)>>other.Value)), e.Width)
+		return NewConstantExpr(uint64(uint32(int32(e.Value)>>e.shift(other))), e.Width)
 	case Width64:
-		return NewConstantExpr(uint64(int64(e.Value)>>other.Value), e.Width)
+		return NewConstantExpr(uint64(int64(e.Value)>>e.shift(other)), e.Width)
 	default:
-		panic("ashr: non-standard width")
+		return NewBigConstantExpr(new(big.Int).Rsh(e.signedBigInt(), e.shift(other)), e.Width)
 	}
 }
 
 // Eq returns the equality of e and other.
 func (e *ConstantExpr) Eq(other *ConstantExpr) *ConstantExpr {
 	assert(e.Width == other.Width, "eq: width mismatch: %d != %d", e.Width, other.Width)
+	if e.Width > Width64 {
+		return NewBoolConstantExpr(e.BigInt().Cmp(other.BigInt()) == 0)
+	}
 	if e.Value == other.Value {
 		return NewConstantExpr(1, WidthBool)
 	}
@@ -1002,7 +1177,7 @@ func (e *ConstantExpr) Ult(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewBoolConstantExpr(uint64(e.Value) < uint64(other.Value))
 	default:
-		panic("ult: non-standard width")
+		return NewBoolConstantExpr(e.BigInt().Cmp(other.BigInt()) < 0)
 	}
 }
 
@@ -1023,7 +1198,7 @@ func (e *ConstantExpr) Ule(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewBoolConstantExpr(uint64(e.Value) <= uint64(other.Value))
 	default:
-		panic("ule: non-standard width")
+		return NewBoolConstantExpr(e.BigInt().Cmp(other.BigInt()) <= 0)
 	}
 }
 
@@ -1044,7 +1219,7 @@ func (e *ConstantExpr) Slt(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewBoolConstantExpr(int64(e.Value) < int64(other.Value))
 	default:
-		panic("slt: non-standard width")
+		return NewBoolConstantExpr(e.signedBigInt().Cmp(other.signedBigInt()) < 0)
 	}
 }
 
@@ -1065,7 +1240,7 @@ func (e *ConstantExpr) Sle(other *ConstantExpr) *ConstantExpr {
 	case Width64:
 		return NewBoolConstantExpr(int64(e.Value) <= int64(other.Value))
 	default:
-		panic("sle: non-standard width")
+		return NewBoolConstantExpr(e.signedBigInt().Cmp(other.signedBigInt()) <= 0)
 	}
 }
 
@@ -1079,7 +1254,9 @@ func (e *ConstantExpr) ZExt(width uint) *ConstantExpr {
 	if e.Width == width {
 		return e
 	} else if width == WidthBool {
-		return NewBoolConstantExpr(e.Value != 0)
+		return NewBoolConstantExpr(!e.IsZero())
+	} else if e.Width > Width64 || width > Width64 {
+		return NewBigConstantExpr(e.BigInt(), width)
 	}
 	return NewConstantExpr(e.Value, width)
 }
@@ -1128,21 +1305,30 @@ func (e *ConstantExpr) SExt(width uint) *ConstantExpr {
 			return NewConstantExpr(uint64(int32(int64(e.Value))), width)
 		}
 	}
-	panic(fmt.Sprintf("sext: non-standard width: %d -> %d", e.Width, width))
+	return NewBigConstantExpr(e.signedBigInt(), width)
 }
 
 // Not returns the bitwise NOT of the expression.
 func (e *ConstantExpr) Not() *ConstantExpr {
+	if e.Width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Not(e.BigInt()), e.Width)
+	}
 	return NewConstantExpr((^e.Value)&bitmask(e.Width), e.Width)
 }
 
 // Extract returns width number of bits starting at offset.
 func (e *ConstantExpr) Extract(offset, width uint) *ConstantExpr {
+	if e.Width > Width64 || width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Rsh(e.BigInt(), offset), width)
+	}
 	return NewConstantExpr(uint64(int64(e.Value)>>offset)&bitmask(e.Width), width)
 }
 
 // Concat returns the concatenation of e and lsb.
 func (e *ConstantExpr) Concat(lsb *ConstantExpr) *ConstantExpr {
+	if width := e.Width + lsb.Width; width > Width64 {
+		return NewBigConstantExpr(new(big.Int).Or(new(big.Int).Lsh(e.BigInt(), lsb.Width), lsb.BigInt()), width)
+	}
 	return NewConstantExpr((e.Value<<lsb.Width)|lsb.Value, ExprWidth(e)+ExprWidth(lsb))
 }
 
@@ -1150,6 +1336,12 @@ func bitmask(width uint) uint64 {
 	return (1 << width) - 1
 }
 
+// bigmask returns a value with the low width bits set.
+func bigmask(width uint) *big.Int {
+	v := new(big.Int).Lsh(big.NewInt(1), width)
+	return v.Sub(v, big.NewInt(1))
+}
+
 // IsConstantExpr returns true if expr is an instance of ConstantExpr.
 func IsConstantExpr(expr Expr) bool {
 	_, ok := expr.(*ConstantExpr)
@@ -1211,8 +1403,8 @@ func CompareExpr(a, b Expr) int {
 		return -1
 	} else if a != nil && b == nil {
 		return 1
-	} else if a == nil && b == nil {
-		return 0
+	} else if a == b {
+		return 0 // identical or interned expressions
 	}
 
 	if ak, bk := exprKind(a), exprKind(b); ak < bk {
@@ -1238,6 +1430,12 @@ func CompareExpr(a, b Expr) int {
 		return compareCastExpr(a, b.(*CastExpr))
 	case *BinaryExpr:
 		return compareBinaryExpr(a, b.(*BinaryExpr))
+	case *FPBinaryExpr:
+		return compareFPBinaryExpr(a, b.(*FPBinaryExpr))
+	case *FPCastExpr:
+		return compareFPCastExpr(a, b.(*FPCastExpr))
+	case *IteExpr:
+		return compareIteExpr(a, b.(*IteExpr))
 	default:
 		panic("unreachable")
 	}
@@ -1250,6 +1448,10 @@ func compareConstantExpr(a, b *ConstantExpr) int {
 		return 1
 	}
 
+	if a.Width > Width64 {
+		return a.BigInt().Cmp(b.BigInt())
+	}
+
 	if a.Value < b.Value {
 		return -1
 	} else if a.Value > b.Value {
@@ -1322,6 +1524,16 @@ func compareBinaryExpr(a, b *BinaryExpr) int {
 	return CompareExpr(a.RHS, b.RHS)
 }
 
+func compareIteExpr(a, b *IteExpr) int {
+	if cmp := CompareExpr(a.Cond, b.Cond); cmp != 0 {
+		return cmp
+	}
+	if cmp := CompareExpr(a.Then, b.Then); cmp != 0 {
+		return cmp
+	}
+	return CompareExpr(a.Else, b.Else)
+}
+
 // exprKind returns a numeric value for the type of expression.
 // Only used internally for equality checks and sorting.
 func exprKind(expr Expr) int {
@@ -1342,6 +1554,12 @@ func exprKind(expr Expr) int {
 		return 7
 	case *BinaryExpr:
 		return 8
+	case *FPBinaryExpr:
+		return 9
+	case *FPCastExpr:
+		return 10
+	case *IteExpr:
+		return 11
 	default:
 		panic("unreachable")
 	}
@@ -1384,6 +1602,27 @@ func WalkExpr(v ExprVisitor, expr Expr) Expr {
 		if other := WalkExpr(v, expr.Expr); other != expr.Expr {
 			expr.Expr = other
 		}
+	case *FPBinaryExpr:
+		if other := WalkExpr(v, expr.LHS); other != expr.LHS {
+			expr.LHS = other
+		}
+		if other := WalkExpr(v, expr.RHS); other != expr.RHS {
+			expr.RHS = other
+		}
+	case *FPCastExpr:
+		if other := WalkExpr(v, expr.Src); other != expr.Src {
+			expr.Src = other
+		}
+	case *IteExpr:
+		if other := WalkExpr(v, expr.Cond); other != expr.Cond {
+			expr.Cond = other
+		}
+		if other := WalkExpr(v, expr.Then); other != expr.Then {
+			expr.Then = other
+		}
+		if other := WalkExpr(v, expr.Else); other != expr.Else {
+			expr.Else = other
+		}
 	case *NotExpr:
 		if other := WalkExpr(v, expr.Expr); other != expr.Expr {
 			expr.Expr = other
@@ -1501,6 +1740,30 @@ func (ee *ExprEvaluator) Evaluate(expr Expr) (*ConstantExpr, error) {
 			return nil, err
 		}
 		return NewExtractExpr(exp, expr.Offset, expr.Width).(*ConstantExpr), nil
+	case *FPBinaryExpr:
+		lhs, err := ee.Evaluate(expr.LHS)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := ee.Evaluate(expr.RHS)
+		if err != nil {
+			return nil, err
+		}
+		return NewFPBinaryExpr(expr.Op, lhs, rhs).(*ConstantExpr), nil
+	case *FPCastExpr:
+		src, err := ee.Evaluate(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+		return NewFPCastExpr(expr.Op, src, expr.Width).(*ConstantExpr), nil
+	case *IteExpr:
+		cond, err := ee.Evaluate(expr.Cond)
+		if err != nil {
+			return nil, err
+		} else if cond.IsTrue() {
+			return ee.Evaluate(expr.Then)
+		}
+		return ee.Evaluate(expr.Else)
 	case *NotExpr:
 		exp, err := ee.Evaluate(expr.Expr)
 		if err != nil {
@@ -0,0 +1,153 @@
+package glee
+
+// ExprBuilder constructs expressions with structural hash-consing. Interned
+// expressions that are structurally identical are the same pointer so they
+// can be compared in constant time & their subexpressions are shared instead
+// of copied.
+//
+// Interned expressions are retained for the lifetime of the builder.
+type ExprBuilder struct {
+	exprs    map[exprKey]Expr  // canonical expression by structure
+	interned map[Expr]struct{} // set of canonical expressions
+}
+
+// exprKey represents the structure of an expression. Child expressions must
+// already be interned so they can be compared by pointer.
+type exprKey struct {
+	kind   int
+	op     int
+	a, b   Expr
+	c      Expr
+	array  *Array
+	value  uint64
+	big    string // full value of constants wider than 64 bits
+	width  uint
+	offset uint
+	signed bool
+}
+
+// NewExprBuilder returns a new instance of ExprBuilder.
+func NewExprBuilder() *ExprBuilder {
+	return &ExprBuilder{
+		exprs:    make(map[exprKey]Expr),
+		interned: make(map[Expr]struct{}),
+	}
+}
+
+// Len returns the number of distinct interned expressions.
+func (b *ExprBuilder) Len() int {
+	return len(b.exprs)
+}
+
+// Intern returns the canonical expression that is structurally identical to
+// expr. Subexpressions are interned recursively. Array contents are not
+// interned so selects are only shared for the same array value.
+func (b *ExprBuilder) Intern(expr Expr) Expr {
+	if expr == nil {
+		return nil
+	} else if _, ok := b.interned[expr]; ok {
+		return expr
+	}
+
+	// Build the key from interned children & create a copy of the
+	// expression pointing to those children.
+	var key exprKey
+	switch e := expr.(type) {
+	case *ConstantExpr:
+		key = exprKey{value: e.Value, width: e.Width}
+		if e.Big != nil {
+			key.big = e.Big.Text(16)
+		}
+	case *NotOptimizedExpr:
+		key = exprKey{a: b.Intern(e.Src)}
+		expr = &NotOptimizedExpr{Src: key.a}
+	case *SelectExpr:
+		key = exprKey{a: b.Intern(e.Index), array: e.Array}
+		expr = &SelectExpr{Array: e.Array, Index: key.a}
+	case *ConcatExpr:
+		key = exprKey{a: b.Intern(e.MSB), b: b.Intern(e.LSB)}
+		expr = &ConcatExpr{MSB: key.a, LSB: key.b}
+	case *ExtractExpr:
+		key = exprKey{a: b.Intern(e.Expr), offset: e.Offset, width: e.Width}
+		expr = &ExtractExpr{Expr: key.a, Offset: e.Offset, Width: e.Width}
+	case *NotExpr:
+		key = exprKey{a: b.Intern(e.Expr)}
+		expr = &NotExpr{Expr: key.a}
+	case *CastExpr:
+		key = exprKey{a: b.Intern(e.Src), width: e.Width, signed: e.Signed}
+		expr = &CastExpr{Src: key.a, Width: e.Width, Signed: e.Signed}
+	case *BinaryExpr:
+		key = exprKey{op: int(e.Op), a: b.Intern(e.LHS), b: b.Intern(e.RHS)}
+		expr = &BinaryExpr{Op: e.Op, LHS: key.a, RHS: key.b}
+	case *FPBinaryExpr:
+		key = exprKey{op: int(e.Op), a: b.Intern(e.LHS), b: b.Intern(e.RHS)}
+		expr = &FPBinaryExpr{Op: e.Op, LHS: key.a, RHS: key.b}
+	case *FPCastExpr:
+		key = exprKey{op: int(e.Op), a: b.Intern(e.Src), width: e.Width}
+		expr = &FPCastExpr{Op: e.Op, Src: key.a, Width: e.Width}
+	case *IteExpr:
+		key = exprKey{a: b.Intern(e.Cond), b: b.Intern(e.Then), c: b.Intern(e.Else)}
+		expr = &IteExpr{Cond: key.a, Then: key.b, Else: key.c}
+	}
+	key.kind = exprKind(expr)
+
+	if other, ok := b.exprs[key]; ok {
+		return other
+	}
+	b.exprs[key] = expr
+	b.interned[expr] = struct{}{}
+	return expr
+}
+
+// InternBinding interns each expression within a binding. Arrays are
+// returned as-is.
+func (b *ExprBuilder) InternBinding(binding Binding) Binding {
+	switch binding := binding.(type) {
+	case Expr:
+		return b.Intern(binding)
+	case Tuple:
+		other := make(Tuple, len(binding))
+		for i := range binding {
+			other[i] = b.InternBinding(binding[i])
+		}
+		return other
+	default:
+		return binding
+	}
+}
+
+// NewConstantExpr returns an interned constant expression.
+func (b *ExprBuilder) NewConstantExpr(value uint64, width uint) Expr {
+	return b.Intern(NewConstantExpr(value, width))
+}
+
+// NewBinaryExpr returns an interned binary expression. Operands are
+// simplified the same as NewBinaryExpr().
+func (b *ExprBuilder) NewBinaryExpr(op BinaryOp, lhs, rhs Expr) Expr {
+	return b.Intern(NewBinaryExpr(op, lhs, rhs))
+}
+
+// NewNotExpr returns an interned logical or bitwise NOT expression.
+func (b *ExprBuilder) NewNotExpr(expr Expr) Expr {
+	return b.Intern(NewNotExpr(expr))
+}
+
+// NewIteExpr returns an interned conditional expression.
+func (b *ExprBuilder) NewIteExpr(cond, then, els Expr) Expr {
+	return b.Intern(NewIteExpr(cond, then, els))
+}
+
+// NewCastExpr returns an interned cast expression.
+func (b *ExprBuilder) NewCastExpr(src Expr, width uint, signed bool) Expr {
+	return b.Intern(NewCastExpr(src, width, signed))
+}
+
+// NewExtractExpr returns an interned extract expression.
+func (b *ExprBuilder) NewExtractExpr(expr Expr, offset, width uint) Expr {
+	return b.Intern(NewExtractExpr(expr, offset, width))
+}
+
+// NewConcatExpr returns an interned concatenation expression.
+func (b *ExprBuilder) NewConcatExpr(msb, lsb Expr) Expr {
+	return b.Intern(NewConcatExpr(msb, lsb))
+}
@@ -0,0 +1,58 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestExprBuilder_Intern(t *testing.T) {
+	t.Run("Structural", func(t *testing.T) {
+		b := glee.NewExprBuilder()
+		a := glee.NewArray(1, 1)
+
+		// Build the same expression twice from separate nodes.
+		newExpr := func() glee.Expr {
+			x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+			return glee.NewBinaryExpr(glee.ULT, glee.NewBinaryExpr(glee.ADD, x, glee.NewConstantExpr(1, 8)), glee.NewConstantExpr(10, 8))
+		}
+		e0, e1 := newExpr(), newExpr()
+		if e0 == e1 {
+			t.Fatal("expected distinct nodes before interning")
+		}
+
+		i0, i1 := b.Intern(e0), b.Intern(e1)
+		if i0 != i1 {
+			t.Fatal("expected shared node")
+		} else if glee.CompareExpr(i0, e0) != 0 {
+			t.Fatalf("interned expression differs: %s != %s", i0, e0)
+		} else if b.Intern(i0) != i0 {
+			t.Fatal("expected interned expression to be returned as-is")
+		}
+
+		// Subexpressions are shared as well.
+		if lhs := b.NewBinaryExpr(glee.ADD, b.Intern(a.Select(glee.NewConstantExpr(0, 32), 8, false)), b.NewConstantExpr(1, 8)); lhs != i0.(*glee.BinaryExpr).LHS {
+			t.Fatal("expected shared subexpression")
+		}
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		b := glee.NewExprBuilder()
+		x := glee.NewArray(1, 1).Select(glee.NewConstantExpr(0, 32), 8, false)
+		if b.NewCastExpr(x, 16, true) == b.NewCastExpr(x, 16, false) {
+			t.Fatal("expected distinct casts")
+		} else if b.NewConstantExpr(1, 8) == b.NewConstantExpr(1, 16) {
+			t.Fatal("expected distinct constants")
+		} else if b.NewBinaryExpr(glee.ULT, x, b.NewConstantExpr(1, 8)) == b.NewBinaryExpr(glee.ULE, x, b.NewConstantExpr(1, 8)) {
+			t.Fatal("expected distinct binary expressions")
+		}
+	})
+
+	t.Run("Binding", func(t *testing.T) {
+		b := glee.NewExprBuilder()
+		tuple := b.InternBinding(glee.Tuple{glee.NewConstantExpr(1, 8), glee.NewConstantExpr(1, 8)}).(glee.Tuple)
+		if tuple[0] != tuple[1] {
+			t.Fatal("expected shared tuple elements")
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package glee_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/benbjohnson/glee"
@@ -782,6 +784,26 @@ func TestNewBinaryExpr_SHL(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+	t.Run("Oversized", func(t *testing.T) {
+		wide := glee.NewBigConstantExpr(new(big.Int).Lsh(big.NewInt(1), 64), 128) // low 64 bits are zero
+		for _, tt := range []struct {
+			lhs, rhs *glee.ConstantExpr
+			want     *glee.ConstantExpr
+		}{
+			{glee.NewConstantExpr(0x81, 8), glee.NewConstantExpr(7, 8), glee.NewConstantExpr(0x80, 8)},
+			{glee.NewConstantExpr(0x81, 8), glee.NewConstantExpr(8, 8), glee.NewConstantExpr(0, 8)},
+			{glee.NewConstantExpr(0x81, 8), glee.NewConstantExpr(255, 8), glee.NewConstantExpr(0, 8)},
+			{glee.NewConstantExpr(1, 32), glee.NewConstantExpr64(32), glee.NewConstantExpr(0, 32)},
+			{glee.NewConstantExpr64(1), glee.NewConstantExpr64(63), glee.NewConstantExpr64(1 << 63)},
+			{glee.NewConstantExpr64(1), glee.NewConstantExpr64(64), glee.NewConstantExpr64(0)},
+			{glee.NewConstantExpr64(1), wide, glee.NewConstantExpr64(0)},
+			{glee.NewConstantExpr(1, 24), glee.NewConstantExpr(24, 24), glee.NewConstantExpr(0, 24)},
+		} {
+			if got := glee.NewBinaryExpr(glee.SHL, tt.lhs, tt.rhs); glee.CompareExpr(got, tt.want) != 0 {
+				t.Fatalf("%s shl %s: got %s, want %s", tt.lhs, tt.rhs, got, tt.want)
+			}
+		}
+	})
 }
 
 func TestNewBinaryExpr_LSHR(t *testing.T) {
@@ -837,6 +859,23 @@ func TestNewBinaryExpr_LSHR(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+	t.Run("Oversized", func(t *testing.T) {
+		wide := glee.NewBigConstantExpr(new(big.Int).Lsh(big.NewInt(1), 64), 128) // low 64 bits are zero
+		for _, tt := range []struct {
+			lhs, rhs *glee.ConstantExpr
+			want     *glee.ConstantExpr
+		}{
+			{glee.NewConstantExpr(0x80, 8), glee.NewConstantExpr(7, 8), glee.NewConstantExpr(1, 8)},
+			{glee.NewConstantExpr(0x80, 8), glee.NewConstantExpr(8, 8), glee.NewConstantExpr(0, 8)},
+			{glee.NewConstantExpr(0xFFFF, 16), glee.NewConstantExpr64(1000), glee.NewConstantExpr(0, 16)},
+			{glee.NewConstantExpr64(1 << 63), glee.NewConstantExpr64(64), glee.NewConstantExpr64(0)},
+			{glee.NewConstantExpr64(1 << 63), wide, glee.NewConstantExpr64(0)},
+		} {
+			if got := glee.NewBinaryExpr(glee.LSHR, tt.lhs, tt.rhs); glee.CompareExpr(got, tt.want) != 0 {
+				t.Fatalf("%s lshr %s: got %s, want %s", tt.lhs, tt.rhs, got, tt.want)
+			}
+		}
+	})
 }
 
 func TestNewBinaryExpr_ASHR(t *testing.T) {
@@ -873,6 +912,25 @@ func TestNewBinaryExpr_ASHR(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+	t.Run("Oversized", func(t *testing.T) {
+		wide := glee.NewBigConstantExpr(new(big.Int).Lsh(big.NewInt(1), 64), 128) // low 64 bits are zero
+		for _, tt := range []struct {
+			lhs, rhs *glee.ConstantExpr
+			want     *glee.ConstantExpr
+		}{
+			{glee.NewConstantExpr(0x80, 8), glee.NewConstantExpr(7, 8), glee.NewConstantExpr(0xFF, 8)},
+			{glee.NewConstantExpr(0x80, 8), glee.NewConstantExpr(8, 8), glee.NewConstantExpr(0xFF, 8)},
+			{glee.NewConstantExpr(0x7F, 8), glee.NewConstantExpr(200, 8), glee.NewConstantExpr(0, 8)},
+			{glee.NewConstantExpr(0x8000, 16), glee.NewConstantExpr64(16), glee.NewConstantExpr(0xFFFF, 16)},
+			{glee.NewConstantExpr64(1 << 63), glee.NewConstantExpr64(64), glee.NewConstantExpr64(^uint64(0))},
+			{glee.NewConstantExpr64(1 << 63), wide, glee.NewConstantExpr64(^uint64(0))},
+			{glee.NewConstantExpr64(1 << 62), wide, glee.NewConstantExpr64(0)},
+		} {
+			if got := glee.NewBinaryExpr(glee.ASHR, tt.lhs, tt.rhs); glee.CompareExpr(got, tt.want) != 0 {
+				t.Fatalf("%s ashr %s: got %s, want %s", tt.lhs, tt.rhs, got, tt.want)
+			}
+		}
+	})
 }
 
 func TestNewBinaryExpr_EQ(t *testing.T) {
@@ -1565,6 +1623,58 @@ func TestNotExpr_String(t *testing.T) {
 	}
 }
 
+func TestNewIteExpr(t *testing.T) {
+	cond := glee.NewArray(1, 1).Select(glee.NewConstantExpr64(0), 1, false)
+	then, els := glee.NewConstantExpr(1, 32), glee.NewConstantExpr(2, 32)
+
+	t.Run("True", func(t *testing.T) {
+		if got := glee.NewIteExpr(glee.NewBoolConstantExpr(true), then, els); got != then {
+			t.Fatalf("unexpected expr: %s", got)
+		}
+	})
+	t.Run("False", func(t *testing.T) {
+		if got := glee.NewIteExpr(glee.NewBoolConstantExpr(false), then, els); got != els {
+			t.Fatalf("unexpected expr: %s", got)
+		}
+	})
+	t.Run("SameBranches", func(t *testing.T) {
+		if got := glee.NewIteExpr(cond, then, glee.NewConstantExpr(1, 32)); got != then {
+			t.Fatalf("unexpected expr: %s", got)
+		}
+	})
+	t.Run("Symbolic", func(t *testing.T) {
+		got := glee.NewIteExpr(cond, then, els)
+		exp := &glee.IteExpr{Cond: cond, Then: then, Else: els}
+		if glee.CompareExpr(got, exp) != 0 {
+			t.Fatalf("unexpected expr: %s", got)
+		} else if w := glee.ExprWidth(got); w != 32 {
+			t.Fatalf("unexpected width: %d", w)
+		}
+	})
+	t.Run("Evaluate", func(t *testing.T) {
+		array := glee.NewArray(1, 1)
+		expr := glee.NewIteExpr(glee.NewIsZeroExpr(array.Select(glee.NewConstantExpr64(0), 8, false)), then, els)
+		for _, tt := range []struct {
+			value byte
+			want  uint64
+		}{{0, 1}, {5, 2}} {
+			ee := glee.NewExprEvaluator([]*glee.Array{array}, [][]byte{{tt.value}})
+			if got, err := ee.Evaluate(expr); err != nil {
+				t.Fatal(err)
+			} else if got.Value != tt.want {
+				t.Fatalf("value=%d: unexpected result: %d", tt.value, got.Value)
+			}
+		}
+	})
+}
+
+func TestIteExpr_String(t *testing.T) {
+	expr := &glee.IteExpr{Cond: glee.NewBoolConstantExpr(true), Then: glee.NewConstantExpr(1, 8), Else: glee.NewConstantExpr(2, 8)}
+	if s := expr.String(); s != "(ite (const 1 1) (const 1 8) (const 2 8))" {
+		t.Fatalf("unexpected string: %s", s)
+	}
+}
+
 func TestNewCastExpr(t *testing.T) {
 	t.Run("Signed", func(t *testing.T) {
 		t.Run("SameWidth", func(t *testing.T) {
@@ -1897,6 +2007,16 @@ func TestConstantExpr_SDiv(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+	// Ensure MinInt / -1 wraps to MinInt as it does in Go.
+	t.Run("MinInt", func(t *testing.T) {
+		for _, width := range []uint{8, 16, 24, 32, 64, 128} {
+			minInt := glee.NewBigConstantExpr(new(big.Int).Lsh(big.NewInt(1), width-1), width)
+			negOne := glee.NewConstantExpr(0, width).Not()
+			if got := minInt.SDiv(negOne); glee.CompareExpr(got, minInt) != 0 {
+				t.Fatalf("width=%d: got %s, want %s", width, got, minInt)
+			}
+		}
+	})
 }
 
 func TestConstantExpr_URem(t *testing.T) {
@@ -1963,6 +2083,54 @@ func TestConstantExpr_SRem(t *testing.T) {
 			t.Fatal(diff)
 		}
 	})
+	// Ensure MinInt % -1 is zero as it is in Go.
+	t.Run("MinInt", func(t *testing.T) {
+		for _, width := range []uint{8, 16, 24, 32, 64, 128} {
+			minInt := glee.NewBigConstantExpr(new(big.Int).Lsh(big.NewInt(1), width-1), width)
+			negOne := glee.NewConstantExpr(0, width).Not()
+			if got := minInt.SRem(negOne); !got.IsZero() {
+				t.Fatalf("width=%d: got %s, want 0", width, got)
+			}
+		}
+	})
+}
+
+func TestConstantExpr_Overflows(t *testing.T) {
+	c8 := func(v int8) *glee.ConstantExpr { return glee.NewConstantExpr(uint64(uint8(v)), 8) }
+	c64 := func(v int64) *glee.ConstantExpr { return glee.NewConstantExpr64(uint64(v)) }
+
+	for _, tt := range []struct {
+		name string
+		fn   func(x, y *glee.ConstantExpr) bool
+		x, y *glee.ConstantExpr
+		want bool
+	}{
+		{"Add/8/Max", (*glee.ConstantExpr).AddOverflows, c8(math.MaxInt8), c8(0), false},
+		{"Add/8/MaxPlusOne", (*glee.ConstantExpr).AddOverflows, c8(math.MaxInt8), c8(1), true},
+		{"Add/8/MinMinusOne", (*glee.ConstantExpr).AddOverflows, c8(math.MinInt8), c8(-1), true},
+		{"Add/8/Mixed", (*glee.ConstantExpr).AddOverflows, c8(math.MinInt8), c8(math.MaxInt8), false},
+		{"Add/64/MaxPlusOne", (*glee.ConstantExpr).AddOverflows, c64(math.MaxInt64), c64(1), true},
+		{"Sub/8/MinMinusOne", (*glee.ConstantExpr).SubOverflows, c8(math.MinInt8), c8(1), true},
+		{"Sub/8/ZeroMinusMin", (*glee.ConstantExpr).SubOverflows, c8(0), c8(math.MinInt8), true},
+		{"Sub/8/NegOneMinusMin", (*glee.ConstantExpr).SubOverflows, c8(-1), c8(math.MinInt8), false},
+		{"Mul/8/Fits", (*glee.ConstantExpr).MulOverflows, c8(-16), c8(8), false},
+		{"Mul/8/TooLarge", (*glee.ConstantExpr).MulOverflows, c8(16), c8(8), true},
+		{"Mul/8/MinTimesNegOne", (*glee.ConstantExpr).MulOverflows, c8(math.MinInt8), c8(-1), true},
+		{"Mul/64/MinTimesOne", (*glee.ConstantExpr).MulOverflows, c64(math.MinInt64), c64(1), false},
+		{"Mul/64/TooLarge", (*glee.ConstantExpr).MulOverflows, c64(1 << 32), c64(1 << 31), true},
+		{"SDiv/8/MinByNegOne", (*glee.ConstantExpr).SDivOverflows, c8(math.MinInt8), c8(-1), true},
+		{"SDiv/8/MinByOne", (*glee.ConstantExpr).SDivOverflows, c8(math.MinInt8), c8(1), false},
+		{"SDiv/8/MinPlusOneByNegOne", (*glee.ConstantExpr).SDivOverflows, c8(math.MinInt8 + 1), c8(-1), false},
+		{"SDiv/8/ByZero", (*glee.ConstantExpr).SDivOverflows, c8(math.MinInt8), c8(0), false},
+		{"SDiv/64/MinByNegOne", (*glee.ConstantExpr).SDivOverflows, c64(math.MinInt64), c64(-1), true},
+		{"Add/1", (*glee.ConstantExpr).AddOverflows, glee.NewBoolConstantExpr(true), glee.NewBoolConstantExpr(true), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.x, tt.y); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
 }
 
 func TestConstantExpr_And(t *testing.T) {
@@ -2265,6 +2433,63 @@ func TestConstantExpr_Sge(t *testing.T) {
 	}
 }
 
+func TestConstantExpr_Big(t *testing.T) {
+	// 2^64 + 1 as a 128-bit value.
+	x := glee.NewConstantExpr(1, 128).Shl(glee.NewConstantExpr(64, 128)).Add(glee.NewConstantExpr(1, 128))
+
+	for _, tt := range []struct {
+		name string
+		got  *glee.ConstantExpr
+		exp  string
+	}{
+		{"New", x, "(const 18446744073709551617 128)"},
+		{"NewBig", glee.NewBigConstantExpr(big.NewInt(-1), 128), "(const 340282366920938463463374607431768211455 128)"},
+		{"NewBigNarrow", glee.NewBigConstantExpr(big.NewInt(-1), 8), "(const 255 8)"},
+		{"Add", x.Add(x), "(const 36893488147419103234 128)"},
+		{"Sub", glee.NewConstantExpr(0, 128).Sub(glee.NewConstantExpr(1, 128)), "(const 340282366920938463463374607431768211455 128)"},
+		{"Mul", x.Mul(x), "(const 36893488147419103233 128)"},
+		{"UDiv", x.UDiv(glee.NewConstantExpr(2, 128)), "(const 9223372036854775808 128)"},
+		{"SDiv", glee.NewConstantExpr(0, 128).Sub(x).SDiv(glee.NewConstantExpr(2, 128)), "(const 340282366920938463454151235394913435648 128)"},
+		{"URem", x.URem(glee.NewConstantExpr(10, 128)), "(const 7 128)"},
+		{"SRem", glee.NewConstantExpr(0, 128).Sub(x).SRem(glee.NewConstantExpr(10, 128)), "(const 340282366920938463463374607431768211449 128)"},
+		{"And", x.And(glee.NewConstantExpr(3, 128)), "(const 1 128)"},
+		{"Or", x.Or(glee.NewConstantExpr(2, 128)), "(const 18446744073709551619 128)"},
+		{"Xor", x.Xor(x), "(const 0 128)"},
+		{"Not", glee.NewConstantExpr(0, 128).Not(), "(const 340282366920938463463374607431768211455 128)"},
+		{"LShr", x.LShr(glee.NewConstantExpr(64, 128)), "(const 1 128)"},
+		{"AShr", glee.NewConstantExpr(0, 128).Not().AShr(glee.NewConstantExpr(100, 128)), "(const 340282366920938463463374607431768211455 128)"},
+		{"ShlOverflow", x.Shl(glee.NewConstantExpr(200, 128)), "(const 0 128)"},
+		{"ZExt", glee.NewConstantExpr(0xFF, 8).ZExt(128), "(const 255 128)"},
+		{"SExt", glee.NewConstantExpr(0xFF, 8).SExt(128), "(const 340282366920938463463374607431768211455 128)"},
+		{"Truncate", x.ZExt(64), "(const 1 64)"},
+		{"Extract", x.Extract(64, 8), "(const 1 8)"},
+		{"Concat", glee.NewConstantExpr(1, 64).Concat(glee.NewConstantExpr(1, 64)), "(const 18446744073709551617 128)"},
+		{"Eq", x.Eq(glee.NewConstantExpr(1, 128)), "(const 0 1)"},
+		{"Ult", glee.NewConstantExpr(1, 128).Ult(x), "(const 1 1)"},
+		{"Slt", glee.NewConstantExpr(0, 128).Not().Slt(x), "(const 1 1)"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.exp {
+				t.Fatalf("String()=%s, expected %s", got, tt.exp)
+			}
+		})
+	}
+
+	// Ensure the low bits are kept in Value for narrow consumers.
+	if x.Value != 1 {
+		t.Fatalf("unexpected value: %d", x.Value)
+	} else if x.IsZero() {
+		t.Fatal("expected non-zero")
+	}
+
+	// Ensure wide constants which only differ in their high bits are distinct.
+	if glee.CompareExpr(x, glee.NewConstantExpr(1, 128)) != 1 {
+		t.Fatal("expected greater than")
+	} else if glee.IsConstantTrue(glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr(1, 128))) {
+		t.Fatal("expected not equal")
+	}
+}
+
 func TestIsConstantTrue(t *testing.T) {
 	t.Run("Bool", func(t *testing.T) {
 		t.Run("True", func(t *testing.T) {
@@ -2329,3 +2554,33 @@ func TestTuple_String(t *testing.T) {
 		t.Fatalf("unexpected string: %s", s)
 	}
 }
+
+func TestNewFPBinaryExpr(t *testing.T) {
+	t.Run("Constant", func(t *testing.T) {
+		x, y := glee.NewFPConstantExpr(1.5, 64), glee.NewFPConstantExpr(0.25, 64)
+		if got := glee.NewFPBinaryExpr(glee.FADD, x, y).(*glee.ConstantExpr).Float(); got != 1.75 {
+			t.Fatalf("unexpected sum: %v", got)
+		} else if got := glee.NewFPBinaryExpr(glee.FDIV, x, y).(*glee.ConstantExpr).Float(); got != 6 {
+			t.Fatalf("unexpected quotient: %v", got)
+		} else if !glee.IsConstantTrue(glee.NewFPBinaryExpr(glee.FLT, y, x)) {
+			t.Fatal("expected true")
+		}
+	})
+
+	t.Run("Float32", func(t *testing.T) {
+		x, y := glee.NewFPConstantExpr(16777216, 32), glee.NewFPConstantExpr(1, 32)
+		if got := glee.NewFPBinaryExpr(glee.FADD, x, y).(*glee.ConstantExpr).Float(); got != 16777216 {
+			t.Fatalf("unexpected rounding: %v", got)
+		}
+	})
+}
+
+func TestNewFPCastExpr(t *testing.T) {
+	if got := glee.NewFPCastExpr(glee.SITOFP, glee.NewConstantExpr(uint64(0xFFFFFFFE), 32), 64).(*glee.ConstantExpr).Float(); got != -2 {
+		t.Fatalf("unexpected float: %v", got)
+	} else if got := glee.NewFPCastExpr(glee.FPTOSI, glee.NewFPConstantExpr(-2.75, 64), 32).(*glee.ConstantExpr).Value; int32(got) != -2 {
+		t.Fatalf("unexpected int: %d", int32(got))
+	} else if got := glee.NewFPCastExpr(glee.FPEXT, glee.NewFPConstantExpr(0.1, 64), 32).(*glee.ConstantExpr).Float(); got != float64(float32(0.1)) {
+		t.Fatalf("unexpected float: %v", got)
+	}
+}
@@ -0,0 +1,224 @@
+package glee
+
+import (
+	"fmt"
+	"math"
+)
+
+// Floating-point values are represented as bit vectors holding their IEEE 754
+// encoding so they can be stored in arrays like any other value. The FP
+// expressions below interpret their operands as IEEE 754 values of the same
+// width. All arithmetic uses round-to-nearest-even, same as Go.
+
+// NewFPConstantExpr returns a constant expression holding the IEEE 754
+// encoding of v. Width must be 32 or 64.
+func NewFPConstantExpr(v float64, width uint) *ConstantExpr {
+	switch width {
+	case Width32:
+		return NewConstantExpr(uint64(math.Float32bits(float32(v))), width)
+	case Width64:
+		return NewConstantExpr(math.Float64bits(v), width)
+	default:
+		panic(fmt.Sprintf("invalid floating-point width: %d", width))
+	}
+}
+
+// Float returns the floating-point value of the IEEE 754 encoding in expr.
+func (expr *ConstantExpr) Float() float64 {
+	switch expr.Width {
+	case Width32:
+		return float64(math.Float32frombits(uint32(expr.Value)))
+	case Width64:
+		return math.Float64frombits(expr.Value)
+	default:
+		panic(fmt.Sprintf("invalid floating-point width: %d", expr.Width))
+	}
+}
+
+// FPBinaryOp represents a floating-point binary operation.
+type FPBinaryOp int
+
+// FPBinaryExpr operations.
+const (
+	FADD = FPBinaryOp(iota + 1)
+	FSUB
+	FMUL
+	FDIV
+	FEQ
+	FLT
+	FLE
+)
+
+// String returns the string representation of the operation.
+func (op FPBinaryOp) String() string {
+	switch op {
+	case FADD:
+		return "fadd"
+	case FSUB:
+		return "fsub"
+	case FMUL:
+		return "fmul"
+	case FDIV:
+		return "fdiv"
+	case FEQ:
+		return "feq"
+	case FLT:
+		return "flt"
+	case FLE:
+		return "fle"
+	default:
+		return fmt.Sprintf("FPBinaryOp<%d>", op)
+	}
+}
+
+// IsCompare returns true if the operation returns a boolean.
+func (op FPBinaryOp) IsCompare() bool {
+	return op == FEQ || op == FLT || op == FLE
+}
+
+// FPBinaryExpr represents a floating-point binary operation on two expressions.
+type FPBinaryExpr struct {
+	Op  FPBinaryOp
+	LHS Expr
+	RHS Expr
+}
+
+// NewFPBinaryExpr returns a new floating-point binary expression. Constant
+// operands are folded.
+func NewFPBinaryExpr(op FPBinaryOp, lhs, rhs Expr) Expr {
+	assert(ExprWidth(lhs) == ExprWidth(rhs), "fp binary expr width mismatch: %d != %d", ExprWidth(lhs), ExprWidth(rhs))
+
+	lc, lok := lhs.(*ConstantExpr)
+	rc, rok := rhs.(*ConstantExpr)
+	if !lok || !rok {
+		return &FPBinaryExpr{Op: op, LHS: lhs, RHS: rhs}
+	}
+
+	// Operate at the original precision so rounding matches.
+	width := lc.Width
+	x, y := lc.Float(), rc.Float()
+	switch op {
+	case FADD:
+		return NewFPConstantExpr(roundFloat(x+y, width), width)
+	case FSUB:
+		return NewFPConstantExpr(roundFloat(x-y, width), width)
+	case FMUL:
+		return NewFPConstantExpr(roundFloat(x*y, width), width)
+	case FDIV:
+		return NewFPConstantExpr(roundFloat(x/y, width), width)
+	case FEQ:
+		return NewBoolConstantExpr(x == y)
+	case FLT:
+		return NewBoolConstantExpr(x < y)
+	case FLE:
+		return NewBoolConstantExpr(x <= y)
+	default:
+		panic(fmt.Sprintf("invalid fp binary op: %s", op))
+	}
+}
+
+// String returns the string representation of the expression.
+func (e *FPBinaryExpr) String() string {
+	return fmt.Sprintf("(%s %s %s)", e.Op, e.LHS, e.RHS)
+}
+
+// FPCastOp represents a conversion to or from a floating-point value.
+type FPCastOp int
+
+// FPCastExpr operations.
+const (
+	FPEXT  = FPCastOp(iota + 1) // float to float of a different width
+	SITOFP                      // signed integer to float
+	UITOFP                      // unsigned integer to float
+	FPTOSI                      // float to signed integer, truncated
+	FPTOUI                      // float to unsigned integer, truncated
+)
+
+// String returns the string representation of the operation.
+func (op FPCastOp) String() string {
+	switch op {
+	case FPEXT:
+		return "fpext"
+	case SITOFP:
+		return "sitofp"
+	case UITOFP:
+		return "uitofp"
+	case FPTOSI:
+		return "fptosi"
+	case FPTOUI:
+		return "fptoui"
+	default:
+		return fmt.Sprintf("FPCastOp<%d>", op)
+	}
+}
+
+// FPCastExpr represents a conversion to or from a floating-point value.
+type FPCastExpr struct {
+	Op    FPCastOp
+	Src   Expr
+	Width uint
+}
+
+// NewFPCastExpr returns a new floating-point conversion of src to width bits.
+// Constant sources are folded.
+func NewFPCastExpr(op FPCastOp, src Expr, width uint) Expr {
+	c, ok := src.(*ConstantExpr)
+	if !ok {
+		return &FPCastExpr{Op: op, Src: src, Width: width}
+	}
+
+	switch op {
+	case FPEXT:
+		return NewFPConstantExpr(c.Float(), width)
+	case SITOFP:
+		return NewFPConstantExpr(roundFloat(float64(int64(c.SExt(Width64).Value)), width), width)
+	case UITOFP:
+		return NewFPConstantExpr(roundFloat(float64(c.Value), width), width)
+	case FPTOSI:
+		return NewConstantExpr(uint64(int64(c.Float())), width)
+	case FPTOUI:
+		return NewConstantExpr(uint64(c.Float()), width)
+	default:
+		panic(fmt.Sprintf("invalid fp cast op: %s", op))
+	}
+}
+
+// String returns the string representation of the expression.
+func (e *FPCastExpr) String() string {
+	return fmt.Sprintf("(%s %s %d)", e.Op, e.Src, e.Width)
+}
+
+// roundFloat rounds v to the precision of a float with the given width.
+func roundFloat(v float64, width uint) float64 {
+	if width == Width32 {
+		return float64(float32(v))
+	}
+	return v
+}
+
+func compareFPBinaryExpr(a, b *FPBinaryExpr) int {
+	if a.Op < b.Op {
+		return -1
+	} else if a.Op > b.Op {
+		return 1
+	}
+	if cmp := CompareExpr(a.LHS, b.LHS); cmp != 0 {
+		return cmp
+	}
+	return CompareExpr(a.RHS, b.RHS)
+}
+
+func compareFPCastExpr(a, b *FPCastExpr) int {
+	if a.Op < b.Op {
+		return -1
+	} else if a.Op > b.Op {
+		return 1
+	}
+
+	if a.Width < b.Width {
+		return -1
+	} else if a.Width > b.Width {
+		return 1
+	}
+	return CompareExpr(a.Src, b.Src)
+}
@@ -0,0 +1,312 @@
+package glee
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatExpr returns expr in an infix, Go-like syntax such as "x + 1 < 10".
+// Symbolic arrays are named by name, if it returns a non-blank name, and by
+// their ID otherwise. Reads of every byte of an array in little-endian order
+// are shown by the array name alone so integers are shown as their variable.
+//
+// Go has no signed operators so constants within signed operations are shown
+// as signed values. The output is for display only & cannot be parsed.
+func FormatExpr(expr Expr, name func(array *Array) string) string {
+	f := &exprFormatter{name: name}
+	s, _ := f.format(expr, formatUnsigned)
+	return s
+}
+
+// exprFormatter renders expressions for FormatExpr().
+type exprFormatter struct {
+	name func(array *Array) string
+}
+
+// formatKind describes how constant operands are interpreted.
+type formatKind int
+
+const (
+	formatUnsigned = formatKind(iota)
+	formatSigned
+	formatFloat
+)
+
+// Operator precedence, from loosest to tightest binding. Matches Go.
+const (
+	precOr = iota + 1
+	precAnd
+	precCompare
+	precAdd
+	precMul
+	precUnary
+)
+
+// format returns the text of expr & the precedence of its outermost operator.
+func (f *exprFormatter) format(expr Expr, kind formatKind) (string, int) {
+	switch expr := expr.(type) {
+	case *ConstantExpr:
+		return f.formatConstant(expr, kind)
+	case *NotOptimizedExpr:
+		return f.format(expr.Src, kind)
+	case *SelectExpr:
+		if array, _, _, ok := concatRead([]Expr{expr}); ok && array.Size == 1 {
+			return f.arrayName(array), precUnary
+		}
+		return f.arrayName(expr.Array) + "[" + f.operand(expr.Index, formatUnsigned, 0) + "]", precUnary
+	case *ConcatExpr:
+		return f.formatConcat(expr), precUnary
+	case *ExtractExpr:
+		if expr.Offset == 0 {
+			return fmt.Sprintf("uint%d(%s)", expr.Width, f.operand(expr.Expr, kind, 0)), precUnary
+		}
+		return fmt.Sprintf("uint%d(%s >> %d)", expr.Width, f.operand(expr.Expr, kind, precMul), expr.Offset), precUnary
+	case *NotExpr:
+		if ExprWidth(expr.Expr) == WidthBool {
+			return "!" + f.operand(expr.Expr, kind, precUnary), precUnary
+		}
+		return "^" + f.operand(expr.Expr, kind, precUnary), precUnary
+	case *CastExpr:
+		if expr.Signed {
+			return fmt.Sprintf("int%d(%s)", expr.Width, f.operand(expr.Src, formatSigned, 0)), precUnary
+		}
+		return fmt.Sprintf("uint%d(%s)", expr.Width, f.operand(expr.Src, formatUnsigned, 0)), precUnary
+	case *BinaryExpr:
+		return f.formatBinary(expr)
+	case *FPBinaryExpr:
+		return f.formatFPBinary(expr)
+	case *FPCastExpr:
+		return f.formatFPCast(expr), precUnary
+	case *IteExpr:
+		return fmt.Sprintf("ite(%s, %s, %s)", f.operand(expr.Cond, kind, 0), f.operand(expr.Then, kind, 0), f.operand(expr.Else, kind, 0)), precUnary
+	default:
+		return expr.String(), precUnary
+	}
+}
+
+// operand returns the text of expr, wrapped in parentheses if its operator
+// binds more loosely than prec.
+func (f *exprFormatter) operand(expr Expr, kind formatKind, prec int) string {
+	s, p := f.format(expr, kind)
+	if p < prec {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func (f *exprFormatter) formatConstant(expr *ConstantExpr, kind formatKind) (string, int) {
+	if expr.Width == WidthBool {
+		return strconv.FormatBool(expr.IsTrue()), precUnary
+	}
+
+	switch {
+	case kind == formatFloat && (expr.Width == Width32 || expr.Width == Width64):
+		return strconv.FormatFloat(expr.Float(), 'g', -1, int(expr.Width)), precUnary
+	case kind == formatSigned:
+		return expr.signedBigInt().String(), precUnary
+	case expr.Big != nil:
+		return expr.Big.String(), precUnary
+	default:
+		return strconv.FormatUint(expr.Value, 10), precUnary
+	}
+}
+
+// formatConcat returns a read of consecutive bytes of a single array as a
+// slice of the array, or the array itself if every byte is read. Otherwise,
+// each part of the concatenation is listed from most significant.
+func (f *exprFormatter) formatConcat(expr *ConcatExpr) string {
+	parts := flattenConcat(expr, nil)
+
+	if array, lo, hi, ok := concatRead(parts); ok {
+		if lo == 0 && hi == uint64(array.Size) {
+			return f.arrayName(array)
+		}
+		return fmt.Sprintf("%s[%d:%d]", f.arrayName(array), lo, hi)
+	}
+
+	a := make([]string, len(parts))
+	for i, part := range parts {
+		a[i] = f.operand(part, formatUnsigned, 0)
+	}
+	return "concat(" + strings.Join(a, ", ") + ")"
+}
+
+// flattenConcat appends the parts of nested concatenations to a, ordered
+// from most significant.
+func flattenConcat(expr Expr, a []Expr) []Expr {
+	if expr, ok := expr.(*ConcatExpr); ok {
+		return flattenConcat(expr.LSB, flattenConcat(expr.MSB, a))
+	}
+	return append(a, expr)
+}
+
+// concatRead returns the array & byte range read by parts if every part is a
+// constant-index select of the same array in little-endian order.
+func concatRead(parts []Expr) (array *Array, lo, hi uint64, ok bool) {
+	for i, part := range parts {
+		sel, ok := part.(*SelectExpr)
+		if !ok {
+			return nil, 0, 0, false
+		}
+		index, ok := sel.Index.(*ConstantExpr)
+		if !ok {
+			return nil, 0, 0, false
+		}
+
+		if i == 0 {
+			array, hi = sel.Array, index.Value+1
+		} else if sel.Array != array || index.Value+uint64(i)+1 != hi {
+			return nil, 0, 0, false
+		}
+		lo = index.Value
+	}
+	return array, lo, hi, true
+}
+
+// arrayName returns the name of array followed by its updates, if any.
+func (f *exprFormatter) arrayName(array *Array) string {
+	var name string
+	if f.name != nil {
+		name = f.name(array)
+	}
+	if name == "" {
+		name = fmt.Sprintf("array%d", array.ID)
+	}
+	if array.Updates == nil {
+		return name
+	}
+
+	// Updates are listed in the order they were applied.
+	var a []string
+	for upd := array.Updates; upd != nil; upd = upd.Next {
+		a = append(a, f.operand(upd.Index, formatUnsigned, 0)+": "+f.operand(upd.Value, formatUnsigned, 0))
+	}
+	var buf bytes.Buffer
+	buf.WriteString(name)
+	buf.WriteString("{")
+	for i := len(a) - 1; i >= 0; i-- {
+		buf.WriteString(a[i])
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+func (f *exprFormatter) formatBinary(expr *BinaryExpr) (string, int) {
+	// Negations of booleans are represented as an equality with false.
+	if expr.Op == EQ && IsConstantFalse(expr.LHS) {
+		if rhs, ok := expr.RHS.(*BinaryExpr); ok && rhs.Op == EQ && !IsConstantFalse(rhs.LHS) {
+			return f.infix(rhs.LHS, "!=", rhs.RHS, formatUnsigned, precCompare)
+		}
+		return "!" + f.operand(expr.RHS, formatUnsigned, precUnary), precUnary
+	}
+
+	isBool := ExprWidth(expr.LHS) == WidthBool
+	switch expr.Op {
+	case ADD:
+		return f.infix(expr.LHS, "+", expr.RHS, formatUnsigned, precAdd)
+	case SUB:
+		return f.infix(expr.LHS, "-", expr.RHS, formatUnsigned, precAdd)
+	case MUL:
+		return f.infix(expr.LHS, "*", expr.RHS, formatUnsigned, precMul)
+	case UDIV:
+		return f.infix(expr.LHS, "/", expr.RHS, formatUnsigned, precMul)
+	case SDIV:
+		return f.infix(expr.LHS, "/", expr.RHS, formatSigned, precMul)
+	case UREM:
+		return f.infix(expr.LHS, "%", expr.RHS, formatUnsigned, precMul)
+	case SREM:
+		return f.infix(expr.LHS, "%", expr.RHS, formatSigned, precMul)
+	case AND:
+		if isBool {
+			return f.infix(expr.LHS, "&&", expr.RHS, formatUnsigned, precAnd)
+		}
+		return f.infix(expr.LHS, "&", expr.RHS, formatUnsigned, precMul)
+	case OR:
+		if isBool {
+			return f.infix(expr.LHS, "||", expr.RHS, formatUnsigned, precOr)
+		}
+		return f.infix(expr.LHS, "|", expr.RHS, formatUnsigned, precAdd)
+	case XOR:
+		if isBool {
+			return f.infix(expr.LHS, "!=", expr.RHS, formatUnsigned, precCompare)
+		}
+		return f.infix(expr.LHS, "^", expr.RHS, formatUnsigned, precAdd)
+	case SHL:
+		return f.infix(expr.LHS, "<<", expr.RHS, formatUnsigned, precMul)
+	case LSHR:
+		return f.infix(expr.LHS, ">>", expr.RHS, formatUnsigned, precMul)
+	case ASHR:
+		return f.infix(expr.LHS, ">>", expr.RHS, formatSigned, precMul)
+	case EQ:
+		return f.infix(expr.LHS, "==", expr.RHS, formatUnsigned, precCompare)
+	case NE:
+		return f.infix(expr.LHS, "!=", expr.RHS, formatUnsigned, precCompare)
+	case ULT:
+		return f.infix(expr.LHS, "<", expr.RHS, formatUnsigned, precCompare)
+	case ULE:
+		return f.infix(expr.LHS, "<=", expr.RHS, formatUnsigned, precCompare)
+	case UGT:
+		return f.infix(expr.LHS, ">", expr.RHS, formatUnsigned, precCompare)
+	case UGE:
+		return f.infix(expr.LHS, ">=", expr.RHS, formatUnsigned, precCompare)
+	case SLT:
+		return f.infix(expr.LHS, "<", expr.RHS, formatSigned, precCompare)
+	case SLE:
+		return f.infix(expr.LHS, "<=", expr.RHS, formatSigned, precCompare)
+	case SGT:
+		return f.infix(expr.LHS, ">", expr.RHS, formatSigned, precCompare)
+	case SGE:
+		return f.infix(expr.LHS, ">=", expr.RHS, formatSigned, precCompare)
+	default:
+		return expr.String(), precUnary
+	}
+}
+
+func (f *exprFormatter) formatFPBinary(expr *FPBinaryExpr) (string, int) {
+	switch expr.Op {
+	case FADD:
+		return f.infix(expr.LHS, "+", expr.RHS, formatFloat, precAdd)
+	case FSUB:
+		return f.infix(expr.LHS, "-", expr.RHS, formatFloat, precAdd)
+	case FMUL:
+		return f.infix(expr.LHS, "*", expr.RHS, formatFloat, precMul)
+	case FDIV:
+		return f.infix(expr.LHS, "/", expr.RHS, formatFloat, precMul)
+	case FEQ:
+		return f.infix(expr.LHS, "==", expr.RHS, formatFloat, precCompare)
+	case FLT:
+		return f.infix(expr.LHS, "<", expr.RHS, formatFloat, precCompare)
+	case FLE:
+		return f.infix(expr.LHS, "<=", expr.RHS, formatFloat, precCompare)
+	default:
+		return expr.String(), precUnary
+	}
+}
+
+func (f *exprFormatter) formatFPCast(expr *FPCastExpr) string {
+	switch expr.Op {
+	case FPEXT:
+		return fmt.Sprintf("float%d(%s)", expr.Width, f.operand(expr.Src, formatFloat, 0))
+	case SITOFP:
+		return fmt.Sprintf("float%d(%s)", expr.Width, f.operand(expr.Src, formatSigned, 0))
+	case UITOFP:
+		return fmt.Sprintf("float%d(%s)", expr.Width, f.operand(expr.Src, formatUnsigned, 0))
+	case FPTOSI:
+		return fmt.Sprintf("int%d(%s)", expr.Width, f.operand(expr.Src, formatFloat, 0))
+	case FPTOUI:
+		return fmt.Sprintf("uint%d(%s)", expr.Width, f.operand(expr.Src, formatFloat, 0))
+	default:
+		return expr.String()
+	}
+}
+
+// infix returns a binary operation. Operators are left-associative so the
+// right operand is wrapped if it binds as loosely as the operator.
+func (f *exprFormatter) infix(lhs Expr, op string, rhs Expr, kind formatKind, prec int) (string, int) {
+	return f.operand(lhs, kind, prec) + " " + op + " " + f.operand(rhs, kind, prec+1), prec
+}
@@ -0,0 +1,54 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestFormatExpr(t *testing.T) {
+	x, y := glee.NewArray(1, 8), glee.NewArray(2, 1)
+	name := func(array *glee.Array) string {
+		switch array.ID {
+		case 1:
+			return "x"
+		case 2:
+			return "y"
+		}
+		return ""
+	}
+	xv := x.Select(glee.NewConstantExpr(0, 32), 64, true)
+	yv := y.Select(glee.NewConstantExpr(0, 32), 8, true)
+
+	for _, tt := range []struct {
+		name string
+		expr glee.Expr
+		exp  string
+	}{
+		{"Constant", glee.NewConstantExpr(10, 64), "10"},
+		{"Bool", glee.NewBoolConstantExpr(true), "true"},
+		{"Array", xv, "x"},
+		{"Byte", yv, "y"},
+		{"Slice", x.Select(glee.NewConstantExpr(2, 32), 16, true), "x[2:4]"},
+		{"Index", x.Select(glee.NewConstantExpr(3, 32), 8, true), "x[3]"},
+		{"Unnamed", glee.NewArray(3, 4).Select(glee.NewConstantExpr(0, 32), 32, true), "array3"},
+		{"BigEndian", x.Select(glee.NewConstantExpr(0, 32), 16, false), "concat(x[0], x[1])"},
+		{"Compare", glee.NewBinaryExpr(glee.ULT, glee.NewBinaryExpr(glee.ADD, xv, glee.NewConstantExpr(1, 64)), glee.NewConstantExpr(10, 64)), "1 + x < 10"},
+		{"Signed", glee.NewBinaryExpr(glee.SLT, xv, glee.NewConstantExpr(0, 64).Sub(glee.NewConstantExpr(5, 64))), "x < -5"},
+		{"NotEqual", glee.NewBinaryExpr(glee.NE, yv, glee.NewConstantExpr(3, 8)), "3 != y"},
+		{"Parens", glee.NewBinaryExpr(glee.MUL, glee.NewBinaryExpr(glee.ADD, xv, glee.NewConstantExpr(1, 64)), xv), "(1 + x) * x"},
+		{"Logical", glee.NewBinaryExpr(glee.OR,
+			glee.NewBinaryExpr(glee.EQ, yv, glee.NewConstantExpr(1, 8)),
+			glee.NewBinaryExpr(glee.AND, glee.NewBinaryExpr(glee.ULT, yv, glee.NewConstantExpr(5, 8)), glee.NewBinaryExpr(glee.UGT, yv, glee.NewConstantExpr(2, 8))),
+		), "1 == y || y < 5 && 2 < y"},
+		{"Cast", glee.NewCastExpr(yv, 64, true), "int64(y)"},
+		{"Extract", glee.NewExtractExpr(xv, 8, 8), "x[1]"},
+		{"Not", glee.NewNotExpr(xv), "^x"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glee.FormatExpr(tt.expr, name); got != tt.exp {
+				t.Fatalf("FormatExpr()=%q, expected %q", got, tt.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+//go:build ignore
+// +build ignore
+
+// This program generates osarch.go from the ports supported by the Go
+// toolchain. Run it with "go generate" after upgrading Go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+func main() {
+	out, err := exec.Command("go", "tool", "dist", "list").Output()
+	if err != nil {
+		log.Fatalf("go tool dist list: %s", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_osarch.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package glee")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// osArchs is the set of valid OS & architecture combinations.")
+	fmt.Fprintln(&buf, "var osArchs = map[string]struct{}{")
+	for _, line := range strings.Fields(string(out)) {
+		fmt.Fprintf(&buf, "\t%q: {},\n", line)
+	}
+	fmt.Fprintln(&buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	} else if err := ioutil.WriteFile("osarch.go", src, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
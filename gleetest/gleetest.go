@@ -0,0 +1,258 @@
+// Package gleetest runs symbolic execution from within "go test".
+//
+// A test passes a function from the package under test to Run():
+//
+//	func TestFoo(t *testing.T) {
+//		gleetest.Run(t, Foo)
+//	}
+//
+// The package is loaded & built in SSA form from the test's working directory
+// and the function is executed with symbolic arguments. Each path which does
+// not finish normally, such as a panic or a failed glee.Assert(), is reported
+// as a failing subtest named with the concrete arguments which reach it.
+//
+// Existing fuzz targets can be executed with RunFuzz(), which executes the
+// callback passed to f.Fuzz() with symbolic arguments:
+//
+//	func TestFuzzFoo(t *testing.T) {
+//		gleetest.RunFuzz(t, FuzzFoo)
+//	}
+package gleetest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+	"github.com/benbjohnson/glee/z3"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Run symbolically executes fn with the default runner & reports each failing
+// path as a subtest of t.
+func Run(t *testing.T, fn interface{}) {
+	t.Helper()
+	NewRunner().Run(t, fn)
+}
+
+// RunFuzz symbolically executes the f.Fuzz() callback of the fuzz target fn
+// with the default runner & reports each failing path as a subtest of t.
+func RunFuzz(t *testing.T, fn func(*testing.F)) {
+	t.Helper()
+	NewRunner().RunFuzz(t, fn)
+}
+
+// Runner symbolically executes functions of the package under test.
+type Runner struct {
+	// Solver used for exploring paths & solving arguments. If nil, a Z3
+	// solver is created for each function.
+	Solver glee.Solver
+
+	// Length, in bytes, of symbolic string & byte slice arguments.
+	StringLen int
+
+	// Exploration limits for each function.
+	Limits glee.Limits
+
+	// Values preferred for solved arguments.
+	Preference glee.Preference
+}
+
+// NewRunner returns a new instance of Runner.
+func NewRunner() *Runner {
+	return &Runner{StringLen: testgen.DefaultStringLen}
+}
+
+// Run symbolically executes fn & reports each failing path as a subtest of t.
+func (r *Runner) Run(t *testing.T, fn interface{}) {
+	t.Helper()
+
+	failures, err := r.Failures(context.Background(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportFailures(t, funcName(fn), failures)
+}
+
+// RunFuzz symbolically executes the f.Fuzz() callback of the fuzz target fn
+// & reports each failing path as a subtest of t. See FuzzFailures().
+func (r *Runner) RunFuzz(t *testing.T, fn func(*testing.F)) {
+	t.Helper()
+
+	failures, err := r.FuzzFailures(context.Background(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportFailures(t, funcName(fn), failures)
+}
+
+// reportFailures reports each failing path of the function name as a subtest
+// of t named with its arguments.
+func reportFailures(t *testing.T, name string, failures []*testgen.TestCase) {
+	t.Helper()
+
+	for _, tc := range failures {
+		tc := tc
+		t.Run(fmt.Sprintf("%s(%s)", name, strings.Join(tc.Args, ", ")), func(t *testing.T) {
+			if tc.Reason != "" {
+				t.Errorf("%s: %s", tc.Status, tc.Reason)
+			} else {
+				t.Error(tc.Status)
+			}
+		})
+	}
+}
+
+// Failures symbolically executes fn & returns a test case for each path which
+// does not finish normally. fn must be a top-level function of a package in
+// the current working directory.
+func (r *Runner) Failures(ctx context.Context, fn interface{}) ([]*testgen.TestCase, error) {
+	f, err := findFunction(fn)
+	if err != nil {
+		return nil, err
+	}
+	return r.failures(ctx, f, false)
+}
+
+// FuzzFailures symbolically executes the callback passed to f.Fuzz() by the
+// fuzz target fn & returns a test case for each path which does not finish
+// normally. The callback must not capture variables. The *testing.T argument
+// of the callback is omitted from each test case.
+func (r *Runner) FuzzFailures(ctx context.Context, fn func(*testing.F)) ([]*testgen.TestCase, error) {
+	f, err := findFunction(fn)
+	if err != nil {
+		return nil, err
+	}
+	return r.failures(ctx, f, true)
+}
+
+// failures returns a test case for each failing path of f. If fuzz is true,
+// f is a fuzz target & its callback is executed instead.
+func (r *Runner) failures(ctx context.Context, f *ssa.Function, fuzz bool) ([]*testgen.TestCase, error) {
+	g := testgen.NewGenerator()
+	g.Solver = r.Solver
+	g.StringLen = r.StringLen
+	g.Limits = r.Limits
+	g.Preference = r.Preference
+
+	if g.Solver == nil {
+		solver := z3.NewSolver()
+		defer solver.Close()
+		g.Solver = solver
+	}
+
+	var cases []*testgen.TestCase
+	var err error
+	if fuzz {
+		cases, err = g.GenerateFuzz(ctx, f)
+	} else {
+		cases, err = g.Generate(ctx, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var a []*testgen.TestCase
+	for _, tc := range cases {
+		switch tc.Status {
+		case glee.ExecutionStatusFinished, glee.ExecutionStatusExited:
+		default:
+			a = append(a, tc)
+		}
+	}
+	return a, nil
+}
+
+// funcName returns the fully qualified name of the function value fn, such
+// as "github.com/user/pkg.Foo".
+func funcName(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	} else if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
+
+// findFunction returns the SSA function for the function value fn.
+func findFunction(fn interface{}) (*ssa.Function, error) {
+	name := funcName(fn)
+	if name == "" {
+		return nil, fmt.Errorf("gleetest: function required: %T", fn)
+	}
+
+	// Split the package path from the function name. The package path may
+	// contain dots but only before its final slash.
+	i := strings.LastIndex(name, "/") + 1
+	j := strings.Index(name[i:], ".")
+	if j == -1 {
+		return nil, fmt.Errorf("gleetest: invalid function name: %s", name)
+	}
+	path, fnName := name[:i+j], name[i+j+1:]
+	if strings.ContainsAny(fnName, ".()") {
+		return nil, fmt.Errorf("gleetest: only top-level functions are supported: %s", name)
+	}
+
+	pkgs, err := loadPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	// Test variants of a package include its test files so they are
+	// checked after the package itself.
+	for _, pkg := range pkgs {
+		if pkg.Pkg.Path() != path {
+			continue
+		} else if f, ok := pkg.Members[fnName].(*ssa.Function); ok {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("gleetest: function not found: %s", name)
+}
+
+var program struct {
+	once sync.Once
+	pkgs []*ssa.Package
+	err  error
+}
+
+// loadPackages loads the package in the current working directory, along
+// with its tests, & builds it in SSA form. The program is only built once.
+func loadPackages() ([]*ssa.Package, error) {
+	program.once.Do(func() {
+		program.pkgs, program.err = buildProgram(".")
+	})
+	return program.pkgs, program.err
+}
+
+// buildProgram builds the packages matching patterns in SSA form.
+func buildProgram(patterns ...string) ([]*ssa.Package, error) {
+	initial, err := packages.Load(&packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Tests: true,
+	}, patterns...)
+	if err != nil {
+		return nil, err
+	} else if packages.PrintErrors(initial) > 0 {
+		return nil, fmt.Errorf("gleetest: packages contain errors")
+	}
+
+	prog, pkgs := ssautil.AllPackages(initial, ssa.BuilderMode(0))
+	for i, pkg := range pkgs {
+		if pkg == nil {
+			return nil, fmt.Errorf("gleetest: cannot build SSA for package %s", initial[i])
+		}
+		pkg.SetDebugMode(true)
+	}
+	prog.Build()
+	return pkgs, nil
+}
@@ -0,0 +1,70 @@
+package gleetest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/gleetest"
+)
+
+func classify(n int) int {
+	if n > 10 {
+		return 1
+	}
+	return 0
+}
+
+func divide(n int) int {
+	return 100 / n
+}
+
+func TestRun(t *testing.T) {
+	gleetest.Run(t, classify)
+}
+
+func TestRunner_Failures(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		if failures, err := gleetest.NewRunner().Failures(context.Background(), classify); err != nil {
+			t.Fatal(err)
+		} else if len(failures) != 0 {
+			t.Fatalf("unexpected failures: %d", len(failures))
+		}
+	})
+
+	// Ensure the division by zero is reported with its argument.
+	t.Run("Panic", func(t *testing.T) {
+		failures, err := gleetest.NewRunner().Failures(context.Background(), divide)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(failures) != 1 {
+			t.Fatalf("unexpected failures: %d", len(failures))
+		} else if tc := failures[0]; tc.Status != glee.ExecutionStatusPanicked || tc.Args[0] != "0" {
+			t.Fatalf("unexpected failure: status=%s args=%v", tc.Status, tc.Args)
+		}
+	})
+
+	t.Run("ErrClosure", func(t *testing.T) {
+		if _, err := gleetest.NewRunner().Failures(context.Background(), func(n int) {}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func FuzzDivide(f *testing.F) {
+	f.Fuzz(func(t *testing.T, n int) {
+		divide(n)
+	})
+}
+
+func TestRunner_FuzzFailures(t *testing.T) {
+	// Ensure the division by zero within the callback is reported.
+	failures, err := gleetest.NewRunner().FuzzFailures(context.Background(), FuzzDivide)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(failures) != 1 {
+		t.Fatalf("unexpected failures: %d", len(failures))
+	} else if tc := failures[0]; tc.Status != glee.ExecutionStatusPanicked || len(tc.Args) != 1 || tc.Args[0] != "0" {
+		t.Fatalf("unexpected failure: status=%s args=%v", tc.Status, tc.Args)
+	}
+}
@@ -0,0 +1,162 @@
+package glee
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Default limits for goroutine exploration.
+const (
+	DefaultMaxGoroutines  = 4
+	DefaultMaxPreemptions = 2
+)
+
+// mainGoroutineID is the ID of the goroutine executing the entry function.
+// The program exits once this goroutine returns.
+const mainGoroutineID = 0
+
+// goroutine represents a goroutine that is not currently executing.
+type goroutine struct {
+	id    int
+	stack []*StackFrame
+	waits []chanWait // blocked channel operations, if any
+}
+
+// clone returns a deep copy of the goroutine's stack.
+func (g *goroutine) clone() *goroutine {
+	return &goroutine{id: g.id, stack: cloneStack(g.stack), waits: g.waits}
+}
+
+// GoroutineID returns the ID of the currently executing goroutine. The
+// entry function executes on goroutine zero.
+func (s *ExecutionState) GoroutineID() int { return s.gid }
+
+// GoroutineN returns the number of live goroutines, including the current one.
+func (s *ExecutionState) GoroutineN() int { return len(s.goroutines) + 1 }
+
+// spawn creates a new goroutine which calls fn. The goroutine does not run
+// until it is scheduled.
+func (s *ExecutionState) spawn(fn *ssa.Function, args, freeVars []Binding) {
+	// Build the new stack in place & then restore the current stack.
+	stack := s.stack
+	s.stack = nil
+	s.Push(fn)
+	for i, arg := range args {
+		s.Frame().bind(fn.Params[i], arg)
+	}
+	s.bindFreeVars(freeVars)
+
+	s.goroutineSeq++
+	s.goroutines = append(s.goroutines, &goroutine{id: s.goroutineSeq, stack: s.stack})
+	s.stack = stack
+}
+
+// switchGoroutine suspends the current goroutine & resumes the goroutine with
+// the given ID. The current goroutine is discarded if it has exited.
+func (s *ExecutionState) switchGoroutine(id int) {
+	if id == s.gid {
+		return
+	}
+
+	goroutines := make([]*goroutine, 0, len(s.goroutines))
+	if len(s.stack) > 0 {
+		goroutines = append(goroutines, &goroutine{id: s.gid, stack: s.stack, waits: s.waits})
+	}
+
+	for _, g := range s.goroutines {
+		if g.id == id {
+			s.gid, s.stack, s.waits = g.id, g.stack, g.waits
+		} else {
+			goroutines = append(goroutines, g)
+		}
+	}
+	s.goroutines = goroutines
+}
+
+// runnableGoroutineIDs returns the IDs of suspended goroutines that are not
+// blocked on a channel operation.
+func (s *ExecutionState) runnableGoroutineIDs() []int {
+	var ids []int
+	for _, g := range s.goroutines {
+		if len(g.waits) == 0 {
+			ids = append(ids, g.id)
+		}
+	}
+	return ids
+}
+
+func (e *Executor) executeGoInstr(state *ExecutionState, instr *ssa.Go) error {
+	if _, ok := instr.Call.Value.(*ssa.Builtin); ok {
+		return fmt.Errorf("glee.Executor: go statements calling builtins are not supported")
+	} else if state.GoroutineN() >= e.MaxGoroutines {
+		return fmt.Errorf("glee.Executor: goroutine limit exceeded: %d", e.MaxGoroutines)
+	} else if ok, err := e.resolveCallee(state, instr); err != nil || ok {
+		return err
+	}
+
+	fn, args := state.ExtractCall(instr)
+	if e.registeredFn(fn) != nil {
+		return fmt.Errorf("glee.Executor: go statements calling registered functions are not supported: %s", fn.Name())
+	}
+	state.spawn(fn, args, state.freeVarBindings(instr))
+
+	return e.yield(state)
+}
+
+// yield represents a preemption point. A child state is forked for each
+// runnable goroutine so every interleaving at this point is explored. Once a
+// path has reached MaxPreemptions, the current goroutine continues without
+// forking.
+func (e *Executor) yield(state *ExecutionState) error {
+	others := state.runnableGoroutineIDs()
+	if len(others) == 0 || state.preemptN >= e.MaxPreemptions {
+		return nil
+	}
+	ids := append([]int{state.gid}, others...)
+
+	for _, id := range ids {
+		e.infof("[fork] goroutine: %d", id)
+		newState := state.Fork(nil)
+		newState.id = e.nextStateID()
+		if id != state.gid {
+			newState.switchGoroutine(id)
+			newState.preemptN++
+		}
+		e.addState(newState)
+	}
+	return nil
+}
+
+// exitGoroutine is called once a goroutine other than the main goroutine has
+// returned. Execution continues on one of the remaining runnable goroutines.
+func (e *Executor) exitGoroutine(state *ExecutionState) error {
+	e.infof("[goroutine] exit: %d", state.gid)
+
+	ids := state.runnableGoroutineIDs()
+	if len(ids) == 0 {
+		state.status = ExecutionStatusPanicked
+		state.reason = "all goroutines are asleep - deadlock!"
+		return nil
+	}
+	return e.schedule(state, ids)
+}
+
+// schedule switches execution to one of the goroutines in ids. A child state
+// is forked for each goroutine if there is more than one choice. Switching is
+// not counted against MaxPreemptions as the current goroutine cannot run.
+func (e *Executor) schedule(state *ExecutionState, ids []int) error {
+	if len(ids) == 1 {
+		state.switchGoroutine(ids[0])
+		return nil
+	}
+
+	for _, id := range ids {
+		e.infof("[fork] goroutine: %d", id)
+		newState := state.Fork(nil)
+		newState.id = e.nextStateID()
+		newState.switchGoroutine(id)
+		e.addState(newState)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package glee
+
+import (
+	"context"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// havoc binds fresh symbolic values to the results of instr instead of
+// executing fn. Arguments are assumed to be left unchanged by the call.
+func (e *Executor) havoc(state *ExecutionState, instr *ssa.Call, fn *ssa.Function, reason string) {
+	e.infof("[havoc] %s: %s", fn, reason)
+
+	results := instr.Call.Signature().Results()
+	values := make(Tuple, results.Len())
+	for i := range values {
+		values[i] = state.havocValue(results.At(i).Type())
+	}
+
+	switch len(values) {
+	case 0:
+	case 1:
+		state.Frame().bind(instr, values[0])
+	default:
+		state.Frame().bind(instr, values)
+	}
+}
+
+// havocFailedCall pops the frame of state which failed with err & havocs the
+// call which created it. Returns false if the frame was not created by a call
+// instruction, such as the entry function or a deferred call, or if err was
+// caused by cancellation or the solver.
+func (e *Executor) havocFailedCall(ctx context.Context, state *ExecutionState, err error) bool {
+	if ctx.Err() != nil || IsSolverUnknown(err) {
+		return false
+	}
+
+	caller := state.CallerFrame()
+	if caller == nil {
+		return false
+	}
+	call, ok := caller.Instr().(*ssa.Call)
+	if !ok {
+		return false
+	}
+
+	fn := state.Frame().Fn()
+	state.Pop()
+	e.havoc(state, call, fn, err.Error())
+	return true
+}
+
+// havocValue returns a fresh symbolic value of typ. Types which cannot be
+// symbolic, such as pointers & interfaces, return their zero value.
+func (s *ExecutionState) havocValue(typ types.Type) Binding {
+	e := s.executor
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsString != 0:
+			_, array := s.Alloc(uint(e.HavocStringLen))
+			return array
+		case t.Info()&types.IsBoolean != 0:
+			_, array := s.Alloc(1)
+			return NewNotExpr(NewIsZeroExpr(array.Select(NewConstantExpr(0, 32), 8, e.IsLittleEndian())))
+		case isExprType(t):
+			width := e.Sizeof(t)
+			_, array := s.Alloc(width / 8)
+			return array.Select(NewConstantExpr(0, 32), width, e.IsLittleEndian())
+		}
+
+	case *types.Slice:
+		if elem, ok := t.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			addr, _ := s.Alloc(uint(e.HavocStringLen))
+			length := NewConstantExpr(uint64(e.HavocStringLen), e.PointerWidth())
+			_, hdr := s.Alloc((e.PointerWidth() / 8) * 3)
+			hdr = s.storeIntAt(hdr, 0, addr)   // data
+			hdr = s.storeIntAt(hdr, 1, length) // len
+			hdr = s.storeIntAt(hdr, 2, length) // cap
+			s.heap = s.heap.Set(hdr.ID, hdr)
+			return hdr
+		}
+	}
+	return s.zeroBinding(typ)
+}
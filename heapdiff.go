@@ -0,0 +1,122 @@
+package glee
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benbjohnson/immutable"
+)
+
+// HeapDiff represents an allocation that differs between two heaps. Old is
+// nil if the allocation was added & New is nil if it was removed.
+type HeapDiff struct {
+	Addr    uint64
+	Old     *Array
+	New     *Array
+	Offsets []uint // byte offsets that differ, if allocated in both
+}
+
+// Bytes returns the number of bytes that differ in the allocation.
+func (d *HeapDiff) Bytes() uint {
+	switch {
+	case d.Old == nil:
+		return d.New.Size
+	case d.New == nil:
+		return d.Old.Size
+	default:
+		return uint(len(d.Offsets))
+	}
+}
+
+// String returns a one-line summary of the difference.
+func (d *HeapDiff) String() string {
+	switch {
+	case d.Old == nil:
+		return fmt.Sprintf("+%08d (%d bytes)", d.Addr, d.New.Size)
+	case d.New == nil:
+		return fmt.Sprintf("-%08d (%d bytes)", d.Addr, d.Old.Size)
+	default:
+		return fmt.Sprintf("~%08d [%s] (%d bytes)", d.Addr, formatOffsetRanges(d.Offsets), len(d.Offsets))
+	}
+}
+
+// DiffHeap returns the allocations that differ between the heap of other &
+// the heap of s, ordered by address. Typically other is an ancestor of s so
+// the result is the memory written along the branch between the two states.
+func (s *ExecutionState) DiffHeap(other *ExecutionState) []*HeapDiff {
+	return diffHeap(other.heap, s.heap)
+}
+
+// diffHeap returns the allocations that differ between two heaps. Arrays are
+// copy-on-write so allocations sharing the same array are skipped without
+// comparing their contents.
+func diffHeap(a, b *immutable.SortedMap) []*HeapDiff {
+	var diffs []*HeapDiff
+	itrA, itrB := a.Iterator(), b.Iterator()
+	kA, vA := itrA.Next()
+	kB, vB := itrB.Next()
+	for kA != nil || kB != nil {
+		switch {
+		case kB == nil || (kA != nil && kA.(uint64) < kB.(uint64)):
+			diffs = append(diffs, &HeapDiff{Addr: kA.(uint64), Old: vA.(*Array)})
+			kA, vA = itrA.Next()
+
+		case kA == nil || kB.(uint64) < kA.(uint64):
+			diffs = append(diffs, &HeapDiff{Addr: kB.(uint64), New: vB.(*Array)})
+			kB, vB = itrB.Next()
+
+		default:
+			if offsets := diffArray(vA.(*Array), vB.(*Array)); len(offsets) > 0 {
+				diffs = append(diffs, &HeapDiff{Addr: kA.(uint64), Old: vA.(*Array), New: vB.(*Array), Offsets: offsets})
+			}
+			kA, vA = itrA.Next()
+			kB, vB = itrB.Next()
+		}
+	}
+	return diffs
+}
+
+// diffArray returns the byte offsets whose values differ between two arrays.
+// Bytes past the end of the shorter array are always considered different.
+func diffArray(a, b *Array) []uint {
+	if a == b {
+		return nil
+	}
+
+	size := a.Size
+	if b.Size > size {
+		size = b.Size
+	}
+
+	var offsets []uint
+	for i := uint(0); i < size; i++ {
+		if i >= a.Size || i >= b.Size {
+			offsets = append(offsets, i)
+			continue
+		}
+		index := NewConstantExpr64(uint64(i))
+		if CompareExpr(a.selectByte(index), b.selectByte(index)) != 0 {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+// formatOffsetRanges returns sorted offsets as comma-separated ranges.
+// For example, offsets 0, 1, 2 & 5 are formatted as "0-2,5".
+func formatOffsetRanges(offsets []uint) string {
+	var a []string
+	for i := 0; i < len(offsets); {
+		j := i
+		for j+1 < len(offsets) && offsets[j+1] == offsets[j]+1 {
+			j++
+		}
+		if i == j {
+			a = append(a, fmt.Sprint(offsets[i]))
+		} else {
+			a = append(a, fmt.Sprintf("%d-%d", offsets[i], offsets[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(a, ",")
+}
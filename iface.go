@@ -0,0 +1,137 @@
+package glee
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// resolveInvoke resolves the dynamic type of the interface receiver of an
+// invoke-mode call. Method lookup is performed by the SSA program so methods
+// promoted from embedded fields & value methods called through pointers are
+// dispatched to their synthesized wrappers.
+//
+// A nil interface panics. If the dynamic type is symbolic then a child state
+// is forked for each type implementing the interface & the interface is rebound
+// with that type within the child. Boxed values with a symbolic address are
+// resolved similarly for each allocation. The call is then executed again.
+// Returns true if state was handled & the call should not proceed.
+func (e *Executor) resolveInvoke(state *ExecutionState, instr ssa.CallInstruction) (bool, error) {
+	common := instr.Common()
+	iface := state.Eval(common.Value).(*Array)
+
+	typeID, ok := state.selectIntAt(iface, 0).(*ConstantExpr)
+	if !ok {
+		return true, e.forkInvokeTypes(state, common, iface)
+	} else if typeID.Value == 0 {
+		e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+		return true, nil
+	}
+
+	typ := e.typesByID[int(typeID.Value)]
+	if !isBoxedType(typ) {
+		return false, nil
+	} else if _, ok := state.selectIntAt(iface, 1).(*ConstantExpr); ok {
+		return false, nil
+	} else if e.Sizeof(typ) == 0 {
+		// Zero-sized values are not allocated so always use a nil address.
+		state.Frame().bind(common.Value, state.storeIntAt(iface, 1, NewConstantExpr(0, e.PointerWidth())))
+		return false, nil
+	}
+	return true, e.forkInvokeValues(state, common, iface)
+}
+
+// forkInvokeTypes forks a child of state for each dynamic type of iface which
+// implements the interface of the call.
+func (e *Executor) forkInvokeTypes(state *ExecutionState, common *ssa.CallCommon, iface *Array) error {
+	typeID := state.selectIntAt(iface, 0)
+	ifaceType := common.Value.Type().Underlying().(*types.Interface)
+
+	ids := make([]int, 0, len(e.typesByID))
+	for id, typ := range e.typesByID {
+		if !types.IsInterface(typ) && types.Implements(typ, ifaceType) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	// Conditions for each type followed by the condition that the interface
+	// holds none of them, which includes nil.
+	conds := make([]Expr, 0, len(ids)+1)
+	invalid := Expr(NewBoolConstantExpr(true))
+	for _, id := range ids {
+		cond := newEqExpr(typeID, NewConstantExpr(uint64(id), ExprWidth(typeID)))
+		conds = append(conds, cond)
+		invalid = newAndExpr(invalid, NewNotExpr(cond))
+	}
+	conds = append(conds, invalid)
+
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		if i == len(conds)-1 {
+			e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+			return
+		}
+
+		e.infof("[fork] invoke: type=%s", e.typesByID[ids[i]])
+		other := state.storeIntAt(iface, 0, NewConstantExpr(uint64(ids[i]), e.PointerWidth()))
+		state.Frame().bind(common.Value, other)
+		state.Frame().pc-- // execute call again with resolved type
+	})
+}
+
+// forkInvokeValues forks a child of state for each allocation that the
+// symbolic address of a boxed value held by iface may reference.
+func (e *Executor) forkInvokeValues(state *ExecutionState, common *ssa.CallCommon, iface *Array) error {
+	addr := state.selectIntAt(iface, 1)
+	targets, invalid, err := e.resolveAddr(state, addr)
+	if err != nil {
+		return err
+	}
+
+	// Boxed values are referenced by the base address of their allocation.
+	conds := make([]Expr, 0, len(targets)+1)
+	for _, target := range targets {
+		conds = append(conds, newEqExpr(addr, target.base))
+	}
+	if invalid != nil {
+		conds = append(conds, invalid)
+	}
+
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		if i == len(targets) {
+			e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+			return
+		}
+
+		e.infof("[fork] invoke: value=%d", targets[i].base.Value)
+		other := state.storeIntAt(iface, 1, targets[i].base)
+		state.Frame().bind(common.Value, other)
+		state.Frame().pc-- // execute call again with resolved value
+	})
+}
+
+// execWrapNilChk implements the nil check of the wrappers synthesized for
+// value methods called through a pointer. The pointer is returned if it is
+// non-nil. Otherwise, the state, or a forked child state, panics.
+func execWrapNilChk(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	ptr := args[0].(Expr)
+
+	// Match the runtime which only qualifies the first receiver type.
+	recvType := constant.StringVal(instr.Call.Args[1].(*ssa.Const).Value)
+	methodName := constant.StringVal(instr.Call.Args[2].(*ssa.Const).Value)
+	typeName := recvType[strings.LastIndex(recvType, ".")+1:]
+
+	isNil := NewIsZeroExpr(ptr)
+	return forkEach(state, []Expr{NewNotExpr(isNil), isNil}, func(state *ExecutionState, i int) {
+		if i == 0 {
+			state.Frame().bind(instr, ptr)
+			return
+		}
+		state.Executor().runtimePanic(state, fmt.Sprintf("value method %s.%s called using nil *%s pointer", recvType, methodName, typeName))
+	})
+}
@@ -0,0 +1,175 @@
+package glee
+
+import (
+	"context"
+)
+
+// Ensure type implements interface.
+var _ ContextSolver = (*IndependenceSolver)(nil)
+var _ SolverStats = (*IndependenceSolver)(nil)
+
+// IndependenceSolver wraps a solver and only passes along the constraints that
+// are relevant to a query. Constraints are split into independent groups which
+// do not share any arrays.
+//
+// The last constraint is treated as the query and all preceding constraints
+// are assumed to be satisfiable, which holds for a state's path condition. Only
+// the query's group and the groups referencing a requested array are solved.
+type IndependenceSolver struct {
+	Solver Solver
+}
+
+// NewIndependenceSolver returns a new instance of IndependenceSolver.
+func NewIndependenceSolver(solver Solver) *IndependenceSolver {
+	return &IndependenceSolver{Solver: solver}
+}
+
+// QueryStats returns the statistics of the underlying solver, if available.
+func (s *IndependenceSolver) QueryStats() QueryStats {
+	if solver, ok := s.Solver.(SolverStats); ok {
+		return solver.QueryStats()
+	}
+	return QueryStats{}
+}
+
+// Solve returns the satisfiability of the constraints relevant to the last
+// constraint & the requested arrays. Arrays which are not referenced by any
+// constraint are unconstrained and are returned as zero values.
+func (s *IndependenceSolver) Solve(constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	return s.SolveContext(context.Background(), constraints, arrays)
+}
+
+// SolveContext is the same as Solve() but passes ctx to the underlying solver.
+func (s *IndependenceSolver) SolveContext(ctx context.Context, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	groups, arrayGroups, ok := splitIndependentConstraints(constraints)
+	if !ok {
+		return false, nil, nil
+	}
+
+	// Determine the groups required to answer the query.
+	required := make(map[int]bool)
+	if len(constraints) > 0 {
+		if i, ok := groups.find(len(constraints) - 1); ok {
+			required[i] = true
+		}
+	}
+	for _, array := range arrays {
+		if i, ok := arrayGroups[array.ID]; ok {
+			required[groups.root(i)] = true
+		}
+	}
+
+	// Solve each group separately & combine the array values.
+	values = make([][]byte, len(arrays))
+	for _, root := range groups.roots() {
+		if !required[root] {
+			continue
+		}
+
+		var groupArrays []*Array
+		var indices []int
+		for i, array := range arrays {
+			if j, ok := arrayGroups[array.ID]; ok && groups.root(j) == root {
+				groupArrays, indices = append(groupArrays, array), append(indices, i)
+			}
+		}
+
+		satisfiable, groupValues, err := SolveContext(ctx, s.Solver, groups.constraints(root), groupArrays)
+		if err != nil {
+			return false, nil, err
+		} else if !satisfiable {
+			return false, nil, nil
+		}
+		for i, j := range indices {
+			values[j] = groupValues[i]
+		}
+	}
+
+	// Unconstrained arrays can hold any value so default them to zero.
+	for i, array := range arrays {
+		if values[i] == nil {
+			values[i] = make([]byte, array.Size)
+		}
+	}
+	return true, values, nil
+}
+
+// constraintGroups is a disjoint set of constraint indices. Constraints in the
+// same set share at least one array, directly or transitively.
+type constraintGroups struct {
+	exprs   []Expr
+	parents []int // parent index, -1 if constant
+}
+
+// find returns the root of the group for the constraint at index i. Returns
+// false if the constraint is constant true and belongs to no group.
+func (g *constraintGroups) find(i int) (int, bool) {
+	if g.parents[i] == -1 {
+		return 0, false
+	}
+	return g.root(i), true
+}
+
+// root returns the root index of the group containing the constraint at i.
+func (g *constraintGroups) root(i int) int {
+	for g.parents[i] != i {
+		g.parents[i] = g.parents[g.parents[i]]
+		i = g.parents[i]
+	}
+	return i
+}
+
+// union merges the groups containing constraints i & j.
+func (g *constraintGroups) union(i, j int) {
+	if i, j = g.root(i), g.root(j); i != j {
+		g.parents[j] = i
+	}
+}
+
+// roots returns the root index of each group in constraint order.
+func (g *constraintGroups) roots() []int {
+	var a []int
+	for i, parent := range g.parents {
+		if parent == i {
+			a = append(a, i)
+		}
+	}
+	return a
+}
+
+// constraints returns the constraints of the group with the given root, in order.
+func (g *constraintGroups) constraints(root int) []Expr {
+	var a []Expr
+	for i, expr := range g.exprs {
+		if g.parents[i] != -1 && g.root(i) == root {
+			a = append(a, expr)
+		}
+	}
+	return a
+}
+
+// splitIndependentConstraints groups constraints by the arrays they reference.
+// Also returns a lookup of array ID to the index of a constraint referencing it.
+// Returns false if any constraint is constant false.
+func splitIndependentConstraints(constraints []Expr) (*constraintGroups, map[uint64]int, bool) {
+	g := &constraintGroups{exprs: constraints, parents: make([]int, len(constraints))}
+	arrayGroups := make(map[uint64]int)
+	for i, expr := range constraints {
+		if IsConstantFalse(expr) {
+			return nil, nil, false
+		} else if IsConstantTrue(expr) {
+			g.parents[i] = -1
+			continue
+		}
+
+		g.parents[i] = i
+		for _, array := range FindArrays(expr) {
+			if j, ok := arrayGroups[array.ID]; ok {
+				g.union(j, i)
+			} else {
+				arrayGroups[array.ID] = i
+			}
+		}
+	}
+	return g, arrayGroups, true
+}
@@ -0,0 +1,81 @@
+package glee_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestIndependenceSolver_Solve(t *testing.T) {
+	a, b := glee.NewArray(1, 1), glee.NewArray(2, 1)
+	x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+	y := b.Select(glee.NewConstantExpr(0, 32), 8, false)
+	xc := glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr(10, 8))
+	yc := glee.NewBinaryExpr(glee.ULT, y, glee.NewConstantExpr(20, 8))
+	yc2 := glee.NewBinaryExpr(glee.UGT, y, glee.NewConstantExpr(5, 8))
+
+	t.Run("Query", func(t *testing.T) {
+		var solver SolverMock
+		solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+			if len(constraints) != 2 || constraints[0] != yc || constraints[1] != yc2 {
+				t.Fatalf("unexpected constraints: %v", constraints)
+			}
+			return true, nil, nil
+		}
+
+		s := glee.NewIndependenceSolver(&solver)
+		if satisfiable, _, err := s.Solve([]glee.Expr{xc, yc, yc2}, nil); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if solver.SolveN != 1 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		}
+	})
+
+	t.Run("Arrays", func(t *testing.T) {
+		var solver SolverMock
+		solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+			if len(arrays) != 1 {
+				t.Fatalf("unexpected arrays: %v", arrays)
+			}
+			return true, [][]byte{{byte(arrays[0].ID)}}, nil
+		}
+
+		c := glee.NewArray(3, 2)
+		s := glee.NewIndependenceSolver(&solver)
+		if satisfiable, values, err := s.Solve([]glee.Expr{xc, yc}, []*glee.Array{a, b, c}); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if solver.SolveN != 2 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		} else if !bytes.Equal(values[0], []byte{1}) || !bytes.Equal(values[1], []byte{2}) || !bytes.Equal(values[2], []byte{0, 0}) {
+			t.Fatalf("unexpected values: %v", values)
+		}
+	})
+
+	t.Run("ConstantFalse", func(t *testing.T) {
+		var solver SolverMock
+		s := glee.NewIndependenceSolver(&solver)
+		if satisfiable, _, err := s.Solve([]glee.Expr{xc, glee.NewBoolConstantExpr(false)}, nil); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		} else if solver.SolveN != 0 {
+			t.Fatalf("unexpected solve count: %d", solver.SolveN)
+		}
+	})
+}
+
+// SolverMock is a mock implementation of glee.Solver.
+type SolverMock struct {
+	SolveN    int
+	SolveFunc func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error)
+}
+
+func (s *SolverMock) Solve(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+	s.SolveN++
+	return s.SolveFunc(constraints, arrays)
+}
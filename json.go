@@ -0,0 +1,488 @@
+package glee
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// jsonVersion is the version of the JSON encoding. Other versions are
+// rejected by DecodeJSON() so the format can change without being misread.
+const jsonVersion = 1
+
+// EncodeJSON returns a JSON encoding of a set of constraints & the arrays to
+// solve for. Unlike the SMT-LIB2 & KQuery formats, the encoding preserves the
+// exact structure of each expression & array update chain so DecodeJSON()
+// returns equivalent values. This allows path conditions to be persisted or
+// passed to a solver in another process.
+//
+// Expressions, arrays & updates are each encoded once & referenced by index
+// so subexpressions shared by constraints are not duplicated.
+func EncodeJSON(constraints []Expr, arrays []*Array) ([]byte, error) {
+	enc := &jsonEncoder{
+		doc:     jsonDocument{Version: jsonVersion, Constraints: []int{}},
+		exprs:   make(map[Expr]int),
+		arrays:  make(map[*Array]int),
+		updates: make(map[*ArrayUpdate]int),
+	}
+
+	for _, expr := range constraints {
+		i, err := enc.expr(expr)
+		if err != nil {
+			return nil, err
+		}
+		enc.doc.Constraints = append(enc.doc.Constraints, i)
+	}
+	for _, array := range arrays {
+		i, err := enc.array(array)
+		if err != nil {
+			return nil, err
+		}
+		enc.doc.Symbolic = append(enc.doc.Symbolic, i)
+	}
+	return json.Marshal(enc.doc)
+}
+
+// DecodeJSON returns the constraints & arrays encoded by EncodeJSON(). An
+// array referenced multiple times is decoded as a single instance.
+func DecodeJSON(data []byte) (constraints []Expr, arrays []*Array, err error) {
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("glee.DecodeJSON: %s", err)
+	} else if doc.Version != jsonVersion {
+		return nil, nil, fmt.Errorf("glee.DecodeJSON: unsupported version: %d", doc.Version)
+	}
+
+	dec := &jsonDecoder{
+		doc:     &doc,
+		exprs:   make([]Expr, len(doc.Exprs)),
+		arrays:  make([]*Array, len(doc.Arrays)),
+		updates: make([]*ArrayUpdate, len(doc.Updates)),
+		pending: make(map[string]struct{}),
+	}
+
+	for _, i := range doc.Constraints {
+		expr, err := dec.expr(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		constraints = append(constraints, expr)
+	}
+	for _, i := range doc.Symbolic {
+		array, err := dec.array(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		arrays = append(arrays, array)
+	}
+	return constraints, arrays, nil
+}
+
+// jsonDocument is the top-level JSON representation. Constraints & Symbolic
+// are indices into the expression & array tables, respectively.
+type jsonDocument struct {
+	Version     int          `json:"version"`
+	Constraints []int        `json:"constraints"`
+	Symbolic    []int        `json:"symbolic,omitempty"`
+	Exprs       []jsonExpr   `json:"exprs,omitempty"`
+	Arrays      []jsonArray  `json:"arrays,omitempty"`
+	Updates     []jsonUpdate `json:"updates,omitempty"`
+}
+
+// jsonExpr represents a single expression. Fields are used based on the kind.
+type jsonExpr struct {
+	Kind   string `json:"kind"`
+	Op     string `json:"op,omitempty"`
+	Args   []int  `json:"args,omitempty"`  // indices of child expressions
+	Array  *int   `json:"array,omitempty"` // index of selected array
+	Value  string `json:"value,omitempty"` // decimal value of a constant
+	Width  uint   `json:"width,omitempty"`
+	Offset uint   `json:"offset,omitempty"`
+	Signed bool   `json:"signed,omitempty"`
+}
+
+// Expression kinds.
+const (
+	jsonKindConstant     = "const"
+	jsonKindNotOptimized = "no-opt"
+	jsonKindSelect       = "select"
+	jsonKindConcat       = "concat"
+	jsonKindExtract      = "extract"
+	jsonKindNot          = "not"
+	jsonKindCast         = "cast"
+	jsonKindBinary       = "binary"
+	jsonKindFPBinary     = "fp-binary"
+	jsonKindFPCast       = "fp-cast"
+	jsonKindIte          = "ite"
+)
+
+// jsonArray represents an array. Updates, Left & Right are table indices.
+type jsonArray struct {
+	ID      uint64 `json:"id"`
+	Size    uint   `json:"size"`
+	Updates *int   `json:"updates,omitempty"`
+	Left    *int   `json:"left,omitempty"`
+	Right   *int   `json:"right,omitempty"`
+}
+
+// jsonUpdate represents an array update. Index & Value are expression
+// indices & Next is an update index.
+type jsonUpdate struct {
+	Index int  `json:"index"`
+	Value int  `json:"value"`
+	Next  *int `json:"next,omitempty"`
+}
+
+// jsonEncoder builds a jsonDocument. Values are added to their table once
+// & referenced by index afterward.
+type jsonEncoder struct {
+	doc     jsonDocument
+	exprs   map[Expr]int
+	arrays  map[*Array]int
+	updates map[*ArrayUpdate]int
+}
+
+func (enc *jsonEncoder) expr(expr Expr) (int, error) {
+	if i, ok := enc.exprs[expr]; ok {
+		return i, nil
+	}
+
+	var v jsonExpr
+	var err error
+	switch expr := expr.(type) {
+	case *ConstantExpr:
+		v = jsonExpr{Kind: jsonKindConstant, Value: expr.BigInt().String(), Width: expr.Width}
+	case *NotOptimizedExpr:
+		v = jsonExpr{Kind: jsonKindNotOptimized}
+		v.Args, err = enc.args(expr.Src)
+	case *SelectExpr:
+		v = jsonExpr{Kind: jsonKindSelect}
+		var array int
+		if array, err = enc.array(expr.Array); err == nil {
+			v.Array = &array
+			v.Args, err = enc.args(expr.Index)
+		}
+	case *ConcatExpr:
+		v = jsonExpr{Kind: jsonKindConcat}
+		v.Args, err = enc.args(expr.MSB, expr.LSB)
+	case *ExtractExpr:
+		v = jsonExpr{Kind: jsonKindExtract, Offset: expr.Offset, Width: expr.Width}
+		v.Args, err = enc.args(expr.Expr)
+	case *NotExpr:
+		v = jsonExpr{Kind: jsonKindNot}
+		v.Args, err = enc.args(expr.Expr)
+	case *CastExpr:
+		v = jsonExpr{Kind: jsonKindCast, Width: expr.Width, Signed: expr.Signed}
+		v.Args, err = enc.args(expr.Src)
+	case *BinaryExpr:
+		v = jsonExpr{Kind: jsonKindBinary, Op: expr.Op.String()}
+		v.Args, err = enc.args(expr.LHS, expr.RHS)
+	case *FPBinaryExpr:
+		v = jsonExpr{Kind: jsonKindFPBinary, Op: expr.Op.String()}
+		v.Args, err = enc.args(expr.LHS, expr.RHS)
+	case *FPCastExpr:
+		v = jsonExpr{Kind: jsonKindFPCast, Op: expr.Op.String(), Width: expr.Width}
+		v.Args, err = enc.args(expr.Src)
+	case *IteExpr:
+		v = jsonExpr{Kind: jsonKindIte}
+		v.Args, err = enc.args(expr.Cond, expr.Then, expr.Else)
+	default:
+		return 0, fmt.Errorf("glee.EncodeJSON: invalid expression type: %T", expr)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	i := len(enc.doc.Exprs)
+	enc.doc.Exprs = append(enc.doc.Exprs, v)
+	enc.exprs[expr] = i
+	return i, nil
+}
+
+// args returns the expression indices of each expression.
+func (enc *jsonEncoder) args(exprs ...Expr) ([]int, error) {
+	a := make([]int, len(exprs))
+	for i, expr := range exprs {
+		var err error
+		if a[i], err = enc.expr(expr); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (enc *jsonEncoder) array(array *Array) (int, error) {
+	if i, ok := enc.arrays[array]; ok {
+		return i, nil
+	}
+
+	v := jsonArray{ID: array.ID, Size: array.Size}
+	if array.Updates != nil {
+		i, err := enc.update(array.Updates)
+		if err != nil {
+			return 0, err
+		}
+		v.Updates = &i
+	}
+	if array.Left != nil {
+		left, err := enc.array(array.Left)
+		if err != nil {
+			return 0, err
+		}
+		right, err := enc.array(array.Right)
+		if err != nil {
+			return 0, err
+		}
+		v.Left, v.Right = &left, &right
+	}
+
+	i := len(enc.doc.Arrays)
+	enc.doc.Arrays = append(enc.doc.Arrays, v)
+	enc.arrays[array] = i
+	return i, nil
+}
+
+func (enc *jsonEncoder) update(upd *ArrayUpdate) (int, error) {
+	if i, ok := enc.updates[upd]; ok {
+		return i, nil
+	}
+
+	var v jsonUpdate
+	var err error
+	if v.Index, err = enc.expr(upd.Index); err != nil {
+		return 0, err
+	} else if v.Value, err = enc.expr(upd.Value); err != nil {
+		return 0, err
+	}
+	if upd.Next != nil {
+		next, err := enc.update(upd.Next)
+		if err != nil {
+			return 0, err
+		}
+		v.Next = &next
+	}
+
+	i := len(enc.doc.Updates)
+	enc.doc.Updates = append(enc.doc.Updates, v)
+	enc.updates[upd] = i
+	return i, nil
+}
+
+// jsonDecoder decodes values from a jsonDocument. Each table entry is decoded
+// once, on first reference, so shared values remain shared.
+type jsonDecoder struct {
+	doc     *jsonDocument
+	exprs   []Expr
+	arrays  []*Array
+	updates []*ArrayUpdate
+
+	// Entries currently being decoded. Used to reject cyclic references.
+	pending map[string]struct{}
+}
+
+// enter marks a table entry as being decoded. Returns an error if the entry
+// is out of range or is already being decoded.
+func (dec *jsonDecoder) enter(table string, i, n int) error {
+	key := fmt.Sprintf("%s/%d", table, i)
+	if i < 0 || i >= n {
+		return fmt.Errorf("glee.DecodeJSON: %s index out of range: %d", table, i)
+	} else if _, ok := dec.pending[key]; ok {
+		return fmt.Errorf("glee.DecodeJSON: cyclic %s reference: %d", table, i)
+	}
+	dec.pending[key] = struct{}{}
+	return nil
+}
+
+// leave unmarks a table entry marked by enter().
+func (dec *jsonDecoder) leave(table string, i int) {
+	delete(dec.pending, fmt.Sprintf("%s/%d", table, i))
+}
+
+func (dec *jsonDecoder) expr(i int) (Expr, error) {
+	if i >= 0 && i < len(dec.exprs) && dec.exprs[i] != nil {
+		return dec.exprs[i], nil
+	} else if err := dec.enter("expr", i, len(dec.exprs)); err != nil {
+		return nil, err
+	}
+	defer dec.leave("expr", i)
+
+	v := dec.doc.Exprs[i]
+	args, err := dec.args(v.Kind, v.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var expr Expr
+	switch v.Kind {
+	case jsonKindConstant:
+		value, ok := new(big.Int).SetString(v.Value, 10)
+		if !ok || value.Sign() < 0 || value.BitLen() > int(v.Width) || v.Width == 0 {
+			return nil, fmt.Errorf("glee.DecodeJSON: invalid constant: %q (width %d)", v.Value, v.Width)
+		}
+		expr = NewBigConstantExpr(value, v.Width)
+	case jsonKindNotOptimized:
+		expr = &NotOptimizedExpr{Src: args[0]}
+	case jsonKindSelect:
+		if v.Array == nil {
+			return nil, fmt.Errorf("glee.DecodeJSON: select array required")
+		}
+		array, err := dec.array(*v.Array)
+		if err != nil {
+			return nil, err
+		}
+		expr = &SelectExpr{Array: array, Index: args[0]}
+	case jsonKindConcat:
+		expr = &ConcatExpr{MSB: args[0], LSB: args[1]}
+	case jsonKindExtract:
+		expr = &ExtractExpr{Expr: args[0], Offset: v.Offset, Width: v.Width}
+	case jsonKindNot:
+		expr = &NotExpr{Expr: args[0]}
+	case jsonKindCast:
+		expr = &CastExpr{Src: args[0], Width: v.Width, Signed: v.Signed}
+	case jsonKindBinary:
+		op, ok := parseBinaryOp(v.Op)
+		if !ok {
+			return nil, fmt.Errorf("glee.DecodeJSON: invalid binary op: %q", v.Op)
+		}
+		expr = &BinaryExpr{Op: op, LHS: args[0], RHS: args[1]}
+	case jsonKindFPBinary:
+		op, ok := parseFPBinaryOp(v.Op)
+		if !ok {
+			return nil, fmt.Errorf("glee.DecodeJSON: invalid fp binary op: %q", v.Op)
+		}
+		expr = &FPBinaryExpr{Op: op, LHS: args[0], RHS: args[1]}
+	case jsonKindFPCast:
+		op, ok := parseFPCastOp(v.Op)
+		if !ok {
+			return nil, fmt.Errorf("glee.DecodeJSON: invalid fp cast op: %q", v.Op)
+		}
+		expr = &FPCastExpr{Op: op, Src: args[0], Width: v.Width}
+	case jsonKindIte:
+		expr = &IteExpr{Cond: args[0], Then: args[1], Else: args[2]}
+	}
+
+	dec.exprs[i] = expr
+	return expr, nil
+}
+
+// args decodes the child expressions of an expression of the given kind.
+// Returns an error if the number of children does not match the kind.
+func (dec *jsonDecoder) args(kind string, indices []int) ([]Expr, error) {
+	var n int
+	switch kind {
+	case jsonKindConstant:
+		n = 0
+	case jsonKindNotOptimized, jsonKindSelect, jsonKindExtract, jsonKindNot, jsonKindCast, jsonKindFPCast:
+		n = 1
+	case jsonKindConcat, jsonKindBinary, jsonKindFPBinary:
+		n = 2
+	case jsonKindIte:
+		n = 3
+	default:
+		return nil, fmt.Errorf("glee.DecodeJSON: invalid expression kind: %q", kind)
+	}
+	if len(indices) != n {
+		return nil, fmt.Errorf("glee.DecodeJSON: %s expression requires %d arguments, got %d", kind, n, len(indices))
+	}
+
+	args := make([]Expr, n)
+	for i, index := range indices {
+		var err error
+		if args[i], err = dec.expr(index); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func (dec *jsonDecoder) array(i int) (*Array, error) {
+	if i >= 0 && i < len(dec.arrays) && dec.arrays[i] != nil {
+		return dec.arrays[i], nil
+	} else if err := dec.enter("array", i, len(dec.arrays)); err != nil {
+		return nil, err
+	}
+	defer dec.leave("array", i)
+
+	v := dec.doc.Arrays[i]
+	array := NewArray(v.ID, v.Size)
+	if v.Updates != nil {
+		upd, err := dec.update(*v.Updates)
+		if err != nil {
+			return nil, err
+		}
+		array.Updates = upd
+	}
+	if (v.Left == nil) != (v.Right == nil) {
+		return nil, fmt.Errorf("glee.DecodeJSON: array requires both halves: %d", i)
+	} else if v.Left != nil {
+		var err error
+		if array.Left, err = dec.array(*v.Left); err != nil {
+			return nil, err
+		} else if array.Right, err = dec.array(*v.Right); err != nil {
+			return nil, err
+		}
+	}
+
+	dec.arrays[i] = array
+	return array, nil
+}
+
+func (dec *jsonDecoder) update(i int) (*ArrayUpdate, error) {
+	if i >= 0 && i < len(dec.updates) && dec.updates[i] != nil {
+		return dec.updates[i], nil
+	} else if err := dec.enter("update", i, len(dec.updates)); err != nil {
+		return nil, err
+	}
+	defer dec.leave("update", i)
+
+	v := dec.doc.Updates[i]
+	index, err := dec.expr(v.Index)
+	if err != nil {
+		return nil, err
+	}
+	value, err := dec.expr(v.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *ArrayUpdate
+	if v.Next != nil {
+		if next, err = dec.update(*v.Next); err != nil {
+			return nil, err
+		}
+	}
+
+	upd := &ArrayUpdate{Index: index, Value: value, Next: next}
+	dec.updates[i] = upd
+	return upd, nil
+}
+
+// parseBinaryOp returns the binary operation with the given name.
+func parseBinaryOp(s string) (BinaryOp, bool) {
+	for op, name := range binaryOps {
+		if name != "" && name == s {
+			return BinaryOp(op), true
+		}
+	}
+	return 0, false
+}
+
+// parseFPBinaryOp returns the floating-point operation with the given name.
+func parseFPBinaryOp(s string) (FPBinaryOp, bool) {
+	for op := FADD; op <= FLE; op++ {
+		if op.String() == s {
+			return op, true
+		}
+	}
+	return 0, false
+}
+
+// parseFPCastOp returns the floating-point conversion with the given name.
+func parseFPCastOp(s string) (FPCastOp, bool) {
+	for op := FPEXT; op <= FPTOUI; op++ {
+		if op.String() == s {
+			return op, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package glee_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		a, b := glee.NewArray(1, 1), glee.NewArray(2, 2)
+		i := glee.NewCastExpr(a.Select(glee.NewConstantExpr64(0), 8, true), 64, false)
+		b = b.Store(i, glee.NewConstantExpr8(5), true)
+		x := b.Select(glee.NewConstantExpr64(0), 16, true)
+
+		constraints := []glee.Expr{
+			glee.NewBinaryExpr(glee.EQ, b.Select(glee.NewConstantExpr64(1), 8, true), glee.NewConstantExpr8(3)),
+			glee.NewBinaryExpr(glee.SLT, glee.NewCastExpr(x, 128, true), glee.NewConstantExpr(0, 128).Not().LShr(glee.NewConstantExpr(1, 128))),
+			glee.NewNotOptimizedExpr(glee.NewNotExpr(glee.NewBinaryExpr(glee.ULT, glee.NewExtractExpr(x, 8, 8), glee.NewConstantExpr8(10)))),
+			glee.NewFPBinaryExpr(glee.FLT, glee.NewFPCastExpr(glee.SITOFP, x, 64), glee.NewFPConstantExpr(1.5, 64)),
+			glee.NewBinaryExpr(glee.EQ, glee.NewIteExpr(glee.NewIsZeroExpr(x), x, glee.NewConstantExpr(1, 16)), glee.NewConstantExpr(1, 16)),
+		}
+
+		buf, err := glee.EncodeJSON(constraints, []*glee.Array{a, b})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		other, arrays, err := glee.DecodeJSON(buf)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(other) != len(constraints) {
+			t.Fatalf("unexpected constraint count: %d", len(other))
+		}
+		for i := range constraints {
+			if glee.CompareExpr(other[i], constraints[i]) != 0 {
+				t.Fatalf("constraint %d mismatch: %s != %s", i, other[i], constraints[i])
+			}
+		}
+
+		if len(arrays) != 2 {
+			t.Fatalf("unexpected array count: %d", len(arrays))
+		} else if glee.CompareArray(arrays[0], a) != 0 || glee.CompareArray(arrays[1], b) != 0 {
+			t.Fatal("array mismatch")
+		} else if sel := other[0].(*glee.BinaryExpr).RHS.(*glee.SelectExpr); sel.Array != arrays[1] {
+			t.Fatal("expected shared array")
+		}
+
+		// Ensure the encoding is stable.
+		if other, err := glee.EncodeJSON(other, arrays); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(buf, other) {
+			t.Fatalf("encoding mismatch:\n%s\n%s", buf, other)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		buf, err := glee.EncodeJSON(nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if constraints, arrays, err := glee.DecodeJSON(buf); err != nil {
+			t.Fatal(err)
+		} else if len(constraints) != 0 || len(arrays) != 0 {
+			t.Fatalf("unexpected values: %v %v", constraints, arrays)
+		}
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"Version", `{"version":2,"constraints":[]}`, "unsupported version: 2"},
+		{"Range", `{"version":1,"constraints":[1],"exprs":[{"kind":"const","value":"1","width":1}]}`, "expr index out of range: 1"},
+		{"Cycle", `{"version":1,"constraints":[0],"exprs":[{"kind":"not","args":[0]}]}`, "cyclic expr reference: 0"},
+		{"Kind", `{"version":1,"constraints":[0],"exprs":[{"kind":"foo"}]}`, `invalid expression kind: "foo"`},
+		{"Args", `{"version":1,"constraints":[0],"exprs":[{"kind":"concat","args":[0]}]}`, "concat expression requires 2 arguments, got 1"},
+		{"Op", `{"version":1,"constraints":[2],"exprs":[{"kind":"const","value":"1","width":8},{"kind":"const","value":"2","width":8},{"kind":"binary","op":"foo","args":[0,1]}]}`, `invalid binary op: "foo"`},
+		{"Constant", `{"version":1,"constraints":[0],"exprs":[{"kind":"const","value":"256","width":8}]}`, `invalid constant: "256" (width 8)`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := glee.DecodeJSON([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
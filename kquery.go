@@ -0,0 +1,240 @@
+package glee
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Format represents a textual format for a set of constraints.
+type Format int
+
+// Constraint formats.
+const (
+	FormatSMTLIB2 = Format(iota)
+	FormatKQuery
+	FormatJSON
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatSMTLIB2:
+		return "smtlib2"
+	case FormatKQuery:
+		return "kquery"
+	case FormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("Format<%d>", int(f))
+	}
+}
+
+// EncodeKQuery returns a query in KLEE's .kquery format which declares each
+// array and checks the validity of the constraints against false. Arrays are
+// listed after the query so KLEE's tools report a value for each of them.
+func EncodeKQuery(constraints []Expr, arrays []*Array) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, array := range arrays {
+		fmt.Fprintf(&buf, "array %s[%d] : w64 -> w8 = symbolic\n", kqueryArrayName(array), array.Size)
+	}
+
+	terms := make([]string, len(constraints))
+	for i, constraint := range constraints {
+		term, err := kqueryTerm(constraint)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = term
+	}
+
+	names := make([]string, len(arrays))
+	for i, array := range arrays {
+		names[i] = kqueryArrayName(array)
+	}
+
+	fmt.Fprintf(&buf, "(query [%s]\n       false [] [%s])\n", strings.Join(terms, "\n        "), strings.Join(names, " "))
+	return buf.Bytes(), nil
+}
+
+// kqueryTerm returns the KQuery expression for expr.
+func kqueryTerm(expr Expr) (string, error) {
+	switch expr := expr.(type) {
+	case *ConstantExpr:
+		if expr.Width == WidthBool {
+			if expr.IsTrue() {
+				return "true", nil
+			}
+			return "false", nil
+		}
+		if expr.Big != nil {
+			return fmt.Sprintf("(w%d %s)", expr.Width, expr.Big), nil
+		}
+		return fmt.Sprintf("(w%d %d)", expr.Width, expr.Value), nil
+
+	case *NotOptimizedExpr:
+		return kqueryTerm(expr.Src)
+
+	case *SelectExpr:
+		index, err := kqueryTerm(expr.Index)
+		if err != nil {
+			return "", err
+		}
+		array, err := kqueryArray(expr.Array)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(Read w8 %s %s)", index, array), nil
+
+	case *ConcatExpr:
+		msb, err := kqueryTerm(expr.MSB)
+		if err != nil {
+			return "", err
+		}
+		lsb, err := kqueryTerm(expr.LSB)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(Concat w%d %s %s)", ExprWidth(expr), msb, lsb), nil
+
+	case *ExtractExpr:
+		src, err := kqueryTerm(expr.Expr)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(Extract w%d %d %s)", expr.Width, expr.Offset, src), nil
+
+	case *CastExpr:
+		src, err := kqueryTerm(expr.Src)
+		if err != nil {
+			return "", err
+		}
+		if expr.Signed {
+			return fmt.Sprintf("(SExt w%d %s)", expr.Width, src), nil
+		}
+		return fmt.Sprintf("(ZExt w%d %s)", expr.Width, src), nil
+
+	case *NotExpr:
+		src, err := kqueryTerm(expr.Expr)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(Not w%d %s)", ExprWidth(expr.Expr), src), nil
+
+	case *IteExpr:
+		cond, err := kqueryTerm(expr.Cond)
+		if err != nil {
+			return "", err
+		}
+		then, err := kqueryTerm(expr.Then)
+		if err != nil {
+			return "", err
+		}
+		els, err := kqueryTerm(expr.Else)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(Select w%d %s %s %s)", ExprWidth(expr), cond, then, els), nil
+
+	case *BinaryExpr:
+		return kqueryBinaryTerm(expr)
+
+	default:
+		return "", fmt.Errorf("glee.EncodeKQuery: unsupported expression type: %T", expr)
+	}
+}
+
+func kqueryBinaryTerm(expr *BinaryExpr) (string, error) {
+	lhs, err := kqueryTerm(expr.LHS)
+	if err != nil {
+		return "", err
+	}
+	rhs, err := kqueryTerm(expr.RHS)
+	if err != nil {
+		return "", err
+	}
+
+	var op string
+	switch expr.Op {
+	case ADD:
+		op = "Add"
+	case SUB:
+		op = "Sub"
+	case MUL:
+		op = "Mul"
+	case UDIV:
+		op = "UDiv"
+	case SDIV:
+		op = "SDiv"
+	case UREM:
+		op = "URem"
+	case SREM:
+		op = "SRem"
+	case AND:
+		op = "And"
+	case OR:
+		op = "Or"
+	case XOR:
+		op = "Xor"
+	case SHL:
+		op = "Shl"
+	case LSHR:
+		op = "LShr"
+	case ASHR:
+		op = "AShr"
+	case EQ:
+		op = "Eq"
+	case NE:
+		op = "Ne"
+	case ULT:
+		op = "Ult"
+	case ULE:
+		op = "Ule"
+	case UGT:
+		op = "Ugt"
+	case UGE:
+		op = "Uge"
+	case SLT:
+		op = "Slt"
+	case SLE:
+		op = "Sle"
+	case SGT:
+		op = "Sgt"
+	case SGE:
+		op = "Sge"
+	default:
+		return "", fmt.Errorf("glee.EncodeKQuery: unexpected binary operation: %s", expr.Op)
+	}
+
+	// Comparisons always produce a boolean so their width is implied.
+	if expr.Op.IsCompare() {
+		return fmt.Sprintf("(%s %s %s)", op, lhs, rhs), nil
+	}
+	return fmt.Sprintf("(%s w%d %s %s)", op, ExprWidth(expr), lhs, rhs), nil
+}
+
+// kqueryArray returns the array reference with its update list, newest first.
+func kqueryArray(array *Array) (string, error) {
+	if array.Updates == nil {
+		return kqueryArrayName(array), nil
+	}
+
+	var updates []string
+	for upd := array.Updates; upd != nil; upd = upd.Next {
+		index, err := kqueryTerm(upd.Index)
+		if err != nil {
+			return "", err
+		}
+		value, err := kqueryTerm(upd.Value)
+		if err != nil {
+			return "", err
+		}
+		updates = append(updates, index+"="+value)
+	}
+	return fmt.Sprintf("[%s] @ %s", strings.Join(updates, ", "), kqueryArrayName(array)), nil
+}
+
+// kqueryArrayName returns the name of the array. Matches the SMT-LIB2 naming.
+func kqueryArrayName(array *Array) string {
+	return fmt.Sprintf("A%d", array.ID)
+}
@@ -0,0 +1,55 @@
+package glee_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestEncodeKQuery(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		a := glee.NewArray(1, 2)
+		x := a.Select(glee.NewConstantExpr64(0), 16, true)
+		cond := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(100, 16))
+
+		buf, err := glee.EncodeKQuery([]glee.Expr{cond}, []*glee.Array{a})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := string(buf)
+		for _, exp := range []string{
+			"array A1[2] : w64 -> w8 = symbolic\n",
+			"(Ult (Concat w16 (Read w8 (w64 1) A1) (Read w8 (w64 0) A1)) (w16 100))",
+			"false [] [A1])\n",
+		} {
+			if !strings.Contains(s, exp) {
+				t.Fatalf("expected %q in query:\n%s", exp, s)
+			}
+		}
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		a, b := glee.NewArray(1, 1), glee.NewArray(2, 2)
+		i := glee.NewCastExpr(a.Select(glee.NewConstantExpr64(0), 8, true), 64, false)
+		b = b.Store(i, glee.NewConstantExpr8(5), true)
+		cond := glee.NewBinaryExpr(glee.EQ, b.Select(glee.NewConstantExpr64(1), 8, true), glee.NewConstantExpr8(3))
+
+		buf, err := glee.EncodeKQuery([]glee.Expr{cond}, []*glee.Array{a, b})
+		if err != nil {
+			t.Fatal(err)
+		} else if s := string(buf); !strings.Contains(s, "(Read w8 (w64 1) [(ZExt w64 (Read w8 (w64 0) A1))=(w8 5)] @ A2)") {
+			t.Fatalf("unexpected query:\n%s", s)
+		}
+	})
+
+	t.Run("ErrFloat", func(t *testing.T) {
+		a := glee.NewArray(1, 4)
+		x := a.Select(glee.NewConstantExpr64(0), 32, true)
+		cond := glee.NewFPBinaryExpr(glee.FLT, x, x)
+		if _, err := glee.EncodeKQuery([]glee.Expr{cond}, nil); err == nil || !strings.Contains(err.Error(), "unsupported expression type") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
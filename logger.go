@@ -0,0 +1,67 @@
+package glee
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// LogLevel represents the verbosity of a log message.
+type LogLevel int
+
+// Log levels, from most to least verbose.
+const (
+	LogLevelDebug = LogLevel(iota) // individual instructions & memory operations
+	LogLevelInfo                   // forks, goroutine switches & panics
+)
+
+// String returns the string representation of the level.
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("LogLevel<%d>", level)
+	}
+}
+
+// Logger represents a destination for executor log messages.
+type Logger interface {
+	Logf(level LogLevel, format string, args ...interface{})
+}
+
+// NewLogger returns a Logger that writes messages at or above level to w.
+func NewLogger(w io.Writer, level LogLevel) Logger {
+	return &logger{Logger: log.New(w, "", 0), level: level}
+}
+
+// logger is the standard library implementation of Logger.
+type logger struct {
+	*log.Logger
+	level LogLevel
+}
+
+// Logf writes the message if level is at or above the logger's level.
+func (l *logger) Logf(level LogLevel, format string, args ...interface{}) {
+	if level >= l.level {
+		l.Printf(format, args...)
+	}
+}
+
+// nopLogger is a Logger that discards all messages.
+type nopLogger struct{}
+
+// Logf is a no-op.
+func (nopLogger) Logf(level LogLevel, format string, args ...interface{}) {}
+
+// debugf logs a debug message to the executor's logger.
+func (e *Executor) debugf(format string, args ...interface{}) {
+	e.Logger.Logf(LogLevelDebug, format, args...)
+}
+
+// infof logs an info message to the executor's logger.
+func (e *Executor) infof(format string, args ...interface{}) {
+	e.Logger.Logf(LogLevelInfo, format, args...)
+}
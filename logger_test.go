@@ -0,0 +1,30 @@
+package glee_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestLogger_Logf(t *testing.T) {
+	t.Run("Info", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := glee.NewLogger(&buf, glee.LogLevelInfo)
+		l.Logf(glee.LogLevelDebug, "debug %d", 1)
+		l.Logf(glee.LogLevelInfo, "info %d", 2)
+		if got, exp := buf.String(), "info 2\n"; got != exp {
+			t.Fatalf("unexpected output: %q", got)
+		}
+	})
+
+	t.Run("Debug", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := glee.NewLogger(&buf, glee.LogLevelDebug)
+		l.Logf(glee.LogLevelDebug, "debug %d", 1)
+		l.Logf(glee.LogLevelInfo, "info %d", 2)
+		if got, exp := buf.String(), "debug 1\ninfo 2\n"; got != exp {
+			t.Fatalf("unexpected output: %q", got)
+		}
+	})
+}
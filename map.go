@@ -0,0 +1,254 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// mapObject represents the contents of a map allocated on a state. Maps are
+// referenced by address so the object is shared by every binding of the map.
+//
+// Entries are kept in insertion order & are never updated in place. Keys are
+// unique under the state's constraints so at most one entry matches a key.
+type mapObject struct {
+	entries []mapEntry
+}
+
+// mapEntry represents a single key/value pair in a map.
+type mapEntry struct {
+	key   Binding
+	value Binding
+}
+
+// clone returns a copy of m which can be modified.
+func (m *mapObject) clone() *mapObject {
+	other := &mapObject{entries: make([]mapEntry, len(m.entries))}
+	copy(other.entries, m.entries)
+	return other
+}
+
+// keyConds returns the condition that key matches each entry along with the
+// condition that key matches no entry.
+func (m *mapObject) keyConds(key Binding) (conds []Expr, notFound Expr) {
+	notFound = NewBoolConstantExpr(true)
+	for _, entry := range m.entries {
+		cond := bindingEq(key, entry.key)
+		conds = append(conds, cond)
+		notFound = newAndExpr(notFound, NewNotExpr(cond))
+	}
+	return conds, notFound
+}
+
+// entriesTuple returns the entries as a tuple of key/value tuples.
+func (m *mapObject) entriesTuple() Tuple {
+	a := make(Tuple, len(m.entries))
+	for i, entry := range m.entries {
+		a[i] = Tuple{entry.key, entry.value}
+	}
+	return a
+}
+
+// objectAddr returns the address referenced by a map or channel binding.
+// Returns zero for a nil reference.
+func (s *ExecutionState) objectAddr(b Binding) (uint64, error) {
+	switch b := b.(type) {
+	case *ConstantExpr:
+		return b.Value, nil
+	case *Array: // loaded from memory or nil constant
+		if addr, ok := s.selectIntAt(b, 0).(*ConstantExpr); ok {
+			return addr.Value, nil
+		}
+	}
+	return 0, fmt.Errorf("glee: reference must have a constant address")
+}
+
+// findMap returns the map object at addr. Returns nil for a nil map.
+func (s *ExecutionState) findMap(addr uint64) *mapObject {
+	if v, _ := s.maps.Get(addr); v != nil {
+		return v.(*mapObject)
+	}
+	return nil
+}
+
+// bindingEq returns an expression for the equality of two bindings.
+func bindingEq(a, b Binding) Expr {
+	switch a := a.(type) {
+	case Expr:
+		return newEqExpr(a, b.(Expr))
+	case *Array:
+		return a.Equal(b.(*Array))
+	default:
+		panic(fmt.Sprintf("glee: unsupported comparison: %T", a))
+	}
+}
+
+// zeroBinding returns the zero value of typ as a binding. Reference types,
+// such as pointers & maps, are nil addresses.
+func (s *ExecutionState) zeroBinding(typ types.Type) Binding {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() == types.Invalid: // unused range key or value
+			return NewBoolConstantExpr(false)
+		case u.Info()&types.IsString != 0:
+			return NewArray(0, 0)
+		case u.Kind() == types.UnsafePointer:
+			return NewConstantExpr(0, s.executor.PointerWidth())
+		case isExprType(u):
+			return NewConstantExpr(0, s.executor.Sizeof(typ))
+		}
+	case *types.Pointer, *types.Map, *types.Chan, *types.Signature:
+		return NewConstantExpr(0, s.executor.PointerWidth())
+	}
+
+	_, array := s.Alloc(s.executor.Sizeof(typ) / 8)
+	array.zero()
+	return array
+}
+
+// forkEach invokes fn for each condition that can hold. If a condition is
+// known to be true & state has not already forked then fn is called on state
+// directly. Otherwise, a child state is forked for every satisfiable condition
+// & passed to fn.
+func forkEach(state *ExecutionState, conds []Expr, fn func(state *ExecutionState, i int)) error {
+	for i, cond := range conds {
+		if IsConstantTrue(cond) && !state.Forked() {
+			fn(state, i)
+			return nil
+		}
+	}
+
+	for i, cond := range conds {
+		newState, err := forkIfSatisfiable(state, cond)
+		if err != nil {
+			return err
+		} else if newState == nil {
+			continue
+		}
+		fn(newState, i)
+		state.Executor().addState(newState)
+	}
+	return nil
+}
+
+func (e *Executor) executeMakeMapInstr(state *ExecutionState, instr *ssa.MakeMap) error {
+	addr, _ := state.Alloc(e.PointerWidth() / 8)
+	state.maps = state.maps.Set(addr.Value, &mapObject{})
+	state.Frame().bind(instr, addr)
+	return nil
+}
+
+func (e *Executor) executeMapUpdateInstr(state *ExecutionState, instr *ssa.MapUpdate) error {
+	addr, err := state.objectAddr(state.Eval(instr.Map))
+	if err != nil {
+		return err
+	}
+	m := state.findMap(addr)
+	if m == nil {
+		e.runtimePanic(state, "assignment to entry in nil map")
+		return nil
+	}
+
+	key, value := state.Eval(instr.Key), state.Eval(instr.Value)
+	conds, notFound := m.keyConds(key)
+	return forkEach(state, append(conds, notFound), func(state *ExecutionState, i int) {
+		other := m.clone()
+		if i < len(m.entries) {
+			other.entries[i].value = value
+		} else {
+			other.entries = append(other.entries, mapEntry{key: key, value: value})
+		}
+		state.maps = state.maps.Set(addr, other)
+	})
+}
+
+func (e *Executor) executeLookupInstrMap(state *ExecutionState, instr *ssa.Lookup) error {
+	addr, err := state.objectAddr(state.Eval(instr.X))
+	if err != nil {
+		return err
+	}
+
+	// Bind the value, or the value & whether it exists for the comma-ok form.
+	bind := func(state *ExecutionState, value Binding, ok bool) {
+		if instr.CommaOk {
+			state.Frame().bind(instr, Tuple{value, NewBoolConstantExpr(ok)})
+		} else {
+			state.Frame().bind(instr, value)
+		}
+	}
+
+	valueType := instr.X.Type().Underlying().(*types.Map).Elem()
+	m := state.findMap(addr)
+	if m == nil {
+		bind(state, state.zeroBinding(valueType), false)
+		return nil
+	}
+
+	conds, notFound := m.keyConds(state.Eval(instr.Index))
+	return forkEach(state, append(conds, notFound), func(state *ExecutionState, i int) {
+		if i < len(m.entries) {
+			bind(state, m.entries[i].value, true)
+		} else {
+			bind(state, state.zeroBinding(valueType), false)
+		}
+	})
+}
+
+// executeRangeInstrMap binds an iterator over a map. The iterator is a tuple
+// of the map's entries at the start of iteration and the next entry index.
+func (e *Executor) executeRangeInstrMap(state *ExecutionState, instr *ssa.Range) error {
+	addr, err := state.objectAddr(state.Eval(instr.X))
+	if err != nil {
+		return err
+	}
+
+	entries := Tuple{}
+	if m := state.findMap(addr); m != nil {
+		entries = m.entriesTuple()
+	}
+	state.Frame().bind(instr, Tuple{entries, NewConstantExpr64(0)})
+	return nil
+}
+
+// executeNextInstrMap advances a map iterator by a single entry. Entries are
+// returned in insertion order.
+func (e *Executor) executeNextInstrMap(state *ExecutionState, instr *ssa.Next) error {
+	iter := state.Eval(instr.Iter).(Tuple)
+	entries, i := iter[0].(Tuple), iter[1].(*ConstantExpr).Value
+
+	if i >= uint64(len(entries)) {
+		typ := instr.Type().(*types.Tuple)
+		state.Frame().bind(instr, Tuple{NewBoolConstantExpr(false), state.zeroBinding(typ.At(1).Type()), state.zeroBinding(typ.At(2).Type())})
+		return nil
+	}
+
+	entry := entries[i].(Tuple)
+	state.Frame().bind(instr.Iter, Tuple{entries, NewConstantExpr64(i + 1)})
+	state.Frame().bind(instr, Tuple{NewBoolConstantExpr(true), entry[0], entry[1]})
+	return nil
+}
+
+// execDelete represents a function handler for the builtin delete() function.
+func execDelete(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+
+	addr, err := state.objectAddr(args[0])
+	if err != nil {
+		return err
+	}
+	m := state.findMap(addr)
+	if m == nil {
+		return nil
+	}
+
+	conds, notFound := m.keyConds(args[1])
+	return forkEach(state, append(conds, notFound), func(state *ExecutionState, i int) {
+		if i < len(m.entries) {
+			other := m.clone()
+			other.entries = append(other.entries[:i], other.entries[i+1:]...)
+			state.maps = state.maps.Set(addr, other)
+		}
+	})
+}
@@ -0,0 +1,565 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// addrTarget represents an allocation that a symbolic address may point into.
+type addrTarget struct {
+	base *ConstantExpr
+	cond Expr // true if the address is within the allocation
+}
+
+// resolveAddr returns each allocation that addr may point into under the
+// constraints of state. Similar to KLEE, an example address is solved for &
+// its containing allocation is excluded from the next query until no more
+// addresses are possible.
+//
+// If an example address is not within any allocation or red zone then the
+// remaining allocations are checked individually. The returned invalid
+// condition is non-nil if addr may not point into any allocation.
+func (e *Executor) resolveAddr(state *ExecutionState, addr Expr) (targets []addrTarget, invalid Expr, err error) {
+	remaining := Expr(NewBoolConstantExpr(true))
+	for {
+		constraints := append(append([]Expr{}, state.constraints...), remaining)
+		arrays := FindArrays(append(constraints, addr)...)
+
+		satisfiable, values, err := e.solve(constraints, arrays)
+		if err != nil {
+			return nil, nil, err
+		} else if !satisfiable {
+			return targets, nil, nil
+		}
+
+		example, err := NewExprEvaluator(arrays, values).Evaluate(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		base, array := state.findAllocNearAddr(example)
+		if array == nil {
+			break
+		}
+
+		cond := addrInRange(addr, base, array)
+		targets = append(targets, addrTarget{base: base, cond: cond})
+		remaining = newAndExpr(remaining, NewNotExpr(cond))
+	}
+
+	// Fall back to checking every allocation once an invalid address is found.
+	for itr := state.heap.Iterator(); !itr.Done(); {
+		k, v := itr.Next()
+		base := NewConstantExpr(k.(uint64), e.PointerWidth())
+		cond := newAndExpr(remaining, addrInRange(addr, base, v.(*Array)))
+
+		if satisfiable, err := isSatisfiable(state, cond); err != nil {
+			return nil, nil, err
+		} else if satisfiable {
+			targets = append(targets, addrTarget{base: base, cond: cond})
+			remaining = newAndExpr(remaining, NewNotExpr(cond))
+		}
+	}
+	return targets, remaining, nil
+}
+
+// addrInRange returns an expression that is true if addr is within array or
+// its red zone.
+func addrInRange(addr Expr, base *ConstantExpr, array *Array) Expr {
+	end := NewConstantExpr(base.Value+allocExtent(array), base.Width)
+	return newAndExpr(NewNotExpr(newUltExpr(addr, base)), newUltExpr(addr, end))
+}
+
+// forkResolve invokes fn with the base address of each allocation that addr
+// may point into. If addr can only point into a single allocation then fn is
+// invoked on state directly. Otherwise, a child state is forked per target
+// allocation with the constraint that addr is within it.
+//
+// Addresses that do not point into an allocation cause the state, or a forked
+// child state, to panic. Accesses of size bytes which extend past the end of
+// their allocation into its red zone terminate the state, or a forked child
+// state, with a memory error.
+func (e *Executor) forkResolve(state *ExecutionState, addr Expr, size uint, fn func(state *ExecutionState, base *ConstantExpr) error) error {
+	if addr, ok := addr.(*ConstantExpr); ok {
+		base, array := state.findAllocNearAddr(addr)
+		if array == nil {
+			e.runtimePanic(state, "invalid memory address or nil pointer dereference")
+			return nil
+		} else if addr.Value+uint64(size) > base.Value+uint64(array.Size) {
+			return e.memoryError(state, addr, size, base, array)
+		}
+		return fn(state, base)
+	}
+
+	targets, invalid, err := e.resolveAddr(state, addr)
+	if err != nil {
+		return err
+	}
+
+	// Split each target on whether the access fits within the allocation.
+	var overflows []addrTarget
+	for i := 0; i < len(targets); i++ {
+		target := targets[i]
+		fits := accessInRange(addr, target.base, state.findAllocByAddr(target.base), size)
+		overflow := newAndExpr(target.cond, NewNotExpr(fits))
+		if satisfiable, err := isSatisfiable(state, overflow); err != nil {
+			return err
+		} else if !satisfiable {
+			continue
+		}
+		overflows = append(overflows, addrTarget{base: target.base, cond: overflow})
+
+		// Remove the target if every access overflows the allocation.
+		cond := newAndExpr(target.cond, fits)
+		if satisfiable, err := isSatisfiable(state, cond); err != nil {
+			return err
+		} else if !satisfiable {
+			targets = append(targets[:i], targets[i+1:]...)
+			i--
+			continue
+		}
+		targets[i].cond = cond
+	}
+
+	if len(targets) == 1 && invalid == nil && len(overflows) == 0 {
+		return fn(state, targets[0].base)
+	}
+
+	for _, target := range targets {
+		e.infof("[fork] resolve address: base=%d", target.base.Value)
+		newState := state.Fork(target.cond)
+		newState.id = e.nextStateID()
+		if err := fn(newState, target.base); err != nil {
+			return err
+		}
+		e.addState(newState)
+	}
+
+	for _, target := range overflows {
+		e.infof("[fork] resolve address: out of bounds, base=%d", target.base.Value)
+		newState := state.Fork(target.cond)
+		newState.id = e.nextStateID()
+		if err := e.memoryError(newState, addr, size, target.base, newState.findAllocByAddr(target.base)); err != nil {
+			return err
+		}
+		e.addState(newState)
+	}
+
+	if invalid != nil {
+		e.infof("[fork] resolve address: invalid")
+		newState := state.Fork(invalid)
+		newState.id = e.nextStateID()
+		e.runtimePanic(newState, "invalid memory address or nil pointer dereference")
+		e.addState(newState)
+	}
+	return nil
+}
+
+// accessInRange returns an expression that is true if size bytes starting
+// at addr are all within array.
+func accessInRange(addr Expr, base *ConstantExpr, array *Array, size uint) Expr {
+	if size > array.Size {
+		return NewBoolConstantExpr(false)
+	}
+	last := NewConstantExpr(base.Value+uint64(array.Size-size), base.Width)
+	return NewNotExpr(newUltExpr(last, addr))
+}
+
+// memoryError terminates state with a memory error for an access of size
+// bytes at addr within the allocation at base. Inputs which cause the access
+// are solved & reported along with the source position.
+func (e *Executor) memoryError(state *ExecutionState, addr Expr, size uint, base *ConstantExpr, array *Array) error {
+	arrays, values, example, err := e.exampleAddr(state, addr)
+	if err != nil {
+		return err
+	}
+
+	pos := state.Position()
+	state.status = ExecutionStatusMemoryError
+	state.reason = fmt.Sprintf("out of bounds memory access: %s", pos)
+	state.memoryError = &MemoryError{
+		Pos:       pos,
+		Addr:      example.Value,
+		Size:      size,
+		Base:      base.Value,
+		AllocSize: array.Size,
+		Arrays:    arrays,
+		Values:    values,
+	}
+	return nil
+}
+
+// executeConvertInstrUintptrToPointer converts an integer address, such as
+// the result of uintptr arithmetic, back to an unsafe.Pointer. Similar to the
+// checkptr instrumentation of the Go runtime, the address must be nil or point
+// within a known allocation, or to the end of one. Otherwise, the state, or
+// a forked child state, terminates with a memory error.
+func (e *Executor) executeConvertInstrUintptrToPointer(state *ExecutionState, instr *ssa.Convert) error {
+	addr := NewCastExpr(state.MustEvalAsExpr(instr.X), e.PointerWidth(), false)
+
+	if addr, ok := addr.(*ConstantExpr); ok {
+		if addr.Value == 0 {
+			state.Frame().bind(instr, addr)
+			return nil
+		} else if base, array := state.findAllocNearAddr(addr); array != nil && addr.Value <= base.Value+uint64(array.Size) {
+			state.Frame().bind(instr, addr)
+			return nil
+		}
+		return e.pointerError(state, addr)
+	}
+
+	targets, _, err := e.resolveAddr(state, addr)
+	if err != nil {
+		return err
+	}
+
+	// Conditions for a nil address & for each allocation, followed by the
+	// condition that the address is not valid.
+	conds := []Expr{NewIsZeroExpr(addr)}
+	for _, target := range targets {
+		fits := accessInRange(addr, target.base, state.findAllocByAddr(target.base), 0)
+		conds = append(conds, newAndExpr(target.cond, fits))
+	}
+	invalid := Expr(NewBoolConstantExpr(true))
+	for _, cond := range conds {
+		invalid = newAndExpr(invalid, NewNotExpr(cond))
+	}
+	conds = append(conds, invalid)
+
+	var ferr error
+	if err := forkEach(state, conds, func(state *ExecutionState, i int) {
+		if i < len(conds)-1 {
+			state.Frame().bind(instr, addr)
+		} else if err := e.pointerError(state, addr); err != nil && ferr == nil {
+			ferr = err
+		}
+	}); err != nil {
+		return err
+	}
+	return ferr
+}
+
+// exampleAddr solves for a feasible value of addr on the path of state. The
+// arrays of addr are solved along with the path as the address may not be
+// constrained by the path, such as when it is derived from an unchecked input.
+func (e *Executor) exampleAddr(state *ExecutionState, addr Expr) ([]*Array, [][]byte, *ConstantExpr, error) {
+	arrays := FindArrays(append(state.constraints[:len(state.constraints):len(state.constraints)], addr)...)
+	satisfiable, values, err := e.solve(state.constraints, arrays)
+	if err != nil {
+		return nil, nil, nil, err
+	} else if !satisfiable {
+		return nil, nil, nil, fmt.Errorf("glee.Executor: cannot solve address on unsatisfiable path")
+	}
+
+	example, err := NewExprEvaluator(arrays, values).Evaluate(addr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return arrays, values, example, nil
+}
+
+// pointerError terminates state with a memory error for an address converted
+// to a pointer which does not point within any allocation.
+func (e *Executor) pointerError(state *ExecutionState, addr Expr) error {
+	arrays, values, example, err := e.exampleAddr(state, addr)
+	if err != nil {
+		return err
+	}
+
+	pos := state.Position()
+	state.status = ExecutionStatusMemoryError
+	state.reason = fmt.Sprintf("invalid pointer arithmetic: %s", pos)
+	state.memoryError = &MemoryError{
+		Pos:    pos,
+		Addr:   example.Value,
+		Arrays: arrays,
+		Values: values,
+	}
+	if base, array := state.findAllocNearAddr(example); array != nil {
+		state.memoryError.Base, state.memoryError.AllocSize = base.Value, array.Size
+	}
+	return nil
+}
+
+// load returns the value of type typ at addr within the allocation at base.
+// Scalar types (such as ints & pointers) are extracted as expressions.
+// Complex data types such as structs & interfaces are extracted as arrays.
+func (s *ExecutionState) load(base *ConstantExpr, addr Expr, typ types.Type) (Binding, error) {
+	return s.extract(s.findAllocByAddr(base), newSubExpr(addr, base), typ)
+}
+
+// extract returns the value of type typ at the byte offset index within array.
+// Same as load() except the value is read from an aggregate value, such as a
+// struct or array, instead of from an address on the heap.
+func (s *ExecutionState) extract(array *Array, index Expr, typ types.Type) (Binding, error) {
+	e := s.executor
+	width := e.Sizeof(typ)
+
+	// Booleans occupy a byte in memory but are evaluated as a single bit.
+	if isBooleanType(typ) {
+		return array.Select(index, WidthBool, e.IsLittleEndian()), nil
+	} else if isScalarType(typ) {
+		return array.Select(index, width, e.IsLittleEndian()), nil
+	} else if isStringType(typ) {
+		return s.extractString(array, index)
+	}
+
+	_, dst := s.Alloc(width / 8)
+	for i := uint64(0); i < uint64(dst.Size); i++ {
+		dst.storeByte(NewConstantExpr64(i), array.selectByte(newAddExpr(index, NewConstantExpr(i, e.PointerWidth()))))
+	}
+	s.heap = s.heap.Set(dst.ID, dst)
+	return dst, nil
+}
+
+// extractString returns the bytes of the string whose header is at the byte
+// offset index within array. Strings are held in memory as a data pointer &
+// length, the same as Go, but are bound to instructions as their bytes. The
+// header must be constant.
+func (s *ExecutionState) extractString(array *Array, index Expr) (Binding, error) {
+	e := s.executor
+	pointerWidth := e.PointerWidth()
+
+	data, ok := array.Select(index, pointerWidth, e.IsLittleEndian()).(*ConstantExpr)
+	if !ok {
+		return nil, fmt.Errorf("glee: expected constant string data address")
+	}
+	n, ok := array.Select(newAddExpr(index, NewConstantExpr(uint64(pointerWidth/8), pointerWidth)), pointerWidth, e.IsLittleEndian()).(*ConstantExpr)
+	if !ok {
+		return nil, fmt.Errorf("glee: expected constant string len")
+	}
+
+	str := NewArray(0, uint(n.Value))
+	if n.Value == 0 {
+		return str, nil
+	}
+
+	base, src := s.findAllocContainingAddr(data)
+	if src == nil {
+		return nil, fmt.Errorf("glee: string data not found: %d", data.Value)
+	}
+	for i := uint64(0); i < n.Value; i++ {
+		str.storeByte(NewConstantExpr64(i), src.selectByte(NewConstantExpr64(data.Value-base.Value+i)))
+	}
+	return str, nil
+}
+
+// storeAt updates the bytes at addr within the allocation at base with value.
+func (s *ExecutionState) storeAt(base *ConstantExpr, addr Expr, value Expr) {
+	array := s.findAllocByAddr(base)
+	newArray := array.Store(newSubExpr(addr, base), value, s.executor.IsLittleEndian())
+	s.heap = s.heap.Set(base.Value, newArray)
+}
+
+// storeStringAt copies the bytes of str to a new allocation & writes its
+// header to addr within the allocation at base.
+func (s *ExecutionState) storeStringAt(base *ConstantExpr, addr Expr, str *Array) {
+	pointerWidth := s.executor.PointerWidth()
+	s.storeAt(base, addr, s.box(str))
+	s.storeAt(base, newAddExpr(addr, NewConstantExpr(uint64(pointerWidth/8), pointerWidth)), NewConstantExpr(uint64(str.Size), pointerWidth))
+}
+
+// copyAt copies the bytes in the value array to addr within the allocation at base.
+func (s *ExecutionState) copyAt(base *ConstantExpr, addr Expr, value *Array) {
+	newArray := s.findAllocByAddr(base).Clone()
+	for i := uint64(0); i < uint64(value.Size); i++ {
+		index := newAddExpr(newSubExpr(addr, base), NewConstantExpr64(i))
+		newArray.storeByte(index, value.selectByte(NewConstantExpr64(i)))
+	}
+	s.heap = s.heap.Set(base.Value, newArray)
+}
+
+// copyValueAt copies value, a value of type typ, to addr within the
+// allocation at base. Only the bytes of typ which hold data are written so
+// padding in the destination is unchanged. Values smaller than typ are
+// copied up to their own size.
+func (s *ExecutionState) copyValueAt(base *ConstantExpr, addr Expr, value *Array, typ types.Type) {
+	newArray := s.findAllocByAddr(base).Clone()
+	offset := newSubExpr(addr, base)
+	for _, r := range s.executor.dataRanges(typ) {
+		for i := r.offset; i < r.offset+r.size && i < uint64(value.Size); i++ {
+			index := newAddExpr(offset, NewConstantExpr64(i))
+			newArray.storeByte(index, value.selectByte(NewConstantExpr64(i)))
+		}
+	}
+	s.heap = s.heap.Set(base.Value, newArray)
+}
+
+// byteRange represents a span of bytes within a value.
+type byteRange struct {
+	offset uint64
+	size   uint64
+}
+
+// dataRanges returns the byte ranges of typ which hold data based on the
+// layout of the target architecture. Padding between struct fields & after
+// the last field is excluded. Adjacent ranges are joined.
+func (e *Executor) dataRanges(typ types.Type) []byteRange {
+	return e.appendDataRanges(nil, 0, typ)
+}
+
+func (e *Executor) appendDataRanges(a []byteRange, offset uint64, typ types.Type) []byteRange {
+	sizes := e.Sizes()
+
+	switch typ := typ.Underlying().(type) {
+	case *types.Struct:
+		offsets := sizes.Offsetsof(structFields(typ))
+		for i := 0; i < typ.NumFields(); i++ {
+			a = e.appendDataRanges(a, offset+uint64(offsets[i]), typ.Field(i).Type())
+		}
+		return a
+
+	case *types.Array:
+		// Elements without padding are joined into a single range.
+		elemSize := uint64(sizes.Sizeof(typ.Elem()))
+		if elem := e.dataRanges(typ.Elem()); len(elem) == 1 && elem[0].size == elemSize {
+			return appendByteRange(a, offset, uint64(typ.Len())*elemSize)
+		}
+		for i := uint64(0); i < uint64(typ.Len()); i++ {
+			a = e.appendDataRanges(a, offset+(i*elemSize), typ.Elem())
+		}
+		return a
+
+	default:
+		return appendByteRange(a, offset, uint64(sizes.Sizeof(typ)))
+	}
+}
+
+// appendByteRange appends a range to a, joining it with the last range if
+// they are adjacent.
+func appendByteRange(a []byteRange, offset, size uint64) []byteRange {
+	if size == 0 {
+		return a
+	} else if n := len(a); n > 0 && a[n-1].offset+a[n-1].size == offset {
+		a[n-1].size += size
+		return a
+	}
+	return append(a, byteRange{offset: offset, size: size})
+}
+
+// isStringType returns true if the underlying type of typ is a string.
+func isStringType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// isBooleanType returns true if the underlying type of typ is a boolean.
+func isBooleanType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
+
+// isScalarType returns true if values of typ are held as a single
+// expression, such as integers & pointers, rather than as an array of bytes.
+func isScalarType(typ types.Type) bool {
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return isExprType(typ) || typ.Kind() == types.UnsafePointer
+	case *types.Pointer, *types.Map, *types.Chan, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
+
+// sliceBytesCase represents the bytes of a byte slice under a condition.
+type sliceBytesCase struct {
+	cond  Expr   // condition for the slice to hold bytes
+	bytes *Array // nil if the slice is not within its backing array
+}
+
+// sliceBytesCases returns the bytes of the byte slice hdr for each backing
+// array & length the slice may have. The data pointer & length may be
+// symbolic. Bytes are selected from the backing array at the offset of the
+// data pointer so they alias the array instead of copying its bytes. A slice
+// spanning its entire backing array returns that array directly since arrays
+// on the heap are never updated in place.
+//
+// The last case, if any, holds when the slice extends past the end of its
+// backing array or its data pointer is not within any allocation.
+func (e *Executor) sliceBytesCases(state *ExecutionState, hdr *Array) ([]sliceBytesCase, error) {
+	pointerWidth := e.PointerWidth()
+	data, length := state.selectIntAt(hdr, 0), state.selectIntAt(hdr, 1)
+
+	// Empty slices may have a nil data pointer so they are never resolved.
+	empty := NewIsZeroExpr(length)
+	cases := []sliceBytesCase{{cond: empty, bytes: NewArray(0, 0)}}
+	if IsConstantTrue(empty) {
+		return cases, nil
+	}
+
+	var targets []addrTarget
+	if addr, ok := data.(*ConstantExpr); ok {
+		if base, array := state.findAllocContainingAddr(addr); array != nil {
+			targets = []addrTarget{{base: base, cond: NewBoolConstantExpr(true)}}
+		}
+	} else if resolved, _, err := e.resolveAddr(state, data); err != nil {
+		return nil, err
+	} else {
+		targets = resolved
+	}
+
+	for _, target := range targets {
+		src := state.findAllocByAddr(target.base)
+		offset := newSubExpr(data, target.base)
+
+		// A constant length only needs to fit within the array. Otherwise,
+		// each length up to the size of the array is a separate case.
+		lo, hi := uint64(1), uint64(src.Size)
+		if n, ok := length.(*ConstantExpr); ok {
+			lo, hi = n.Value, n.Value
+		}
+		for n := lo; n <= hi && n <= uint64(src.Size); n++ {
+			fits := NewNotExpr(newUltExpr(NewConstantExpr(uint64(src.Size)-n, pointerWidth), offset))
+			cond := newAndExpr(target.cond, newAndExpr(newEqExpr(length, NewConstantExpr(n, pointerWidth)), fits))
+			cases = append(cases, sliceBytesCase{cond: cond, bytes: selectBytes(src, offset, n)})
+		}
+	}
+
+	invalid := Expr(NewBoolConstantExpr(true))
+	for _, c := range cases {
+		invalid = newAndExpr(invalid, NewNotExpr(c.cond))
+	}
+	if !IsConstantFalse(invalid) {
+		cases = append(cases, sliceBytesCase{cond: invalid})
+	}
+	return cases, nil
+}
+
+// selectBytes returns an array of the n bytes within src starting at offset.
+func selectBytes(src *Array, offset Expr, n uint64) *Array {
+	if offset, ok := offset.(*ConstantExpr); ok && offset.Value == 0 && n == uint64(src.Size) {
+		return src
+	}
+
+	dst := NewArray(0, uint(n))
+	index := newZExtExpr(offset, Width64)
+	for i := uint64(0); i < n; i++ {
+		dst.storeByte(NewConstantExpr64(i), src.selectByte(newAddExpr(index, NewConstantExpr64(i))))
+	}
+	return dst
+}
+
+// forkSliceBytes calls fn with the bytes of the byte slice hdr on state, or
+// on a child state forked for each case from sliceBytesCases(). States in
+// which the slice is not within its backing array panic.
+func (e *Executor) forkSliceBytes(state *ExecutionState, hdr *Array, fn func(state *ExecutionState, p *Array)) error {
+	cases, err := e.sliceBytesCases(state, hdr)
+	if err != nil {
+		return err
+	}
+
+	conds := make([]Expr, len(cases))
+	for i := range cases {
+		conds[i] = cases[i].cond
+	}
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		if cases[i].bytes == nil {
+			e.runtimePanic(state, "slice bounds out of range")
+			return
+		}
+		fn(state, cases[i].bytes)
+	})
+}
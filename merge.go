@@ -0,0 +1,96 @@
+package glee
+
+import (
+	"fmt"
+)
+
+// mergeBinding returns a binding that evaluates to a when cond is true and to
+// b otherwise. Expressions are merged with a conditional expression, arrays are
+// merged byte by byte, and tuples are merged element-wise. Both bindings must
+// have the same shape: expressions of equal width, arrays of equal size, or
+// tuples of equal length.
+//
+// Phi instructions only need merged values once states are merged so these are
+// not yet used by the executor.
+func mergeBinding(cond Expr, a, b Binding) (Binding, error) {
+	switch a := a.(type) {
+	case Expr:
+		b, ok := b.(Expr)
+		if !ok {
+			return nil, fmt.Errorf("glee: cannot merge expression with %T", b)
+		} else if ExprWidth(a) != ExprWidth(b) {
+			return nil, fmt.Errorf("glee: cannot merge expressions of different widths: %d != %d", ExprWidth(a), ExprWidth(b))
+		}
+		return NewIteExpr(cond, a, b), nil
+
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok {
+			return nil, fmt.Errorf("glee: cannot merge array with %T", b)
+		}
+		return mergeArray(cond, a, b)
+
+	case Tuple:
+		b, ok := b.(Tuple)
+		if !ok {
+			return nil, fmt.Errorf("glee: cannot merge tuple with %T", b)
+		}
+		return mergeTuple(cond, a, b)
+
+	default:
+		return nil, fmt.Errorf("glee: cannot merge binding: %T", a)
+	}
+}
+
+// mergeArray returns a new array where each byte is merged from a & b.
+func mergeArray(cond Expr, a, b *Array) (*Array, error) {
+	if IsConstantTrue(cond) || a == b {
+		return a, nil
+	} else if IsConstantFalse(cond) {
+		return b, nil
+	} else if a.Size != b.Size {
+		return nil, fmt.Errorf("glee: cannot merge arrays of different sizes: %d != %d", a.Size, b.Size)
+	}
+
+	other := NewArray(0, a.Size)
+	for i := uint64(0); i < uint64(a.Size); i++ {
+		index := NewConstantExpr64(i)
+		other.storeByte(index, NewIteExpr(cond, a.selectByte(index), b.selectByte(index)))
+	}
+	return other, nil
+}
+
+// mergeTuple returns a new tuple where each element is merged from a & b.
+func mergeTuple(cond Expr, a, b Tuple) (Tuple, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("glee: cannot merge tuples of different lengths: %d != %d", len(a), len(b))
+	}
+
+	other := make(Tuple, len(a))
+	for i := range a {
+		v, err := mergeBinding(cond, a[i], b[i])
+		if err != nil {
+			return nil, err
+		}
+		other[i] = v
+	}
+	return other, nil
+}
+
+// mergePhi returns the value of a phi instruction for a state that joins
+// paths from several predecessors. The i-th condition holds when the path
+// entered from the i-th predecessor with the i-th value bound. Conditions are
+// assumed to be mutually exclusive so the final value needs no condition.
+func mergePhi(conds []Expr, values []Binding) (Binding, error) {
+	assert(len(conds) == len(values), "phi merge: condition & value count mismatch")
+	assert(len(values) > 0, "phi merge: no values")
+
+	v := values[len(values)-1]
+	for i := len(values) - 2; i >= 0; i-- {
+		var err error
+		if v, err = mergeBinding(conds[i], values[i], v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
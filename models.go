@@ -0,0 +1,534 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+	"math"
+	"strconv"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// RegisterStringsModels registers function handlers on e that implement the
+// semantics of common strings & bytes functions directly as constraints.
+// Executing the library implementations forks a state for every byte compared
+// so searching a symbolic string quickly exhausts the state limits.
+//
+// Strings & byte slices must have a constant length. strconv.Atoi() is
+// modeled by default. Models must be registered before execution begins.
+func RegisterStringsModels(e *Executor) {
+	e.Register("strings", "Contains", execStringsContains)
+	e.Register("strings", "HasPrefix", execStringsHasPrefix)
+	e.Register("strings", "Index", execStringsIndex)
+
+	e.Register("bytes", "Equal", execBytesEqual)
+}
+
+// execStringsContains represents a function handler for strings.Contains().
+func execStringsContains(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	s, substr := args[0].(*Array), args[1].(*Array)
+
+	// OR-concat a match at every possible offset.
+	var cond Expr = NewBoolConstantExpr(false)
+	for _, match := range stringMatches(s, substr) {
+		if cond = newOrExpr(cond, match); IsConstantTrue(cond) {
+			break
+		}
+	}
+	state.Frame().bind(instr, cond)
+	return nil
+}
+
+// execStringsHasPrefix represents a function handler for strings.HasPrefix().
+func execStringsHasPrefix(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	s, prefix := args[0].(*Array), args[1].(*Array)
+
+	if prefix.Size > s.Size {
+		state.Frame().bind(instr, NewBoolConstantExpr(false))
+		return nil
+	}
+	state.Frame().bind(instr, stringMatchAt(s, prefix, 0))
+	return nil
+}
+
+// execStringsIndex represents a function handler for strings.Index().
+//
+// A state is forked for each offset where substr is first found along with
+// a state where substr is not found.
+func execStringsIndex(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	s, substr := args[0].(*Array), args[1].(*Array)
+	width := state.Executor().Sizeof(types.Typ[types.Int])
+
+	// Each offset is only the first match if no earlier offset matches.
+	matches := stringMatches(s, substr)
+	conds := make([]Expr, len(matches)+1)
+	var notFound Expr = NewBoolConstantExpr(true)
+	for i, match := range matches {
+		conds[i] = newAndExpr(notFound, match)
+		notFound = newAndExpr(notFound, NewNotExpr(match))
+	}
+	conds[len(matches)] = notFound
+
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		index := int64(i)
+		if i == len(matches) {
+			index = -1
+		}
+		state.Frame().bind(instr, NewConstantExpr(uint64(index), width))
+	})
+}
+
+// execBytesEqual represents a function handler for bytes.Equal().
+func execBytesEqual(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+
+	x, xOffset, xLen, err := state.sliceData(args[0].(*Array))
+	if err != nil {
+		return err
+	}
+	y, yOffset, yLen, err := state.sliceData(args[1].(*Array))
+	if err != nil {
+		return err
+	}
+
+	// Slices with a different number of bytes are never equal.
+	if xLen != yLen {
+		state.Frame().bind(instr, NewBoolConstantExpr(false))
+		return nil
+	}
+
+	// Compare every byte.
+	var cond Expr = NewBoolConstantExpr(true)
+	for i := uint64(0); i < xLen; i++ {
+		cond = newAndExpr(cond, newEqExpr(
+			x.selectByte(NewConstantExpr64(xOffset+i)),
+			y.selectByte(NewConstantExpr64(yOffset+i)),
+		))
+		if IsConstantFalse(cond) {
+			break
+		}
+	}
+	state.Frame().bind(instr, cond)
+	return nil
+}
+
+// stringMatches returns a condition for substr matching s at each offset.
+func stringMatches(s, substr *Array) []Expr {
+	if substr.Size > s.Size {
+		return nil
+	}
+
+	a := make([]Expr, s.Size-substr.Size+1)
+	for i := range a {
+		a[i] = stringMatchAt(s, substr, uint(i))
+	}
+	return a
+}
+
+// stringMatchAt returns a condition that the bytes of s starting at offset
+// are equal to substr. substr must fit within s.
+func stringMatchAt(s, substr *Array, offset uint) Expr {
+	var cond Expr = NewBoolConstantExpr(true)
+	for i := uint(0); i < substr.Size; i++ {
+		cond = newAndExpr(cond, newEqExpr(
+			s.selectByte(NewConstantExpr64(uint64(offset+i))),
+			substr.selectByte(NewConstantExpr64(uint64(i))),
+		))
+		if IsConstantFalse(cond) {
+			break
+		}
+	}
+	return cond
+}
+
+// execErrorsNew represents a function handler for errors.New().
+//
+// Error values are modeled as a *errors.errorString interface whose data
+// word points to a heap object holding the message bytes. Each call returns a
+// distinct address so errors only compare equal to themselves, the same as
+// the library implementation.
+func execErrorsNew(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	return bindErrorValue(state, instr, args[0].(*Array), "errors.New()")
+}
+
+// execFmtErrorf represents a function handler for fmt.Errorf().
+//
+// The format is not applied to the arguments so the message of the returned
+// error is the format itself. Wrapped errors are not modeled.
+func execFmtErrorf(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	return bindErrorValue(state, instr, args[0].(*Array), "fmt.Errorf()")
+}
+
+// execPrint represents a function handler for the builtin print() & println()
+// functions. Arguments are evaluated but output is discarded.
+func execPrint(state *ExecutionState, instr *ssa.Call) error {
+	state.ExtractCall(instr)
+	return nil
+}
+
+// execFmtPrint represents a function handler for fmt.Print(), fmt.Printf() &
+// fmt.Println(). Output is discarded & the call returns zero bytes written
+// with a nil error. Functions writing to other writers are executed normally.
+func execFmtPrint(state *ExecutionState, instr *ssa.Call) error {
+	state.ExtractCall(instr)
+	state.Frame().bind(instr, Tuple{
+		NewConstantExpr(0, state.Executor().Sizeof(types.Typ[types.Int])),
+		nilErrorBinding(state),
+	})
+	return nil
+}
+
+// execErrorsError represents a function handler for the Error() method of
+// error values created by errors.New() & fmt.Errorf().
+func execErrorsError(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	addr, ok := args[0].(*ConstantExpr)
+	if !ok {
+		return fmt.Errorf("glee: (*errors.errorString).Error(): expected constant receiver address")
+	}
+	obj := state.findAllocByAddr(addr)
+	if obj == nil {
+		return fmt.Errorf("glee: (*errors.errorString).Error(): error value not found: %d", addr.Value)
+	}
+
+	// Strings are represented by their bytes so copy the message.
+	msg := NewArray(0, obj.Size)
+	for i := uint64(0); i < uint64(obj.Size); i++ {
+		index := NewConstantExpr64(i)
+		msg.storeByte(index, obj.selectByte(index))
+	}
+	state.Frame().bind(instr, msg)
+	return nil
+}
+
+// bindErrorValue allocates an error value with the message msg & binds it to
+// instr. name is the modeled function, used for error reporting.
+func bindErrorValue(state *ExecutionState, instr *ssa.Call, msg *Array, name string) error {
+	iface, err := newErrorStringBinding(state, msg)
+	if err != nil {
+		return fmt.Errorf("glee: %s: %s", name, err)
+	}
+	state.Frame().bind(instr, iface)
+	return nil
+}
+
+// newErrorStringBinding allocates an *errors.errorString value with the
+// message msg & returns it as an error interface value.
+func newErrorStringBinding(state *ExecutionState, msg *Array) (*Array, error) {
+	e := state.Executor()
+	typ, err := e.errorStringType()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, obj := state.Alloc(msg.Size)
+	for i := uint64(0); i < uint64(msg.Size); i++ {
+		index := NewConstantExpr64(i)
+		obj.storeByte(index, msg.selectByte(index))
+	}
+
+	_, iface := state.Alloc((e.PointerWidth() * 2) / 8)
+	iface = state.storeIntAt(iface, 0, NewConstantExpr(uint64(e.typeID(typ)), e.PointerWidth()))
+	iface = state.storeIntAt(iface, 1, addr)
+	state.heap = state.heap.Set(iface.ID, iface)
+	return iface, nil
+}
+
+// errorStringType returns the *errors.errorString type from the program.
+// Method calls on modeled error values are dispatched through this type.
+func (e *Executor) errorStringType() (types.Type, error) {
+	if e.errorType != nil {
+		return e.errorType, nil
+	}
+
+	pkg := e.prog.ImportedPackage("errors")
+	if pkg == nil {
+		return nil, fmt.Errorf("errors package not found in program")
+	}
+	obj := pkg.Pkg.Scope().Lookup("errorString")
+	if obj == nil {
+		return nil, fmt.Errorf("errors.errorString type not found")
+	}
+
+	// Reuse the program's type, if registered, so type IDs are stable.
+	e.errorType = e.typesByID[e.typeID(types.NewPointer(obj.Type()))]
+	return e.errorType, nil
+}
+
+// numErrorType returns the *strconv.NumError type from the program. Parse
+// errors of modeled strconv functions have this dynamic type.
+func (e *Executor) numErrorType() (types.Type, error) {
+	if e.numErrType != nil {
+		return e.numErrType, nil
+	}
+
+	pkg := e.prog.ImportedPackage("strconv")
+	if pkg == nil {
+		return nil, fmt.Errorf("strconv package not found in program")
+	}
+	obj := pkg.Pkg.Scope().Lookup("NumError")
+	if obj == nil {
+		return nil, fmt.Errorf("strconv.NumError type not found")
+	}
+
+	// Reuse the program's type, if registered, so type IDs are stable.
+	e.numErrType = e.typesByID[e.typeID(types.NewPointer(obj.Type()))]
+	return e.numErrType, nil
+}
+
+// execStrconvAtoi represents a function handler for strconv.Atoi().
+//
+// The string must have a constant length. The state is forked for each valid
+// form of the string (unsigned, '-' prefixed, & '+' prefixed) with constraints
+// relating every byte to a decimal digit of the result. Each form is forked
+// again for values out of range of an int, which return the nearest int &
+// strconv.ErrRange. An additional state is forked for an invalid string which
+// returns strconv.ErrSyntax.
+func execStrconvAtoi(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	s := args[0].(*Array)
+
+	width := state.Executor().Sizeof(types.Typ[types.Int])
+	maxInt := uint64(1)<<(width-1) - 1
+
+	var cases []strconvCase
+	if s.Size > 0 {
+		inRange, outOfRange, value := parseDecimal(s, 0, maxInt)
+		cases = append(cases,
+			strconvCase{cond: inRange, value: newZExtExpr(value, width)},
+			strconvCase{cond: outOfRange, value: NewConstantExpr(maxInt, width), err: strconvErrRange},
+		)
+	}
+	if s.Size > 1 {
+		for _, sign := range []byte{'-', '+'} {
+			signCond := newEqExpr(s.selectByte(NewConstantExpr64(0)), NewConstantExpr8(uint64(sign)))
+
+			// Negative values may have one more than the maximum int.
+			max, limit := maxInt, NewConstantExpr(maxInt, width)
+			if sign == '-' {
+				max, limit = maxInt+1, NewConstantExpr(maxInt+1, width)
+			}
+
+			inRange, outOfRange, value := parseDecimal(s, 1, max)
+			if sign == '-' {
+				value = newSubExpr(NewConstantExpr64(0), value)
+			}
+			cases = append(cases,
+				strconvCase{cond: newAndExpr(signCond, inRange), value: newZExtExpr(value, width)},
+				strconvCase{cond: newAndExpr(signCond, outOfRange), value: limit, err: strconvErrRange},
+			)
+		}
+	}
+	return forkStrconvCases(state, instr, "Atoi", s, cases, width)
+}
+
+// execStrconvParseUint represents a function handler for strconv.ParseUint().
+// Only a constant base of 10 is supported and the string must have a constant
+// length. See execStrconvAtoi() for details.
+func execStrconvParseUint(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	s := args[0].(*Array)
+
+	if base, ok := args[1].(*ConstantExpr); !ok || base.Value != 10 {
+		return fmt.Errorf("glee: strconv.ParseUint(): only a constant base of 10 is supported")
+	}
+	bitSize, ok := args[2].(*ConstantExpr)
+	if !ok {
+		return fmt.Errorf("glee: strconv.ParseUint(): bit size must be constant")
+	}
+
+	// Determine the maximum value for the bit size.
+	var max uint64
+	switch bitSize.Value {
+	case 8, 16, 32:
+		max = uint64(1)<<bitSize.Value - 1
+	case 0, 64:
+		max = math.MaxUint64
+	default:
+		return fmt.Errorf("glee: strconv.ParseUint(): invalid bit size: %d", bitSize.Value)
+	}
+
+	var cases []strconvCase
+	if s.Size > 0 {
+		inRange, outOfRange, value := parseDecimal(s, 0, max)
+		cases = append(cases,
+			strconvCase{cond: inRange, value: value},
+			strconvCase{cond: outOfRange, value: NewConstantExpr64(max), err: strconvErrRange},
+		)
+	}
+	return forkStrconvCases(state, instr, "ParseUint", s, cases, Width64)
+}
+
+// Messages of the errors wrapped by a *strconv.NumError.
+const (
+	strconvErrRange  = "value out of range"
+	strconvErrSyntax = "invalid syntax"
+)
+
+// strconvCase represents a form of a parsed string.
+type strconvCase struct {
+	cond  Expr   // condition for the string to match the form
+	value Expr   // parsed value if cond is true
+	err   string // message of the wrapped error, if the form fails to parse
+}
+
+// forkStrconvCases forks a new state for every satisfiable case & binds its
+// value and error to instr. A final state is forked for the invalid case which
+// binds a zero value and a *strconv.NumError wrapping strconv.ErrSyntax.
+func forkStrconvCases(state *ExecutionState, instr *ssa.Call, name string, s *Array, cases []strconvCase, width uint) error {
+	var invalid Expr = NewBoolConstantExpr(true)
+	for _, c := range cases {
+		invalid = newAndExpr(invalid, NewNotExpr(c.cond))
+		if err := forkStrconvCase(state, instr, name, s, c); err != nil {
+			return err
+		}
+	}
+	return forkStrconvCase(state, instr, name, s, strconvCase{
+		cond:  invalid,
+		value: NewConstantExpr(0, width),
+		err:   strconvErrSyntax,
+	})
+}
+
+// forkStrconvCase forks a new state for c, if satisfiable, & binds its value
+// and error to instr.
+func forkStrconvCase(state *ExecutionState, instr *ssa.Call, name string, s *Array, c strconvCase) error {
+	newState, err := forkIfSatisfiable(state, c.cond)
+	if err != nil {
+		return err
+	} else if newState == nil {
+		return nil
+	}
+
+	errValue := nilErrorBinding(newState)
+	if c.err != "" {
+		if errValue, err = newNumErrorBinding(newState, name, s, c.err); err != nil {
+			return fmt.Errorf("glee: strconv.%s(): %s", name, err)
+		}
+	}
+	newState.Frame().bind(instr, Tuple{c.value, errValue})
+	state.Executor().addState(newState)
+	return nil
+}
+
+// parseDecimal returns conditions for the bytes of s starting from offset to
+// be a decimal number of at most max & to be a decimal number greater than
+// max, along with a 64-bit expression of the value if it is at most max.
+//
+// Leading zeros are allowed so only the digits needed to represent max are
+// parsed. These are compared to the digits of max so the value cannot overflow.
+func parseDecimal(s *Array, offset uint, max uint64) (inRange, outOfRange, value Expr) {
+	maxDigits := strconv.FormatUint(max, 10)
+
+	// Every byte must be a digit.
+	var digits Expr = NewBoolConstantExpr(true)
+	for i := offset; i < s.Size; i++ {
+		b := s.selectByte(NewConstantExpr64(uint64(i)))
+		digits = newAndExpr(digits, newAndExpr(
+			newUleExpr(NewConstantExpr8('0'), b),
+			newUleExpr(b, NewConstantExpr8('9')),
+		))
+	}
+
+	// Numbers shorter than max cannot exceed it.
+	if n := s.Size - offset; n < uint(len(maxDigits)) {
+		return digits, NewBoolConstantExpr(false), parseDigits(s, offset, s.Size)
+	}
+
+	// Leading digits beyond the length of max must be zero.
+	start := s.Size - uint(len(maxDigits))
+	var fits Expr = NewBoolConstantExpr(true)
+	for i := offset; i < start; i++ {
+		fits = newAndExpr(fits, newEqExpr(s.selectByte(NewConstantExpr64(uint64(i))), NewConstantExpr8('0')))
+	}
+
+	// Compare the remaining digits to the digits of max from the least
+	// significant so the first differing digit determines the result.
+	var le Expr = NewBoolConstantExpr(true)
+	for i := len(maxDigits) - 1; i >= 0; i-- {
+		b, m := s.selectByte(NewConstantExpr64(uint64(start)+uint64(i))), NewConstantExpr8(uint64(maxDigits[i]))
+		le = newOrExpr(newUltExpr(b, m), newAndExpr(newEqExpr(b, m), le))
+	}
+	fits = newAndExpr(fits, le)
+
+	return newAndExpr(digits, fits), newAndExpr(digits, NewNotExpr(fits)), parseDigits(s, start, s.Size)
+}
+
+// parseDigits returns a 64-bit expression of the decimal value of the bytes in
+// s from start up to end. The bytes are assumed to be digits.
+func parseDigits(s *Array, start, end uint) Expr {
+	var value Expr = NewConstantExpr64(0)
+	for i := start; i < end; i++ {
+		b := s.selectByte(NewConstantExpr64(uint64(i)))
+		digit := newZExtExpr(newSubExpr(b, NewConstantExpr8('0')), Width64)
+		value = newAddExpr(newMulExpr(value, NewConstantExpr64(10)), digit)
+	}
+	return value
+}
+
+// forkIfSatisfiable returns a child of state with cond added as a constraint.
+// Returns nil if the constraints are unsatisfiable with cond.
+func forkIfSatisfiable(state *ExecutionState, cond Expr) (*ExecutionState, error) {
+	if satisfiable, err := isSatisfiable(state, cond); err != nil || !satisfiable {
+		return nil, err
+	}
+
+	newState := state.Fork(cond)
+	newState.id = state.Executor().nextStateID()
+	return newState, nil
+}
+
+// isSatisfiable returns true if the constraints of state are satisfiable with cond.
+func isSatisfiable(state *ExecutionState, cond Expr) (bool, error) {
+	if IsConstantFalse(cond) {
+		return false, nil
+	}
+	satisfiable, _, err := state.Executor().solve(append(state.constraints, cond), nil)
+	return satisfiable, err
+}
+
+// nilErrorBinding allocates a nil error interface value on state.
+func nilErrorBinding(state *ExecutionState) *Array {
+	_, iface := state.Alloc((state.Executor().PointerWidth() * 2) / 8)
+	iface.zero()
+	return iface
+}
+
+// newNumErrorBinding allocates a *strconv.NumError value for a failed parse of
+// s by the strconv function name & returns it as an error interface value. The
+// wrapped error has the message msg, such as the message of strconv.ErrSyntax.
+func newNumErrorBinding(state *ExecutionState, name string, s *Array, msg string) (*Array, error) {
+	e := state.Executor()
+	typ, err := e.numErrorType()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := newErrorStringBinding(state, stringArray(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate the Func, Num & Err fields using the layout of the struct.
+	st := typ.(*types.Pointer).Elem().Underlying().(*types.Struct)
+	offsets := e.Sizes().Offsetsof(structFields(st))
+	fieldAddr := func(addr *ConstantExpr, i int) Expr {
+		return newAddExpr(addr, NewConstantExpr(uint64(offsets[i]), e.PointerWidth()))
+	}
+
+	addr, obj := state.Alloc(e.Sizeof(st) / 8)
+	obj.zero()
+	state.storeStringAt(addr, fieldAddr(addr, 0), stringArray(name))
+	state.storeStringAt(addr, fieldAddr(addr, 1), s)
+	state.copyAt(addr, fieldAddr(addr, 2), wrapped)
+
+	_, iface := state.Alloc((e.PointerWidth() * 2) / 8)
+	iface = state.storeIntAt(iface, 0, NewConstantExpr(uint64(e.typeID(typ)), e.PointerWidth()))
+	iface = state.storeIntAt(iface, 1, addr)
+	state.heap = state.heap.Set(iface.ID, iface)
+	return iface, nil
+}
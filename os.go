@@ -0,0 +1,292 @@
+package glee
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// BindSymbolicArgs sets os.Args to the name of the entry function's package
+// followed by n symbolic arguments which are each strlen bytes long. This
+// allows main() to be used as an entry function.
+//
+// Once bound, environment variables read by os.Getenv() & os.LookupEnv() and
+// flags defined by the flag package are also symbolic, with string values of
+// strlen bytes. Otherwise the environment is empty & flags keep their
+// defaults. Returns the symbolic array backing each argument in order. Must be
+// called before execution begins.
+func (e *Executor) BindSymbolicArgs(n, strlen int) ([]*Array, error) {
+	pkg := e.prog.ImportedPackage("os")
+	if pkg == nil {
+		return nil, fmt.Errorf("glee.Executor: os package not found in program")
+	}
+	global := pkg.Var("Args")
+	if global == nil {
+		return nil, fmt.Errorf("glee.Executor: os.Args not found")
+	}
+
+	state := e.root
+	pointerWidth := e.PointerWidth()
+	headerSize := uint64(pointerWidth/8) * 2
+
+	// Allocate a string header for each argument.
+	data, elems := state.Alloc(uint(uint64(n+1) * headerSize))
+	elems.zero()
+	state.storeStringAt(data, data, stringArray(e.fn.Pkg.Pkg.Name()))
+
+	arrays := make([]*Array, n)
+	for i := range arrays {
+		addr, array := state.Alloc(uint(strlen))
+		state.names = state.names.Set(array.ID, fmt.Sprintf("os.Args[%d]", i+1))
+		arrays[i] = array
+
+		elem := NewConstantExpr(data.Value+(uint64(i+1)*headerSize), pointerWidth)
+		state.storeAt(data, elem, addr)
+		state.storeAt(data, newAddExpr(elem, NewConstantExpr(uint64(pointerWidth/8), pointerWidth)), NewConstantExpr(uint64(strlen), pointerWidth))
+	}
+
+	// Write the slice header to os.Args.
+	addr := state.globalAddr(global)
+	length := NewConstantExpr(uint64(n+1), pointerWidth)
+	state.Store(addr, data)
+	state.Store(NewConstantExpr(addr.Value+uint64(pointerWidth/8), pointerWidth), length)
+	state.Store(NewConstantExpr(addr.Value+uint64(pointerWidth/8)*2, pointerWidth), length)
+
+	e.argsBound, e.argsStrLen = true, strlen
+	return arrays, nil
+}
+
+// osArgs returns the backing array of os.Args along with the byte offset of
+// its first element & its length. Returns a nil array if os.Args is empty.
+func (s *ExecutionState) osArgs() (array *Array, offset, n uint64, err error) {
+	pkg := s.executor.prog.ImportedPackage("os")
+	if pkg == nil {
+		return nil, 0, 0, nil
+	}
+	hdr, err := s.LoadValue(s.globalAddr(pkg.Var("Args")), types.NewSlice(types.Typ[types.String]))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return s.sliceData(hdr.(*Array))
+}
+
+// stringArray returns a constant array holding the bytes of str.
+func stringArray(str string) *Array {
+	array := NewArray(0, uint(len(str)))
+	for i := 0; i < len(str); i++ {
+		array.storeByte(NewConstantExpr64(uint64(i)), NewConstantExpr8(uint64(str[i])))
+	}
+	return array
+}
+
+// envVar represents a symbolic environment variable.
+type envVar struct {
+	set   Expr   // true if the variable is set
+	value *Array // value if set
+}
+
+// envVar returns the environment variable with the given key. Each variable
+// is allocated on first use so later reads return the same value.
+func (s *ExecutionState) envVar(key string) *envVar {
+	if v, _ := s.env.Get(key); v != nil {
+		return v.(*envVar)
+	}
+
+	e := s.executor
+	_, set := s.Alloc(1)
+	_, value := s.Alloc(uint(e.argsStrLen))
+	s.names = s.names.Set(set.ID, "$"+key+".set")
+	s.names = s.names.Set(value.ID, "$"+key)
+
+	v := &envVar{
+		set:   NewNotExpr(NewIsZeroExpr(set.Select(NewConstantExpr(0, 32), 8, e.IsLittleEndian()))),
+		value: value,
+	}
+	s.env = s.env.Set(key, v)
+	return v
+}
+
+// forkEnv invokes fn with the value & existence of the environment variable
+// passed as the first argument of instr. A state is forked for a set & unset
+// variable. The environment is empty unless BindSymbolicArgs() is called.
+func forkEnv(state *ExecutionState, instr *ssa.Call, name string, fn func(state *ExecutionState, value *Array, ok bool)) error {
+	c, ok := instr.Call.Args[0].(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return fmt.Errorf("glee: %s: only constant keys allowed", name)
+	}
+
+	if !state.Executor().argsBound {
+		fn(state, NewArray(0, 0), false)
+		return nil
+	}
+
+	v := state.envVar(constant.StringVal(c.Value))
+	return forkEach(state, []Expr{v.set, NewNotExpr(v.set)}, func(state *ExecutionState, i int) {
+		if i == 0 {
+			fn(state, v.value, true)
+			return
+		}
+		fn(state, NewArray(0, 0), false)
+	})
+}
+
+// execOSGetenv represents a function handler for os.Getenv().
+func execOSGetenv(state *ExecutionState, instr *ssa.Call) error {
+	return forkEnv(state, instr, "os.Getenv()", func(state *ExecutionState, value *Array, ok bool) {
+		state.Frame().bind(instr, value)
+	})
+}
+
+// execOSLookupEnv represents a function handler for os.LookupEnv().
+func execOSLookupEnv(state *ExecutionState, instr *ssa.Call) error {
+	return forkEnv(state, instr, "os.LookupEnv()", func(state *ExecutionState, value *Array, ok bool) {
+		state.Frame().bind(instr, Tuple{value, NewBoolConstantExpr(ok)})
+	})
+}
+
+// modelFlag represents a flag defined with the flag package.
+type modelFlag struct {
+	addr *ConstantExpr // address of the flag value
+	typ  types.Type    // type of the flag value
+}
+
+// execFlagDefine represents a function handler for flag.String(), flag.Int()
+// & flag.Bool(). The default value is written to a new allocation & its
+// address is returned.
+func execFlagDefine(state *ExecutionState, instr *ssa.Call) error {
+	c, ok := instr.Call.Args[0].(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return fmt.Errorf("glee: flag: only constant flag names allowed")
+	}
+	name := constant.StringVal(c.Value)
+	if v, _ := state.flags.Get(name); v != nil {
+		return fmt.Errorf("glee: flag redefined: %s", name)
+	}
+
+	_, args := state.ExtractCall(instr)
+	typ := deref(instr.Type())
+	addr, array := state.Alloc(state.Executor().Sizeof(typ) / 8)
+	array.zero()
+
+	switch value := args[1].(type) {
+	case *Array:
+		state.storeStringAt(addr, addr, value)
+	case Expr:
+		state.Store(addr, value)
+	default:
+		return fmt.Errorf("glee: flag: unexpected default value: %T", value)
+	}
+
+	state.flags = state.flags.Set(name, &modelFlag{addr: addr, typ: typ})
+	state.Frame().bind(instr, addr)
+	return nil
+}
+
+// execFlagParse represents a function handler for flag.Parse().
+//
+// Flags are not parsed from os.Args. Instead, each defined flag is set to a
+// symbolic value & os.Args, excluding the program name, are the positional
+// arguments. Flags keep their default values unless BindSymbolicArgs() is
+// called.
+func execFlagParse(state *ExecutionState, instr *ssa.Call) error {
+	e := state.Executor()
+	if !e.argsBound {
+		return nil
+	}
+
+	for itr := state.flags.Iterator(); !itr.Done(); {
+		k, v := itr.Next()
+		name, f := k.(string), v.(*modelFlag)
+
+		if isStringType(f.typ) {
+			_, value := state.Alloc(uint(e.argsStrLen))
+			state.names = state.names.Set(value.ID, "-"+name)
+			state.storeStringAt(f.addr, f.addr, value)
+			continue
+		}
+
+		width := e.Sizeof(f.typ)
+		_, value := state.Alloc(width / 8)
+		state.names = state.names.Set(value.ID, "-"+name)
+		if basic, ok := f.typ.Underlying().(*types.Basic); ok && basic.Info()&types.IsBoolean != 0 {
+			state.Store(f.addr, NewNotExpr(NewIsZeroExpr(value.Select(NewConstantExpr(0, 32), 8, e.IsLittleEndian()))))
+			continue
+		}
+		state.Store(f.addr, value.Select(NewConstantExpr(0, 32), width, e.IsLittleEndian()))
+	}
+	return nil
+}
+
+// execFlagArgs represents a function handler for flag.Args().
+func execFlagArgs(state *ExecutionState, instr *ssa.Call) error {
+	array, offset, n, err := state.osArgs()
+	if err != nil {
+		return err
+	}
+
+	// Slice os.Args after the program name.
+	e := state.Executor()
+	pointerWidth := e.PointerWidth()
+	data, length := NewConstantExpr(0, pointerWidth), NewConstantExpr(0, pointerWidth)
+	if n > 1 {
+		headerSize := uint64(pointerWidth/8) * 2
+		data = NewConstantExpr(array.ID+offset+headerSize, pointerWidth)
+		length = NewConstantExpr(n-1, pointerWidth)
+	}
+
+	_, hdr := state.Alloc((pointerWidth / 8) * 3)
+	hdr = state.storeIntAt(hdr, 0, data)   // data
+	hdr = state.storeIntAt(hdr, 1, length) // len
+	hdr = state.storeIntAt(hdr, 2, length) // cap
+	state.heap = state.heap.Set(hdr.ID, hdr)
+	state.Frame().bind(instr, hdr)
+	return nil
+}
+
+// execFlagNArg represents a function handler for flag.NArg().
+func execFlagNArg(state *ExecutionState, instr *ssa.Call) error {
+	_, _, n, err := state.osArgs()
+	if err != nil {
+		return err
+	} else if n > 0 {
+		n--
+	}
+	state.Frame().bind(instr, NewConstantExpr(n, state.Executor().Sizeof(types.Typ[types.Int])))
+	return nil
+}
+
+// execFlagArg represents a function handler for flag.Arg(). The index must
+// be constant.
+func execFlagArg(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	i, ok := args[0].(*ConstantExpr)
+	if !ok {
+		return fmt.Errorf("glee: flag.Arg(): only constant indexes allowed")
+	}
+
+	array, offset, n, err := state.osArgs()
+	if err != nil {
+		return err
+	}
+
+	// Out of range indexes return an empty string.
+	index := int64(i.Value)
+	if i.Width == Width32 {
+		index = int64(int32(i.Value))
+	}
+	if index < 0 || uint64(index)+1 >= n {
+		state.Frame().bind(instr, NewArray(0, 0))
+		return nil
+	}
+
+	pointerWidth := state.Executor().PointerWidth()
+	headerSize := uint64(pointerWidth/8) * 2
+	value, err := state.extractString(array, NewConstantExpr(offset+(uint64(index)+1)*headerSize, pointerWidth))
+	if err != nil {
+		return err
+	}
+	state.Frame().bind(instr, value)
+	return nil
+}
@@ -0,0 +1,121 @@
+package glee
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// deferredCall represents a call registered by a defer statement.
+type deferredCall struct {
+	fn       *ssa.Function
+	args     []Binding
+	freeVars []Binding
+}
+
+func (e *Executor) executeDeferInstr(state *ExecutionState, instr *ssa.Defer) error {
+	if _, ok := instr.Call.Value.(*ssa.Builtin); ok {
+		return fmt.Errorf("glee.Executor: deferred builtin calls are not supported")
+	}
+
+	// Arguments are evaluated at the point of the defer statement.
+	fn, args := state.ExtractCall(instr)
+	if e.registeredFn(fn) != nil {
+		return fmt.Errorf("glee.Executor: deferred calls to registered functions are not supported: %s", fn.Name())
+	}
+
+	frame := state.Frame()
+	frame.defers = append(frame.defers, &deferredCall{fn: fn, args: args, freeVars: state.freeVarBindings(instr)})
+	return nil
+}
+
+func (e *Executor) executeRunDefersInstr(state *ExecutionState, instr *ssa.RunDefers) error {
+	state.runDefers(false)
+	return nil
+}
+
+// executePanicInstr begins unwinding the stack with the panic value. If the
+// panic is not recovered then the state is terminated as panicked.
+func (e *Executor) executePanicInstr(state *ExecutionState, instr *ssa.Panic) error {
+	state.panicValue, state.reason = state.Eval(instr.X), panicReason(instr.X)
+	e.infof("[panic] %s", state.reason)
+	e.unwind(state)
+	return nil
+}
+
+// runtimePanic begins unwinding the stack for a run-time error, such as an
+// assignment to a nil map, so deferred calls run & may recover the same as a
+// call to panic(). The runtime package is not modeled so the recovered value
+// is the reason as a string instead of a runtime.Error.
+func (e *Executor) runtimePanic(state *ExecutionState, reason string) {
+	_, iface := state.Alloc((e.PointerWidth() * 2) / 8)
+	iface = state.storeIntAt(iface, 0, NewConstantExpr(uint64(e.typeID(types.Typ[types.String])), e.PointerWidth()))
+	iface = state.storeIntAt(iface, 1, state.box(stringArray(reason)))
+	state.heap = state.heap.Set(iface.ID, iface)
+
+	state.panicValue, state.reason = iface, reason
+	e.infof("[panic] %s", reason)
+	e.unwind(state)
+}
+
+// unwind continues a panic from the current frame. Frames without deferred
+// calls are discarded until a frame with deferred calls is found. Those calls
+// are then executed & unwinding continues once they return.
+//
+// If a deferred call recovers then execution resumes at the recover block of
+// the deferring function. If no deferred calls remain on the stack then the
+// state is marked as panicked with the stack left at the point of the panic.
+func (e *Executor) unwind(state *ExecutionState) {
+	for frame := state.Frame(); frame != nil; frame = state.Frame() {
+		// Resume the function normally if a deferred call recovered.
+		if frame.unwinding && state.panicValue == nil {
+			e.infof("[panic] recovered")
+			frame.unwinding = false
+			frame.jump(frame.fn.Recover)
+			return
+		}
+
+		// Execute deferred calls. Unwinding resumes once they all return.
+		if len(frame.defers) > 0 {
+			frame.unwinding = true
+			state.runDefers(true)
+			return
+		}
+
+		if !state.hasDefers() {
+			break
+		}
+		state.Pop()
+	}
+
+	state.status = ExecutionStatusPanicked
+}
+
+// panicReason returns a description of the panic value. Constant values are
+// returned as-is and non-constant values are described by their SSA value.
+func panicReason(v ssa.Value) string {
+	if mi, ok := v.(*ssa.MakeInterface); ok {
+		v = mi.X
+	}
+	if c, ok := v.(*ssa.Const); ok && c.Value != nil {
+		if c.Value.Kind() == constant.String {
+			return constant.StringVal(c.Value)
+		}
+		return c.Value.String()
+	}
+	return v.String()
+}
+
+// execRecover represents a function handler for the builtin recover() function.
+// Only calls made directly by a deferred call during a panic stop the panic.
+func execRecover(state *ExecutionState, instr *ssa.Call) error {
+	if frame := state.Frame(); frame.recoverable && state.panicValue != nil {
+		state.Frame().bind(instr, state.panicValue)
+		state.panicValue, state.reason = nil, ""
+		return nil
+	}
+	state.Frame().bind(instr, state.zeroBinding(instr.Type()))
+	return nil
+}
@@ -0,0 +1,195 @@
+package glee
+
+import (
+	"context"
+	"fmt"
+)
+
+// Preference represents the values preferred for solved array bytes. By
+// default, a solver may return any value which satisfies the constraints.
+type Preference int
+
+// Value preferences.
+const (
+	PreferNone      = Preference(iota)
+	PreferZero      // zero bytes
+	PreferPrintable // printable ASCII bytes
+	PreferMinimal   // lexicographically smallest bytes
+)
+
+// String returns the name of the preference.
+func (p Preference) String() string {
+	switch p {
+	case PreferNone:
+		return "none"
+	case PreferZero:
+		return "zero"
+	case PreferPrintable:
+		return "printable"
+	case PreferMinimal:
+		return "minimal"
+	default:
+		return fmt.Sprintf("Preference<%d>", int(p))
+	}
+}
+
+// ParsePreference returns the preference with the given name.
+func ParsePreference(s string) (Preference, error) {
+	for _, p := range []Preference{PreferNone, PreferZero, PreferPrintable, PreferMinimal} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return PreferNone, fmt.Errorf("glee: invalid preference: %q", s)
+}
+
+// Ensure type implements interface.
+var _ ContextSolver = (*PreferringSolver)(nil)
+var _ SolverStats = (*PreferringSolver)(nil)
+
+// PreferringSolver wraps a solver and adjusts the values of satisfiable
+// queries to match a preference, where possible, so solved inputs are
+// readable.
+//
+// Preferences are soft constraints. All bytes are first constrained together
+// in a single query. If that fails, bytes are constrained one at a time and
+// each preference is only kept if the query remains satisfiable. Minimal
+// values are found by a binary search on each byte.
+type PreferringSolver struct {
+	Solver     Solver
+	Preference Preference
+}
+
+// NewPreferringSolver returns a new instance of PreferringSolver.
+func NewPreferringSolver(solver Solver, preference Preference) *PreferringSolver {
+	return &PreferringSolver{Solver: solver, Preference: preference}
+}
+
+// QueryStats returns the statistics of the underlying solver, if available.
+// Additional queries issued to satisfy preferences are included.
+func (s *PreferringSolver) QueryStats() QueryStats {
+	if solver, ok := s.Solver.(SolverStats); ok {
+		return solver.QueryStats()
+	}
+	return QueryStats{}
+}
+
+// Solve returns the satisfiability of the constraints & a preferred value for
+// each array.
+func (s *PreferringSolver) Solve(constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	return s.SolveContext(context.Background(), constraints, arrays)
+}
+
+// SolveContext is the same as Solve() but passes ctx to the underlying solver.
+// If a preference query fails for any reason other than ctx being done then
+// the best values found so far are returned.
+func (s *PreferringSolver) SolveContext(ctx context.Context, constraints []Expr, arrays []*Array) (satisfiable bool, values [][]byte, err error) {
+	if satisfiable, values, err = SolveContext(ctx, s.Solver, constraints, arrays); err != nil || !satisfiable {
+		return satisfiable, values, err
+	} else if s.Preference == PreferNone || len(arrays) == 0 {
+		return satisfiable, values, nil
+	}
+
+	if values, err = s.refine(ctx, constraints, arrays, values); err != nil && ctx.Err() != nil {
+		return false, nil, ctx.Err()
+	}
+	return true, values, nil
+}
+
+// refine returns values which satisfy constraints & as many preferences as
+// possible. The values passed in must satisfy the constraints.
+func (s *PreferringSolver) refine(ctx context.Context, constraints []Expr, arrays []*Array, values [][]byte) ([][]byte, error) {
+	// Attempt to satisfy every byte's preference with a single query.
+	if s.Preference != PreferMinimal {
+		preferred := append([]Expr{}, constraints...)
+		for _, array := range arrays {
+			for i := uint(0); i < array.Size; i++ {
+				preferred = append(preferred, s.prefer(preferByte(array, i)))
+			}
+		}
+		if satisfiable, other, err := SolveContext(ctx, s.Solver, preferred, arrays); err != nil {
+			return values, err
+		} else if satisfiable {
+			return other, nil
+		}
+	}
+
+	// Otherwise constrain each byte in turn. Accepted constraints are always
+	// satisfied by the current values so a preference which already holds
+	// can be accepted without a query.
+	accepted := append([]Expr{}, constraints...)
+	for i, array := range arrays {
+		for j := uint(0); j < array.Size; j++ {
+			b := preferByte(array, j)
+
+			if s.Preference == PreferMinimal {
+				var err error
+				if accepted, values, err = s.minimize(ctx, accepted, arrays, values, b, values[i][j]); err != nil {
+					return values, err
+				}
+				continue
+			}
+
+			constraint := s.prefer(b)
+			if s.satisfied(values[i][j]) {
+				accepted = append(accepted, constraint)
+				continue
+			}
+
+			query := append(accepted[:len(accepted):len(accepted)], constraint)
+			if satisfiable, other, err := SolveContext(ctx, s.Solver, query, arrays); err != nil {
+				return values, err
+			} else if satisfiable {
+				accepted, values = query, other
+			}
+		}
+	}
+	return values, nil
+}
+
+// minimize finds the smallest value of b, whose current value is v, which
+// satisfies the accepted constraints. Returns the accepted constraints with
+// an upper bound on b & the updated values.
+func (s *PreferringSolver) minimize(ctx context.Context, accepted []Expr, arrays []*Array, values [][]byte, b Expr, v byte) ([]Expr, [][]byte, error) {
+	lo, hi := 0, int(v) // v is always satisfiable
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		query := append(accepted[:len(accepted):len(accepted)], NewBinaryExpr(ULE, b, NewConstantExpr8(uint64(mid))))
+		if satisfiable, other, err := SolveContext(ctx, s.Solver, query, arrays); err != nil {
+			return accepted, values, err
+		} else if satisfiable {
+			hi, values = mid, other
+		} else {
+			lo = mid + 1
+		}
+	}
+	return append(accepted, NewBinaryExpr(EQ, b, NewConstantExpr8(uint64(hi)))), values, nil
+}
+
+// prefer returns the preferred constraint on the byte b.
+func (s *PreferringSolver) prefer(b Expr) Expr {
+	switch s.Preference {
+	case PreferPrintable:
+		return NewBinaryExpr(AND,
+			NewBinaryExpr(UGE, b, NewConstantExpr8(0x20)),
+			NewBinaryExpr(ULE, b, NewConstantExpr8(0x7E)),
+		)
+	default:
+		return NewBinaryExpr(EQ, b, NewConstantExpr8(0))
+	}
+}
+
+// satisfied returns true if the value v already matches the preference.
+func (s *PreferringSolver) satisfied(v byte) bool {
+	switch s.Preference {
+	case PreferPrintable:
+		return v >= 0x20 && v <= 0x7E
+	default:
+		return v == 0
+	}
+}
+
+// preferByte returns an expression for byte i of the initial contents of array.
+func preferByte(array *Array, i uint) Expr {
+	return NewArray(array.ID, array.Size).Select(NewConstantExpr64(uint64(i)), Width8, false)
+}
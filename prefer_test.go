@@ -0,0 +1,88 @@
+package glee_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestPreferringSolver_Solve(t *testing.T) {
+	a := glee.NewArray(1, 2)
+	x := a.Select(glee.NewConstantExpr(0, 64), 8, false)
+	gt5 := glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(5, 8))
+
+	for _, tt := range []struct {
+		preference glee.Preference
+		value      []byte
+	}{
+		{glee.PreferNone, []byte{0xFF, 0xFF}},
+		{glee.PreferZero, []byte{0xFF, 0x00}},
+		{glee.PreferPrintable, []byte{0x7E, 0x7E}},
+		{glee.PreferMinimal, []byte{0x06, 0x00}},
+	} {
+		t.Run(tt.preference.String(), func(t *testing.T) {
+			s := glee.NewPreferringSolver(&BruteForceSolver{Array: a}, tt.preference)
+			if satisfiable, values, err := s.Solve([]glee.Expr{gt5}, []*glee.Array{a}); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if !bytes.Equal(values[0], tt.value) {
+				t.Fatalf("value=%x, expected %x", values[0], tt.value)
+			}
+		})
+	}
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		s := glee.NewPreferringSolver(&BruteForceSolver{Array: a}, glee.PreferZero)
+		if satisfiable, _, err := s.Solve([]glee.Expr{glee.NewBoolConstantExpr(false)}, []*glee.Array{a}); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		}
+	})
+}
+
+func TestParsePreference(t *testing.T) {
+	if p, err := glee.ParsePreference("printable"); err != nil {
+		t.Fatal(err)
+	} else if p != glee.PreferPrintable {
+		t.Fatalf("unexpected preference: %s", p)
+	}
+	if _, err := glee.ParsePreference("marker"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// BruteForceSolver solves queries over a single two-byte array by evaluating
+// every value from largest to smallest.
+type BruteForceSolver struct {
+	Array *glee.Array
+}
+
+func (s *BruteForceSolver) Solve(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+	for v := 0xFFFF; v >= 0; v-- {
+		value := []byte{byte(v >> 8), byte(v)}
+		evaluator := glee.NewExprEvaluator([]*glee.Array{s.Array}, [][]byte{value})
+
+		ok := true
+		for _, constraint := range constraints {
+			if result, err := evaluator.Evaluate(constraint); err != nil {
+				return false, nil, err
+			} else if !result.IsTrue() {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		values := make([][]byte, len(arrays))
+		for i := range arrays {
+			values[i] = value
+		}
+		return true, values, nil
+	}
+	return false, nil, nil
+}
@@ -0,0 +1,109 @@
+package glee
+
+import (
+	"context"
+	"math"
+)
+
+// GetRange returns the minimum & maximum unsigned values of expr for which
+// the constraints are satisfiable. Each bound is found by a binary search
+// using solver queries. Returns ErrUnsatisfiable if the constraints cannot
+// be satisfied.
+func GetRange(ctx context.Context, solver Solver, constraints []Expr, expr Expr) (min, max uint64, err error) {
+	if expr, ok := expr.(*ConstantExpr); ok {
+		return expr.Value, expr.Value, nil
+	}
+
+	// Find an initial value to split the search for each bound.
+	arrays := FindArrays(expr)
+	satisfiable, values, err := SolveContext(ctx, solver, constraints, arrays)
+	if err != nil {
+		return 0, 0, err
+	} else if !satisfiable {
+		return 0, 0, ErrUnsatisfiable
+	}
+	value, err := NewExprEvaluator(arrays, values).Evaluate(expr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	width := ExprWidth(expr)
+	if min, err = getRangeMin(ctx, solver, constraints, expr, value.Value); err != nil {
+		return 0, 0, err
+	}
+	if max, err = getRangeMax(ctx, solver, constraints, expr, value.Value, maxUnsigned(width)); err != nil {
+		return 0, 0, err
+	}
+	return min, max, nil
+}
+
+// getRangeMin returns the smallest value of expr up to hi, which is known to
+// be satisfiable.
+func getRangeMin(ctx context.Context, solver Solver, constraints []Expr, expr Expr, hi uint64) (uint64, error) {
+	width := ExprWidth(expr)
+	lo := uint64(0)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		query := append(constraints[:len(constraints):len(constraints)], NewBinaryExpr(ULE, expr, NewConstantExpr(mid, width)))
+		if satisfiable, _, err := SolveContext(ctx, solver, query, nil); err != nil {
+			return 0, err
+		} else if satisfiable {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return hi, nil
+}
+
+// getRangeMax returns the largest value of expr up to hi. The value lo is
+// known to be satisfiable.
+func getRangeMax(ctx context.Context, solver Solver, constraints []Expr, expr Expr, lo, hi uint64) (uint64, error) {
+	width := ExprWidth(expr)
+	for lo < hi {
+		mid := hi - (hi-lo)/2
+		query := append(constraints[:len(constraints):len(constraints)], NewBinaryExpr(UGE, expr, NewConstantExpr(mid, width)))
+		if satisfiable, _, err := SolveContext(ctx, solver, query, nil); err != nil {
+			return 0, err
+		} else if satisfiable {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, nil
+}
+
+// maxUnsigned returns the largest unsigned value of the given width.
+func maxUnsigned(width uint) uint64 {
+	if width >= 64 {
+		return math.MaxUint64
+	}
+	return (1 << width) - 1
+}
+
+// GetRange returns the minimum & maximum unsigned values of expr on the
+// state's path.
+func (e *Executor) GetRange(state *ExecutionState, expr Expr) (min, max uint64, err error) {
+	return e.getRange(state.constraints, expr)
+}
+
+// getRange returns the range of expr for the given constraints using the
+// context of the executing state.
+func (e *Executor) getRange(constraints []Expr, expr Expr) (min, max uint64, err error) {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return GetRange(ctx, &executorSolver{e}, constraints, expr)
+}
+
+// executorSolver passes queries through Executor.solve() so they are
+// simplified, traced & counted in the executor's statistics.
+type executorSolver struct {
+	e *Executor
+}
+
+func (s *executorSolver) Solve(constraints []Expr, arrays []*Array) (bool, [][]byte, error) {
+	return s.e.solve(constraints, arrays)
+}
@@ -0,0 +1,48 @@
+package glee_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestGetRange(t *testing.T) {
+	a := glee.NewArray(1, 2)
+	x := a.Select(glee.NewConstantExpr(0, 64), 8, false)
+	gt5 := glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(5, 8))
+	lt100 := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(100, 8))
+
+	t.Run("Symbolic", func(t *testing.T) {
+		min, max, err := glee.GetRange(context.Background(), &BruteForceSolver{Array: a}, []glee.Expr{gt5, lt100}, x)
+		if err != nil {
+			t.Fatal(err)
+		} else if min != 6 || max != 99 {
+			t.Fatalf("range=[%d,%d], expected [6,99]", min, max)
+		}
+	})
+
+	t.Run("Unconstrained", func(t *testing.T) {
+		y := a.Select(glee.NewConstantExpr(0, 64), 16, false)
+		min, max, err := glee.GetRange(context.Background(), &BruteForceSolver{Array: a}, nil, y)
+		if err != nil {
+			t.Fatal(err)
+		} else if min != 0 || max != 0xFFFF {
+			t.Fatalf("range=[%d,%d], expected [0,65535]", min, max)
+		}
+	})
+
+	t.Run("Constant", func(t *testing.T) {
+		if min, max, err := glee.GetRange(context.Background(), &BruteForceSolver{Array: a}, nil, glee.NewConstantExpr(7, 8)); err != nil {
+			t.Fatal(err)
+		} else if min != 7 || max != 7 {
+			t.Fatalf("range=[%d,%d], expected [7,7]", min, max)
+		}
+	})
+
+	t.Run("ErrUnsatisfiable", func(t *testing.T) {
+		if _, _, err := glee.GetRange(context.Background(), &BruteForceSolver{Array: a}, []glee.Expr{glee.NewBoolConstantExpr(false)}, x); err != glee.ErrUnsatisfiable {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package glee
+
+var _ Searcher = (*ReplaySearcher)(nil)
+
+// ReplaySearcher represents a depth-first searcher which only explores the
+// subtree of states below a fork path. States on the way to the subtree are
+// replayed from the root while their siblings are ignored.
+//
+// This allows exploration to be split between executors, such as on separate
+// machines, by handing each a fork path. Executors must be configured
+// identically & exploration must be deterministic so that forks are made in
+// the same order on every run. Time limits, solver time budgets, & merging of
+// states are not deterministic and should not be used with a replay.
+type ReplaySearcher struct {
+	prefix []int
+	states []*ExecutionState
+}
+
+// NewReplaySearcher returns a new instance of ReplaySearcher which explores
+// the subtree of the state with the fork path prefix. An empty prefix
+// explores all states.
+func NewReplaySearcher(prefix []int) *ReplaySearcher {
+	return &ReplaySearcher{prefix: prefix}
+}
+
+// Prefix returns the fork path of the root of the explored subtree.
+func (s *ReplaySearcher) Prefix() []int { return s.prefix }
+
+// Contains returns true if state is the root of the explored subtree or
+// one of its descendants.
+func (s *ReplaySearcher) Contains(state *ExecutionState) bool {
+	return hasForkPathPrefix(state.forkPath, s.prefix)
+}
+
+// SelectState returns the most recently added state.
+func (s *ReplaySearcher) SelectState() *ExecutionState {
+	if len(s.states) == 0 {
+		return nil
+	}
+	state := s.states[len(s.states)-1]
+	s.states = s.states[:len(s.states)-1]
+	return state
+}
+
+// AddState adds state to the searcher if it is within the explored subtree
+// or is an ancestor of its root. Otherwise the state is ignored.
+func (s *ReplaySearcher) AddState(state *ExecutionState) {
+	if !hasForkPathPrefix(state.forkPath, s.prefix) && !hasForkPathPrefix(s.prefix, state.forkPath) {
+		return
+	}
+	s.states = append(s.states, state)
+}
+
+// Offload removes up to n states below the root of the explored subtree so
+// they can be explored elsewhere. The oldest states are removed first as they
+// are closest to the root & tend to have the largest subtrees. Returns the
+// removed states, which are not explored by the executor.
+func (s *ReplaySearcher) Offload(n int) []*ExecutionState {
+	var a []*ExecutionState
+	other := s.states[:0]
+	for _, state := range s.states {
+		if len(a) < n && len(state.forkPath) > len(s.prefix) {
+			a = append(a, state)
+			continue
+		}
+		other = append(other, state)
+	}
+	s.states = other
+	return a
+}
+
+// hasForkPathPrefix returns true if path begins with prefix.
+func hasForkPathPrefix(path, prefix []int) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnyForkPathPrefix returns true if path begins with any of prefixes.
+func hasAnyForkPathPrefix(path []int, prefixes [][]int) bool {
+	for _, prefix := range prefixes {
+		if hasForkPathPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareForkPaths compares two fork paths element by element. A path sorts
+// before any longer path which it prefixes.
+func compareForkPaths(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
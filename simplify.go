@@ -0,0 +1,281 @@
+package glee
+
+// maxSimplifyPasses is the maximum number of substitution passes performed by
+// Simplify(). Each pass may expose new equalities for the next pass.
+const maxSimplifyPasses = 4
+
+// Simplify returns an equivalent set of constraints which is cheaper to solve.
+// Duplicate constraints are removed, constraints implying an expression is
+// equal to a constant are substituted into the other constraints, constant
+// constraints are folded, & bounds subsumed by a tighter bound are dropped.
+//
+// The equalities used for substitution are kept so a solution for the
+// simplified constraints is also a solution for the original constraints.
+// If the constraints are unsatisfiable by folding alone then a single
+// constant false constraint is returned.
+func Simplify(constraints []Expr) []Expr {
+	a := dedupeConstraints(constraints)
+
+	for pass := 0; pass < maxSimplifyPasses; pass++ {
+		subs := make([]substitution, len(a))
+		for i, constraint := range a {
+			subs[i] = constraintSubstitution(constraint)
+		}
+
+		// Rewrite each constraint using the substitutions from all other
+		// constraints. A constraint must not be rewritten by itself.
+		var changed bool
+		other := make([]Expr, 0, len(a))
+		for i, constraint := range a {
+			expr := constraint
+			for j, sub := range subs {
+				if i != j {
+					expr = sub.apply(expr)
+				}
+			}
+
+			if IsConstantFalse(expr) {
+				return []Expr{NewBoolConstantExpr(false)}
+			} else if IsConstantTrue(expr) {
+				changed = true
+				continue
+			} else if expr != constraint {
+				changed = true
+			}
+			other = append(other, expr)
+		}
+		a = dedupeConstraints(other)
+
+		if !changed {
+			break
+		}
+	}
+
+	return dropSubsumedBounds(a)
+}
+
+// dedupeConstraints returns constraints with duplicates & constant true
+// constraints removed. Order is maintained.
+func dedupeConstraints(constraints []Expr) []Expr {
+	a := make([]Expr, 0, len(constraints))
+loop:
+	for _, constraint := range constraints {
+		if IsConstantTrue(constraint) {
+			continue
+		}
+		for _, other := range a {
+			if CompareExpr(constraint, other) == 0 {
+				continue loop
+			}
+		}
+		a = append(a, constraint)
+	}
+	return a
+}
+
+// substitution represents a replacement of an expression with a constant.
+type substitution struct {
+	from Expr
+	to   *ConstantExpr
+}
+
+// constraintSubstitution returns the substitution implied by a constraint.
+// An equality with a constant implies the other side equals the constant and
+// any other boolean constraint implies the expression is true.
+func constraintSubstitution(constraint Expr) substitution {
+	switch expr := constraint.(type) {
+	case *BinaryExpr:
+		if expr.Op == EQ {
+			if lhs, ok := expr.LHS.(*ConstantExpr); ok {
+				return substitution{from: expr.RHS, to: lhs}
+			}
+		}
+	case *NotExpr:
+		return substitution{from: expr.Expr, to: NewBoolConstantExpr(false)}
+	}
+	return substitution{from: constraint, to: NewBoolConstantExpr(true)}
+}
+
+// apply returns expr with every occurrence of the substituted expression
+// replaced by its constant. Parent expressions are rebuilt so constants fold.
+func (sub substitution) apply(expr Expr) Expr {
+	if sub.from == nil {
+		return expr
+	}
+	return replaceExpr(expr, func(expr Expr) Expr {
+		if CompareExpr(expr, sub.from) == 0 {
+			return sub.to
+		}
+		return nil
+	}, make(map[Expr]Expr))
+}
+
+// replaceExpr returns expr with each subexpression for which fn returns a
+// non-nil replacement substituted. Parent expressions are rebuilt so constants
+// fold. Rewritten subexpressions are cached in m.
+func replaceExpr(expr Expr, fn func(Expr) Expr, m map[Expr]Expr) Expr {
+	if other := fn(expr); other != nil {
+		return other
+	} else if other, ok := m[expr]; ok {
+		return other
+	}
+
+	other := expr
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		if lhs, rhs := replaceExpr(e.LHS, fn, m), replaceExpr(e.RHS, fn, m); lhs != e.LHS || rhs != e.RHS {
+			other = NewBinaryExpr(e.Op, lhs, rhs)
+		}
+	case *CastExpr:
+		if src := replaceExpr(e.Src, fn, m); src != e.Src {
+			other = NewCastExpr(src, e.Width, e.Signed)
+		}
+	case *ConcatExpr:
+		if msb, lsb := replaceExpr(e.MSB, fn, m), replaceExpr(e.LSB, fn, m); msb != e.MSB || lsb != e.LSB {
+			other = NewConcatExpr(msb, lsb)
+		}
+	case *ExtractExpr:
+		if src := replaceExpr(e.Expr, fn, m); src != e.Expr {
+			other = NewExtractExpr(src, e.Offset, e.Width)
+		}
+	case *FPBinaryExpr:
+		if lhs, rhs := replaceExpr(e.LHS, fn, m), replaceExpr(e.RHS, fn, m); lhs != e.LHS || rhs != e.RHS {
+			other = NewFPBinaryExpr(e.Op, lhs, rhs)
+		}
+	case *FPCastExpr:
+		if src := replaceExpr(e.Src, fn, m); src != e.Src {
+			other = NewFPCastExpr(e.Op, src, e.Width)
+		}
+	case *IteExpr:
+		cond, then, els := replaceExpr(e.Cond, fn, m), replaceExpr(e.Then, fn, m), replaceExpr(e.Else, fn, m)
+		if cond != e.Cond || then != e.Then || els != e.Else {
+			other = NewIteExpr(cond, then, els)
+		}
+	case *NotExpr:
+		if src := replaceExpr(e.Expr, fn, m); src != e.Expr {
+			other = NewNotExpr(src)
+		}
+	case *NotOptimizedExpr:
+		if src := replaceExpr(e.Src, fn, m); src != e.Src {
+			other = NewNotOptimizedExpr(src)
+		}
+	case *SelectExpr:
+		// Array updates are not rewritten as arrays are shared by states.
+		if index := replaceExpr(e.Index, fn, m); index != e.Index {
+			other = NewSelectExpr(e.Array, index)
+		}
+	}
+	m[expr] = other
+	return other
+}
+
+// bound represents a constraint comparing an expression against a constant.
+type bound struct {
+	expr   Expr
+	value  *ConstantExpr
+	upper  bool // expr is less than value, otherwise greater than
+	strict bool // excludes value
+	signed bool
+}
+
+// constraintBound returns the bound represented by constraint, if any.
+func constraintBound(constraint Expr) (bound, bool) {
+	expr, ok := constraint.(*BinaryExpr)
+	if !ok {
+		return bound{}, false
+	}
+
+	var b bound
+	switch expr.Op {
+	case ULT:
+		b.strict = true
+	case ULE:
+	case SLT:
+		b.strict, b.signed = true, true
+	case SLE:
+		b.signed = true
+	default:
+		return bound{}, false
+	}
+
+	lhs, lok := expr.LHS.(*ConstantExpr)
+	rhs, rok := expr.RHS.(*ConstantExpr)
+	switch {
+	case !lok && rok:
+		b.expr, b.value, b.upper = expr.LHS, rhs, true
+	case lok && !rok:
+		b.expr, b.value, b.upper = expr.RHS, lhs, false
+	default:
+		return bound{}, false
+	}
+	return b, true
+}
+
+// tighter returns true if b excludes at least every value that other excludes.
+// Both bounds must have the same expression, direction, & signedness.
+func (b bound) tighter(other bound) bool {
+	if cmp := b.compareValue(other); cmp != 0 {
+		return (cmp < 0) == b.upper
+	}
+	return b.strict || !other.strict
+}
+
+// compareValue compares the constant values of two bounds.
+func (b bound) compareValue(other bound) int {
+	if b.value.Width > Width64 {
+		if b.signed {
+			return b.value.signedBigInt().Cmp(other.value.signedBigInt())
+		}
+		return b.value.BigInt().Cmp(other.value.BigInt())
+	}
+
+	if b.signed {
+		x, y := int64(b.value.SExt(Width64).Value), int64(other.value.SExt(Width64).Value)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	}
+
+	if b.value.Value < other.value.Value {
+		return -1
+	} else if b.value.Value > other.value.Value {
+		return 1
+	}
+	return 0
+}
+
+// dropSubsumedBounds removes bounds that are implied by a tighter bound on
+// the same expression. For example, x < 10 is dropped if x < 5 exists.
+func dropSubsumedBounds(constraints []Expr) []Expr {
+	bounds := make([]bound, len(constraints))
+	ok := make([]bool, len(constraints))
+	for i, constraint := range constraints {
+		bounds[i], ok[i] = constraintBound(constraint)
+	}
+
+	a := make([]Expr, 0, len(constraints))
+	for i, constraint := range constraints {
+		if ok[i] && isSubsumed(bounds, ok, i) {
+			continue
+		}
+		a = append(a, constraint)
+	}
+	return a
+}
+
+// isSubsumed returns true if another bound is tighter than the bound at i.
+// Ties between equivalent bounds are broken by keeping the earliest.
+func isSubsumed(bounds []bound, ok []bool, i int) bool {
+	b := bounds[i]
+	for j, other := range bounds {
+		if i == j || !ok[j] || other.upper != b.upper || other.signed != b.signed || CompareExpr(other.expr, b.expr) != 0 {
+			continue
+		} else if other.tighter(b) && (!b.tighter(other) || j < i) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package glee_test
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestSimplify(t *testing.T) {
+	a := glee.NewArray(1, 2)
+	x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+	y := a.Select(glee.NewConstantExpr(1, 32), 8, false)
+	c := func(v uint64) glee.Expr { return glee.NewConstantExpr(v, 8) }
+
+	t.Run("Dedupe", func(t *testing.T) {
+		cond := glee.NewBinaryExpr(glee.ULT, x, y)
+		other := glee.NewBinaryExpr(glee.ULT, x, y)
+		if a := glee.Simplify([]glee.Expr{cond, glee.NewBoolConstantExpr(true), other}); len(a) != 1 {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+
+	t.Run("Substitute", func(t *testing.T) {
+		eq := glee.NewBinaryExpr(glee.EQ, x, c(5))
+		lt := glee.NewBinaryExpr(glee.ULT, glee.NewBinaryExpr(glee.ADD, x, y), c(20))
+		a := glee.Simplify([]glee.Expr{eq, lt})
+		if len(a) != 2 {
+			t.Fatalf("unexpected constraints: %v", a)
+		} else if got, exp := a[1].String(), glee.NewBinaryExpr(glee.ULT, glee.NewBinaryExpr(glee.ADD, c(5), y), c(20)).String(); got != exp {
+			t.Fatalf("unexpected constraint: %s, expected %s", got, exp)
+		}
+	})
+
+	t.Run("Implied", func(t *testing.T) {
+		eq := glee.NewBinaryExpr(glee.EQ, x, c(5))
+		lt := glee.NewBinaryExpr(glee.ULT, x, c(10))
+		if a := glee.Simplify([]glee.Expr{eq, lt}); len(a) != 1 || a[0] != eq {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		eq := glee.NewBinaryExpr(glee.EQ, x, c(5))
+		lt := glee.NewBinaryExpr(glee.ULT, x, c(3))
+		if a := glee.Simplify([]glee.Expr{eq, lt}); len(a) != 1 || !glee.IsConstantFalse(a[0]) {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+
+	t.Run("Negation", func(t *testing.T) {
+		cond := glee.NewBinaryExpr(glee.ULT, x, y)
+		if a := glee.Simplify([]glee.Expr{cond, glee.NewNotExpr(cond)}); len(a) != 1 || !glee.IsConstantFalse(a[0]) {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+
+	t.Run("Subsume", func(t *testing.T) {
+		lt10 := glee.NewBinaryExpr(glee.ULT, x, c(10))
+		lt5 := glee.NewBinaryExpr(glee.ULT, x, c(5))
+		le5 := glee.NewBinaryExpr(glee.ULE, x, c(5))
+		gt1 := glee.NewBinaryExpr(glee.UGT, x, c(1))
+		gt2 := glee.NewBinaryExpr(glee.UGT, x, c(2))
+		a := glee.Simplify([]glee.Expr{lt10, gt1, le5, lt5, gt2})
+		if len(a) != 2 || a[0] != lt5 || a[1] != gt2 {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+
+	t.Run("SubsumeSigned", func(t *testing.T) {
+		lt := glee.NewBinaryExpr(glee.SLT, x, c(0xFE)) // x < -2
+		le := glee.NewBinaryExpr(glee.SLE, x, c(3))
+		if a := glee.Simplify([]glee.Expr{le, lt}); len(a) != 1 || a[0] != lt {
+			t.Fatalf("unexpected constraints: %v", a)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package smtlib
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benbjohnson/glee"
+)
+
+// Decode parses the output of a script generated by Encode(). The first line
+// is the check-sat result and, if satisfiable, the get-value response holds
+// a value for each byte of each array in request order.
+func Decode(output []byte, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	line, rest := output, []byte(nil)
+	if i := bytes.IndexByte(output, '\n'); i != -1 {
+		line, rest = output[:i], output[i+1:]
+	}
+
+	switch result := string(bytes.TrimSpace(line)); result {
+	case "sat":
+	case "unsat":
+		return false, nil, nil
+	case "unknown":
+		return false, nil, glee.ErrSolverUnknown
+	default:
+		return false, nil, fmt.Errorf("smtlib.Decode: unexpected check-sat result: %q", result)
+	}
+
+	if len(arrays) == 0 {
+		return true, nil, nil
+	}
+
+	v, _, err := parseSExpr(rest)
+	if err != nil {
+		return true, nil, err
+	}
+
+	// Response is a list of (term value) pairs.
+	pairs, ok := v.([]interface{})
+	if !ok {
+		return true, nil, fmt.Errorf("smtlib.Decode: unexpected get-value response: %s", rest)
+	}
+
+	values = make([][]byte, len(arrays))
+	for i, array := range arrays {
+		values[i] = make([]byte, array.Size)
+		for j := range values[i] {
+			if len(pairs) == 0 {
+				return true, nil, fmt.Errorf("smtlib.Decode: missing value for array %s", arrayName(array))
+			}
+
+			pair, ok := pairs[0].([]interface{})
+			if !ok || len(pair) != 2 {
+				return true, nil, fmt.Errorf("smtlib.Decode: invalid get-value pair: %v", pairs[0])
+			}
+			pairs = pairs[1:]
+
+			b, err := parseByte(pair[1])
+			if err != nil {
+				return true, nil, err
+			}
+			values[i][j] = b
+		}
+	}
+	return true, values, nil
+}
+
+// parseByte parses an 8-bit value in hex (#xFF), binary (#b11111111), or
+// indexed (_ bv255 8) form.
+func parseByte(v interface{}) (byte, error) {
+	var s string
+	var base int
+	switch v := v.(type) {
+	case string:
+		switch {
+		case strings.HasPrefix(v, "#x"):
+			s, base = v[2:], 16
+		case strings.HasPrefix(v, "#b"):
+			s, base = v[2:], 2
+		}
+	case []interface{}:
+		if len(v) == 3 && v[0] == "_" {
+			if str, ok := v[1].(string); ok && strings.HasPrefix(str, "bv") {
+				s, base = str[2:], 10
+			}
+		}
+	}
+	if s == "" {
+		return 0, fmt.Errorf("smtlib.Decode: invalid byte value: %v", v)
+	}
+
+	n, err := strconv.ParseUint(s, base, 8)
+	if err != nil {
+		return 0, fmt.Errorf("smtlib.Decode: invalid byte value: %v", v)
+	}
+	return byte(n), nil
+}
+
+// parseSExpr parses a single s-expression from b. Atoms are returned as
+// strings & lists as []interface{}. Returns the remaining unparsed bytes.
+func parseSExpr(b []byte) (interface{}, []byte, error) {
+	b = bytes.TrimLeftFunc(b, unicode.IsSpace)
+	if len(b) == 0 {
+		return nil, nil, fmt.Errorf("smtlib.Decode: unexpected end of output")
+	}
+
+	switch b[0] {
+	case '(':
+		list := []interface{}{}
+		b = b[1:]
+		for {
+			b = bytes.TrimLeftFunc(b, unicode.IsSpace)
+			if len(b) == 0 {
+				return nil, nil, fmt.Errorf("smtlib.Decode: unterminated list")
+			} else if b[0] == ')' {
+				return list, b[1:], nil
+			}
+
+			v, rest, err := parseSExpr(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			list, b = append(list, v), rest
+		}
+
+	case ')':
+		return nil, nil, fmt.Errorf("smtlib.Decode: unexpected ')'")
+
+	default:
+		i := bytes.IndexFunc(b, func(r rune) bool { return unicode.IsSpace(r) || r == '(' || r == ')' })
+		if i == -1 {
+			i = len(b)
+		}
+		return string(b[:i]), b[i:], nil
+	}
+}
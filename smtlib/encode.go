@@ -0,0 +1,19 @@
+package smtlib
+
+import (
+	"fmt"
+
+	"github.com/benbjohnson/glee"
+)
+
+// Encode returns an SMT-LIB2 script which asserts each constraint, checks
+// satisfiability, and requests the value of every byte of each array.
+// It is equivalent to glee.EncodeSMTLIB2.
+func Encode(constraints []glee.Expr, arrays []*glee.Array) ([]byte, error) {
+	return glee.EncodeSMTLIB2(constraints, arrays)
+}
+
+// arrayName returns the name of the array. Matches the encoder naming.
+func arrayName(array *glee.Array) string {
+	return fmt.Sprintf("A%d", array.ID)
+}
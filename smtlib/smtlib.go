@@ -0,0 +1,97 @@
+// Package smtlib implements a glee.Solver that communicates with an external
+// solver process using the SMT-LIB2 text format. This avoids a cgo dependency
+// on a solver library at the cost of serializing every query.
+package smtlib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/glee"
+)
+
+// Ensure solver implements interface.
+var _ glee.ContextSolver = (*Solver)(nil)
+var _ glee.SolverStats = (*Solver)(nil)
+
+// DefaultCommand is the default solver command. The script is written to the
+// command's stdin.
+//
+// Other SMT-LIB2 solvers may be used by changing the command:
+//
+//	cvc5 --lang smt2 --produce-models
+//	boolector --smt2 --model-gen
+//
+// Floating-point expressions require a solver supporting fp.to_ieee_bv, such as Z3.
+var DefaultCommand = []string{"z3", "-in", "-smt2"}
+
+// Solver represents a solver that executes an external process per query.
+type Solver struct {
+	// Solver executable & arguments.
+	Command []string
+
+	stats Stats
+}
+
+// NewSolver returns a new instance of Solver using DefaultCommand.
+func NewSolver() *Solver {
+	return &Solver{Command: DefaultCommand}
+}
+
+// Stats returns statistics for the solver.
+func (s *Solver) Stats() Stats {
+	return s.stats
+}
+
+// QueryStats returns statistics for the queries answered by the solver.
+func (s *Solver) QueryStats() glee.QueryStats {
+	return s.stats.QueryStats
+}
+
+// Solve returns the satisfiability of the constraints & a value for each array.
+func (s *Solver) Solve(constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	return s.SolveContext(context.Background(), constraints, arrays)
+}
+
+// SolveContext solves the constraints, same as Solve(). The solver process is
+// killed if ctx is done before it completes & ctx.Err() is returned.
+func (s *Solver) SolveContext(ctx context.Context, constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	t := time.Now()
+	defer func() {
+		s.stats.Record(len(constraints), satisfiable, time.Since(t), err)
+	}()
+
+	if len(s.Command) == 0 {
+		return false, nil, errors.New("smtlib.Solver: command required")
+	}
+
+	script, err := Encode(constraints, arrays)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(script)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	// Solvers may exit with a non-zero code after reporting a result, such
+	// as when get-value fails for an unsatisfiable query, so only fail if
+	// the process could not run or no result was printed.
+	if err := cmd.Run(); ctx.Err() != nil {
+		return false, nil, ctx.Err()
+	} else if err != nil && stdout.Len() == 0 {
+		return false, nil, fmt.Errorf("smtlib.Solver: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return Decode(stdout.Bytes(), arrays)
+}
+
+// Stats represents statistics for the solver.
+type Stats struct {
+	glee.QueryStats
+}
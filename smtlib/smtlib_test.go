@@ -0,0 +1,103 @@
+package smtlib_test
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/smtlib"
+)
+
+func TestEncode(t *testing.T) {
+	a := glee.NewArray(1, 2)
+	x := a.Select(glee.NewConstantExpr(0, 32), 16, true)
+	cond := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(100, 16))
+
+	buf, err := smtlib.Encode([]glee.Expr{cond, glee.NewNotExpr(cond)}, []*glee.Array{a})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(buf)
+	for _, exp := range []string{
+		"(set-logic QF_ABV)\n",
+		"(declare-fun A1 () (Array (_ BitVec 64) (_ BitVec 8)))\n",
+		"(check-sat)\n",
+		"(get-value ((select A1 (_ bv0 64)) (select A1 (_ bv1 64)) ))\n",
+	} {
+		if !strings.Contains(s, exp) {
+			t.Fatalf("expected %q in script:\n%s", exp, s)
+		}
+	}
+
+	// Shared subexpressions are only defined once.
+	if n := strings.Count(s, "(bvult "); n != 1 {
+		t.Fatalf("unexpected definition count: %d\n%s", n, s)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	arrays := []*glee.Array{glee.NewArray(1, 2), glee.NewArray(2, 1)}
+
+	t.Run("Hex", func(t *testing.T) {
+		output := "sat\n(((select A1 #x0000000000000000) #x2a)\n ((select A1 #x0000000000000001) #xff)\n ((select A2 #x0000000000000000) #x00))\n"
+		if satisfiable, values, err := smtlib.Decode([]byte(output), arrays); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if !bytes.Equal(values[0], []byte{0x2a, 0xff}) || !bytes.Equal(values[1], []byte{0x00}) {
+			t.Fatalf("unexpected values: %v", values)
+		}
+	})
+
+	t.Run("BinaryAndIndexed", func(t *testing.T) {
+		output := "sat\n(((select A1 (_ bv0 64)) #b00000001) ((select A1 (_ bv1 64)) (_ bv2 8)) ((select A2 (_ bv0 64)) #b11111111))\n"
+		if _, values, err := smtlib.Decode([]byte(output), arrays); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(values[0], []byte{1, 2}) || !bytes.Equal(values[1], []byte{0xff}) {
+			t.Fatalf("unexpected values: %v", values)
+		}
+	})
+
+	t.Run("Unsat", func(t *testing.T) {
+		output := "unsat\n(error \"line 5 column 10: model is not available\")\n"
+		if satisfiable, _, err := smtlib.Decode([]byte(output), arrays); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, _, err := smtlib.Decode([]byte("unknown\n"), nil); err != glee.ErrSolverUnknown {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestSolver_Solve(t *testing.T) {
+	if _, err := exec.LookPath(smtlib.DefaultCommand[0]); err != nil {
+		t.Skipf("%s not available", smtlib.DefaultCommand[0])
+	}
+
+	a := glee.NewArray(1, 1)
+	x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+	cond := glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr(42, 8))
+
+	s := smtlib.NewSolver()
+	if satisfiable, values, err := s.Solve([]glee.Expr{cond}, []*glee.Array{a}); err != nil {
+		t.Fatal(err)
+	} else if !satisfiable {
+		t.Fatal("expected satisfiable")
+	} else if values[0][0] != 42 {
+		t.Fatalf("unexpected value: %d", values[0][0])
+	}
+
+	if satisfiable, _, err := s.Solve([]glee.Expr{cond, glee.NewNotExpr(cond)}, nil); err != nil {
+		t.Fatal(err)
+	} else if satisfiable {
+		t.Fatal("expected unsatisfiable")
+	}
+}
@@ -0,0 +1,445 @@
+package glee
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// EncodeSMTLIB2 returns an SMT-LIB2 script which asserts each constraint, checks
+// satisfiability, and requests the value of every byte of each array.
+//
+// Each distinct subexpression is defined once with define-fun so shared
+// expression trees do not grow exponentially when printed.
+func EncodeSMTLIB2(constraints []Expr, arrays []*Array) ([]byte, error) {
+	enc := newSMTEncoder()
+
+	var asserts []string
+	for _, constraint := range constraints {
+		term, err := enc.encode(constraint)
+		if err != nil {
+			return nil, err
+		}
+		asserts = append(asserts, term)
+	}
+
+	// Requested arrays may not be referenced by any constraint.
+	for _, array := range arrays {
+		enc.arrayConst(array)
+	}
+
+	// Floating-point terms require a logic that includes FP theory.
+	logic := "QF_ABV"
+	if enc.fp {
+		logic = "QF_ABVFP"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "(set-option :produce-models true)")
+	fmt.Fprintf(&buf, "(set-logic %s)\n", logic)
+	buf.Write(enc.defs.Bytes())
+	for _, term := range asserts {
+		fmt.Fprintf(&buf, "(assert %s)\n", term)
+	}
+	fmt.Fprintln(&buf, "(check-sat)")
+
+	if len(arrays) > 0 {
+		buf.WriteString("(get-value (")
+		for _, array := range arrays {
+			for offset := uint(0); offset < array.Size; offset++ {
+				fmt.Fprintf(&buf, "(select %s %s) ", smtArrayName(array), smtBVConst(uint64(offset), Width64))
+			}
+		}
+		buf.WriteString("))\n")
+	}
+	fmt.Fprintln(&buf, "(exit)")
+
+	return buf.Bytes(), nil
+}
+
+// smtEncoder converts expressions into SMT-LIB2 terms.
+type smtEncoder struct {
+	defs    bytes.Buffer // declarations & definitions
+	names   map[Expr]string
+	updates map[*ArrayUpdate]string
+	arrays  map[uint64]struct{}
+	fp      bool // true if FP theory is used
+}
+
+// newSMTEncoder returns a new instance of smtEncoder.
+func newSMTEncoder() *smtEncoder {
+	return &smtEncoder{
+		names:   make(map[Expr]string),
+		updates: make(map[*ArrayUpdate]string),
+		arrays:  make(map[uint64]struct{}),
+	}
+}
+
+// encode returns the term for expr. Non-constant expressions are defined
+// with define-fun on first use & referenced by name afterward.
+func (enc *smtEncoder) encode(expr Expr) (string, error) {
+	switch expr := expr.(type) {
+	case *ConstantExpr:
+		if expr.Width == WidthBool {
+			if expr.IsTrue() {
+				return "true", nil
+			}
+			return "false", nil
+		}
+		return smtConst(expr), nil
+	case *NotOptimizedExpr:
+		return enc.encode(expr.Src)
+	}
+
+	if name, ok := enc.names[expr]; ok {
+		return name, nil
+	}
+
+	term, err := enc.term(expr)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("e%d", len(enc.names))
+	fmt.Fprintf(&enc.defs, "(define-fun %s () %s %s)\n", name, smtSortName(ExprWidth(expr)), term)
+	enc.names[expr] = name
+	return name, nil
+}
+
+// term returns the term defining a non-constant expression.
+func (enc *smtEncoder) term(expr Expr) (string, error) {
+	switch expr := expr.(type) {
+	case *SelectExpr:
+		array, err := enc.arrayWithUpdate(expr.Array, expr.Array.Updates)
+		if err != nil {
+			return "", err
+		}
+		index, err := enc.encode(expr.Index)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(select %s %s)", array, index), nil
+
+	case *ConcatExpr:
+		msb, err := enc.encode(expr.MSB)
+		if err != nil {
+			return "", err
+		}
+		lsb, err := enc.encode(expr.LSB)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(concat %s %s)", msb, lsb), nil
+
+	case *ExtractExpr:
+		src, err := enc.encode(expr.Expr)
+		if err != nil {
+			return "", err
+		}
+
+		// If extracting single bit, use EQ expression to convert to bool sort.
+		if expr.Width == WidthBool {
+			return fmt.Sprintf("(= ((_ extract %d %d) %s) #b1)", expr.Offset, expr.Offset, src), nil
+		}
+		return fmt.Sprintf("((_ extract %d %d) %s)", expr.Offset+expr.Width-1, expr.Offset, src), nil
+
+	case *CastExpr:
+		return enc.castTerm(expr)
+
+	case *NotExpr:
+		src, err := enc.encode(expr.Expr)
+		if err != nil {
+			return "", err
+		}
+		if ExprWidth(expr.Expr) == WidthBool {
+			return fmt.Sprintf("(not %s)", src), nil
+		}
+		return fmt.Sprintf("(bvnot %s)", src), nil
+
+	case *BinaryExpr:
+		return enc.binaryTerm(expr)
+	case *FPBinaryExpr:
+		return enc.fpBinaryTerm(expr)
+	case *FPCastExpr:
+		return enc.fpCastTerm(expr)
+
+	case *IteExpr:
+		cond, err := enc.encode(expr.Cond)
+		if err != nil {
+			return "", err
+		}
+		then, err := enc.encode(expr.Then)
+		if err != nil {
+			return "", err
+		}
+		els, err := enc.encode(expr.Else)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(ite %s %s %s)", cond, then, els), nil
+
+	default:
+		return "", fmt.Errorf("glee.EncodeSMTLIB2: invalid expression type: %T", expr)
+	}
+}
+
+func (enc *smtEncoder) castTerm(expr *CastExpr) (string, error) {
+	src, err := enc.encode(expr.Src)
+	if err != nil {
+		return "", err
+	}
+
+	// Convert boolean cast to if-then-else expression.
+	srcWidth := ExprWidth(expr.Src)
+	if srcWidth == WidthBool {
+		whenTrue := NewConstantExpr(1, WidthBool).ZExt(expr.Width)
+		if expr.Signed {
+			whenTrue = NewConstantExpr(0, expr.Width).Not()
+		}
+		return fmt.Sprintf("(ite %s %s %s)", src, smtConst(whenTrue), smtBVConst(0, expr.Width)), nil
+	}
+
+	if expr.Signed {
+		return fmt.Sprintf("((_ sign_extend %d) %s)", expr.Width-srcWidth, src), nil
+	}
+	return fmt.Sprintf("((_ zero_extend %d) %s)", expr.Width-srcWidth, src), nil
+}
+
+func (enc *smtEncoder) binaryTerm(expr *BinaryExpr) (string, error) {
+	lhs, err := enc.encode(expr.LHS)
+	if err != nil {
+		return "", err
+	}
+
+	// Shift amounts must have the sort of the shifted value.
+	rhsExpr := expr.RHS
+	if expr.Op == SHL || expr.Op == LSHR || expr.Op == ASHR {
+		rhsExpr = ShiftAmountExpr(expr.RHS, ExprWidth(expr.LHS))
+	}
+	rhs, err := enc.encode(rhsExpr)
+	if err != nil {
+		return "", err
+	}
+
+	// Logical operations on booleans use core theory functions.
+	if ExprWidth(expr.LHS) == WidthBool {
+		switch expr.Op {
+		case AND:
+			return fmt.Sprintf("(and %s %s)", lhs, rhs), nil
+		case OR:
+			return fmt.Sprintf("(or %s %s)", lhs, rhs), nil
+		case XOR:
+			return fmt.Sprintf("(xor %s %s)", lhs, rhs), nil
+		}
+	}
+
+	var op string
+	switch expr.Op {
+	case ADD:
+		op = "bvadd"
+	case SUB:
+		op = "bvsub"
+	case MUL:
+		op = "bvmul"
+	case UDIV:
+		op = "bvudiv"
+	case SDIV:
+		op = "bvsdiv"
+	case UREM:
+		op = "bvurem"
+	case SREM:
+		op = "bvsrem"
+	case AND:
+		op = "bvand"
+	case OR:
+		op = "bvor"
+	case XOR:
+		op = "bvxor"
+	case SHL:
+		op = "bvshl"
+	case LSHR:
+		op = "bvlshr"
+	case ASHR:
+		op = "bvashr"
+	case EQ:
+		op = "="
+	case ULT:
+		op = "bvult"
+	case ULE:
+		op = "bvule"
+	case SLT:
+		op = "bvslt"
+	case SLE:
+		op = "bvsle"
+	default:
+		return "", fmt.Errorf("glee.EncodeSMTLIB2: unexpected binary operation: %s", expr.Op)
+	}
+	return fmt.Sprintf("(%s %s %s)", op, lhs, rhs), nil
+}
+
+// fpBinaryTerm converts the IEEE 754 bit vector operands to floating-point
+// terms, applies the operation, and converts arithmetic results back.
+func (enc *smtEncoder) fpBinaryTerm(expr *FPBinaryExpr) (string, error) {
+	lhs, err := enc.fpTerm(expr.LHS)
+	if err != nil {
+		return "", err
+	}
+	rhs, err := enc.fpTerm(expr.RHS)
+	if err != nil {
+		return "", err
+	}
+
+	switch expr.Op {
+	case FADD:
+		return fmt.Sprintf("(fp.to_ieee_bv (fp.add RNE %s %s))", lhs, rhs), nil
+	case FSUB:
+		return fmt.Sprintf("(fp.to_ieee_bv (fp.sub RNE %s %s))", lhs, rhs), nil
+	case FMUL:
+		return fmt.Sprintf("(fp.to_ieee_bv (fp.mul RNE %s %s))", lhs, rhs), nil
+	case FDIV:
+		return fmt.Sprintf("(fp.to_ieee_bv (fp.div RNE %s %s))", lhs, rhs), nil
+	case FEQ:
+		return fmt.Sprintf("(fp.eq %s %s)", lhs, rhs), nil
+	case FLT:
+		return fmt.Sprintf("(fp.lt %s %s)", lhs, rhs), nil
+	case FLE:
+		return fmt.Sprintf("(fp.leq %s %s)", lhs, rhs), nil
+	default:
+		return "", fmt.Errorf("glee.EncodeSMTLIB2: unexpected fp operation: %s", expr.Op)
+	}
+}
+
+func (enc *smtEncoder) fpCastTerm(expr *FPCastExpr) (string, error) {
+	// Go rounds to nearest when converting to floats & truncates to integers.
+	switch expr.Op {
+	case FPEXT, FPTOSI, FPTOUI:
+		src, err := enc.fpTerm(expr.Src)
+		if err != nil {
+			return "", err
+		}
+
+		switch expr.Op {
+		case FPTOSI:
+			return fmt.Sprintf("((_ fp.to_sbv %d) RTZ %s)", expr.Width, src), nil
+		case FPTOUI:
+			return fmt.Sprintf("((_ fp.to_ubv %d) RTZ %s)", expr.Width, src), nil
+		}
+
+		fpSort, err := smtFPSort(expr.Width)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(fp.to_ieee_bv ((_ to_fp %s) RNE %s))", fpSort, src), nil
+
+	case SITOFP, UITOFP:
+		src, err := enc.encode(expr.Src)
+		if err != nil {
+			return "", err
+		}
+		fpSort, err := smtFPSort(expr.Width)
+		if err != nil {
+			return "", err
+		}
+
+		if expr.Op == SITOFP {
+			return fmt.Sprintf("(fp.to_ieee_bv ((_ to_fp %s) RNE %s))", fpSort, src), nil
+		}
+		return fmt.Sprintf("(fp.to_ieee_bv ((_ to_fp_unsigned %s) RNE %s))", fpSort, src), nil
+
+	default:
+		return "", fmt.Errorf("glee.EncodeSMTLIB2: unexpected fp cast operation: %s", expr.Op)
+	}
+}
+
+// fpTerm returns a floating-point term from an expression holding an IEEE 754 encoding.
+func (enc *smtEncoder) fpTerm(expr Expr) (string, error) {
+	enc.fp = true
+
+	src, err := enc.encode(expr)
+	if err != nil {
+		return "", err
+	}
+	fpSort, err := smtFPSort(ExprWidth(expr))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("((_ to_fp %s) %s)", fpSort, src), nil
+}
+
+// arrayConst declares the root array, if not yet declared, and returns its name.
+func (enc *smtEncoder) arrayConst(array *Array) string {
+	name := smtArrayName(array)
+	if _, ok := enc.arrays[array.ID]; !ok {
+		fmt.Fprintf(&enc.defs, "(declare-fun %s () (Array (_ BitVec 64) (_ BitVec 8)))\n", name)
+		enc.arrays[array.ID] = struct{}{}
+	}
+	return name
+}
+
+// arrayWithUpdate returns an array term with updates recursively applied.
+// Each update is defined once so arrays sharing updates share terms.
+func (enc *smtEncoder) arrayWithUpdate(root *Array, upd *ArrayUpdate) (string, error) {
+	if upd == nil {
+		return enc.arrayConst(root), nil
+	} else if name, ok := enc.updates[upd]; ok {
+		return name, nil
+	}
+
+	array, err := enc.arrayWithUpdate(root, upd.Next)
+	if err != nil {
+		return "", err
+	}
+	index, err := enc.encode(upd.Index)
+	if err != nil {
+		return "", err
+	}
+	value, err := enc.encode(upd.Value)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("u%d", len(enc.updates))
+	fmt.Fprintf(&enc.defs, "(define-fun %s () (Array (_ BitVec 64) (_ BitVec 8)) (store %s %s %s))\n", name, array, index, value)
+	enc.updates[upd] = name
+	return name, nil
+}
+
+// smtSortName returns the sort for an expression of the given width.
+func smtSortName(width uint) string {
+	if width == WidthBool {
+		return "Bool"
+	}
+	return fmt.Sprintf("(_ BitVec %d)", width)
+}
+
+// smtFPSort returns the exponent & significand widths for a 32 or 64-bit float.
+func smtFPSort(width uint) (string, error) {
+	switch width {
+	case Width32:
+		return "8 24", nil
+	case Width64:
+		return "11 53", nil
+	default:
+		return "", fmt.Errorf("glee.EncodeSMTLIB2: invalid fp width: %d", width)
+	}
+}
+
+// smtConst returns a bit vector constant term for a constant of any width.
+func smtConst(expr *ConstantExpr) string {
+	if expr.Big != nil {
+		return fmt.Sprintf("(_ bv%s %d)", expr.Big, expr.Width)
+	}
+	return smtBVConst(expr.Value, expr.Width)
+}
+
+// smtBVConst returns a bit vector constant term.
+func smtBVConst(value uint64, width uint) string {
+	if width < 64 {
+		value &= 1<<width - 1
+	}
+	return fmt.Sprintf("(_ bv%d %d)", value, width)
+}
+
+// smtArrayName returns the name of the array. Matches the Z3 solver naming.
+func smtArrayName(array *Array) string {
+	return fmt.Sprintf("A%d", array.ID)
+}
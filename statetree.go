@@ -0,0 +1,147 @@
+package glee
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benbjohnson/immutable"
+)
+
+// stateNode represents a state recorded in the state tree.
+type stateNode struct {
+	id     int
+	parent int            // zero for the root
+	pos    token.Position // position the state was forked at
+	cond   Expr           // branch condition taken, if any
+	status ExecutionStatus
+	reason string
+
+	// Heap when the state was created & when it forked or terminated.
+	start, end *immutable.SortedMap
+}
+
+// node returns the recorded node for state, creating it if necessary.
+func (e *Executor) node(state *ExecutionState) *stateNode {
+	if e.tree == nil {
+		e.tree = make(map[int]*stateNode)
+	}
+	n := e.tree[state.id]
+	if n == nil {
+		n = &stateNode{id: state.id, pos: state.SourcePosition()}
+		if state.parent == nil {
+			n.start = immutable.NewSortedMap(&uint64Comparer{})
+		}
+		e.tree[state.id] = n
+	}
+	return n
+}
+
+// recordFork records child as forked from parent. The branch condition is
+// the constraint added by the fork, if any. The child's heap is diffed from
+// the parent's heap at the fork as the child may already have been written.
+func (e *Executor) recordFork(parent, child *ExecutionState) {
+	p := e.node(parent)
+	p.end = parent.heap
+
+	n := e.node(child)
+	n.parent, n.start = p.id, parent.heap
+	if child.depth > parent.depth && len(child.constraints) > 0 {
+		n.cond = child.constraints[len(child.constraints)-1]
+	}
+}
+
+// recordTerminalState records the status, reason & final heap of a terminated state.
+func (e *Executor) recordTerminalState(state *ExecutionState) {
+	n := e.node(state)
+	n.status, n.reason, n.end = state.status, state.reason, state.heap
+}
+
+// WriteStateTree writes the tree of every state created by the executor to w
+// in the DOT format used by Graphviz. Each node is labeled with the state's
+// ID, the position it was forked at, the branch condition taken, the heap
+// allocations written by the state & its status once terminated. RecordStateTree must be set before execution.
+func (e *Executor) WriteStateTree(w io.Writer) error {
+	if !e.RecordStateTree {
+		return errors.New("glee.Executor: state tree not recorded")
+	}
+	e.node(e.root)
+
+	nodes := make([]*stateNode, 0, len(e.tree))
+	for _, n := range e.tree {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+
+	if _, err := fmt.Fprintln(w, "digraph states {"); err != nil {
+		return err
+	} else if _, err := fmt.Fprintln(w, "\tnode [shape=box];"); err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		if _, err := fmt.Fprintf(w, "\ts%d [label=%s];\n", n.id, dotQuote(n.label())); err != nil {
+			return err
+		}
+	}
+	for _, n := range nodes {
+		if n.parent == 0 {
+			continue
+		} else if _, err := fmt.Fprintf(w, "\ts%d -> s%d;\n", n.parent, n.id); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// label returns the lines of the node's label.
+func (n *stateNode) label() []string {
+	pos := n.pos
+	pos.Filename = filepath.Base(pos.Filename)
+	lines := []string{fmt.Sprintf("#%d", n.id), pos.String()}
+
+	if n.cond != nil {
+		lines = append(lines, n.cond.String())
+	}
+	if n.start != nil && n.end != nil {
+		for _, diff := range diffHeap(n.start, n.end) {
+			lines = append(lines, "heap "+diff.String())
+		}
+	}
+	if n.status != "" {
+		if n.reason != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", n.status, n.reason))
+		} else {
+			lines = append(lines, string(n.status))
+		}
+	}
+	return lines
+}
+
+// dotQuote returns lines as a quoted DOT string with each line left-justified.
+func dotQuote(lines []string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, line := range lines {
+		for _, ch := range line {
+			switch ch {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteRune(ch)
+			case '\n':
+				buf.WriteString(`\l`)
+			default:
+				buf.WriteRune(ch)
+			}
+		}
+		buf.WriteString(`\l`)
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
@@ -0,0 +1,96 @@
+package glee
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SolverStats is implemented by solvers which record statistics about the
+// queries they answer. Wrapping solvers report the statistics of the solver
+// they wrap along with their own.
+type SolverStats interface {
+	QueryStats() QueryStats
+}
+
+// QueryDurationBounds are the upper bounds of the buckets in the query
+// duration histogram. Queries slower than the last bound are counted in the
+// final bucket.
+var QueryDurationBounds = [...]time.Duration{
+	1 * time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	1 * time.Second,
+	10 * time.Second,
+}
+
+// QueryStats represents statistics for a set of solver queries.
+type QueryStats struct {
+	QueryN   int // total queries
+	SatN     int // satisfiable queries
+	UnsatN   int // unsatisfiable queries
+	UnknownN int // queries which timed out, were cancelled, or hit a limit
+	ErrorN   int // queries which failed for any other reason
+
+	// Total & largest number of constraints per query.
+	ConstraintN    int
+	MaxConstraintN int
+
+	// Queries answered by a cache & queries passed through to a solver.
+	CacheHitN  int
+	CacheMissN int
+
+	// Total time spent solving & the number of queries by duration. Each
+	// bucket counts queries faster than the matching QueryDurationBounds.
+	SolveTime time.Duration
+	Durations [len(QueryDurationBounds) + 1]int
+}
+
+// Record adds the result of a single query to the statistics.
+func (s *QueryStats) Record(constraintN int, satisfiable bool, d time.Duration, err error) {
+	s.QueryN++
+	switch {
+	case err == nil && satisfiable:
+		s.SatN++
+	case err == nil:
+		s.UnsatN++
+	case IsSolverUnknown(err):
+		s.UnknownN++
+	default:
+		s.ErrorN++
+	}
+
+	s.ConstraintN += constraintN
+	if constraintN > s.MaxConstraintN {
+		s.MaxConstraintN = constraintN
+	}
+
+	s.SolveTime += d
+	i := 0
+	for i < len(QueryDurationBounds) && d >= QueryDurationBounds[i] {
+		i++
+	}
+	s.Durations[i]++
+}
+
+// String returns a single line summary of the statistics.
+func (s QueryStats) String() string {
+	var avg time.Duration
+	if s.QueryN > 0 {
+		avg = s.SolveTime / time.Duration(s.QueryN)
+	}
+	return fmt.Sprintf("queries=%d sat=%d unsat=%d unknown=%d error=%d constraints=%d max_constraints=%d cache_hit=%d cache_miss=%d time=%s avg=%s",
+		s.QueryN, s.SatN, s.UnsatN, s.UnknownN, s.ErrorN, s.ConstraintN, s.MaxConstraintN, s.CacheHitN, s.CacheMissN, s.SolveTime, avg)
+}
+
+// IsSolverUnknown returns true if err indicates the solver could not
+// determine satisfiability, such as a timeout or cancellation.
+func IsSolverUnknown(err error) bool {
+	switch err {
+	case ErrSolverTimeout, ErrSolverCanceled, ErrSolverResourceLimit, ErrSolverUnknown,
+		context.Canceled, context.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package glee_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/glee"
+)
+
+func TestQueryStats_Record(t *testing.T) {
+	var stats glee.QueryStats
+	stats.Record(2, true, 500*time.Microsecond, nil)
+	stats.Record(5, false, 20*time.Millisecond, nil)
+	stats.Record(3, false, 2*time.Second, glee.ErrSolverTimeout)
+	stats.Record(1, false, time.Minute, errors.New("marker"))
+
+	if stats.QueryN != 4 {
+		t.Fatalf("unexpected query count: %d", stats.QueryN)
+	} else if stats.SatN != 1 || stats.UnsatN != 1 || stats.UnknownN != 1 || stats.ErrorN != 1 {
+		t.Fatalf("unexpected results: %s", stats)
+	} else if stats.ConstraintN != 11 || stats.MaxConstraintN != 5 {
+		t.Fatalf("unexpected constraint counts: %s", stats)
+	} else if stats.SolveTime != time.Minute+2*time.Second+20*time.Millisecond+500*time.Microsecond {
+		t.Fatalf("unexpected solve time: %s", stats.SolveTime)
+	} else if got, exp := stats.Durations, [...]int{1, 0, 1, 0, 1, 1}; got != exp {
+		t.Fatalf("durations=%v, expected %v", got, exp)
+	}
+}
+
+func TestCachingSolver_QueryStats(t *testing.T) {
+	a := glee.NewArray(1, 1)
+	x := a.Select(glee.NewConstantExpr(0, 32), 8, false)
+	gt5 := glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(5, 8))
+
+	var solver SolverMock
+	solver.SolveFunc = func(constraints []glee.Expr, arrays []*glee.Array) (bool, [][]byte, error) {
+		return true, [][]byte{{10}}, nil
+	}
+
+	s := glee.NewIndependenceSolver(glee.NewCachingSolver(&solver))
+	for i := 0; i < 3; i++ {
+		if _, _, err := s.Solve([]glee.Expr{gt5}, []*glee.Array{a}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := s.QueryStats(); stats.CacheHitN != 2 || stats.CacheMissN != 1 {
+		t.Fatalf("unexpected stats: %s", stats)
+	}
+}
@@ -0,0 +1,268 @@
+package glee
+
+import (
+	"context"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxSummaryStates is the maximum number of states created while computing
+// a function summary. Functions with more paths are executed normally.
+const maxSummaryStates = 64
+
+// summary represents every path through a pure function. Constraints &
+// results are expressed in terms of the symbolic arrays bound to the
+// function's parameters so they can be instantiated with the arguments of
+// any call.
+type summary struct {
+	fn     *ssa.Function
+	params map[uint64]int // parameter index, by array ID
+	paths  []summaryPath
+}
+
+// summaryPath represents a single path through a summarized function.
+type summaryPath struct {
+	constraints []Expr
+	results     Tuple
+}
+
+// summary returns the summary of fn, computing it on first use. Returns nil
+// if fn is not pure or any of its paths do not return normally.
+func (e *Executor) summary(fn *ssa.Function) (*summary, error) {
+	if s, ok := e.summaries[fn]; ok {
+		return s, nil
+	}
+
+	s, err := e.computeSummary(fn)
+	if err != nil {
+		return nil, err
+	}
+	e.summaries[fn] = s
+	return s, nil
+}
+
+// computeSummary executes fn with symbolic parameters on a separate executor
+// & records the constraints & results of each path.
+func (e *Executor) computeSummary(fn *ssa.Function) (*summary, error) {
+	if !e.isPure(fn, make(map[*ssa.Function]bool)) {
+		return nil, nil
+	}
+
+	sub := e.newSummaryExecutor(fn)
+	arrays, err := sub.BindSymbolicParams(0)
+	if err != nil {
+		return nil, nil
+	}
+
+	s := &summary{fn: fn, params: make(map[uint64]int, len(arrays))}
+	for i, array := range arrays {
+		s.params[array.ID] = i
+	}
+
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for {
+		state, err := sub.ExecuteNextStateContext(ctx)
+		if err == ErrNoStateAvailable {
+			break
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if err != nil {
+			e.debugf("[summary] %s: %s", fn, err)
+			return nil, nil
+		} else if !state.Terminated() {
+			continue
+		}
+
+		// Paths which panic or reach a limit cannot be represented.
+		if state.Status() != ExecutionStatusFinished || state.results == nil {
+			e.debugf("[summary] %s: %s path", fn, state.Status())
+			return nil, nil
+		}
+		s.paths = append(s.paths, summaryPath{constraints: state.Constraints(), results: state.results})
+	}
+
+	e.infof("[summary] %s: paths=%d", fn, len(s.paths))
+	return s, nil
+}
+
+// newSummaryExecutor returns an executor for computing the summary of fn.
+// Type IDs, handlers & summaries are shared with e.
+func (e *Executor) newSummaryExecutor(fn *ssa.Function) *Executor {
+	sub := &Executor{
+		fn:    fn,
+		exprs: e.exprs,
+
+		prog:  e.prog,
+		fns:   e.fns,
+		funcs: e.funcs,
+
+		typeIDs:     e.typeIDs,
+		typesByID:   e.typesByID,
+		typesByName: e.typesByName,
+
+		funcAddrs:   e.funcAddrs,
+		funcsByAddr: e.funcsByAddr,
+
+		branchHotSpots: make(map[token.Position]*HotSpot),
+		funcHotSpots:   make(map[*ssa.Function]*HotSpot),
+
+		coverFuncs: make(map[*ssa.Function]struct{}),
+		covered:    make(map[string]map[uint]struct{}),
+		branches:   make(map[*ssa.If]*BranchCoverage),
+
+		errorType: e.errorType,
+
+		summaries:   e.summaries,
+		summarizing: true,
+
+		funcAnnotations: e.funcAnnotations,
+		funcPathN:       make(map[*ssa.Function]int),
+
+		OS:                  e.OS,
+		Arch:                e.Arch,
+		Solver:              e.Solver,
+		Searcher:            NewDFSSearcher(),
+		HeapBase:            e.HeapBase,
+		AssumeValidUTF8:     e.AssumeValidUTF8,
+		MaxGoroutines:       e.MaxGoroutines,
+		MaxPreemptions:      e.MaxPreemptions,
+		CheckOverflow:       e.CheckOverflow,
+		SimplifyConstraints: e.SimplifyConstraints,
+		Summarize:           true,
+		Logger:              e.Logger,
+
+		Limits: Limits{
+			MaxStates:       maxSummaryStates,
+			MaxDepth:        e.MaxDepth,
+			MaxInstructions: e.MaxInstructions,
+		},
+	}
+
+	sub.root = NewExecutionState(sub, fn)
+	sub.root.id = sub.nextStateID()
+	sub.states = map[*ExecutionState]struct{}{sub.root: struct{}{}}
+	sub.Searcher.AddState(sub.root)
+	return sub
+}
+
+// isPure returns true if fn only computes its results from scalar parameters.
+// Pure functions do not access memory & only call other pure functions.
+// Recursive & annotated functions are excluded.
+func (e *Executor) isPure(fn *ssa.Function, visiting map[*ssa.Function]bool) bool {
+	if fn.Blocks == nil || len(fn.FreeVars) > 0 || visiting[fn] || e.registeredFn(fn) != nil {
+		return false
+	} else if a, err := e.annotations(fn); err != nil || a != nil {
+		return false
+	}
+	visiting[fn] = true
+	defer delete(visiting, fn)
+
+	for _, param := range fn.Params {
+		if !isExprType(param.Type().Underlying()) {
+			return false
+		}
+	}
+	if !isExprTuple(fn.Signature.Results()) {
+		return false
+	}
+
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			if v, ok := instr.(ssa.Value); ok && !isExprTuple(v.Type()) {
+				return false
+			}
+
+			switch instr := instr.(type) {
+			case *ssa.BinOp, *ssa.ChangeType, *ssa.Convert, *ssa.DebugRef, *ssa.Extract, *ssa.If, *ssa.Jump, *ssa.Phi, *ssa.Return:
+			case *ssa.UnOp:
+				if instr.Op == token.MUL || instr.Op == token.ARROW {
+					return false
+				}
+			case *ssa.Call:
+				if callee := instr.Call.StaticCallee(); callee == nil || !e.isPure(callee, visiting) {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isExprTuple returns true if typ, or every element of typ if it is a tuple,
+// is represented by an expression.
+func isExprTuple(typ types.Type) bool {
+	tuple, ok := typ.(*types.Tuple)
+	if !ok {
+		return isExprType(typ.Underlying())
+	}
+	for i := 0; i < tuple.Len(); i++ {
+		if !isExprType(tuple.At(i).Type().Underlying()) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply forks a state from state for each path of the summary which is
+// satisfiable with the arguments of instr. The path's results are bound to
+// instr on each new state.
+func (s *summary) apply(state *ExecutionState, instr *ssa.Call, args []Binding) error {
+	littleEndian := state.Executor().IsLittleEndian()
+
+	// Replace each byte read from a parameter with the byte of its argument.
+	m := make(map[Expr]Expr)
+	replace := func(expr Expr) Expr {
+		sel, ok := expr.(*SelectExpr)
+		if !ok || sel.Array.Updates != nil {
+			return nil
+		}
+		i, ok := s.params[sel.Array.ID]
+		index, isConst := sel.Index.(*ConstantExpr)
+		if !ok || !isConst {
+			return nil
+		}
+
+		arg := args[i].(Expr)
+		width := ExprWidth(arg)
+		if width == WidthBool {
+			return NewCastExpr(arg, Width8, false)
+		}
+		offset := uint(index.Value) * 8
+		if !littleEndian {
+			offset = width - Width8 - offset
+		}
+		return NewExtractExpr(arg, offset, Width8)
+	}
+
+	conds := make([]Expr, len(s.paths))
+	for i, path := range s.paths {
+		var cond Expr = NewBoolConstantExpr(true)
+		for _, constraint := range path.constraints {
+			cond = NewBinaryExpr(AND, cond, replaceExpr(constraint, replace, m))
+		}
+		conds[i] = cond
+	}
+
+	return forkEach(state, conds, func(state *ExecutionState, i int) {
+		results := make(Tuple, len(s.paths[i].results))
+		for j, result := range s.paths[i].results {
+			results[j] = replaceExpr(result.(Expr), replace, m)
+		}
+
+		switch len(results) {
+		case 0:
+		case 1:
+			state.Frame().bind(instr, results[0])
+		default:
+			state.Frame().bind(instr, results)
+		}
+	})
+}
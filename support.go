@@ -0,0 +1,193 @@
+package glee
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// UnsupportedInstr represents an instruction the executor cannot execute.
+type UnsupportedInstr struct {
+	Instr  ssa.Instruction
+	Reason string
+
+	// Function called by Instr which cannot be executed, if any.
+	Callee *ssa.Function
+}
+
+// Unsupported returns the instructions within fn that the executor is known to
+// not support. This is a quick scan of the function body and does not follow
+// calls so execution may still fail for a function with no results.
+func Unsupported(fn *ssa.Function) []UnsupportedInstr {
+	var a []UnsupportedInstr
+	for _, blk := range fn.Blocks {
+		for _, instr := range blk.Instrs {
+			if reason := unsupportedReason(instr); reason != "" {
+				a = append(a, UnsupportedInstr{Instr: instr, Reason: reason})
+			}
+		}
+	}
+	return a
+}
+
+// Vet returns the unsupported instructions within the entry function & every
+// function reachable from it. Unlike Unsupported(), calls are followed through
+// static calls, function values & the methods of every runtime type which may
+// be invoked through an interface. Functions handled by a registered
+// FunctionHandler or annotated with //glee:skip are not followed.
+//
+// Calls to functions without a body, such as assembly, are reported unless
+// Havoc is set, as are calls which the executor cannot dispatch.
+func (e *Executor) Vet() ([]UnsupportedInstr, error) {
+	// Types which may be held by an interface, sorted so results are stable.
+	typs := e.prog.RuntimeTypes()
+	sort.Slice(typs, func(i, j int) bool { return typs[i].String() < typs[j].String() })
+
+	var a []UnsupportedInstr
+	seen := map[*ssa.Function]struct{}{e.fn: {}}
+	queue := []*ssa.Function{e.fn}
+
+	// visit enqueues fn if it has not been seen & is executed rather than
+	// handled or skipped. Returns false if fn cannot be executed.
+	visit := func(fn *ssa.Function) (bool, error) {
+		if _, ok := seen[fn]; ok {
+			return true, nil
+		}
+		seen[fn] = struct{}{}
+
+		if e.registeredFn(fn) != nil {
+			return true, nil
+		} else if ann, err := e.annotations(fn); err != nil {
+			return false, err
+		} else if ann != nil && ann.skip {
+			return true, nil
+		} else if fn.Blocks == nil {
+			return e.Havoc, nil
+		}
+		queue = append(queue, fn)
+		return true, nil
+	}
+
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		a = append(a, Unsupported(fn)...)
+
+		for _, blk := range fn.Blocks {
+			for _, instr := range blk.Instrs {
+				if call, ok := instr.(ssa.CallInstruction); ok {
+					if reason := e.unsupportedCallReason(call); reason != "" {
+						a = append(a, UnsupportedInstr{Instr: instr, Reason: reason})
+					}
+				}
+
+				// Any function referenced by an operand may be called, either
+				// directly or later through a function value.
+				callees := e.invokeCallees(instr, typs)
+				for _, op := range instr.Operands(nil) {
+					if fn, ok := (*op).(*ssa.Function); ok {
+						callees = append(callees, fn)
+					}
+				}
+
+				for _, callee := range callees {
+					if ok, err := visit(callee); err != nil {
+						return nil, err
+					} else if !ok {
+						a = append(a, UnsupportedInstr{Instr: instr, Reason: "function body unavailable", Callee: callee})
+					}
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
+// unsupportedCallReason returns a description of why the executor cannot
+// dispatch call. Returns a blank string if the call is supported.
+func (e *Executor) unsupportedCallReason(call ssa.CallInstruction) string {
+	common := call.Common()
+	if builtin, ok := common.Value.(*ssa.Builtin); ok {
+		if _, ok := call.(*ssa.Go); ok {
+			return "go statement calling builtin"
+		} else if e.fns[funcKey{"", builtin.Name()}] == nil {
+			return fmt.Sprintf("builtin function: %s", builtin.Name())
+		}
+		return ""
+	}
+
+	fn := common.StaticCallee()
+	if fn == nil || e.registeredFn(fn) == nil {
+		return ""
+	}
+	switch call.(type) {
+	case *ssa.Go:
+		return "go statement calling registered function"
+	case *ssa.Defer:
+		return "deferred call to registered function"
+	}
+	return ""
+}
+
+// invokeCallees returns the methods of typs which an interface method
+// invocation by instr may dispatch to. Returns nil if instr is not an
+// invocation.
+func (e *Executor) invokeCallees(instr ssa.Instruction, typs []types.Type) []*ssa.Function {
+	call, ok := instr.(ssa.CallInstruction)
+	if !ok || !call.Common().IsInvoke() {
+		return nil
+	}
+
+	common := call.Common()
+	iface := common.Value.Type().Underlying().(*types.Interface)
+
+	var fns []*ssa.Function
+	for _, typ := range typs {
+		if types.IsInterface(typ) || !types.Implements(typ, iface) {
+			continue
+		} else if fn := e.prog.LookupMethod(typ, common.Method.Pkg(), common.Method.Name()); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	return fns
+}
+
+// unsupportedReason returns a description of why instr cannot be executed.
+// Returns a blank string if the instruction is supported. This must be kept
+// in sync with executeNextInstruction().
+func unsupportedReason(instr ssa.Instruction) string {
+	switch instr := instr.(type) {
+	case *ssa.BinOp:
+		if basic, ok := instr.X.Type().Underlying().(*types.Basic); ok {
+			if basic.Info()&types.IsComplex != 0 {
+				return "complex number operations"
+			}
+		}
+	case *ssa.Convert:
+		switch typ := instr.X.Type().Underlying().(type) {
+		case *types.Basic:
+			if typ.Info()&types.IsComplex != 0 {
+				return "complex number conversion"
+			}
+		}
+	case *ssa.Defer:
+		if _, ok := instr.Call.Value.(*ssa.Builtin); ok {
+			return "deferred builtin call"
+		}
+	case *ssa.UnOp:
+		switch instr.Op {
+		case token.NOT:
+			return "not operator"
+		case token.SUB:
+			if basic, ok := instr.X.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsFloat == 0 {
+				return "negation operator"
+			}
+		case token.XOR:
+			return "xor operator"
+		}
+	}
+	return ""
+}
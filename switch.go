@@ -0,0 +1,153 @@
+package glee
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// switchChain represents a chain of If instructions which each compare the
+// same integer value against a constant. SSA lowers switch statements into
+// these chains where each case is tested in the false branch of the last.
+type switchChain struct {
+	x     ssa.Value    // value compared by every case
+	ifs   []*ssa.If    // branch for each case, in order
+	cases []*ssa.BinOp // comparison for each case, in order
+}
+
+// newSwitchChain returns the chain of comparisons beginning with instr.
+// Returns nil if instr does not begin a chain of at least two comparisons.
+//
+// Only the first comparison is executed before the chain is recognized so
+// later blocks must contain nothing but the comparison & its branch. Their
+// comparisons cannot be used elsewhere as they are never bound.
+func newSwitchChain(instr *ssa.If) *switchChain {
+	binop := switchCase(instr.Cond, nil)
+	if binop == nil {
+		return nil
+	}
+	chain := &switchChain{x: binop.X, ifs: []*ssa.If{instr}, cases: []*ssa.BinOp{binop}}
+
+	for {
+		block := chain.ifs[len(chain.ifs)-1].Block().Succs[1]
+		if len(block.Preds) != 1 {
+			break
+		}
+
+		var instrs []ssa.Instruction
+		for _, instr := range block.Instrs {
+			if _, ok := instr.(*ssa.DebugRef); !ok {
+				instrs = append(instrs, instr)
+			}
+		}
+		if len(instrs) != 2 {
+			break
+		}
+
+		v, _ := instrs[0].(ssa.Value)
+		binop := switchCase(v, chain.x)
+		next, ok := instrs[1].(*ssa.If)
+		if binop == nil || !ok || next.Cond != binop || len(*binop.Referrers()) != 1 {
+			break
+		}
+		chain.ifs, chain.cases = append(chain.ifs, next), append(chain.cases, binop)
+	}
+
+	if len(chain.ifs) < 2 {
+		return nil
+	}
+	return chain
+}
+
+// switchCase returns v as an equality comparison of an integer against a
+// constant. If x is not nil then the comparison must be against x.
+func switchCase(v ssa.Value, x ssa.Value) *ssa.BinOp {
+	binop, ok := v.(*ssa.BinOp)
+	if !ok || binop.Op != token.EQL {
+		return nil
+	} else if _, ok := binop.Y.(*ssa.Const); !ok {
+		return nil
+	} else if x != nil && binop.X != x {
+		return nil
+	}
+
+	typ, ok := binop.X.Type().Underlying().(*types.Basic)
+	if !ok || typ.Info()&types.IsInteger == 0 {
+		return nil
+	}
+	return binop
+}
+
+// executeIfInstrSwitch forks a state for each satisfiable case of the chain
+// of comparisons beginning at instr, along with a state for the default when
+// no case matches. This requires one query per case instead of two for each
+// branch of the chain. Returns false if instr does not begin a chain or if
+// the compared value is constant.
+//
+// Each state continues at the target of its case as if it had branched
+// through the chain. Lines & branches of the skipped comparisons are
+// recorded as covered, however, they are not passed to the tracer.
+func (e *Executor) executeIfInstrSwitch(state *ExecutionState, instr *ssa.If) (bool, error) {
+	chain := newSwitchChain(instr)
+	if chain == nil {
+		return false, nil
+	}
+	x := state.MustEvalAsExpr(chain.x)
+	if _, ok := x.(*ConstantExpr); ok {
+		return false, nil
+	}
+
+	// Distinct constants are exclusive so each case only requires equality.
+	// Duplicate constants can never match after the first so are skipped.
+	n := len(chain.cases)
+	conds := make([]Expr, n+1)
+	seen := make(map[string]struct{})
+	var dflt Expr = NewBoolConstantExpr(true)
+	for i, binop := range chain.cases {
+		key := binop.Y.(*ssa.Const).Value.ExactString()
+		if _, ok := seen[key]; ok {
+			conds[i] = NewBoolConstantExpr(false)
+			continue
+		}
+		seen[key] = struct{}{}
+
+		y := state.MustEvalAsExpr(binop.Y)
+		conds[i] = NewBinaryExpr(EQ, x, y)
+		dflt = NewBinaryExpr(AND, dflt, NewBinaryExpr(NE, x, y))
+	}
+	conds[n] = dflt
+
+	for i, cond := range conds {
+		newState, err := forkIfSatisfiable(state, cond)
+		if err != nil {
+			return true, err
+		} else if newState == nil {
+			continue
+		}
+		e.infof("[fork] switch case %d of %d", i, n)
+
+		// Record coverage of each comparison the path passes through.
+		for j := 0; j < n && j <= i; j++ {
+			if j > 0 {
+				for _, instr := range chain.ifs[j].Block().Instrs {
+					e.recordCoverage(newState, instr)
+				}
+			}
+			if j == i {
+				e.branchCoverage(chain.ifs[j]).True = true
+			} else {
+				e.branchCoverage(chain.ifs[j]).False = true
+			}
+		}
+
+		// Branch from the block of the case so phi nodes see the same edge.
+		if i < n {
+			newState.Frame().jumpFrom(chain.ifs[i].Block(), chain.ifs[i].Block().Succs[0])
+		} else {
+			newState.Frame().jumpFrom(chain.ifs[n-1].Block(), chain.ifs[n-1].Block().Succs[1])
+		}
+		e.addState(newState)
+	}
+	return true, nil
+}
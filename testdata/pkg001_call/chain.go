@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func chain() {
+	x := glee.Int()
+	x = chainA(x)
+	x = chainA(x)
+	if x == 11 {
+		return
+	}
+}
+
+func chainA(x int) int {
+	return chainB(x) + 1
+}
+
+func chainB(x int) int {
+	return x * 2
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func makeT(b int) T {
+	return T{A: 1, B: b, C: 3}
+}
+
+func fieldValue() {
+	if makeT(glee.Int()).B == 9 {
+		return
+	}
+}
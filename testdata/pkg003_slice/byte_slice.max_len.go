@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func byteSliceMaxLen() {
+	b := glee.ByteSliceMaxLen(3)
+	if len(b) == 3 && b[2] == 'z' {
+		return
+	}
+}
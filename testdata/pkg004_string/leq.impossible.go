@@ -7,9 +7,9 @@ import (
 func leqImpossible() {
 	a := glee.String(3)
 	b := glee.String(3)
-	glee.Assert(a[0] == b[0])
-	glee.Assert(a[1] > b[1]) // invalidate leq
-	glee.Assert(a[2] < b[2])
+	glee.Assume(a[0] == b[0])
+	glee.Assume(a[1] > b[1]) // invalidate leq
+	glee.Assume(a[2] < b[2])
 
 	if a <= b {
 		return
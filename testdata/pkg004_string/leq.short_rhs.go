@@ -7,8 +7,8 @@ import (
 func leqShortRHS() {
 	a := glee.String(3)
 	b := glee.String(2)
-	glee.Assert(a[0] == b[0])
-	glee.Assert(a[1] == b[1])
+	glee.Assume(a[0] == b[0])
+	glee.Assume(a[1] == b[1])
 
 	if a <= b {
 		return
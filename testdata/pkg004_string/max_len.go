@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func stringMaxLen() {
+	s := glee.StringMaxLen(4)
+	if s == "ab" {
+		return
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func makeArray(x int) [3]int {
+	return [3]int{1, x, 3}
+}
+
+func indexValue() {
+	i := glee.Int()
+	if i >= 0 && i < 3 && makeArray(4)[i] == 4 {
+		return
+	}
+}
+
+func indexValueOutOfRange() int {
+	i := glee.Int()
+	return makeArray(4)[i]
+}
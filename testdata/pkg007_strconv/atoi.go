@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/benbjohnson/glee"
+)
+
+func atoiNegative() {
+	s := glee.String(3)
+	x, err := strconv.Atoi(s)
+	if err != nil {
+		return
+	}
+	if x == -12 {
+		return
+	}
+}
+
+func atoiLong() {
+	s := glee.String(20)
+	x, err := strconv.Atoi(s)
+	if err == nil {
+		if s[:1] == "0" {
+			return
+		}
+		return
+	}
+
+	if e, ok := err.(*strconv.NumError); !ok {
+		panic("expected *strconv.NumError")
+	} else if e.Func != "Atoi" {
+		panic("unexpected function name")
+	}
+	if x == math.MaxInt64 {
+		return
+	}
+}
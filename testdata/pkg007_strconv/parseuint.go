@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/benbjohnson/glee"
+)
+
+func parseUint8() {
+	s := glee.String(3)
+	x, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		if x == 255 {
+			return
+		}
+		return
+	}
+	if x == 255 {
+		return
+	}
+}
+
+func parseUint32() {
+	s := glee.String(10)
+	x, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		if x == 4294967295 {
+			return
+		}
+		return
+	}
+	if x == 4294967295 {
+		return
+	}
+}
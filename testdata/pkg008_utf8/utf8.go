@@ -0,0 +1,43 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	"github.com/benbjohnson/glee"
+)
+
+func decodeRuneInString() {
+	s := glee.String(2)
+	r, size := utf8.DecodeRuneInString(s)
+	if r == 'é' && size == 2 {
+		return
+	}
+}
+
+func rangeString() {
+	s := glee.String(3)
+	var n int
+	for _, r := range s {
+		if r == '☃' {
+			n++
+		}
+	}
+	if n == 1 {
+		return
+	}
+}
+
+func runeToString() {
+	r := glee.Int32()
+	if string(r) == "ß" {
+		return
+	}
+}
+
+func runeSliceToString() {
+	r := glee.Int32()
+	s := string([]rune{'a', r})
+	if s == "añ" {
+		return
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func scale() {
+	x := glee.Int32()
+	f := float64(x) * 0.5
+	if f == 10.5 {
+		return
+	}
+}
+
+func negate() {
+	x := glee.Int32()
+	f := -float64(x)
+	if f == 3 {
+		return
+	}
+}
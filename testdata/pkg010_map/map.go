@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func lookup() {
+	k := glee.Int()
+	m := map[int]int{10: 100, 20: 200}
+	if v, ok := m[k]; ok && v == 200 {
+		return
+	}
+}
+
+func update() {
+	k1, k2 := glee.Int(), glee.Int()
+	m := make(map[int]int)
+	m[k1] = 1
+	m[k2] = 2
+	if len(m) == 1 {
+		return
+	}
+}
+
+func rangeSum() {
+	x := glee.Int()
+	m := map[string]int{"a": 1, "b": x}
+	var sum int
+	for _, v := range m {
+		sum += v
+	}
+	if sum == 10 {
+		return
+	}
+}
+
+type node struct{ n int }
+
+func missingPointer() {
+	k := glee.Int()
+	m := map[int]*node{1: {n: 1}}
+	if p := m[k]; p == nil {
+		return
+	}
+}
+
+type MyInt int
+
+func missingNamed() {
+	k := glee.Int()
+	m := map[int]MyInt{1: 10}
+	if v := m[k]; v == 0 {
+		return
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func panics() {
+	x := glee.Int()
+	if x == 10 {
+		panic("boom")
+	}
+}
+
+func recovers() (n int) {
+	defer handle()
+	x := glee.Int()
+	if x == 10 {
+		panic("boom")
+	}
+	return x
+}
+
+func handle() {
+	recover()
+}
+
+func recoversDivide() int {
+	defer handle()
+	x := glee.Int()
+	return 10 / x
+}
+
+func recoversIndex() int {
+	defer handle()
+	a := []int{1, 2, 3}
+	return a[glee.Int()]
+}
+
+func recoversNilMap() {
+	defer handle()
+	var m map[int]int
+	if glee.Int() == 10 {
+		m = make(map[int]int)
+	}
+	m[0] = 1
+}
+
+// recoversCaller recovers a run-time panic from a called function.
+func recoversCaller() (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	recoversDivide()
+	divide(glee.Int())
+	return false
+}
+
+func divide(x int) int {
+	return 10 / x
+}
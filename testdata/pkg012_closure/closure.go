@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func capture() {
+	x := glee.Int()
+	base := 10
+	add := func(n int) int { return base + n }
+	if apply(add, x) == 15 {
+		return
+	}
+}
+
+func apply(fn func(int) int, v int) int {
+	return fn(v)
+}
+
+func returned() {
+	x := glee.Int()
+	next := counter()
+	next()
+	if next() == x {
+		return
+	}
+}
+
+func counter() func() int {
+	var n int
+	return func() int {
+		n++
+		return n
+	}
+}
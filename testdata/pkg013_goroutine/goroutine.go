@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func interleave() {
+	v := glee.Int()
+	p := new(int)
+	go func() {
+		*p = 1
+	}()
+	if *p == v {
+		return
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func buffered() {
+	x := glee.Int()
+	ch := make(chan int, 1)
+	ch <- x
+	if <-ch == 5 {
+		return
+	}
+}
+
+func unbuffered() {
+	x := glee.Int()
+	ch := make(chan int)
+	go send(ch, x)
+	if <-ch == 5 {
+		return
+	}
+}
+
+func send(ch chan int, v int) {
+	ch <- v + 1
+}
+
+func deadlock() {
+	ch := make(chan int)
+	ch <- 1
+}
+
+func selectReady() {
+	x := glee.Int()
+	a, b := make(chan int, 1), make(chan int, 1)
+	a <- 1
+	b <- 2
+
+	var v int
+	select {
+	case v = <-a:
+	case v = <-b:
+	}
+	if v == x {
+		return
+	}
+}
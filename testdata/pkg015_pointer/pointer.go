@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func storeIndex() {
+	i := glee.Int()
+	if i < 0 || i >= 4 {
+		return
+	}
+
+	var a [4]int
+	a[i] = 10
+	if a[2] == 10 {
+		return
+	}
+}
+
+func storePointer() {
+	i := glee.Int()
+	if i < 0 || i >= 2 {
+		return
+	}
+
+	x, y := new(int), new(int)
+	ps := []*int{x, y}
+	*ps[i] = 5
+	if *x == 5 {
+		return
+	}
+}
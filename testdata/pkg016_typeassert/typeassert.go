@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func commaOk() {
+	x := glee.Int()
+	var v interface{} = x
+	if n, ok := v.(int); ok && n == 7 {
+		return
+	}
+}
+
+func typeSwitch() {
+	x := glee.Int()
+	var v interface{} = int8(1)
+	if x > 10 {
+		v = uint16(2)
+	}
+
+	switch v.(type) {
+	case int8:
+	case uint16:
+		return
+	}
+}
+
+func mustAssert() {
+	var v interface{} = int8(1)
+	_ = v.(uint16)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func divide() {
+	x, y := glee.Int(), glee.Int()
+	if x/y == 3 {
+		return
+	}
+}
+
+func overflow() {
+	x := glee.Int8()
+	y := x + 100
+	if y < 0 {
+		return
+	}
+}
+
+func divideOverflow() {
+	x, y := glee.Int8(), glee.Int8()
+	if y == 0 {
+		return
+	}
+	if x/y < 0 {
+		return
+	}
+}
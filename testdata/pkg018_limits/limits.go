@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func loop() {
+	x := glee.Int()
+	for i := 0; i < x; i++ {
+	}
+}
+
+func spin() {
+	for {
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func capSlice() {
+	a := make([]int, 2, 5)
+	if cap(a) != 5 || cap(a[1:]) != 4 || cap(a[:1:3]) != 3 {
+		panic("unexpected cap")
+	}
+}
+
+func capChan() {
+	ch := make(chan int, 3)
+	if cap(ch) != 3 {
+		panic("unexpected cap")
+	}
+}
+
+func newInt() {
+	p := new(int)
+	*p = glee.Int()
+	if *p == 12 {
+		return
+	}
+}
+
+func deleteKey() {
+	m := map[int]int{1: 10, 2: 20}
+	delete(m, glee.Int())
+	if len(m) == 1 {
+		return
+	}
+}
+
+func minInt() {
+	x, y := glee.Int(), glee.Int()
+	if min(x, y, 10) == 10 {
+		return
+	}
+}
+
+func maxUint() {
+	x := glee.Uint8()
+	if max(x, 200) == 200 {
+		return
+	}
+}
+
+func minFloat() {
+	x := float64(glee.Int())
+	if min(x, 1.5) != 1.5 {
+		return
+	}
+}
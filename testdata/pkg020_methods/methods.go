@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+type Shape interface {
+	Area() int
+}
+
+type Rect struct {
+	W, H int
+}
+
+func (r *Rect) Area() int { return r.W * r.H }
+
+type Square struct {
+	*Rect
+}
+
+type Label string
+
+func (l Label) Area() int { return len(l) }
+
+func pointerMethod() {
+	r := &Rect{W: glee.Int(), H: 2}
+	if r.Area() == 10 {
+		return
+	}
+}
+
+func methodValue() {
+	r := &Rect{W: 3, H: glee.Int()}
+	area := r.Area
+	if area() == 12 {
+		return
+	}
+}
+
+func methodExpr() {
+	r := &Rect{W: glee.Int(), H: 4}
+	if (*Rect).Area(r) == 8 {
+		return
+	}
+}
+
+func promoted() {
+	s := Square{&Rect{W: glee.Int(), H: 5}}
+	var shape Shape = &s
+	if shape.Area() == 15 {
+		return
+	}
+}
+
+func boxedReceiver() {
+	var shape Shape = Label("foo")
+	if shape.Area() != 3 {
+		panic("unexpected area")
+	}
+	if l, ok := shape.(Label); !ok || l != "foo" {
+		panic("unexpected label")
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func assume() {
+	x := glee.Int()
+	glee.Assume(x > 10)
+	glee.Assume(x < 5)
+}
+
+func assert() {
+	x := glee.Int()
+	glee.Assume(x > 10)
+	glee.Assert(x*2 != 30)
+}
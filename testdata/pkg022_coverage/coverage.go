@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func branch() int {
+	x := glee.Int()
+	if x > 10 {
+		return 1
+	}
+	return 0
+}
+
+func deadBranch() int {
+	x := glee.Uint8()
+	if x > 255 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func magic() int {
+	x := glee.Int32()
+	if x == 0x12345678 {
+		panic("found")
+	} else if x < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func symbolicLen() int {
+	n := glee.Int()
+	if n > 3 {
+		s := make([]byte, n)
+		s[3] = 1
+		return len(s)
+	}
+	return 0
+}
+
+func negativeLen() int {
+	n := glee.Int()
+	s := make([]int, n)
+	return len(s)
+}
+
+func symbolicByteSlice() int {
+	b := glee.ByteSlice(glee.Int())
+	if len(b) > 2 && b[2] == 'x' {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func constantIndex() byte {
+	s := make([]byte, 4)
+	t := s[:8]
+	return t[6]
+}
+
+func symbolicIndex() byte {
+	s := make([]byte, 4)
+	t := s[:8]
+	i := glee.Int()
+	if i < 0 || i >= len(t) {
+		return 0
+	}
+	return t[i]
+}
+
+func symbolicStore() {
+	s := make([]byte, 4)
+	t := s[:8]
+	i := glee.Int()
+	if i >= 0 && i < 6 {
+		t[i] = 1
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func price() int {
+	price := glee.Int()
+	glee.Name("price", price)
+
+	code := glee.String(2)
+	glee.Name("code", code)
+
+	qty := glee.Byte()
+	glee.Name("qty", qty)
+
+	if price > 100 && code == "ab" {
+		return price
+	}
+	return 0
+}
+
+func duplicate() {
+	glee.Name("x", glee.Int())
+	glee.Name("x", glee.Int())
+}
+
+func concrete() {
+	glee.Name("x", 1)
+}
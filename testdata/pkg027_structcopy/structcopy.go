@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+type inner struct {
+	a byte
+	b int32
+	c int64
+}
+
+type outer struct {
+	x  byte
+	p  *inner
+	in inner
+	y  int16
+}
+
+// copyNested copies a struct containing a nested struct & a nil pointer.
+func copyNested() int {
+	var o outer
+	o.x = glee.Byte()
+	o.in.a = 1
+	o.in.b = glee.Int32()
+	o.in.c = 3
+	o.y = 7
+
+	d := new(outer)
+	*d = o
+	d.p = nil
+
+	if d.in.a != 1 || d.in.c != 3 || d.y != 7 {
+		panic("corrupt")
+	}
+	if d.in.b == 5 {
+		return int(d.x)
+	}
+	return 0
+}
+
+// copyArray copies an array of structs with padding between fields.
+func copyArray() int {
+	var a [2]inner
+	a[0].a, a[0].b = glee.Byte(), 2
+	a[1].a, a[1].c = 4, glee.Int64()
+
+	b := new([2]inner)
+	*b = a
+
+	if b[0].b != 2 || b[1].a != 4 {
+		panic("corrupt")
+	}
+	if b[1].c > 10 {
+		return 1
+	}
+	return 0
+}
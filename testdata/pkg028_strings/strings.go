@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/benbjohnson/glee"
+)
+
+func contains() int {
+	s := glee.String(8)
+	if strings.Contains(s, "ab") {
+		return 1
+	}
+	return 0
+}
+
+func hasPrefix() int {
+	s := glee.String(8)
+	if strings.HasPrefix(s, "GET ") {
+		return 1
+	}
+	return 0
+}
+
+func index() int {
+	s := glee.String(3)
+	return strings.Index(s, "a")
+}
+
+func bytesEqual() int {
+	b := glee.ByteSlice(2)
+	if bytes.Equal(b, []byte("hi")) {
+		return 1
+	}
+	return 0
+}
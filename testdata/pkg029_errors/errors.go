@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/benbjohnson/glee"
+)
+
+func check(n int) error {
+	if n < 0 {
+		return errors.New("negative")
+	} else if n > 10 {
+		return fmt.Errorf("too large: %d", n)
+	}
+	return nil
+}
+
+// branch forks on each error returned by check().
+func branch() int {
+	n := glee.Int()
+	err := check(n)
+	if err != nil {
+		if err.Error() == "negative" {
+			glee.Assert(n < 0)
+			return 1
+		}
+		glee.Assert(n > 10)
+		return 2
+	}
+	glee.Assert(n >= 0 && n <= 10)
+	return 0
+}
+
+// identity compares errors with the same message.
+func identity() {
+	a, b := errors.New("x"), errors.New("x")
+	glee.Assert(a == a)
+	glee.Assert(a != b)
+	glee.Assert(a != nil)
+}
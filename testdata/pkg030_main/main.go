@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/benbjohnson/glee"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "verbose")
+	flag.Parse()
+
+	glee.Assert(flag.NArg() == 2)
+	glee.Assert(len(os.Args) == 3)
+
+	n := 0
+	if flag.Arg(0) == "ab" {
+		n++
+	}
+	if os.Args[2] == "cd" {
+		n++
+	}
+	if *verbose {
+		n++
+	}
+	if os.Getenv("HOME") == "" {
+		n++
+	}
+	_ = n
+}
+
+// defaults runs without symbolic arguments.
+func defaults() {
+	name := flag.String("name", "glee", "name")
+	flag.Parse()
+
+	glee.Assert(*name == "glee")
+	glee.Assert(flag.NArg() == 0)
+	glee.Assert(os.Getenv("HOME") == "")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	} else if n > 0 {
+		return 1
+	}
+	return 0
+}
+
+func div(a, b int) int {
+	return a / b
+}
+
+// repeated calls the same pure function on different arguments.
+func repeated() int {
+	a, b := glee.Int(), glee.Int()
+	if abs(a) == abs(b) && a != b {
+		glee.Assert(a == -b)
+		return 1
+	}
+	return 0
+}
+
+// nested calls pure functions with the results of other pure functions.
+func nested() int {
+	n := glee.Int()
+	glee.Assume(n > -100 && n < 100)
+	s := sign(abs(n))
+	glee.Assert(s >= 0)
+	glee.Assert((s == 0) == (n == 0))
+	return s
+}
+
+// fallback calls a function which may panic so it cannot be summarized.
+func fallback() int {
+	return div(10, glee.Int())
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+// external is implemented in assembly so its body is unavailable.
+func external(n int) int
+
+// body calls a function without a body.
+func body() int {
+	if external(glee.Int()) > 10 {
+		return 1
+	}
+	return 0
+}
+
+func flip(n int) int {
+	return ^n
+}
+
+// unsupported calls a function which uses an unsupported operator.
+func unsupported() int {
+	if flip(glee.Int()) > 10 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1 @@
+// Allows functions in this package to be declared without a body.
@@ -0,0 +1,28 @@
+package main
+
+type A struct{}
+
+func (A) Fail() { panic("a") }
+
+type B struct{}
+
+func (*B) Fail() { panic("b") }
+
+// callA calls Fail() on A.
+func callA() int {
+	A{}.Fail()
+	return 1
+}
+
+// callB calls Fail() on *B.
+func callB() int {
+	(&B{}).Fail()
+	return 2
+}
+
+// callIface calls Fail() on A through an interface.
+func callIface() int {
+	var v interface{ Fail() } = A{}
+	v.Fail()
+	return 3
+}
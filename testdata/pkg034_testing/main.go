@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/glee"
+)
+
+// TestRange fails differently depending on the range of a symbolic value.
+func TestRange(t *testing.T) {
+	t.Helper()
+
+	n := glee.Int()
+	if n < 0 {
+		t.Skip("negative")
+	}
+	if n > 10 {
+		t.Errorf("too large: %d", n)
+	}
+	if n > 20 {
+		t.Fatal("much too large")
+	}
+	t.Logf("n=%d", n)
+}
+
+// TestFailed reports whether a previous error failed the test.
+func TestFailed(t *testing.T) {
+	t.Error("failed")
+	if !t.Failed() {
+		panic("unreachable")
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/benbjohnson/glee"
+)
+
+// printing writes a symbolic value with each print function.
+func printing() int {
+	n := glee.Int()
+	println("n:", n)
+	print(n, "\n")
+	fmt.Print(n)
+	fmt.Printf("n=%d\n", n)
+	if _, err := fmt.Println("n", n); err != nil {
+		panic(err)
+	}
+
+	if n > 0 {
+		return 1
+	}
+	return 0
+}
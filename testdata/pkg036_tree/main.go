@@ -0,0 +1,12 @@
+package main
+
+func main() {}
+
+func classify(x int) int {
+	if x < 0 {
+		return -1
+	} else if x == 0 {
+		panic("zero")
+	}
+	return 1
+}
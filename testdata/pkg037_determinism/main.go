@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+type T struct{ n int }
+
+func (t *T) Get() int { return t.n }
+
+type Getter interface{ Get() int }
+
+func inc(n int) int { return n + 1 }
+func dec(n int) int { return n - 1 }
+
+// allocating allocates heap values, closures & function values along each path.
+func allocating() int {
+	buf := glee.ByteSlice(4)
+	t := &T{n: int(buf[0])}
+
+	var g Getter = t
+	f := inc
+	if buf[1] == 'x' {
+		f = dec
+	}
+	add := func(n int) int { return n + t.n }
+
+	if f(g.Get()) > add(10) {
+		return 1
+	}
+	return 0
+}
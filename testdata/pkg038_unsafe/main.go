@@ -0,0 +1,53 @@
+package main
+
+import (
+	"unsafe"
+
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// index reads the i-th byte of an array using pointer arithmetic.
+func index() byte {
+	i := glee.Int()
+	buf := [4]byte{1, 2, 3, 4}
+	p := unsafe.Pointer(uintptr(unsafe.Pointer(&buf[0])) + uintptr(i))
+	return *(*byte)(p)
+}
+
+// roundtrip converts a pointer to a uintptr & back without arithmetic.
+func roundtrip() int {
+	n := glee.Int()
+	p := &n
+	q := (*int)(unsafe.Pointer(uintptr(unsafe.Pointer(p))))
+	glee.Assert(p == q)
+	return *q
+}
+
+// compare selects between two pointers & compares them against each other
+// & against nil.
+func compare() int {
+	x, y := new(int), new(int)
+	var p *int
+	switch glee.Int() {
+	case 0:
+		p = x
+	case 1:
+		p = y
+	}
+
+	if p == nil {
+		return 0
+	} else if p == x {
+		return 1
+	} else if p != y {
+		panic("unreachable")
+	}
+	return 2
+}
+
+// invalid converts an integer which is not the address of any allocation.
+func invalid() {
+	_ = unsafe.Pointer(uintptr(glee.Uint8()) + 1)
+}
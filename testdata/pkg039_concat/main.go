@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// build concatenates symbolic strings in a loop & branches on the result.
+func build() int {
+	s := ""
+	for i := 0; i < 256; i++ {
+		s += glee.String(1) + "-"
+	}
+	if len(s) != 512 {
+		panic("unexpected length")
+	}
+
+	if s[510] == 'x' && s[511] == '-' {
+		return 1
+	}
+	return 0
+}
+
+// compare concatenates strings & compares the result with a constant.
+func compare() int {
+	s := "a" + glee.String(1)
+	s = s + "c"
+	if s == "abc" {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+type point struct {
+	x, y int
+}
+
+type shape interface {
+	area() int
+}
+
+type rect struct {
+	w, h int
+}
+
+func (r rect) area() int { return r.w * r.h }
+
+// pointers returns heap pointers from a function which forks before returning.
+func pointers() int {
+	p, q := newPair(glee.Int())
+	glee.Assert(p != q)
+	return *p + *q
+}
+
+func newPair(n int) (*int, *int) {
+	p, q := new(int), new(int)
+	if n > 0 {
+		*p, *q = n, -n
+	} else {
+		*p, *q = -n, n
+	}
+	return p, q
+}
+
+// interfaces returns an interface & an error from a function which forks.
+func interfaces() int {
+	s, err := newShape(glee.Int())
+	if err != nil {
+		return -1
+	}
+	return s.area()
+}
+
+func newShape(n int) (shape, error) {
+	if n < 0 {
+		return nil, &shapeError{n: n}
+	}
+	return rect{w: n, h: 2}, nil
+}
+
+type shapeError struct{ n int }
+
+func (e *shapeError) Error() string { return "invalid shape" }
+
+// structs returns struct values from a function which forks.
+func structs() int {
+	a, b, ok := split(glee.Int())
+	if !ok {
+		return 0
+	}
+	glee.Assert(a.x+b.x == a.y+b.y)
+	return a.x
+}
+
+func split(n int) (point, point, bool) {
+	if n < 0 || n > 100 {
+		return point{}, point{}, false
+	}
+	return point{x: n, y: 0}, point{x: 0, y: n}, true
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// complement executes an unsupported bitwise complement on only one branch.
+func complement() int {
+	if x := glee.Int(); x > 0 {
+		return ^x
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// alloc allocates a large buffer on only one branch.
+func alloc() []byte {
+	if glee.Int() > 0 {
+		return make([]byte, 4096)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// classify switches over a symbolic value with eight cases & a default.
+func classify() int {
+	var n int
+	switch glee.Int() {
+	case 1:
+		n = 10
+	case 2:
+		n = 20
+	case 3:
+		n = 30
+	case 4:
+		n = 40
+	case 5, 6:
+		n = 50
+	case 7:
+		n = 70
+	case 8:
+		n = 80
+	default:
+		n = -1
+	}
+	return n
+}
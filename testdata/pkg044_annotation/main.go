@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// skip branches on the result of a function which is not executed.
+func skip() int {
+	if expensive(glee.Int()) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// expensive forks a state for every iteration of its loop.
+//
+//glee:skip
+func expensive(n int) int {
+	var sum int
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	return sum
+}
+
+// limited calls a function which forks on each bit of its argument.
+func limited() int {
+	return bits(glee.Int())
+}
+
+// bits returns the number of set bits in the low byte of n.
+//
+//glee:maxpaths=4
+func bits(n int) int {
+	var c int
+	for i := uint(0); i < 8; i++ {
+		if n&(1<<i) != 0 {
+			c++
+		}
+	}
+	return c
+}
+
+// invalid calls a function with an invalid annotation.
+func invalid() int {
+	return bad(glee.Int())
+}
+
+//glee:maxpaths=none
+func bad(n int) int {
+	return n
+}
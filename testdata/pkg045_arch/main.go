@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// wrap asserts an int cannot wrap which only holds on 64-bit targets.
+func wrap() {
+	if x := glee.Int(); x == 1<<31-1 {
+		glee.Assert(x+1 > 0)
+	}
+}
+
+// length takes the length of a string & slices a slice by a symbolic index of
+// a narrow type.
+func length() int {
+	s := glee.String(4)
+	a := []byte(s)
+	i := glee.Uint8()
+	if int(i) > len(s) {
+		return -1
+	}
+	return len(s) + len(a[i:]) + len(a[:i]) + int(a[i%4])
+}
+
+// header builds slice headers by conversion & by make. The length is not a
+// constant so the slice is not built from a new array.
+func header() int {
+	a := []byte(glee.String(2))
+	b := make([]int, two())
+	return len(a) + len(b)
+}
+
+func two() int { return 2 }
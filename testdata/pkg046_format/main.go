@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/benbjohnson/glee"
+)
+
+func main() {}
+
+// compare branches on a parameter & an intrinsic value.
+func compare(x int) int {
+	if y := glee.Int(); x < y {
+		return 1
+	}
+	return 0
+}
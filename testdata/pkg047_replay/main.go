@@ -0,0 +1,18 @@
+package main
+
+func main() {}
+
+// classify branches several times on its parameters.
+func classify(x, y int) int {
+	n := 0
+	if x > 10 {
+		n++
+	}
+	if y > 10 {
+		n++
+	}
+	if x == y {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,13 @@
+package main
+
+func main() {}
+
+// sign branches twice on its parameter.
+func sign(x int) int {
+	if x < 0 {
+		return -1
+	} else if x > 0 {
+		return 1
+	}
+	return 0
+}
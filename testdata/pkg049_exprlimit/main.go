@@ -0,0 +1,15 @@
+package main
+
+func main() {}
+
+// hash combines every byte of s so the depth of h grows with each iteration.
+func hash(s string) int {
+	var h uint32
+	for i := 0; i < len(s); i++ {
+		h = h*31 + uint32(s[i])
+	}
+	if h == 1000 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,13 @@
+package main
+
+func main() {}
+
+func update(x int) []int {
+	a := make([]int, 4)
+	if x > 0 {
+		a[1] = x
+	} else {
+		a[2] = -1
+	}
+	return a
+}
@@ -0,0 +1,17 @@
+package main
+
+func main() {}
+
+func double(x int) int { return x * 2 }
+func negate(x int) int { return -x }
+
+func apply(i, x int) int {
+	var ops [4]func(int) int
+	ops[0], ops[1] = double, negate
+	ops[2] = func(y int) int { return x + y }
+
+	if i < 0 || i >= len(ops) {
+		return 0
+	}
+	return ops[i](x)
+}
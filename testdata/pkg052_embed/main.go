@@ -0,0 +1,86 @@
+package main
+
+func main() {}
+
+type Shape interface {
+	Area() int
+}
+
+type Named interface {
+	Shape
+	Name() string
+}
+
+type Square struct{ side int }
+
+func (s Square) Area() int { return s.side * s.side }
+
+type Rect struct{ w, h int }
+
+func (r *Rect) Area() int { return r.w * r.h }
+
+// Tile promotes Area() from its embedded Square.
+type Tile struct {
+	Square
+	color int
+}
+
+func (t Tile) Name() string { return "tile" }
+
+// Frame promotes the pointer method Area() from its embedded *Rect.
+type Frame struct {
+	*Rect
+}
+
+// promoted calls a value method promoted from an embedded struct.
+func promoted(x int) int {
+	var s Shape = Tile{Square: Square{side: x}}
+	if s.Area() == 49 {
+		return 1
+	}
+	return 0
+}
+
+// promotedPointer calls a pointer method promoted from an embedded pointer.
+func promotedPointer(x int) int {
+	var s Shape = Frame{&Rect{w: x, h: 2}}
+	if s.Area() == 10 {
+		return 1
+	}
+	return 0
+}
+
+// pointerReceiver calls a value method through a pointer.
+func pointerReceiver(x int) int {
+	var s Shape = &Square{side: x}
+	if s.Area() == 9 {
+		return 1
+	}
+	return 0
+}
+
+// nilPointerReceiver calls a value method through a nil pointer.
+func nilPointerReceiver() int {
+	var sq *Square
+	var s Shape = sq
+	return s.Area()
+}
+
+// embeddedInterface calls methods of an interface embedding another.
+func embeddedInterface(x int) int {
+	var n Named = Tile{Square: Square{side: x}}
+	var s Shape = n
+	if s.Area()+len(n.Name()) == 8 {
+		return 1
+	}
+	return 0
+}
+
+// dispatch calls Area() on an interface selected by a symbolic index.
+func dispatch(i, x int) int {
+	shapes := []Shape{Square{side: x}, &Rect{w: x, h: 2}, Tile{Square: Square{side: 1}}, nil}
+	if i < 0 || i >= len(shapes) {
+		return -1
+	}
+	return shapes[i].Area()
+}
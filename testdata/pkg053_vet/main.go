@@ -0,0 +1,35 @@
+package main
+
+func main() {}
+
+type Toggle interface {
+	On() bool
+}
+
+type flag struct{ b bool }
+
+// On is reached through an interface.
+func (f flag) On() bool { return !f.b }
+
+// negate is reached through a static call.
+func negate(x int) int { return -x }
+
+// skipped is not followed as it is annotated.
+//
+//glee:skip
+func skipped(x int) int { return -x }
+
+// unreachable is never called.
+func unreachable(x int) int { return -x }
+
+// check reaches unsupported instructions through a static call, a closure &
+// an interface method.
+func check(x int) int {
+	invert := func(y int) int { return ^y }
+
+	var t Toggle = flag{b: x > 0}
+	if t.On() {
+		return negate(x) + skipped(x)
+	}
+	return invert(x)
+}
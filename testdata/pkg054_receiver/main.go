@@ -0,0 +1,20 @@
+package main
+
+type Account struct {
+	Balance int64
+	Frozen  bool
+	Limit   int32
+}
+
+// Withdraw removes amount from the balance of an account which is not frozen.
+func (a *Account) Withdraw(amount int64) bool {
+	if a.Frozen {
+		return false
+	} else if amount > int64(a.Limit) {
+		panic("over limit")
+	}
+	a.Balance -= amount
+	return true
+}
+
+func main() {}
@@ -0,0 +1,19 @@
+package main
+
+// classify returns a bitmask of conditions on a & b. Six paths are feasible as
+// a & b cannot be equal when only one of them is greater than ten.
+func classify(a, b int) int {
+	r := 0
+	if a > 10 {
+		r += 1
+	}
+	if b > 10 {
+		r += 2
+	}
+	if a == b {
+		r += 4
+	}
+	return r
+}
+
+func main() {}
@@ -0,0 +1,40 @@
+package main
+
+// offset converts a subslice at a symbolic offset into its backing array.
+func offset(i int) {
+	buf := []byte("abcd")
+	if i < 0 || i > 2 {
+		return
+	}
+
+	if string(buf[i:i+2]) == "cd" {
+		if i != 2 {
+			panic("unexpected offset")
+		}
+		panic("found")
+	}
+}
+
+// length converts a subslice with a symbolic length.
+func length(n int) {
+	buf := []byte("abcd")
+	if n < 0 || n > 4 {
+		return
+	}
+
+	if string(buf[:n]) == "ab" {
+		if n != 2 {
+			panic("unexpected length")
+		}
+		panic("found")
+	}
+}
+
+// content converts a subslice which does not span its symbolic backing array.
+func content(b []byte) {
+	if string(b[1:3]) == "hi" {
+		panic("found")
+	}
+}
+
+func main() {}
@@ -0,0 +1,229 @@
+package testgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+// CorpusEntry represents the solved inputs of a single path through a target
+// function. A corpus is a directory of entries stored as JSON at:
+//
+//	<dir>/<function>/<path hash>.json
+//
+// Entries are named by the hash of their path so exploring a function again
+// replaces the entries of paths which were already found.
+type CorpusEntry struct {
+	// Import path & name of the package of the target function.
+	Package     string `json:"package"`
+	PackageName string `json:"packageName"`
+
+	// Name of the target function & whether it is variadic.
+	Function string `json:"function"`
+	Variadic bool   `json:"variadic,omitempty"`
+
+	// Go source literal & solved bytes of each argument.
+	Args   []string `json:"args"`
+	Inputs [][]byte `json:"inputs"`
+
+	// Status & reason of the terminal state the inputs reach.
+	Status glee.ExecutionStatus `json:"status"`
+	Reason string               `json:"reason,omitempty"`
+
+	// Hash of the path's constraints. See PathHash().
+	PathHash string `json:"pathHash"`
+
+	// Lines executed along the path, by filename.
+	Coverage map[string][]int `json:"coverage"`
+}
+
+// NewCorpusEntry returns a corpus entry for a test case of fn.
+func NewCorpusEntry(fn *ssa.Function, tc *TestCase) *CorpusEntry {
+	return &CorpusEntry{
+		Package:     fn.Pkg.Pkg.Path(),
+		PackageName: fn.Pkg.Pkg.Name(),
+		Function:    fn.Name(),
+		Variadic:    fn.Signature.Variadic(),
+		Args:        tc.Args,
+		Inputs:      tc.Inputs,
+		Status:      tc.Status,
+		Reason:      tc.Reason,
+		PathHash:    tc.PathHash,
+		Coverage:    tc.Coverage,
+	}
+}
+
+// Failed returns true if the path does not finish normally.
+func (entry *CorpusEntry) Failed() bool {
+	return entry.Status != glee.ExecutionStatusFinished
+}
+
+// PathHash returns a hash identifying the path through fn with constraints.
+func PathHash(fn *ssa.Function, constraints []glee.Expr) string {
+	h := sha256.New()
+	fmt.Fprintln(h, fn.String())
+	for _, c := range constraints {
+		fmt.Fprintln(h, c.String())
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// WriteCorpusEntry writes entry to the corpus in dir. Returns the path of
+// the entry's file.
+func WriteCorpusEntry(dir string, entry *CorpusEntry) (string, error) {
+	if entry.Function == "" || entry.PathHash == "" {
+		return "", fmt.Errorf("testgen: corpus entry requires function & path hash")
+	}
+
+	buf, err := json.MarshalIndent(entry, "", "\t")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, entry.Function, entry.PathHash+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return "", err
+	} else if err := ioutil.WriteFile(path, append(buf, '\n'), 0666); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ReadCorpusEntry reads a corpus entry from the file at path.
+func ReadCorpusEntry(path string) (*CorpusEntry, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CorpusEntry
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return nil, fmt.Errorf("testgen: invalid corpus entry %s: %w", path, err)
+	} else if entry.PackageName == "" || entry.Function == "" {
+		return nil, fmt.Errorf("testgen: invalid corpus entry %s: package & function required", path)
+	}
+	return &entry, nil
+}
+
+// CorpusFiles returns the paths of all entries in the corpus in dir, sorted.
+func CorpusFiles(dir string) ([]string, error) {
+	var a []string
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if !info.IsDir() && filepath.Ext(path) == ".json" {
+			a = append(a, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(a)
+	return a, nil
+}
+
+// ReplayTestName is the name of the test function in a replay file.
+const ReplayTestName = "TestGleeReplay"
+
+// FormatReplayFile returns the source of a test file in the package of the
+// entry's target which calls the target with the entry's arguments. Failures,
+// such as panics, are not recovered so they are reported by "go test".
+//
+// Paths which only fail under symbolic execution, such as a failed
+// glee.Assert(), pass when replayed.
+func FormatReplayFile(entry *CorpusEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by glee replay. DO NOT EDIT.")
+	fmt.Fprintln(&buf, "")
+	fmt.Fprintf(&buf, "package %s\n\n", entry.PackageName)
+
+	// Only import math if a non-finite float literal is used.
+	if strings.Contains(strings.Join(entry.Args, " "), "math.") {
+		fmt.Fprintln(&buf, `import "math"`)
+	}
+	fmt.Fprintln(&buf, `import "testing"`)
+	fmt.Fprintln(&buf, "")
+
+	fmt.Fprintf(&buf, "func %s(t *testing.T) {\n", ReplayTestName)
+	fmt.Fprintf(&buf, "\tt.Logf(\"replaying %s path %s: %s\")\n", entry.Function, entry.PathHash, entry.Status)
+	call := fmt.Sprintf("%s(%s)", entry.Function, strings.Join(entry.Args, ", "))
+	if entry.Variadic {
+		call = fmt.Sprintf("%s(%s...)", entry.Function, strings.Join(entry.Args, ", "))
+	}
+	fmt.Fprintf(&buf, "\t%s\n", call)
+	fmt.Fprintln(&buf, "}")
+
+	return format.Source(buf.Bytes())
+}
+
+// lineTracer records the source lines executed by each state. Children
+// inherit the lines of their parent at the point of the fork.
+type lineTracer struct {
+	m map[int][]lineRef
+}
+
+// lineRef represents a line of a source file.
+type lineRef struct {
+	filename string
+	line     int
+}
+
+// newLineTracer returns a new instance of lineTracer.
+func newLineTracer() *lineTracer {
+	return &lineTracer{m: make(map[int][]lineRef)}
+}
+
+// lines returns the sorted lines executed by state, by filename, & releases them.
+func (t *lineTracer) lines(state *glee.ExecutionState) map[string][]int {
+	refs := t.m[state.ID()]
+	delete(t.m, state.ID())
+
+	set := make(map[lineRef]struct{}, len(refs))
+	m := make(map[string][]int)
+	for _, ref := range refs {
+		if _, ok := set[ref]; ok {
+			continue
+		}
+		set[ref] = struct{}{}
+		m[ref.filename] = append(m[ref.filename], ref.line)
+	}
+	for _, a := range m {
+		sort.Ints(a)
+	}
+	return m
+}
+
+func (t *lineTracer) OnFork(parent, child *glee.ExecutionState) {
+	t.m[child.ID()] = append([]lineRef(nil), t.m[parent.ID()]...)
+}
+
+func (t *lineTracer) OnInstruction(state *glee.ExecutionState, instr ssa.Instruction) {
+	pos := state.Position()
+	if !pos.IsValid() {
+		return
+	}
+
+	// Only record a line once per consecutive run of instructions.
+	ref := lineRef{filename: pos.Filename, line: pos.Line}
+	if a := t.m[state.ID()]; len(a) > 0 && a[len(a)-1] == ref {
+		return
+	}
+	t.m[state.ID()] = append(t.m[state.ID()], ref)
+}
+
+func (t *lineTracer) OnTerminalState(state *glee.ExecutionState) {}
+
+func (t *lineTracer) OnSolverQuery(constraints []glee.Expr, satisfiable bool, d time.Duration, err error) {
+}
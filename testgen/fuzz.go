@@ -0,0 +1,156 @@
+package testgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"go/types"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// FuzzCallback returns the function passed to f.Fuzz() by the fuzz target fn,
+// such as a function literal. The callback must not capture variables so it
+// can be executed without running the target.
+func FuzzCallback(fn *ssa.Function) (*ssa.Function, error) {
+	if params := fn.Signature.Params(); params.Len() != 1 || !isTestingPointer(params.At(0).Type(), "F") {
+		return nil, fmt.Errorf("testgen: not a fuzz target: %s", fn.Name())
+	}
+
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			callee := call.Call.StaticCallee()
+			if callee == nil || callee.Name() != "Fuzz" || callee.Signature.Recv() == nil || !isTestingPointer(callee.Signature.Recv().Type(), "F") {
+				continue
+			}
+
+			// The callback is passed as an interface value.
+			arg := call.Call.Args[1]
+			if v, ok := arg.(*ssa.MakeInterface); ok {
+				arg = v.X
+			}
+
+			switch arg := arg.(type) {
+			case *ssa.Function:
+				if params := arg.Signature.Params(); params.Len() == 0 || !isTestingPointer(params.At(0).Type(), "T") {
+					return nil, fmt.Errorf("testgen: fuzz callback must accept *testing.T: %s", fn.Name())
+				}
+				return arg, nil
+			case *ssa.MakeClosure:
+				return nil, fmt.Errorf("testgen: fuzz callback captures variables: %s", fn.Name())
+			default:
+				return nil, fmt.Errorf("testgen: fuzz callback must be a function: %s", fn.Name())
+			}
+		}
+	}
+	return nil, fmt.Errorf("testgen: f.Fuzz() not called: %s", fn.Name())
+}
+
+// isTestingPointer returns true if typ is a pointer to the named type of the
+// testing package, such as *testing.T.
+func isTestingPointer(typ types.Type, name string) bool {
+	ptr, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "testing" && named.Obj().Name() == name
+}
+
+// FormatFuzzCorpusEntry returns a seed corpus file, in the format used by Go's
+// native fuzzing, for the solved values of arguments of the given types.
+// Values are decoded in little-endian order if littleEndian is true.
+func FormatFuzzCorpusEntry(typs []types.Type, inputs [][]byte, littleEndian bool) ([]byte, error) {
+	if len(typs) != len(inputs) {
+		return nil, fmt.Errorf("testgen: fuzz argument count mismatch: %d types, %d inputs", len(typs), len(inputs))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("go test fuzz v1\n")
+	for i, typ := range typs {
+		lit, err := FormatFuzzValue(typ, inputs[i], littleEndian)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(lit)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// FormatFuzzValue returns a value of typ encoded in b as a line of a Go fuzzing
+// corpus file, such as "int(5)" or "string(\"foo\")". Only the argument
+// types supported by f.Fuzz() may be formatted.
+func FormatFuzzValue(typ types.Type, b []byte, littleEndian bool) (string, error) {
+	var order binary.ByteOrder = binary.BigEndian
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		switch typ.Kind() {
+		case types.String:
+			return "string(" + strconv.Quote(string(b)) + ")", nil
+		case types.Bool:
+			return "bool(" + strconv.FormatBool(b[0] != 0) + ")", nil
+		case types.Float32:
+			f := math.Float32frombits(order.Uint32(b))
+			if math.IsNaN(float64(f)) && math.Float32bits(f) != math.Float32bits(float32(math.NaN())) {
+				return fmt.Sprintf("math.Float32frombits(0x%x)", math.Float32bits(f)), nil
+			}
+			return fmt.Sprintf("float32(%v)", f), nil
+		case types.Float64:
+			f := math.Float64frombits(order.Uint64(b))
+			if math.IsNaN(f) && math.Float64bits(f) != math.Float64bits(math.NaN()) {
+				return fmt.Sprintf("math.Float64frombits(0x%x)", math.Float64bits(f)), nil
+			}
+			return fmt.Sprintf("float64(%v)", f), nil
+		case types.Uint8:
+			return fmt.Sprintf("byte(%q)", b[0]), nil
+		case types.Int32:
+			v := int32(order.Uint32(b))
+			if r := rune(v); utf8.ValidRune(r) && unicode.IsPrint(r) {
+				return fmt.Sprintf("rune(%q)", r), nil
+			}
+			return fmt.Sprintf("int32(%d)", v), nil
+		case types.Uint, types.Uint16, types.Uint32, types.Uint64:
+			return fmt.Sprintf("%s(%d)", typ.Name(), decodeUint(b, order)), nil
+		case types.Int, types.Int8, types.Int16, types.Int64:
+			v := decodeUint(b, order)
+			shift := uint(64 - len(b)*8) // sign extend
+			return fmt.Sprintf("%s(%d)", typ.Name(), int64(v<<shift)>>shift), nil
+		}
+
+	case *types.Slice:
+		if elem, ok := typ.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return fmt.Sprintf("[]byte(%q)", b), nil
+		}
+	}
+	return "", fmt.Errorf("testgen: unsupported fuzz argument type: %s", typ)
+}
+
+// WriteFuzzCorpusEntry writes a seed corpus file to dir, such as
+// "testdata/fuzz/FuzzXxx", named by the hash of its contents as Go's native
+// fuzzing does. Returns the path of the file.
+func WriteFuzzCorpusEntry(dir string, data []byte) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%x", sha256.Sum256(data))[:16])
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	} else if err := ioutil.WriteFile(path, data, 0666); err != nil {
+		return "", err
+	}
+	return path, nil
+}
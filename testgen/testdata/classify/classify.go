@@ -0,0 +1,12 @@
+package classify
+
+func classify(x int, s string) int {
+	if x < 0 {
+		panic("negative")
+	} else if x > 100 {
+		return 2
+	} else if s == "glee" {
+		return 3
+	}
+	return 1
+}
@@ -0,0 +1,51 @@
+package entrypoints
+
+import "strings"
+
+type Point struct {
+	X, Y    int
+	Visible bool
+}
+
+type Node struct {
+	Next *Node
+}
+
+type Counter struct{ n int }
+
+func Add(a, b int) int { return a + b }
+
+func Greet(name string) string { return "hello " + name }
+
+func Hash(b []byte) uint32 {
+	var h uint32
+	for _, c := range b {
+		h = h*31 + uint32(c)
+	}
+	return h
+}
+
+func Move(p Point, dx int) Point {
+	if p.Visible {
+		p.X += dx
+	}
+	return p
+}
+
+// Walk is excluded as pointers cannot be symbolic.
+func Walk(n *Node) int {
+	var i int
+	for ; n != nil; n = n.Next {
+		i++
+	}
+	return i
+}
+
+// Join is excluded as only byte slices can be symbolic.
+func Join(a []string) string { return strings.Join(a, ",") }
+
+// Incr is excluded as methods are not supported.
+func (c *Counter) Incr(x int) { c.n += x }
+
+// double is excluded as it is not exported.
+func double(x int) int { return x * 2 }
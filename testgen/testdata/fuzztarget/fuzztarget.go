@@ -0,0 +1,21 @@
+package fuzztarget
+
+import "testing"
+
+func FuzzClassify(f *testing.F) {
+	f.Add(1, "")
+	f.Fuzz(func(t *testing.T, x int, s string) {
+		if x < 0 {
+			panic("negative")
+		} else if s == "glee" {
+			t.Fatal("matched")
+		}
+	})
+}
+
+func FuzzCapture(f *testing.F) {
+	var n int
+	f.Fuzz(func(t *testing.T, x int) {
+		n += x
+	})
+}
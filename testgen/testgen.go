@@ -0,0 +1,390 @@
+// Package testgen generates table-driven Go tests by symbolically executing a
+// function & solving for concrete arguments along each path.
+package testgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benbjohnson/glee"
+	"golang.org/x/tools/go/ssa"
+)
+
+// DefaultStringLen is the default length of symbolic string & byte slice arguments.
+const DefaultStringLen = 8
+
+// Generator generates test cases for a function by exploring its paths.
+type Generator struct {
+	// Solver used by the executor & for solving argument values.
+	// Must be set before generating.
+	Solver glee.Solver
+
+	// Length, in bytes, of symbolic string & byte slice arguments.
+	StringLen int
+
+	// Exploration limits passed to the executor. Paths which exhaust a
+	// limit do not produce test cases.
+	Limits glee.Limits
+
+	// Values preferred for solved arguments, such as printable bytes, so
+	// test cases are readable. Only applies once a path has terminated.
+	Preference glee.Preference
+
+	// OS & architecture the function is executed for. Uses the defaults of
+	// the executor if blank.
+	OS   string
+	Arch string
+}
+
+// NewGenerator returns a new instance of Generator.
+func NewGenerator() *Generator {
+	return &Generator{StringLen: DefaultStringLen}
+}
+
+// TestCase represents a set of concrete arguments that exercises a single path.
+type TestCase struct {
+	Args   []string // Go source literal for each argument
+	Inputs [][]byte // solved bytes of each argument
+	Status glee.ExecutionStatus
+	Reason string
+
+	// True if integers & floats in Inputs are encoded in little-endian order.
+	LittleEndian bool
+
+	// Hash of the path's constraints & the lines executed along the path,
+	// by filename. See CorpusEntry.
+	PathHash string
+	Coverage map[string][]int
+}
+
+// Panics returns true if the path ends in a panic.
+func (tc *TestCase) Panics() bool {
+	return tc.Status == glee.ExecutionStatusPanicked
+}
+
+// Generate executes fn with symbolic arguments & returns a test case for each
+// distinct terminal path. Exploration stops early if ctx is cancelled.
+func (g *Generator) Generate(ctx context.Context, fn *ssa.Function) ([]*TestCase, error) {
+	if fn.Signature.Recv() != nil {
+		return nil, fmt.Errorf("testgen: methods are not supported: %s", fn.Name())
+	}
+	return g.generate(ctx, fn, 0)
+}
+
+// EntryPoints returns the exported package-level functions of pkg which can be
+// executed by Generate(), sorted by name. Every parameter must be of a type
+// which can be bound to a symbolic value. See glee.IsSymbolicParamType().
+// Functions declared in test files are excluded.
+func EntryPoints(pkg *ssa.Package) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, m := range pkg.Members {
+		fn, ok := m.(*ssa.Function)
+		if !ok || fn.Synthetic != "" || !ast.IsExported(fn.Name()) {
+			continue
+		} else if strings.HasSuffix(pkg.Prog.Fset.Position(fn.Pos()).Filename, "_test.go") {
+			continue
+		} else if !hasSymbolicParams(fn) {
+			continue
+		}
+		fns = append(fns, fn)
+	}
+	sort.Slice(fns, func(i, j int) bool { return fns[i].Name() < fns[j].Name() })
+	return fns
+}
+
+// hasSymbolicParams returns true if every parameter of fn can be symbolic.
+// Pointers to testing types are excluded as they are not inputs.
+func hasSymbolicParams(fn *ssa.Function) bool {
+	for _, param := range fn.Params {
+		if _, ok := param.Type().Underlying().(*types.Pointer); ok || !glee.IsSymbolicParamType(param.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateFuzz executes the callback passed to f.Fuzz() by the fuzz target fn
+// with symbolic arguments & returns a test case for each distinct terminal
+// path. The *testing.T argument of the callback is omitted from the Args &
+// Inputs of each test case. See FuzzCallback().
+func (g *Generator) GenerateFuzz(ctx context.Context, fn *ssa.Function) ([]*TestCase, error) {
+	callback, err := FuzzCallback(fn)
+	if err != nil {
+		return nil, err
+	}
+	return g.generate(ctx, callback, 1)
+}
+
+// generate executes fn & returns a test case for each distinct terminal path.
+// The first skip parameters are omitted from each test case.
+func (g *Generator) generate(ctx context.Context, fn *ssa.Function, skip int) ([]*TestCase, error) {
+	e := glee.NewExecutor(fn)
+	e.Solver = g.Solver
+	e.Limits = g.Limits
+	tracer := newLineTracer()
+	e.Tracer = tracer
+	if g.OS != "" {
+		e.OS = g.OS
+	}
+	if g.Arch != "" {
+		e.Arch = g.Arch
+	}
+
+	arrays, err := e.BindSymbolicParams(g.StringLen)
+	if err != nil {
+		return nil, err
+	}
+
+	solver := g.Solver
+	if g.Preference != glee.PreferNone {
+		solver = glee.NewPreferringSolver(g.Solver, g.Preference)
+	}
+
+	var a []*TestCase
+	m := make(map[string]struct{})
+	for {
+		state, err := e.ExecuteNextStateContext(ctx)
+		if err == glee.ErrNoStateAvailable {
+			break
+		} else if err != nil {
+			return a, err
+		} else if !state.Terminated() {
+			continue
+		}
+		lines := tracer.lines(state)
+		if status := state.Status(); status == glee.ExecutionStatusExhausted || status == glee.ExecutionStatusDropped {
+			continue
+		}
+
+		// Solve for the arguments along the path.
+		satisfiable, values, err := glee.SolveContext(ctx, solver, state.Constraints(), arrays)
+		if err != nil {
+			return a, err
+		} else if !satisfiable {
+			continue
+		}
+
+		tc := &TestCase{
+			Inputs:       values[skip:],
+			Status:       state.Status(),
+			Reason:       state.Reason(),
+			LittleEndian: e.IsLittleEndian(),
+			PathHash:     PathHash(fn, state.Constraints()),
+			Coverage:     lines,
+		}
+		for i, param := range fn.Params[skip:] {
+			lit, err := formatValue(param.Type(), tc.Inputs[i], e.IsLittleEndian(), e.Sizes())
+			if err != nil {
+				return a, err
+			}
+			tc.Args = append(tc.Args, lit)
+		}
+
+		// Multiple paths may solve to the same arguments.
+		key := strings.Join(append(tc.Args, string(tc.Status)), "\x00")
+		if _, ok := m[key]; ok {
+			continue
+		}
+		m[key] = struct{}{}
+
+		a = append(a, tc)
+	}
+	return a, nil
+}
+
+// FormatValue returns the Go source literal for a value of typ encoded in b.
+// Struct fields are laid out as on a 64-bit architecture. Named struct types
+// are assumed to be declared in the package the literal is used in.
+func FormatValue(typ types.Type, b []byte, littleEndian bool) (string, error) {
+	return formatValue(typ, b, littleEndian, types.SizesFor("gc", "amd64"))
+}
+
+// formatValue returns the Go source literal for a value of typ encoded in b.
+// Struct fields are laid out using sizes.
+func formatValue(typ types.Type, b []byte, littleEndian bool, sizes types.Sizes) (string, error) {
+	var order binary.ByteOrder = binary.BigEndian
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+
+	// Structs are named by typ rather than their underlying type.
+	if st, ok := typ.Underlying().(*types.Struct); ok {
+		return formatStruct(typ, st, b, littleEndian, sizes)
+	}
+
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		info := typ.Info()
+		switch {
+		case info&types.IsString != 0:
+			return strconv.Quote(string(b)), nil
+		case info&types.IsBoolean != 0:
+			return strconv.FormatBool(b[0] != 0), nil
+		case typ.Kind() == types.Float32:
+			return formatFloat(float64(math.Float32frombits(order.Uint32(b))), 32), nil
+		case typ.Kind() == types.Float64:
+			return formatFloat(math.Float64frombits(order.Uint64(b)), 64), nil
+		case info&types.IsUnsigned != 0:
+			return strconv.FormatUint(decodeUint(b, order), 10), nil
+		case info&types.IsInteger != 0:
+			v := decodeUint(b, order)
+			shift := uint(64 - len(b)*8) // sign extend
+			return strconv.FormatInt(int64(v<<shift)>>shift, 10), nil
+		}
+
+	case *types.Slice:
+		return fmt.Sprintf("[]byte(%q)", b), nil
+	}
+	return "", fmt.Errorf("testgen: unsupported argument type: %s", typ)
+}
+
+// formatStruct returns the Go source of a composite literal for a struct
+// value of typ encoded in b. Blank fields are omitted.
+func formatStruct(typ types.Type, st *types.Struct, b []byte, littleEndian bool, sizes types.Sizes) (string, error) {
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(fields)
+
+	elems := make([]string, 0, len(fields))
+	for i, field := range fields {
+		if field.Name() == "_" {
+			continue
+		}
+		start, end := offsets[i], offsets[i]+sizes.Sizeof(field.Type())
+		lit, err := formatValue(field.Type(), b[start:end], littleEndian, sizes)
+		if err != nil {
+			return "", err
+		}
+		elems = append(elems, field.Name()+": "+lit)
+	}
+
+	name := types.TypeString(typ, func(*types.Package) string { return "" })
+	return fmt.Sprintf("%s{%s}", name, strings.Join(elems, ", ")), nil
+}
+
+// decodeUint decodes an unsigned integer of 1, 2, 4, or 8 bytes.
+func decodeUint(b []byte, order binary.ByteOrder) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(order.Uint16(b))
+	case 4:
+		return uint64(order.Uint32(b))
+	default:
+		return order.Uint64(b)
+	}
+}
+
+// formatFloat returns the Go source for f. Non-finite values are expressed
+// using the math package.
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// TestName returns the name of the generated test function for fn.
+func TestName(fn *ssa.Function) string {
+	name := []rune(fn.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return "TestGlee" + string(name)
+}
+
+// FormatTestFile returns the source of a test file in the package of fn that
+// calls fn with the arguments of each test case. Test cases which panic are
+// expected to panic and all other test cases are expected to return.
+func FormatTestFile(fn *ssa.Function, cases []*TestCase) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by glee. DO NOT EDIT.")
+	fmt.Fprintln(&buf, "")
+	fmt.Fprintf(&buf, "package %s\n\n", fn.Pkg.Pkg.Name())
+
+	// Only import math if a non-finite float literal is used.
+	imports := []string{"fmt", "testing"}
+	for _, tc := range cases {
+		if strings.Contains(strings.Join(tc.Args, " "), "math.") {
+			imports = []string{"fmt", "math", "testing"}
+			break
+		}
+	}
+	fmt.Fprintln(&buf, "import (")
+	for _, path := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	fmt.Fprintln(&buf, ")")
+	fmt.Fprintln(&buf, "")
+
+	// Build field & argument names from parameters.
+	names := make([]string, len(fn.Params))
+	for i, param := range fn.Params {
+		if names[i] = param.Name(); names[i] == "" || names[i] == "_" {
+			names[i] = fmt.Sprintf("arg%d", i)
+		}
+	}
+
+	fmt.Fprintf(&buf, "func %s(t *testing.T) {\n", TestName(fn))
+	fmt.Fprintln(&buf, "\tfor i, tt := range []struct {")
+	for i, param := range fn.Params {
+		fmt.Fprintf(&buf, "\t\t%s %s\n", names[i], types.TypeString(param.Type(), types.RelativeTo(fn.Pkg.Pkg)))
+	}
+	fmt.Fprintln(&buf, "\t\tpanics bool")
+	fmt.Fprintln(&buf, "\t}{")
+	for _, tc := range cases {
+		fields := make([]string, 0, len(tc.Args)+1)
+		for i, arg := range tc.Args {
+			fields = append(fields, names[i]+": "+arg)
+		}
+		if tc.Panics() {
+			fields = append(fields, "panics: true")
+		}
+		fmt.Fprintf(&buf, "\t\t{%s},", strings.Join(fields, ", "))
+		if tc.Reason != "" {
+			fmt.Fprintf(&buf, " // %s", tc.Reason)
+		}
+		fmt.Fprintln(&buf, "")
+	}
+	fmt.Fprintln(&buf, "\t} {")
+	fmt.Fprintln(&buf, "\t\ttt := tt")
+	fmt.Fprintln(&buf, "\t\tt.Run(fmt.Sprint(i), func(t *testing.T) {")
+	fmt.Fprintln(&buf, "\t\t\tdefer func() {")
+	fmt.Fprintln(&buf, "\t\t\t\tif r := recover(); r != nil && !tt.panics {")
+	fmt.Fprintf(&buf, "\t\t\t\t\tt.Fatalf(\"unexpected panic: %%v\", r)\n")
+	fmt.Fprintln(&buf, "\t\t\t\t} else if r == nil && tt.panics {")
+	fmt.Fprintln(&buf, "\t\t\t\t\tt.Fatal(\"expected panic\")")
+	fmt.Fprintln(&buf, "\t\t\t\t}")
+	fmt.Fprintln(&buf, "\t\t\t}()")
+
+	args := make([]string, len(names))
+	for i := range names {
+		args[i] = "tt." + names[i]
+	}
+	call := fmt.Sprintf("%s(%s)", fn.Name(), strings.Join(args, ", "))
+	if fn.Signature.Variadic() {
+		call = fmt.Sprintf("%s(%s...)", fn.Name(), strings.Join(args, ", "))
+	}
+	fmt.Fprintf(&buf, "\t\t\t%s\n", call)
+	fmt.Fprintln(&buf, "\t\t})")
+	fmt.Fprintln(&buf, "\t}")
+	fmt.Fprintln(&buf, "}")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,284 @@
+package testgen_test
+
+import (
+	"bytes"
+	"context"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benbjohnson/glee"
+	"github.com/benbjohnson/glee/testgen"
+	"github.com/benbjohnson/glee/z3"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	fn := MustLoadFunction(t, "./testdata/classify", "classify")
+
+	solver := z3.NewSolver()
+	defer solver.Close()
+
+	g := testgen.NewGenerator()
+	g.Solver = solver
+	g.StringLen = 4
+
+	cases, err := g.Generate(context.Background(), fn)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := len(cases), 4; got != exp {
+		t.Fatalf("len(cases)=%d, expected %d", got, exp)
+	}
+
+	// Ensure exactly one path panics & one path solves for the string.
+	var panicN, matchN int
+	for _, tc := range cases {
+		if len(tc.PathHash) != 16 || len(tc.Coverage) == 0 {
+			t.Fatalf("expected path hash & coverage: %#v", tc)
+		}
+		if tc.Panics() {
+			panicN++
+		}
+		if tc.Args[1] == `"glee"` {
+			matchN++
+		}
+	}
+	if panicN != 1 {
+		t.Fatalf("unexpected panic count: %d", panicN)
+	} else if matchN != 1 {
+		t.Fatalf("unexpected string match count: %d", matchN)
+	}
+
+	// Ensure generated file is valid Go.
+	buf, err := testgen.FormatTestFile(fn, cases)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := parser.ParseFile(token.NewFileSet(), "classify_glee_test.go", buf, 0); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(buf), "func TestGleeClassify(t *testing.T) {") {
+		t.Fatalf("unexpected output:\n%s", buf)
+	}
+}
+
+func TestCorpusEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glee-corpus-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	entry := &testgen.CorpusEntry{
+		Package:     "example.com/classify",
+		PackageName: "classify",
+		Function:    "classify",
+		Args:        []string{"-1", `"glee"`},
+		Inputs:      [][]byte{{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, []byte("glee")},
+		Status:      glee.ExecutionStatusPanicked,
+		Reason:      "negative",
+		PathHash:    "0123456789abcdef",
+		Coverage:    map[string][]int{"classify.go": {4, 5, 9}},
+	}
+
+	// Ensure entries are stored by function & path hash.
+	path, err := testgen.WriteCorpusEntry(dir, entry)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := filepath.Join(dir, "classify", "0123456789abcdef.json"); path != exp {
+		t.Fatalf("path=%s, expected %s", path, exp)
+	}
+
+	if other, err := testgen.ReadCorpusEntry(path); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(other, entry) {
+		t.Fatalf("unexpected entry: %#v", other)
+	}
+
+	if a, err := testgen.CorpusFiles(dir); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(a, []string{path}) {
+		t.Fatalf("unexpected files: %v", a)
+	}
+
+	// Ensure the replay file calls the function without recovering.
+	buf, err := testgen.FormatReplayFile(entry)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := parser.ParseFile(token.NewFileSet(), "glee_replay_test.go", buf, 0); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(buf), "\tclassify(-1, \"glee\")\n") || strings.Contains(string(buf), "recover()") {
+		t.Fatalf("unexpected output:\n%s", buf)
+	}
+}
+
+func TestGenerator_GenerateFuzz(t *testing.T) {
+	fn := MustLoadFunction(t, "./testdata/fuzztarget", "FuzzClassify")
+
+	solver := z3.NewSolver()
+	defer solver.Close()
+
+	g := testgen.NewGenerator()
+	g.Solver = solver
+	g.StringLen = 4
+
+	cases, err := g.GenerateFuzz(context.Background(), fn)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := len(cases), 3; got != exp {
+		t.Fatalf("len(cases)=%d, expected %d", got, exp)
+	}
+
+	// Ensure the *testing.T argument is omitted & each path is formatted.
+	callback, err := testgen.FuzzCallback(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typs := []types.Type{callback.Params[1].Type(), callback.Params[2].Type()}
+
+	var failedN int
+	for _, tc := range cases {
+		if len(tc.Args) != 2 || len(tc.Inputs) != 2 {
+			t.Fatalf("unexpected arguments: %v", tc.Args)
+		} else if tc.Status == glee.ExecutionStatusFailed {
+			failedN++
+		}
+
+		if buf, err := testgen.FormatFuzzCorpusEntry(typs, tc.Inputs, tc.LittleEndian); err != nil {
+			t.Fatal(err)
+		} else if !strings.HasPrefix(string(buf), "go test fuzz v1\nint(") {
+			t.Fatalf("unexpected corpus entry:\n%s", buf)
+		}
+	}
+	if failedN != 1 {
+		t.Fatalf("unexpected failed count: %d", failedN)
+	}
+
+	// Ensure callbacks which capture variables are rejected.
+	if _, err := testgen.FuzzCallback(MustLoadFunction(t, "./testdata/fuzztarget", "FuzzCapture")); err == nil || !strings.Contains(err.Error(), "captures variables") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEntryPoints(t *testing.T) {
+	pkg := MustLoadFunction(t, "./testdata/entrypoints", "Add").Pkg
+
+	var names []string
+	for _, fn := range testgen.EntryPoints(pkg) {
+		names = append(names, fn.Name())
+	}
+	if exp := []string{"Add", "Greet", "Hash", "Move"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("EntryPoints()=%v, expected %v", names, exp)
+	}
+}
+
+func TestFormatValue_Struct(t *testing.T) {
+	pkg := types.NewPackage("example.com/geo", "geo")
+	fields := []*types.Var{
+		types.NewField(token.NoPos, pkg, "X", types.Typ[types.Int32], false),
+		types.NewField(token.NoPos, pkg, "Visible", types.Typ[types.Bool], false),
+		types.NewField(token.NoPos, pkg, "_", types.Typ[types.Int8], false),
+		types.NewField(token.NoPos, pkg, "Y", types.Typ[types.Int16], false),
+	}
+	typ := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Point", nil), types.NewStruct(fields, nil), nil)
+
+	// The blank field is omitted but still occupies a byte.
+	b := []byte{0xfe, 0xff, 0xff, 0xff, 0x01, 0x7f, 0x34, 0x12}
+	if got, err := testgen.FormatValue(typ, b, true); err != nil {
+		t.Fatal(err)
+	} else if exp := `Point{X: -2, Visible: true, Y: 4660}`; got != exp {
+		t.Fatalf("FormatValue()=%s, expected %s", got, exp)
+	}
+}
+
+func TestFormatFuzzCorpusEntry(t *testing.T) {
+	typs := []types.Type{
+		types.Typ[types.Int],
+		types.Typ[types.Int8],
+		types.Typ[types.Uint16],
+		types.Typ[types.String],
+		types.NewSlice(types.Typ[types.Byte]),
+		types.Typ[types.Bool],
+		types.Typ[types.Byte],
+		types.Typ[types.Rune],
+		types.Typ[types.Float64],
+		types.Typ[types.Float32],
+	}
+	inputs := [][]byte{
+		{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x80},
+		{0x34, 0x12},
+		[]byte("glee"),
+		{0x00, 'x'},
+		{1},
+		{'a'},
+		{0xe9, 0x00, 0x00, 0x00},
+		{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf8, 0x7f},
+		{0x00, 0x00, 0x80, 0xff},
+	}
+
+	buf, err := testgen.FormatFuzzCorpusEntry(typs, inputs, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := string(buf), `go test fuzz v1
+int(-2)
+int8(-128)
+uint16(4660)
+string("glee")
+[]byte("\x00x")
+bool(true)
+byte('a')
+rune('é')
+float64(NaN)
+float32(-Inf)
+`; got != exp {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+
+	// Ensure unsupported types are rejected.
+	if _, err := testgen.FormatFuzzValue(types.Typ[types.Complex128], make([]byte, 16), true); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Ensure files are named by the hash of their contents.
+	dir, err := ioutil.TempDir("", "glee-fuzz-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if path, err := testgen.WriteFuzzCorpusEntry(filepath.Join(dir, "FuzzX"), buf); err != nil {
+		t.Fatal(err)
+	} else if other, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(other, buf) || len(filepath.Base(path)) != 16 {
+		t.Fatalf("unexpected file: %s", path)
+	}
+}
+
+// MustLoadFunction builds the package at path and returns the named function.
+func MustLoadFunction(tb testing.TB, path, name string) *ssa.Function {
+	tb.Helper()
+
+	initial, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, path)
+	if err != nil {
+		tb.Fatal(err)
+	} else if packages.PrintErrors(initial) > 0 {
+		tb.Fatal("packages contain errors")
+	}
+
+	prog, pkgs := ssautil.AllPackages(initial, ssa.BuilderMode(0))
+	prog.Build()
+
+	fn, _ := pkgs[0].Members[name].(*ssa.Function)
+	if fn == nil {
+		tb.Fatalf("function %q not found", name)
+	}
+	return fn
+}
@@ -0,0 +1,88 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// isTestingType returns true if typ is testing.T or testing.B.
+func isTestingType(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "testing" {
+		return false
+	}
+	switch named.Obj().Name() {
+	case "T", "B":
+		return true
+	default:
+		return false
+	}
+}
+
+// testingReason returns the reason reported for a call to a testing method.
+// Formatting methods use their format string if it is constant.
+func testingReason(state *ExecutionState, instr *ssa.Call) string {
+	fn, args := state.ExtractCall(instr)
+	if strings.HasSuffix(fn.Name(), "f") && len(args) > 1 {
+		if format, ok := args[1].(*Array); ok {
+			if s, ok := constantString(format); ok {
+				return s
+			}
+		}
+	}
+	return fmt.Sprintf("t.%s() called", fn.Name())
+}
+
+// constantString returns the contents of array if every byte is constant.
+func constantString(array *Array) (string, bool) {
+	b := make([]byte, array.Size)
+	for i := range b {
+		value, ok := array.selectByte(NewConstantExpr64(uint64(i))).(*ConstantExpr)
+		if !ok {
+			return "", false
+		}
+		b[i] = byte(value.Value)
+	}
+	return string(b), true
+}
+
+// execTestingFail represents a function handler for t.Fail(), t.Error() &
+// t.Errorf(). The test is marked as failed but the path continues so it is
+// reported as failed once it completes. The first failure's reason is kept.
+func execTestingFail(state *ExecutionState, instr *ssa.Call) error {
+	if state.testFailure == "" {
+		state.testFailure = testingReason(state, instr)
+	}
+	return nil
+}
+
+// execTestingFailed represents a function handler for t.Failed().
+func execTestingFailed(state *ExecutionState, instr *ssa.Call) error {
+	state.Frame().bind(instr, NewBoolConstantExpr(state.testFailure != ""))
+	return nil
+}
+
+// execTestingFailNow represents a function handler for t.FailNow(), t.Fatal()
+// & t.Fatalf(). The path terminates as failed immediately.
+func execTestingFailNow(state *ExecutionState, instr *ssa.Call) error {
+	state.status = ExecutionStatusFailed
+	state.reason = testingReason(state, instr)
+	return nil
+}
+
+// execTestingSkip represents a function handler for t.Skip(), t.Skipf() &
+// t.SkipNow(). The path is dropped.
+func execTestingSkip(state *ExecutionState, instr *ssa.Call) error {
+	state.status = ExecutionStatusDropped
+	state.reason = "test skipped"
+	return nil
+}
+
+// execTestingNop represents a function handler for testing methods without
+// an effect on the path, such as t.Log() & t.Helper().
+func execTestingNop(state *ExecutionState, instr *ssa.Call) error {
+	return nil
+}
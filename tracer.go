@@ -0,0 +1,25 @@
+package glee
+
+import (
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Tracer receives events as an executor explores states. Callbacks are invoked
+// synchronously on the executing goroutine so implementations should return
+// quickly and must not modify the states passed to them.
+type Tracer interface {
+	// Called when child is created from parent. The child has not executed yet.
+	OnFork(parent, child *ExecutionState)
+
+	// Called before instr is executed on state.
+	OnInstruction(state *ExecutionState, instr ssa.Instruction)
+
+	// Called once state has terminated & before it is returned from
+	// ExecuteNextState().
+	OnTerminalState(state *ExecutionState)
+
+	// Called after each solver query with its result & duration.
+	OnSolverQuery(constraints []Expr, satisfiable bool, d time.Duration, err error)
+}
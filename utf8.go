@@ -0,0 +1,253 @@
+package glee
+
+import (
+	"fmt"
+	"go/types"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// runeCase represents one possible encoding or decoding of a rune.
+type runeCase struct {
+	cond  Expr   // condition for the case to hold
+	r     Expr   // 32-bit rune value
+	size  uint   // encoded size, in bytes
+	bytes []Expr // encoded bytes, if encoding
+}
+
+// decodeRuneCases returns every possible decoding of the rune starting at
+// offset within s. This follows the semantics of utf8.DecodeRuneInString().
+//
+// If validOnly is true then the invalid encoding case is excluded and the
+// string is assumed to be valid UTF-8.
+func decodeRuneCases(s *Array, offset uint, validOnly bool) []runeCase {
+	n := s.Size - offset
+	if n == 0 {
+		return []runeCase{{cond: NewBoolConstantExpr(true), r: NewConstantExpr32(utf8.RuneError), size: 0}}
+	}
+
+	b := make([]Expr, 4)
+	for i := uint(0); i < 4 && i < n; i++ {
+		b[i] = s.selectByte(NewConstantExpr64(uint64(offset + i)))
+	}
+
+	// Condition for a byte to be between lo & hi, inclusive.
+	between := func(v Expr, lo, hi uint64) Expr {
+		return newAndExpr(newUleExpr(NewConstantExpr8(lo), v), newUleExpr(v, NewConstantExpr8(hi)))
+	}
+	// Condition that lhs != rhs.
+	ne := func(lhs Expr, rhs uint64) Expr {
+		return NewNotExpr(newEqExpr(lhs, NewConstantExpr8(rhs)))
+	}
+	// Returns the low bits of b zero-extended to 32 bits & shifted left.
+	bits := func(v Expr, mask, shift uint64) Expr {
+		return newShlExpr(newAndExpr(newZExtExpr(v, Width32), NewConstantExpr32(mask)), NewConstantExpr32(shift))
+	}
+
+	// Single byte ASCII.
+	cases := []runeCase{{
+		cond: newUltExpr(b[0], NewConstantExpr8(0x80)),
+		r:    newZExtExpr(b[0], Width32),
+		size: 1,
+	}}
+
+	// Two byte sequence.
+	if n >= 2 {
+		cases = append(cases, runeCase{
+			cond: newAndExpr(between(b[0], 0xC2, 0xDF), between(b[1], 0x80, 0xBF)),
+			r:    newOrExpr(bits(b[0], 0x1F, 6), bits(b[1], 0x3F, 0)),
+			size: 2,
+		})
+	}
+
+	// Three byte sequence. Excludes overlong encodings & surrogates.
+	if n >= 3 {
+		cond := newAndExpr(between(b[0], 0xE0, 0xEF), between(b[1], 0x80, 0xBF))
+		cond = newAndExpr(cond, between(b[2], 0x80, 0xBF))
+		cond = newAndExpr(cond, newOrExpr(ne(b[0], 0xE0), newUleExpr(NewConstantExpr8(0xA0), b[1])))
+		cond = newAndExpr(cond, newOrExpr(ne(b[0], 0xED), newUleExpr(b[1], NewConstantExpr8(0x9F))))
+		cases = append(cases, runeCase{
+			cond: cond,
+			r:    newOrExpr(newOrExpr(bits(b[0], 0x0F, 12), bits(b[1], 0x3F, 6)), bits(b[2], 0x3F, 0)),
+			size: 3,
+		})
+	}
+
+	// Four byte sequence. Excludes overlong encodings & values above MaxRune.
+	if n >= 4 {
+		cond := newAndExpr(between(b[0], 0xF0, 0xF4), between(b[1], 0x80, 0xBF))
+		cond = newAndExpr(cond, newAndExpr(between(b[2], 0x80, 0xBF), between(b[3], 0x80, 0xBF)))
+		cond = newAndExpr(cond, newOrExpr(ne(b[0], 0xF0), newUleExpr(NewConstantExpr8(0x90), b[1])))
+		cond = newAndExpr(cond, newOrExpr(ne(b[0], 0xF4), newUleExpr(b[1], NewConstantExpr8(0x8F))))
+		cases = append(cases, runeCase{
+			cond: cond,
+			r: newOrExpr(
+				newOrExpr(bits(b[0], 0x07, 18), bits(b[1], 0x3F, 12)),
+				newOrExpr(bits(b[2], 0x3F, 6), bits(b[3], 0x3F, 0)),
+			),
+			size: 4,
+		})
+	}
+
+	if validOnly {
+		return cases
+	}
+
+	// Invalid encodings consume a single byte and return RuneError.
+	var invalid Expr = NewBoolConstantExpr(true)
+	for _, c := range cases {
+		invalid = newAndExpr(invalid, NewNotExpr(c.cond))
+	}
+	return append(cases, runeCase{cond: invalid, r: NewConstantExpr32(utf8.RuneError), size: 1})
+}
+
+// encodeRuneCases returns every possible UTF-8 encoding of r. The final case
+// is an invalid rune (negative, surrogate, or above MaxRune) which has a size
+// of zero and no encoded bytes.
+func encodeRuneCases(r Expr) []runeCase {
+	width := ExprWidth(r)
+	c := func(v uint64) Expr { return NewConstantExpr(v, width) }
+
+	// Returns the byte formed from the bits of r at shift OR'd with a prefix.
+	enc := func(prefix, mask, shift uint64) Expr {
+		v := newAndExpr(newLShrExpr(r, c(shift)), c(mask))
+		return newOrExpr(NewExtractExpr(v, 0, Width8), NewConstantExpr8(prefix))
+	}
+
+	isSurrogate := newAndExpr(newUleExpr(c(0xD800), r), newUleExpr(r, c(0xDFFF)))
+
+	cases := []runeCase{
+		{
+			cond:  newUltExpr(r, c(0x80)),
+			size:  1,
+			bytes: []Expr{enc(0x00, 0x7F, 0)},
+		},
+		{
+			cond:  newAndExpr(newUleExpr(c(0x80), r), newUltExpr(r, c(0x800))),
+			size:  2,
+			bytes: []Expr{enc(0xC0, 0x1F, 6), enc(0x80, 0x3F, 0)},
+		},
+		{
+			cond:  newAndExpr(newAndExpr(newUleExpr(c(0x800), r), newUltExpr(r, c(0x10000))), NewNotExpr(isSurrogate)),
+			size:  3,
+			bytes: []Expr{enc(0xE0, 0x0F, 12), enc(0x80, 0x3F, 6), enc(0x80, 0x3F, 0)},
+		},
+		{
+			cond:  newAndExpr(newUleExpr(c(0x10000), r), newUleExpr(r, c(utf8.MaxRune))),
+			size:  4,
+			bytes: []Expr{enc(0xF0, 0x07, 18), enc(0x80, 0x3F, 12), enc(0x80, 0x3F, 6), enc(0x80, 0x3F, 0)},
+		},
+	}
+
+	var invalid Expr = NewBoolConstantExpr(true)
+	for _, c := range cases {
+		invalid = newAndExpr(invalid, NewNotExpr(c.cond))
+	}
+	return append(cases, runeCase{cond: invalid})
+}
+
+// runeErrorBytes returns the UTF-8 encoding of U+FFFD which replaces invalid
+// runes when encoding.
+func runeErrorBytes() []Expr {
+	return []Expr{NewConstantExpr8(0xEF), NewConstantExpr8(0xBF), NewConstantExpr8(0xBD)}
+}
+
+// execUTF8RuneLen represents a function handler for utf8.RuneLen().
+func execUTF8RuneLen(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	r, ok := args[0].(Expr)
+	if !ok {
+		return fmt.Errorf("glee: utf8.RuneLen(): unexpected rune: %T", args[0])
+	}
+	width := state.Executor().Sizeof(types.Typ[types.Int])
+
+	for _, c := range encodeRuneCases(r) {
+		newState, err := forkIfSatisfiable(state, c.cond)
+		if err != nil {
+			return err
+		} else if newState == nil {
+			continue
+		}
+
+		n := NewConstantExpr(uint64(c.size), width)
+		if c.size == 0 {
+			n = NewConstantExpr(^uint64(0), width) // -1
+		}
+		newState.Frame().bind(instr, n)
+		state.Executor().addState(newState)
+	}
+	return nil
+}
+
+// execUTF8DecodeRuneInString represents a function handler for utf8.DecodeRuneInString().
+func execUTF8DecodeRuneInString(state *ExecutionState, instr *ssa.Call) error {
+	_, args := state.ExtractCall(instr)
+	return forkDecodeRune(state, instr, args[0].(*Array), 0)
+}
+
+// execUTF8DecodeRune represents a function handler for utf8.DecodeRune().
+func execUTF8DecodeRune(state *ExecutionState, instr *ssa.Call) error {
+	e := state.Executor()
+	width := e.Sizeof(types.Typ[types.Int])
+	_, args := state.ExtractCall(instr)
+
+	cases, err := e.sliceBytesCases(state, args[0].(*Array))
+	if err != nil {
+		return fmt.Errorf("glee: utf8.DecodeRune(): %s", err)
+	} else if len(cases) == 1 && IsConstantTrue(cases[0].cond) {
+		return forkDecodeRune(state, instr, cases[0].bytes, 0)
+	}
+
+	// Each possible decoding is forked separately for each case of the slice.
+	var conds []Expr
+	var results []Tuple
+	for _, sc := range cases {
+		if sc.bytes == nil {
+			conds, results = append(conds, sc.cond), append(results, nil)
+			continue
+		}
+		for _, c := range decodeRuneCases(sc.bytes, 0, e.AssumeValidUTF8) {
+			conds = append(conds, newAndExpr(sc.cond, c.cond))
+			results = append(results, Tuple{c.r, NewConstantExpr(uint64(c.size), width)})
+		}
+	}
+
+	var decoded bool
+	if err := forkEach(state, conds, func(state *ExecutionState, i int) {
+		decoded = true
+		if results[i] == nil {
+			e.runtimePanic(state, "slice bounds out of range")
+			return
+		}
+		state.Frame().bind(instr, results[i])
+	}); err != nil {
+		return err
+	} else if !decoded {
+		return ErrNoValidUTF8Decoding
+	}
+	return nil
+}
+
+// forkDecodeRune forks a state for every possible decoding of the rune at
+// offset in s and binds a (rune, size) tuple to instr.
+func forkDecodeRune(state *ExecutionState, instr *ssa.Call, s *Array, offset uint) error {
+	e := state.Executor()
+	width := e.Sizeof(types.Typ[types.Int])
+
+	for _, c := range decodeRuneCases(s, offset, e.AssumeValidUTF8) {
+		newState, err := forkIfSatisfiable(state, c.cond)
+		if err != nil {
+			return err
+		} else if newState == nil {
+			continue
+		}
+		newState.Frame().bind(instr, Tuple{c.r, NewConstantExpr(uint64(c.size), width)})
+		e.addState(newState)
+	}
+
+	if !state.Forked() {
+		return ErrNoValidUTF8Decoding
+	}
+	return nil
+}
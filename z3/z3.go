@@ -1,7 +1,9 @@
 package z3
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 	"unsafe"
@@ -18,57 +20,222 @@ import (
 import "C"
 
 // Ensure solver implements interface.
-var _ glee.Solver = (*Solver)(nil)
+var _ glee.ContextSolver = (*Solver)(nil)
+var _ glee.SolverStats = (*Solver)(nil)
 
 // Solver represents a solver that uses an embedded Z3 solver.
 type Solver struct {
-	ctx   *Context
-	stats Stats
+	ctx    *Context
+	stats  Stats
+	config Config
+
+	// If true, a single Z3 solver is retained between queries. Constraints
+	// shared with the prefix of the previous query are kept & only the
+	// remaining constraints are asserted, each within its own scope.
+	Incremental bool
+
+	// Retained solver & constraints asserted in each scope, if incremental.
+	solver   C.Z3_solver
+	asserted []glee.Expr
+
+	// Encoding of reads from arrays at symbolic indexes.
+	// Defaults to ArrayEncodingStore.
+	ArrayEncoding ArrayEncoding
+
+	// Maximum size, in bytes, of arrays encoded as if-then-else trees when
+	// using ArrayEncodingAuto. Zero uses DefaultITEThreshold.
+	ITEThreshold int
+
+	// If true, each byte of a solved array is evaluated by a separate call
+	// to Z3 instead of decoding the model's interpretation of the whole
+	// array. See BenchmarkSolver_EvalArray for a comparison.
+	EvalPerByte bool
 }
 
-// NewSolver returns a new instance of Solver.
+// ArrayEncoding represents a method of encoding a read from an array at a
+// symbolic index. See BenchmarkSolver_ArrayEncoding for a comparison of
+// encodings by array size.
+type ArrayEncoding int
+
+const (
+	// Arrays are encoded as Z3 arrays with a store for each update.
+	ArrayEncodingStore ArrayEncoding = iota
+
+	// Same as ArrayEncodingStore except each symbolic index is also
+	// constrained to the bounds of its array. The executor only reads within
+	// bounds so this narrows the search without excluding any paths.
+	ArrayEncodingBounded
+
+	// Reads are encoded as an if-then-else tree comparing the index to each
+	// position in the array. Avoids the theory of arrays entirely but grows
+	// with the size of the array.
+	ArrayEncodingITE
+
+	// Arrays of up to ITEThreshold bytes use ArrayEncodingITE & larger
+	// arrays use ArrayEncodingBounded.
+	ArrayEncodingAuto
+)
+
+// DefaultITEThreshold is the default maximum size of an array encoded as an
+// if-then-else tree by ArrayEncodingAuto.
+const DefaultITEThreshold = 32
+
+// Config represents the configuration of the Z3 solvers created by a Solver.
+// The zero value uses the Z3 defaults.
+type Config struct {
+	// Maximum duration of each query. A shorter context deadline passed to
+	// SolveContext() takes precedence. Zero is unlimited.
+	Timeout time.Duration
+
+	// Maximum memory used by Z3, in megabytes. Z3 only supports a global
+	// limit so this applies to every Z3 context in the process. Queries
+	// exceeding it fail with glee.ErrSolverResourceLimit. Zero is unlimited.
+	MaxMemory int
+
+	// Seed used by Z3 for randomized heuristics. Zero uses the Z3 default.
+	RandomSeed uint
+
+	// SMT-LIB2 logic used to create solvers, such as "QF_ABV". Z3 selects
+	// the logic from the constraints if blank.
+	Logic string
+
+	// Names of tactics applied in order to create solvers, such as
+	// "simplify", "solve-eqs" & "smt". The last tactic must decide the
+	// constraints otherwise queries fail with glee.ErrSolverUnknown.
+	// Overrides Logic if set.
+	Tactics []string
+}
+
+// NewSolver returns a new instance of Solver using the default configuration.
 func NewSolver() *Solver {
 	return &Solver{
 		ctx: NewContext(),
 	}
 }
 
+// NewSolverWithConfig returns a new instance of Solver using config. Returns
+// an error if a tactic in the configuration does not exist.
+func NewSolverWithConfig(config Config) (*Solver, error) {
+	if config.MaxMemory > 0 {
+		setGlobalParam("memory_max_size", fmt.Sprint(config.MaxMemory))
+	}
+
+	s := &Solver{ctx: NewContext(), config: config}
+	if len(config.Tactics) > 0 {
+		tactic, err := s.ctx.makeTactic(config.Tactics)
+		if err != nil {
+			s.ctx.Close()
+			return nil, err
+		}
+		C.Z3_tactic_dec_ref(s.ctx.raw, tactic)
+	}
+	return s, nil
+}
+
 // Close deletes the underlying Z3 context.
 func (s *Solver) Close() error {
+	s.reset()
 	return s.ctx.Close()
 }
 
+// Interrupt cancels an in-flight call to Solve(). The interrupted call returns
+// glee.ErrSolverCanceled. This is safe to call from another goroutine.
+func (s *Solver) Interrupt() {
+	C.Z3_interrupt(s.ctx.raw)
+}
+
 // Stats returns statistics for the solver.
 func (s *Solver) Stats() Stats {
 	return s.stats
 }
 
+// QueryStats returns statistics for the queries answered by the solver.
+func (s *Solver) QueryStats() glee.QueryStats {
+	return s.stats.QueryStats
+}
+
+// Solve returns the satisfiability of the constraints & a value for each array.
 func (s *Solver) Solve(constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	return s.solve(constraints, arrays, 0)
+}
+
+// SolveContext solves the constraints, same as Solve(), but stops early if ctx
+// is done. The context deadline is passed to Z3 as a timeout and cancellation
+// interrupts the in-flight query. Returns ctx.Err() if ctx is done.
+func (s *Solver) SolveContext(ctx context.Context, constraints []glee.Expr, arrays []*glee.Array) (satisfiable bool, values [][]byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, nil, err
+	}
+
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return false, nil, context.DeadlineExceeded
+		}
+	}
+
+	// Interrupt the query if the context is cancelled while solving.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Interrupt()
+		case <-done:
+		}
+	}()
+
+	satisfiable, values, err = s.solve(constraints, arrays, timeout)
+	if err != nil && ctx.Err() != nil {
+		return false, nil, ctx.Err()
+	}
+	return satisfiable, values, err
+}
+
+// solve returns the satisfiability of the constraints. If timeout is non-zero
+// then the Z3 query stops after the given duration. The configured timeout
+// is used if shorter.
+func (s *Solver) solve(constraints []glee.Expr, arrays []*glee.Array, timeout time.Duration) (satisfiable bool, values [][]byte, err error) {
 	t := time.Now()
 	defer func() {
-		s.stats.SolveN++
-		s.stats.SolveTime += time.Since(t)
+		s.stats.Record(len(constraints), satisfiable, time.Since(t), err)
 	}()
 
-	solver := C.Z3_mk_solver(s.ctx.raw)
-	if err := s.ctx.err("Z3_mk_solver"); err != nil {
-		return false, nil, err
+	if s.config.Timeout > 0 && (timeout == 0 || s.config.Timeout < timeout) {
+		timeout = s.config.Timeout
 	}
-	C.Z3_solver_inc_ref(s.ctx.raw, solver)
-	defer C.Z3_solver_dec_ref(s.ctx.raw, solver)
 
-	// Assert constraints.
-	// println("dbg/solve", len(constraints))
-	for _, constraint := range constraints {
-		z3Constraint, err := s.ctx.toAST(constraint)
-		if err != nil {
+	s.ctx.encoding, s.ctx.iteThreshold = s.ArrayEncoding, s.ITEThreshold
+	s.ctx.evalPerByte = s.EvalPerByte
+	if s.ctx.iteThreshold == 0 {
+		s.ctx.iteThreshold = DefaultITEThreshold
+	}
+
+	var solver C.Z3_solver
+	if s.Incremental {
+		if solver, err = s.prepare(constraints, timeout); err != nil {
+			s.reset() // scopes may not match asserted constraints
 			return false, nil, err
 		}
-		C.Z3_solver_assert(s.ctx.raw, solver, z3Constraint)
-		if err := s.ctx.err("Z3_solver_assert"); err != nil {
+	} else {
+		if solver, err = s.makeSolver(); err != nil {
 			return false, nil, err
 		}
-		// println("dbg/solve.assert\n", s.ctx.astToString(z3Constraint))
+		defer C.Z3_solver_dec_ref(s.ctx.raw, solver)
+
+		if timeout > 0 {
+			if err := s.ctx.setTimeout(solver, timeout); err != nil {
+				return false, nil, err
+			}
+		}
+
+		// Assert constraints.
+		// println("dbg/solve", len(constraints))
+		for _, constraint := range constraints {
+			if err := s.assert(solver, constraint); err != nil {
+				return false, nil, err
+			}
+		}
 	}
 
 	// Check equations with the solver.
@@ -85,9 +252,9 @@ func (s *Solver) Solve(constraints []glee.Expr, arrays []*glee.Array) (satisfiab
 			return false, nil, glee.ErrSolverTimeout
 		case strings.Contains(reason, "canceled"):
 			return false, nil, glee.ErrSolverCanceled
-		case strings.Contains(reason, "(resource limits reached)"):
+		case strings.Contains(reason, "(resource limits reached)"), strings.Contains(reason, "memory"):
 			return false, nil, glee.ErrSolverResourceLimit
-		case strings.Contains(reason, "unknown"):
+		case strings.Contains(reason, "unknown"), strings.Contains(reason, "incomplete"):
 			return false, nil, glee.ErrSolverUnknown
 		default:
 			return false, nil, fmt.Errorf("z3: %s", reason)
@@ -111,9 +278,143 @@ func (s *Solver) Solve(constraints []glee.Expr, arrays []*glee.Array) (satisfiab
 	return true, values, nil
 }
 
+// prepare returns the retained solver with constraints asserted. Scopes for
+// constraints which differ from the previous query are popped & a new scope
+// is pushed for each remaining constraint.
+func (s *Solver) prepare(constraints []glee.Expr, timeout time.Duration) (C.Z3_solver, error) {
+	if s.solver == nil {
+		solver, err := s.makeSolver()
+		if err != nil {
+			return nil, err
+		}
+		s.solver = solver
+	}
+
+	// The timeout is set on every query as the solver outlives the query.
+	if err := s.ctx.setTimeout(s.solver, timeout); err != nil {
+		return nil, err
+	}
+
+	// Determine the number of constraints shared with the previous query.
+	var n int
+	for n < len(s.asserted) && n < len(constraints) && glee.CompareExpr(s.asserted[n], constraints[n]) == 0 {
+		n++
+	}
+	s.stats.ReuseN += n
+
+	// Discard scopes of constraints not shared with this query.
+	if popN := len(s.asserted) - n; popN > 0 {
+		C.Z3_solver_pop(s.ctx.raw, s.solver, C.uint(popN))
+		if err := s.ctx.err("Z3_solver_pop"); err != nil {
+			return nil, err
+		}
+		s.asserted = s.asserted[:n]
+		s.stats.PopN += popN
+	}
+
+	// Assert each new constraint in its own scope so it can be popped later.
+	for _, constraint := range constraints[n:] {
+		C.Z3_solver_push(s.ctx.raw, s.solver)
+		if err := s.ctx.err("Z3_solver_push"); err != nil {
+			return nil, err
+		}
+		s.asserted = append(s.asserted, constraint)
+		s.stats.PushN++
+
+		if err := s.assert(s.solver, constraint); err != nil {
+			return nil, err
+		}
+	}
+	return s.solver, nil
+}
+
+// makeSolver returns a new Z3 solver for the configured tactics or logic. The
+// caller must release the returned solver's reference.
+func (s *Solver) makeSolver() (C.Z3_solver, error) {
+	var solver C.Z3_solver
+	switch {
+	case len(s.config.Tactics) > 0:
+		tactic, err := s.ctx.makeTactic(s.config.Tactics)
+		if err != nil {
+			return nil, err
+		}
+		defer C.Z3_tactic_dec_ref(s.ctx.raw, tactic)
+
+		solver = C.Z3_mk_solver_from_tactic(s.ctx.raw, tactic)
+		if err := s.ctx.err("Z3_mk_solver_from_tactic"); err != nil {
+			return nil, err
+		}
+
+	case s.config.Logic != "":
+		clogic := C.CString(s.config.Logic)
+		defer C.free(unsafe.Pointer(clogic))
+
+		solver = C.Z3_mk_solver_for_logic(s.ctx.raw, C.Z3_mk_string_symbol(s.ctx.raw, clogic))
+		if err := s.ctx.err("Z3_mk_solver_for_logic"); err != nil {
+			return nil, err
+		}
+
+	default:
+		solver = C.Z3_mk_solver(s.ctx.raw)
+		if err := s.ctx.err("Z3_mk_solver"); err != nil {
+			return nil, err
+		}
+	}
+	C.Z3_solver_inc_ref(s.ctx.raw, solver)
+
+	if s.config.RandomSeed != 0 {
+		if err := s.ctx.setSolverParam(solver, "random_seed", s.config.RandomSeed); err != nil {
+			C.Z3_solver_dec_ref(s.ctx.raw, solver)
+			return nil, err
+		}
+	}
+	return solver, nil
+}
+
+// assert adds constraint to solver along with the bounds of any symbolic
+// array indexes within it.
+func (s *Solver) assert(solver C.Z3_solver, constraint glee.Expr) error {
+	s.ctx.bounds = s.ctx.bounds[:0]
+	z3Constraint, err := s.ctx.toAST(constraint)
+	if err != nil {
+		return err
+	}
+	C.Z3_solver_assert(s.ctx.raw, solver, z3Constraint)
+	if err := s.ctx.err("Z3_solver_assert"); err != nil {
+		return err
+	}
+	for _, bound := range s.ctx.bounds {
+		C.Z3_solver_assert(s.ctx.raw, solver, bound)
+		if err := s.ctx.err("Z3_solver_assert[bound]"); err != nil {
+			return err
+		}
+	}
+	s.stats.AssertN++
+	// println("dbg/solve.assert\n", s.ctx.astToString(z3Constraint))
+	return nil
+}
+
+// reset releases the retained solver, if any.
+func (s *Solver) reset() {
+	if s.solver != nil {
+		C.Z3_solver_dec_ref(s.ctx.raw, s.solver)
+		s.solver, s.asserted = nil, nil
+	}
+}
+
 // Context represents a Z3 context object that is used for constructing expressions.
 type Context struct {
 	raw C.Z3_context
+
+	// Encoding of symbolic array reads & evaluation of solved arrays, set
+	// by the solver for each query.
+	encoding     ArrayEncoding
+	iteThreshold int
+	evalPerByte  bool
+
+	// Bounds of symbolic indexes encountered while converting a constraint
+	// with ArrayEncodingBounded. Asserted alongside the constraint.
+	bounds []C.Z3_ast
 }
 
 // NewContext returns a new instance of Context.
@@ -133,6 +434,81 @@ func (ctx *Context) Close() error {
 	return ctx.err("Z3_del_context")
 }
 
+// setTimeout sets the maximum duration of a check on solver. Z3 accepts
+// timeouts in milliseconds so the duration is rounded up. A zero timeout
+// removes the limit.
+func (ctx *Context) setTimeout(solver C.Z3_solver, timeout time.Duration) error {
+	ms := uint64((timeout + time.Millisecond - 1) / time.Millisecond)
+	if timeout <= 0 || ms > math.MaxUint32 {
+		ms = math.MaxUint32
+	}
+	return ctx.setSolverParam(solver, "timeout", uint(ms))
+}
+
+// setSolverParam sets an unsigned integer parameter on solver. Other
+// parameters of the solver are retained.
+func (ctx *Context) setSolverParam(solver C.Z3_solver, name string, value uint) error {
+	params := C.Z3_mk_params(ctx.raw)
+	if err := ctx.err("Z3_mk_params"); err != nil {
+		return err
+	}
+	C.Z3_params_inc_ref(ctx.raw, params)
+	defer C.Z3_params_dec_ref(ctx.raw, params)
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	C.Z3_params_set_uint(ctx.raw, params, C.Z3_mk_string_symbol(ctx.raw, cname), C.uint(value))
+	if err := ctx.err("Z3_params_set_uint"); err != nil {
+		return err
+	}
+
+	C.Z3_solver_set_params(ctx.raw, solver, params)
+	return ctx.err("Z3_solver_set_params")
+}
+
+// makeTactic returns a tactic applying each named tactic in order. The caller
+// must release the returned tactic's reference.
+func (ctx *Context) makeTactic(names []string) (C.Z3_tactic, error) {
+	var tactic C.Z3_tactic
+	for _, name := range names {
+		cname := C.CString(name)
+		t := C.Z3_mk_tactic(ctx.raw, cname)
+		C.free(unsafe.Pointer(cname))
+		if err := ctx.err("Z3_mk_tactic"); err != nil {
+			if tactic != nil {
+				C.Z3_tactic_dec_ref(ctx.raw, tactic)
+			}
+			return nil, fmt.Errorf("z3: invalid tactic %q: %w", name, err)
+		}
+		C.Z3_tactic_inc_ref(ctx.raw, t)
+
+		if tactic == nil {
+			tactic = t
+			continue
+		}
+
+		next := C.Z3_tactic_and_then(ctx.raw, tactic, t)
+		err := ctx.err("Z3_tactic_and_then")
+		C.Z3_tactic_dec_ref(ctx.raw, tactic)
+		C.Z3_tactic_dec_ref(ctx.raw, t)
+		if err != nil {
+			return nil, err
+		}
+		C.Z3_tactic_inc_ref(ctx.raw, next)
+		tactic = next
+	}
+	return tactic, nil
+}
+
+// setGlobalParam sets a Z3 parameter shared by all contexts in the process.
+func setGlobalParam(name, value string) {
+	cname, cvalue := C.CString(name), C.CString(value)
+	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cvalue))
+	C.Z3_global_param_set(cname, cvalue)
+}
+
 // err returns the error for the last API call. Returns nil if last call was successful.
 func (ctx *Context) err(op string) error {
 	if code := C.Z3_get_error_code(ctx.raw); code != C.Z3_OK {
@@ -160,6 +536,12 @@ func (ctx *Context) toAST(expr glee.Expr) (C.Z3_ast, error) {
 		return ctx.toNotAST(expr)
 	case *glee.BinaryExpr:
 		return ctx.toBinaryAST(expr)
+	case *glee.FPBinaryExpr:
+		return ctx.toFPBinaryAST(expr)
+	case *glee.FPCastExpr:
+		return ctx.toFPCastAST(expr)
+	case *glee.IteExpr:
+		return ctx.toIteAST(expr)
 	default:
 		return nil, fmt.Errorf("ctx.Context.toAST: invalid expression type: %T", expr)
 	}
@@ -176,21 +558,141 @@ func (ctx *Context) toConstantAST(expr *glee.ConstantExpr) (C.Z3_ast, error) {
 	} else if expr.Width <= 64 {
 		return ctx.makeUint64(expr.Width, expr.Value)
 	}
-	return nil, fmt.Errorf("z3.Context.toConstantAST: invalid expression width: %d", expr.Width)
+	return ctx.makeNumeral(expr.Width, expr.BigInt().String())
 }
 
 func (ctx *Context) toSelectAST(expr *glee.SelectExpr) (C.Z3_ast, error) {
-	array, err := ctx.makeArrayWithUpdate(expr.Array, expr.Array.Updates)
+	index, err := ctx.toAST(expr.Index)
 	if err != nil {
 		return nil, err
 	}
-	index, err := ctx.toAST(expr.Index)
+
+	// Reads at constant indexes are always encoded as a Z3 array select.
+	if _, ok := expr.Index.(*glee.ConstantExpr); !ok {
+		switch ctx.arrayEncoding(expr.Array) {
+		case ArrayEncodingITE:
+			return ctx.makeSelectITE(expr.Array, index)
+		case ArrayEncodingBounded:
+			size, err := ctx.makeUint64(glee.Width64, uint64(expr.Array.Size))
+			if err != nil {
+				return nil, err
+			}
+			bound := C.Z3_mk_bvult(ctx.raw, index, size)
+			if err := ctx.err("Z3_mk_bvult"); err != nil {
+				return nil, err
+			}
+			ctx.bounds = append(ctx.bounds, bound)
+		}
+	}
+
+	array, err := ctx.makeArray(expr.Array)
 	if err != nil {
 		return nil, err
 	}
 	return C.Z3_mk_select(ctx.raw, array, index), ctx.err("Z3_mk_select")
 }
 
+// arrayEncoding returns the encoding used for symbolic reads from array.
+func (ctx *Context) arrayEncoding(array *glee.Array) ArrayEncoding {
+	if ctx.encoding != ArrayEncodingAuto {
+		return ctx.encoding
+	} else if int(array.Size) <= ctx.iteThreshold {
+		return ArrayEncodingITE
+	}
+	return ArrayEncodingBounded
+}
+
+// makeSelectITE returns an if-then-else tree reading the byte at index from
+// array. Indexes outside the array read from the root array, the same as a
+// select from the Z3 array.
+func (ctx *Context) makeSelectITE(array *glee.Array, index C.Z3_ast) (C.Z3_ast, error) {
+	root, err := ctx.makeArrayConst(array)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert updates once as they are compared against every position.
+	var updates []iteUpdate
+	for upd := array.Updates; upd != nil; upd = upd.Next {
+		u := iteUpdate{upd: upd}
+		if u.index, err = ctx.toAST(upd.Index); err != nil {
+			return nil, err
+		} else if u.value, err = ctx.toAST(upd.Value); err != nil {
+			return nil, err
+		}
+		updates = append(updates, u)
+	}
+
+	result := C.Z3_mk_select(ctx.raw, root, index)
+	if err := ctx.err("Z3_mk_select"); err != nil {
+		return nil, err
+	}
+	for i := int64(array.Size) - 1; i >= 0; i-- {
+		offset, err := ctx.makeUint64(glee.Width64, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		value, err := ctx.makeByteITE(root, offset, uint64(i), updates)
+		if err != nil {
+			return nil, err
+		}
+
+		cond := C.Z3_mk_eq(ctx.raw, index, offset)
+		if err := ctx.err("Z3_mk_eq"); err != nil {
+			return nil, err
+		}
+		result = C.Z3_mk_ite(ctx.raw, cond, value, result)
+		if err := ctx.err("Z3_mk_ite"); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// iteUpdate represents an array update along with its converted index & value.
+type iteUpdate struct {
+	upd          *glee.ArrayUpdate
+	index, value C.Z3_ast
+}
+
+// makeByteITE returns the value of the byte at constant position i of an
+// array with the given updates, ordered newest first. Updates at symbolic
+// indexes are applied conditionally.
+func (ctx *Context) makeByteITE(root, offset C.Z3_ast, i uint64, updates []iteUpdate) (C.Z3_ast, error) {
+	// Updates older than the newest constant update at i are overwritten.
+	n := len(updates)
+	for j, u := range updates {
+		if index, ok := u.upd.Index.(*glee.ConstantExpr); ok && index.Value == i {
+			n = j + 1
+			break
+		}
+	}
+
+	value := C.Z3_mk_select(ctx.raw, root, offset)
+	if err := ctx.err("Z3_mk_select"); err != nil {
+		return nil, err
+	}
+	for j := n - 1; j >= 0; j-- {
+		u := updates[j]
+		if index, ok := u.upd.Index.(*glee.ConstantExpr); ok {
+			if index.Value == i {
+				value = u.value
+			}
+			continue
+		}
+
+		cond := C.Z3_mk_eq(ctx.raw, u.index, offset)
+		if err := ctx.err("Z3_mk_eq"); err != nil {
+			return nil, err
+		}
+		value = C.Z3_mk_ite(ctx.raw, cond, u.value, value)
+		if err := ctx.err("Z3_mk_ite"); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
 func (ctx *Context) toConcatAST(expr *glee.ConcatExpr) (C.Z3_ast, error) {
 	msb, err := ctx.toAST(expr.MSB)
 	if err != nil {
@@ -297,6 +799,24 @@ func (ctx *Context) toNotAST(expr *glee.NotExpr) (C.Z3_ast, error) {
 	return C.Z3_mk_bvnot(ctx.raw, src), ctx.err("Z3_mk_bvnot")
 }
 
+// toIteAST returns an if-then-else term. Boolean branches have the bool sort
+// so they are passed through unchanged like any other branch.
+func (ctx *Context) toIteAST(expr *glee.IteExpr) (C.Z3_ast, error) {
+	cond, err := ctx.toAST(expr.Cond)
+	if err != nil {
+		return nil, err
+	}
+	then, err := ctx.toAST(expr.Then)
+	if err != nil {
+		return nil, err
+	}
+	els, err := ctx.toAST(expr.Else)
+	if err != nil {
+		return nil, err
+	}
+	return C.Z3_mk_ite(ctx.raw, cond, then, els), ctx.err("Z3_mk_ite")
+}
+
 func (ctx *Context) toBinaryAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	switch expr.Op {
 	case glee.ADD:
@@ -479,12 +999,19 @@ func (ctx *Context) toBinaryXorAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	return C.Z3_mk_bvxor(ctx.raw, lhs, rhs), ctx.err("Z3_mk_bvxor")
 }
 
+// toShiftAmountAST returns the shift amount of expr with the width of the
+// shifted value. Z3 shifts yield zero, or the sign bits for arithmetic
+// shifts, for amounts of the width or more which matches Go.
+func (ctx *Context) toShiftAmountAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
+	return ctx.toAST(glee.ShiftAmountExpr(expr.RHS, glee.ExprWidth(expr.LHS)))
+}
+
 func (ctx *Context) toBinaryShlAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	lhs, err := ctx.toAST(expr.LHS)
 	if err != nil {
 		return nil, err
 	}
-	rhs, err := ctx.toAST(expr.RHS)
+	rhs, err := ctx.toShiftAmountAST(expr)
 	if err != nil {
 		return nil, err
 	}
@@ -496,7 +1023,7 @@ func (ctx *Context) toBinaryLShrAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	if err != nil {
 		return nil, err
 	}
-	rhs, err := ctx.toAST(expr.RHS)
+	rhs, err := ctx.toShiftAmountAST(expr)
 	if err != nil {
 		return nil, err
 	}
@@ -508,7 +1035,7 @@ func (ctx *Context) toBinaryAShrAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	if err != nil {
 		return nil, err
 	}
-	rhs, err := ctx.toAST(expr.RHS)
+	rhs, err := ctx.toShiftAmountAST(expr)
 	if err != nil {
 		return nil, err
 	}
@@ -578,6 +1105,134 @@ func (ctx *Context) toBinarySleAST(expr *glee.BinaryExpr) (C.Z3_ast, error) {
 	return C.Z3_mk_bvsle(ctx.raw, lhs, rhs), ctx.err("Z3_mk_bvsle")
 }
 
+// toFPBinaryAST converts the IEEE 754 bit vector operands to floating-point
+// terms, applies the operation, and converts arithmetic results back.
+func (ctx *Context) toFPBinaryAST(expr *glee.FPBinaryExpr) (C.Z3_ast, error) {
+	lhs, err := ctx.toFPAST(expr.LHS)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := ctx.toFPAST(expr.RHS)
+	if err != nil {
+		return nil, err
+	}
+
+	rm := C.Z3_mk_fpa_round_nearest_ties_to_even(ctx.raw)
+	if err := ctx.err("Z3_mk_fpa_round_nearest_ties_to_even"); err != nil {
+		return nil, err
+	}
+
+	var ast C.Z3_ast
+	switch expr.Op {
+	case glee.FADD:
+		ast = C.Z3_mk_fpa_add(ctx.raw, rm, lhs, rhs)
+	case glee.FSUB:
+		ast = C.Z3_mk_fpa_sub(ctx.raw, rm, lhs, rhs)
+	case glee.FMUL:
+		ast = C.Z3_mk_fpa_mul(ctx.raw, rm, lhs, rhs)
+	case glee.FDIV:
+		ast = C.Z3_mk_fpa_div(ctx.raw, rm, lhs, rhs)
+	case glee.FEQ:
+		return C.Z3_mk_fpa_eq(ctx.raw, lhs, rhs), ctx.err("Z3_mk_fpa_eq")
+	case glee.FLT:
+		return C.Z3_mk_fpa_lt(ctx.raw, lhs, rhs), ctx.err("Z3_mk_fpa_lt")
+	case glee.FLE:
+		return C.Z3_mk_fpa_leq(ctx.raw, lhs, rhs), ctx.err("Z3_mk_fpa_leq")
+	default:
+		return nil, fmt.Errorf("ctx.Context.toFPBinaryAST: unexpected operation: %s", expr.Op)
+	}
+	if err := ctx.err("Z3_mk_fpa_" + expr.Op.String()); err != nil {
+		return nil, err
+	}
+	return C.Z3_mk_fpa_to_ieee_bv(ctx.raw, ast), ctx.err("Z3_mk_fpa_to_ieee_bv")
+}
+
+func (ctx *Context) toFPCastAST(expr *glee.FPCastExpr) (C.Z3_ast, error) {
+	// Go rounds to nearest when converting to floats & truncates to integers.
+	rne := C.Z3_mk_fpa_round_nearest_ties_to_even(ctx.raw)
+	if err := ctx.err("Z3_mk_fpa_round_nearest_ties_to_even"); err != nil {
+		return nil, err
+	}
+	rtz := C.Z3_mk_fpa_round_toward_zero(ctx.raw)
+	if err := ctx.err("Z3_mk_fpa_round_toward_zero"); err != nil {
+		return nil, err
+	}
+
+	switch expr.Op {
+	case glee.FPEXT, glee.FPTOSI, glee.FPTOUI:
+		src, err := ctx.toFPAST(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+
+		switch expr.Op {
+		case glee.FPTOSI:
+			return C.Z3_mk_fpa_to_sbv(ctx.raw, rtz, src, C.uint(expr.Width)), ctx.err("Z3_mk_fpa_to_sbv")
+		case glee.FPTOUI:
+			return C.Z3_mk_fpa_to_ubv(ctx.raw, rtz, src, C.uint(expr.Width)), ctx.err("Z3_mk_fpa_to_ubv")
+		}
+
+		t, err := ctx.makeFPSort(expr.Width)
+		if err != nil {
+			return nil, err
+		}
+		ast := C.Z3_mk_fpa_to_fp_float(ctx.raw, rne, src, t)
+		if err := ctx.err("Z3_mk_fpa_to_fp_float"); err != nil {
+			return nil, err
+		}
+		return C.Z3_mk_fpa_to_ieee_bv(ctx.raw, ast), ctx.err("Z3_mk_fpa_to_ieee_bv")
+
+	case glee.SITOFP, glee.UITOFP:
+		src, err := ctx.toAST(expr.Src)
+		if err != nil {
+			return nil, err
+		}
+		t, err := ctx.makeFPSort(expr.Width)
+		if err != nil {
+			return nil, err
+		}
+
+		var ast C.Z3_ast
+		if expr.Op == glee.SITOFP {
+			ast = C.Z3_mk_fpa_to_fp_signed(ctx.raw, rne, src, t)
+		} else {
+			ast = C.Z3_mk_fpa_to_fp_unsigned(ctx.raw, rne, src, t)
+		}
+		if err := ctx.err("Z3_mk_fpa_to_fp_" + expr.Op.String()); err != nil {
+			return nil, err
+		}
+		return C.Z3_mk_fpa_to_ieee_bv(ctx.raw, ast), ctx.err("Z3_mk_fpa_to_ieee_bv")
+
+	default:
+		return nil, fmt.Errorf("ctx.Context.toFPCastAST: unexpected operation: %s", expr.Op)
+	}
+}
+
+// toFPAST returns a floating-point term from an expression holding an IEEE 754 encoding.
+func (ctx *Context) toFPAST(expr glee.Expr) (C.Z3_ast, error) {
+	src, err := ctx.toAST(expr)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ctx.makeFPSort(glee.ExprWidth(expr))
+	if err != nil {
+		return nil, err
+	}
+	return C.Z3_mk_fpa_to_fp_bv(ctx.raw, src, t), ctx.err("Z3_mk_fpa_to_fp_bv")
+}
+
+// makeFPSort returns the IEEE 754 floating-point sort for a 32 or 64-bit width.
+func (ctx *Context) makeFPSort(width uint) (C.Z3_sort, error) {
+	switch width {
+	case glee.Width32:
+		return C.Z3_mk_fpa_sort_32(ctx.raw), ctx.err("Z3_mk_fpa_sort_32")
+	case glee.Width64:
+		return C.Z3_mk_fpa_sort_64(ctx.raw), ctx.err("Z3_mk_fpa_sort_64")
+	default:
+		return nil, fmt.Errorf("z3.Context.makeFPSort: invalid width: %d", width)
+	}
+}
+
 func (ctx *Context) makeTrue() (C.Z3_ast, error) {
 	return C.Z3_mk_true(ctx.raw), ctx.err("Z3_mk_true")
 }
@@ -606,6 +1261,19 @@ func (ctx *Context) makeUint64(width uint, value uint64) (C.Z3_ast, error) {
 	return C.Z3_mk_unsigned_int64(ctx.raw, C.ulonglong(value), t), ctx.err("Z3_mk_unsigned_int64")
 }
 
+// makeNumeral returns a bit vector from a decimal string. Used for values
+// which do not fit in 64 bits.
+func (ctx *Context) makeNumeral(width uint, value string) (C.Z3_ast, error) {
+	t, err := ctx.makeBVSort(width)
+	if err != nil {
+		return nil, err
+	}
+
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	return C.Z3_mk_numeral(ctx.raw, cvalue, t), ctx.err("Z3_mk_numeral")
+}
+
 func (ctx *Context) bvSize(expr C.Z3_ast) uint {
 	t := C.Z3_get_sort(ctx.raw, expr)
 	if err := ctx.err("Z3_get_sort"); err != nil {
@@ -647,6 +1315,124 @@ func (ctx *Context) makeArrayConst(array *glee.Array) (C.Z3_ast, error) {
 	return C.Z3_mk_const(ctx.raw, nameSymbol, arraySort), ctx.err("Z3_mk_const")
 }
 
+// makeArray returns array with its updates applied. If the oldest updates
+// write a constant to every byte, such as a zero-initialized array, they are
+// encoded as a constant array so the root array is not referenced.
+func (ctx *Context) makeArray(array *glee.Array) (C.Z3_ast, error) {
+	values, next := initialValues(array)
+	if values == nil {
+		return ctx.makeArrayWithUpdate(array, array.Updates)
+	}
+
+	result, err := ctx.makeConstArray(values)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the remaining updates, oldest first.
+	for i := len(next) - 1; i >= 0; i-- {
+		index, err := ctx.toAST(next[i].Index)
+		if err != nil {
+			return nil, err
+		}
+		value, err := ctx.toAST(next[i].Value)
+		if err != nil {
+			return nil, err
+		}
+		result = C.Z3_mk_store(ctx.raw, result, index, value)
+		if err := ctx.err("Z3_mk_store"); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// makeConstArray returns an array holding values. The most common value is
+// used for every index & the remaining values are stored over it.
+func (ctx *Context) makeConstArray(values []byte) (C.Z3_ast, error) {
+	var counts [256]int
+	var common byte
+	for _, v := range values {
+		if counts[v]++; counts[v] > counts[common] {
+			common = v
+		}
+	}
+
+	domainSort := C.Z3_mk_bv_sort(ctx.raw, C.uint(glee.Width64))
+	if err := ctx.err("Z3_mk_bv_sort[domain]"); err != nil {
+		return nil, err
+	}
+	defaultValue, err := ctx.makeUint(glee.Width8, uint32(common))
+	if err != nil {
+		return nil, err
+	}
+	result := C.Z3_mk_const_array(ctx.raw, domainSort, defaultValue)
+	if err := ctx.err("Z3_mk_const_array"); err != nil {
+		return nil, err
+	}
+
+	for i, v := range values {
+		if v == common {
+			continue
+		}
+		index, err := ctx.makeUint64(glee.Width64, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		value, err := ctx.makeUint(glee.Width8, uint32(v))
+		if err != nil {
+			return nil, err
+		}
+		result = C.Z3_mk_store(ctx.raw, result, index, value)
+		if err := ctx.err("Z3_mk_store"); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// initialValues returns the value of every byte of array if its oldest
+// updates write a constant value at a constant index to each byte. The
+// remaining updates are returned, newest first. Returns nil values if the
+// array is not fully initialized by constant updates.
+func initialValues(array *glee.Array) (values []byte, next []*glee.ArrayUpdate) {
+	if array.Size == 0 {
+		return nil, nil
+	}
+
+	var updates []*glee.ArrayUpdate
+	for upd := array.Updates; upd != nil; upd = upd.Next {
+		updates = append(updates, upd)
+	}
+
+	// Apply constant updates from the oldest until one is not constant.
+	values = make([]byte, array.Size)
+	written := make([]bool, array.Size)
+	var n uint
+	i := len(updates) - 1
+	for ; i >= 0; i-- {
+		index, ok := updates[i].Index.(*glee.ConstantExpr)
+		if !ok || index.Value >= uint64(array.Size) {
+			break
+		}
+		value, ok := updates[i].Value.(*glee.ConstantExpr)
+		if !ok {
+			break
+		}
+
+		values[index.Value] = byte(value.Value)
+		if !written[index.Value] {
+			written[index.Value] = true
+			n++
+		}
+	}
+
+	if n < array.Size {
+		return nil, nil
+	}
+	return values, updates[:i+1]
+}
+
 // makeArrayWithUpdate returns an array with updates recursively applied.
 func (ctx *Context) makeArrayWithUpdate(root *glee.Array, upd *glee.ArrayUpdate) (C.Z3_ast, error) {
 	if upd == nil {
@@ -682,7 +1468,22 @@ func (ctx *Context) eval(model C.Z3_model, arrays []*glee.Array) ([][]byte, erro
 }
 
 // evalArray evaluates a single array into its initial byte slice value.
+// Arrays fully initialized by constant updates are not evaluated as their
+// root is never read.
 func (ctx *Context) evalArray(model C.Z3_model, array *glee.Array) ([]byte, error) {
+	if values, _ := initialValues(array); values != nil {
+		return values, nil
+	}
+
+	// Decode the interpretation of the array, if in a supported form.
+	if !ctx.evalPerByte {
+		if value, ok, err := ctx.evalArrayInterp(model, array); err != nil {
+			return nil, err
+		} else if ok {
+			return value, nil
+		}
+	}
+
 	value := make([]byte, 0, array.Size)
 	for offset := uint(0); offset < array.Size; offset++ {
 		// Generate a reference to the root array.
@@ -719,6 +1520,127 @@ func (ctx *Context) evalArray(model C.Z3_model, array *glee.Array) ([]byte, erro
 	return value, nil
 }
 
+// evalArrayInterp evaluates array by decoding the model's interpretation of
+// the root array with a single evaluation. Returns false if the
+// interpretation is not a function interpretation, constant array, or chain
+// of stores.
+func (ctx *Context) evalArrayInterp(model C.Z3_model, array *glee.Array) ([]byte, bool, error) {
+	root, err := ctx.makeArrayConst(array)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var interp C.Z3_ast
+	C.Z3_model_eval(ctx.raw, model, root, C.bool(true), &interp)
+	if err := ctx.err("Z3_model_eval"); err != nil {
+		return nil, false, err
+	}
+
+	value := make([]byte, array.Size)
+	if ok, err := ctx.decodeArrayInterp(model, interp, value); err != nil || !ok {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// decodeArrayInterp writes the bytes of the array interpretation ast to value.
+// Returns false if ast is not in a supported form.
+func (ctx *Context) decodeArrayInterp(model C.Z3_model, ast C.Z3_ast, value []byte) (bool, error) {
+	// Function interpretations hold a value for each index & a default.
+	if C.Z3_is_as_array(ctx.raw, ast) {
+		decl := C.Z3_get_as_array_func_decl(ctx.raw, ast)
+		if err := ctx.err("Z3_get_as_array_func_decl"); err != nil {
+			return false, err
+		}
+		interp := C.Z3_model_get_func_interp(ctx.raw, model, decl)
+		if err := ctx.err("Z3_model_get_func_interp"); err != nil {
+			return false, err
+		} else if interp == nil {
+			return false, nil
+		}
+		C.Z3_func_interp_inc_ref(ctx.raw, interp)
+		defer C.Z3_func_interp_dec_ref(ctx.raw, interp)
+
+		if def := C.Z3_func_interp_get_else(ctx.raw, interp); def == nil || !ctx.fillArrayValue(value, def) {
+			return false, nil
+		}
+
+		n := uint(C.Z3_func_interp_get_num_entries(ctx.raw, interp))
+		for i := uint(0); i < n; i++ {
+			entry := C.Z3_func_interp_get_entry(ctx.raw, interp, C.uint(i))
+			if err := ctx.err("Z3_func_interp_get_entry"); err != nil {
+				return false, err
+			}
+			C.Z3_func_entry_inc_ref(ctx.raw, entry)
+			ok := ctx.setArrayValue(value, C.Z3_func_entry_get_arg(ctx.raw, entry, 0), C.Z3_func_entry_get_value(ctx.raw, entry))
+			C.Z3_func_entry_dec_ref(ctx.raw, entry)
+			if !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	if C.Z3_get_ast_kind(ctx.raw, ast) != C.Z3_APP_AST {
+		return false, nil
+	}
+	app := C.Z3_to_app(ctx.raw, ast)
+
+	switch C.Z3_get_decl_kind(ctx.raw, C.Z3_get_app_decl(ctx.raw, app)) {
+	case C.Z3_OP_CONST_ARRAY:
+		return ctx.fillArrayValue(value, C.Z3_get_app_arg(ctx.raw, app, 0)), nil
+	case C.Z3_OP_STORE:
+		// Apply older stores first so newer stores overwrite them.
+		if ok, err := ctx.decodeArrayInterp(model, C.Z3_get_app_arg(ctx.raw, app, 0), value); err != nil || !ok {
+			return false, err
+		}
+		return ctx.setArrayValue(value, C.Z3_get_app_arg(ctx.raw, app, 1), C.Z3_get_app_arg(ctx.raw, app, 2)), nil
+	default:
+		return false, nil
+	}
+}
+
+// fillArrayValue sets every byte of value to the numeral v. Returns false if
+// v is not a numeral.
+func (ctx *Context) fillArrayValue(value []byte, v C.Z3_ast) bool {
+	b, ok := ctx.numeralUint64(v)
+	if !ok {
+		return false
+	}
+	for i := range value {
+		value[i] = byte(b)
+	}
+	return true
+}
+
+// setArrayValue sets the byte of value at the numeral index to the numeral
+// v. Indexes outside of value are ignored. Returns false if either is not a
+// numeral.
+func (ctx *Context) setArrayValue(value []byte, index, v C.Z3_ast) bool {
+	i, ok := ctx.numeralUint64(index)
+	if !ok {
+		return false
+	}
+	b, ok := ctx.numeralUint64(v)
+	if !ok {
+		return false
+	}
+	if i < uint64(len(value)) {
+		value[i] = byte(b)
+	}
+	return true
+}
+
+// numeralUint64 returns the value of the numeral ast. Returns false if ast
+// is not a numeral which fits in 64 bits.
+func (ctx *Context) numeralUint64(ast C.Z3_ast) (uint64, bool) {
+	var v C.ulonglong
+	if !C.Z3_get_numeral_uint64(ctx.raw, ast, &v) || ctx.err("Z3_get_numeral_uint64") != nil {
+		return 0, false
+	}
+	return uint64(v), true
+}
+
 func (ctx *Context) astToString(ast C.Z3_ast) string {
 	return C.GoString(C.Z3_ast_to_string(ctx.raw, ast))
 }
@@ -774,7 +1696,16 @@ const (
 	ErrorCodeException
 )
 
+// Stats represents statistics for a solver.
 type Stats struct {
-	SolveN    int
-	SolveTime time.Duration
+	glee.QueryStats
+
+	// Number of constraints asserted to Z3.
+	AssertN int
+
+	// Incremental mode only. Number of constraints reused from the previous
+	// query along with the number of scopes pushed & popped.
+	ReuseN int
+	PushN  int
+	PopN   int
 }
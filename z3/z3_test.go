@@ -1,7 +1,10 @@
 package z3_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/benbjohnson/glee"
 	"github.com/benbjohnson/glee/z3"
@@ -257,6 +260,48 @@ func TestSolver_Solve(t *testing.T) {
 		})
 	})
 
+	t.Run("Ite", func(t *testing.T) {
+		t.Run("Bool", func(t *testing.T) {
+			s := z3.NewSolver()
+			defer MustCloseSolver(s)
+
+			array := glee.NewArray(100, 1)
+			x := array.Select(glee.NewConstantExpr64(0), 8, false)
+			cond := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr8(10))
+			if satisfiable, values, err := s.Solve([]glee.Expr{
+				glee.NewIteExpr(cond, glee.NewBoolConstantExpr(false), glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr8(20))),
+			}, []*glee.Array{array}); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if values[0][0] != 20 {
+				t.Fatalf("unexpected value: %d", values[0][0])
+			}
+		})
+		t.Run("Int", func(t *testing.T) {
+			s := z3.NewSolver()
+			defer MustCloseSolver(s)
+
+			array := glee.NewArray(100, 1)
+			x := array.Select(glee.NewConstantExpr64(0), 8, false)
+			abs := glee.NewIteExpr(
+				glee.NewBinaryExpr(glee.SLT, x, glee.NewConstantExpr8(0)),
+				glee.NewBinaryExpr(glee.SUB, glee.NewConstantExpr8(0), x),
+				x,
+			)
+			if satisfiable, values, err := s.Solve([]glee.Expr{
+				glee.NewBinaryExpr(glee.EQ, abs, glee.NewConstantExpr8(3)),
+				glee.NewBinaryExpr(glee.SLT, x, glee.NewConstantExpr8(0)),
+			}, []*glee.Array{array}); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if values[0][0] != 0xFD {
+				t.Fatalf("unexpected value: %d", values[0][0])
+			}
+		})
+	})
+
 	t.Run("BinaryExpr", func(t *testing.T) {
 		t.Run("ADD", func(t *testing.T) {
 			s := z3.NewSolver()
@@ -557,6 +602,25 @@ func TestSolver_Solve(t *testing.T) {
 					t.Fatal(diff)
 				}
 			})
+			// Ensure amounts of the width or more are not truncated to the value width.
+			t.Run("Oversized", func(t *testing.T) {
+				s := z3.NewSolver()
+				defer MustCloseSolver(s)
+				array := glee.NewArray(100, 8)
+				amount := array.Select(glee.NewConstantExpr64(0), 64, false)
+				if satisfiable, _, err := s.Solve([]glee.Expr{
+					glee.NewBinaryExpr(glee.EQ, amount, glee.NewConstantExpr64(256)),
+					&glee.BinaryExpr{
+						Op:  glee.EQ,
+						LHS: &glee.BinaryExpr{Op: glee.SHL, LHS: glee.NewConstantExpr(0x81, 8), RHS: amount},
+						RHS: glee.NewConstantExpr(0x81, 8),
+					},
+				}, []*glee.Array{array}); err != nil {
+					t.Fatal(err)
+				} else if satisfiable {
+					t.Fatal("expected unsatisfiable")
+				}
+			})
 		})
 		t.Run("LSHR", func(t *testing.T) {
 			t.Run("Constant", func(t *testing.T) {
@@ -647,6 +711,25 @@ func TestSolver_Solve(t *testing.T) {
 					t.Fatal(diff)
 				}
 			})
+			// Ensure amounts of the width or more are not truncated to the value width.
+			t.Run("Oversized", func(t *testing.T) {
+				s := z3.NewSolver()
+				defer MustCloseSolver(s)
+				array := glee.NewArray(100, 8)
+				amount := array.Select(glee.NewConstantExpr64(0), 64, false)
+				if satisfiable, _, err := s.Solve([]glee.Expr{
+					glee.NewBinaryExpr(glee.EQ, amount, glee.NewConstantExpr64(256)),
+					&glee.BinaryExpr{
+						Op:  glee.EQ,
+						LHS: &glee.BinaryExpr{Op: glee.ASHR, LHS: glee.NewConstantExpr(0x80, 8), RHS: amount},
+						RHS: glee.NewConstantExpr(0xFF, 8),
+					},
+				}, []*glee.Array{array}); err != nil {
+					t.Fatal(err)
+				} else if !satisfiable {
+					t.Fatal("expected satisfiable")
+				}
+			})
 		})
 		t.Run("EQ", func(t *testing.T) {
 			t.Run("Bool", func(t *testing.T) {
@@ -779,6 +862,343 @@ func TestSolver_Solve(t *testing.T) {
 	})
 }
 
+func TestSolver_Incremental(t *testing.T) {
+	s := z3.NewSolver()
+	s.Incremental = true
+	defer MustCloseSolver(s)
+
+	array := glee.NewArray(100, 1)
+	x := array.Select(glee.NewConstantExpr(0, 64), 8, false)
+	gt := glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(10, 8))
+	lt := glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(20, 8))
+	eq := glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr(5, 8))
+
+	// Initial query asserts all constraints.
+	if satisfiable, values, err := s.Solve([]glee.Expr{gt, lt}, []*glee.Array{array}); err != nil {
+		t.Fatal(err)
+	} else if !satisfiable {
+		t.Fatal("expected satisfiable")
+	} else if v := values[0][0]; v <= 10 || v >= 20 {
+		t.Fatalf("unexpected value: %d", v)
+	}
+
+	// Replacing the last constraint pops its scope & reuses the prefix.
+	if satisfiable, _, err := s.Solve([]glee.Expr{gt, eq}, []*glee.Array{array}); err != nil {
+		t.Fatal(err)
+	} else if satisfiable {
+		t.Fatal("expected unsatisfiable")
+	}
+
+	// A prefix of the previous query asserts nothing new.
+	if satisfiable, values, err := s.Solve([]glee.Expr{gt}, []*glee.Array{array}); err != nil {
+		t.Fatal(err)
+	} else if !satisfiable {
+		t.Fatal("expected satisfiable")
+	} else if v := values[0][0]; v <= 10 {
+		t.Fatalf("unexpected value: %d", v)
+	}
+
+	if stats := s.Stats(); stats.QueryN != 3 || stats.SatN != 2 || stats.UnsatN != 1 {
+		t.Fatalf("unexpected query stats: %s", stats.QueryStats)
+	} else if diff := cmp.Diff(stats, z3.Stats{
+		QueryStats: stats.QueryStats,
+		AssertN:    3,
+		ReuseN:     2,
+		PushN:      3,
+		PopN:       2,
+	}); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSolver_ArrayEncoding(t *testing.T) {
+	encodings := map[string]z3.ArrayEncoding{
+		"Store":   z3.ArrayEncodingStore,
+		"Bounded": z3.ArrayEncodingBounded,
+		"ITE":     z3.ArrayEncodingITE,
+		"Auto":    z3.ArrayEncodingAuto,
+	}
+
+	for name, encoding := range encodings {
+		t.Run(name, func(t *testing.T) {
+			// Read a byte at a symbolic index from an array with one update.
+			array, indexArray := glee.NewArray(100, 8), glee.NewArray(200, 1)
+			index := glee.NewCastExpr(indexArray.Select(glee.NewConstantExpr64(0), 8, false), 64, false)
+			updated := array.Store(glee.NewConstantExpr64(3), glee.NewConstantExpr(7, 8), false)
+			arrays := []*glee.Array{array, indexArray}
+
+			constraints := []glee.Expr{
+				glee.NewBinaryExpr(glee.ULT, index, glee.NewConstantExpr64(8)),
+				glee.NewBinaryExpr(glee.EQ, updated.Select(index, 8, false), glee.NewConstantExpr(7, 8)),
+				glee.NewNotExpr(glee.NewBinaryExpr(glee.EQ, index, glee.NewConstantExpr64(3))),
+			}
+
+			s := z3.NewSolver()
+			s.ArrayEncoding = encoding
+			defer MustCloseSolver(s)
+
+			// The read must be from the original array as the updated index is excluded.
+			if satisfiable, values, err := s.Solve(constraints, arrays); err != nil {
+				t.Fatal(err)
+			} else if !satisfiable {
+				t.Fatal("expected satisfiable")
+			} else if i := values[1][0]; i >= 8 || i == 3 || values[0][i] != 7 {
+				t.Fatalf("unexpected values: %v", values)
+			}
+
+			// Excluding the value from every byte of the original array leaves
+			// only the updated byte, which is also excluded.
+			for i := uint64(0); i < 8; i++ {
+				constraints = append(constraints, glee.NewNotExpr(glee.NewBinaryExpr(glee.EQ,
+					array.Select(glee.NewConstantExpr64(i), 8, false),
+					glee.NewConstantExpr(7, 8),
+				)))
+			}
+			if satisfiable, _, err := s.Solve(constraints, arrays); err != nil {
+				t.Fatal(err)
+			} else if satisfiable {
+				t.Fatal("expected unsatisfiable")
+			}
+		})
+	}
+}
+
+func TestSolver_ConstArray(t *testing.T) {
+	// Initialize every byte with a constant, as a zeroed array is.
+	array := glee.NewArray(100, 4)
+	for i, v := range []uint64{0, 0, 7, 0} {
+		array = array.Store(glee.NewConstantExpr64(uint64(i)), glee.NewConstantExpr(v, 8), false)
+	}
+
+	// Reads at any index, even outside the array, only observe the
+	// initialized values rather than the unconstrained root array.
+	indexArray := glee.NewArray(200, 8)
+	index := indexArray.Select(glee.NewConstantExpr64(0), 64, false)
+	read := glee.NewBinaryExpr(glee.EQ, array.Select(index, 8, false), glee.NewConstantExpr(9, 8))
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		s := z3.NewSolver()
+		defer MustCloseSolver(s)
+		if satisfiable, _, err := s.Solve([]glee.Expr{read}, []*glee.Array{indexArray}); err != nil {
+			t.Fatal(err)
+		} else if satisfiable {
+			t.Fatal("expected unsatisfiable")
+		}
+	})
+
+	// Ensure later updates are applied over the initialized values.
+	t.Run("Updated", func(t *testing.T) {
+		s := z3.NewSolver()
+		defer MustCloseSolver(s)
+
+		updated := array.Store(glee.NewConstantExpr64(1), glee.NewConstantExpr(9, 8), false)
+		read := glee.NewBinaryExpr(glee.EQ, updated.Select(index, 8, false), glee.NewConstantExpr(9, 8))
+		if satisfiable, values, err := s.Solve([]glee.Expr{read}, []*glee.Array{indexArray}); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if diff := cmp.Diff(values, [][]byte{{0, 0, 0, 0, 0, 0, 0, 1}}); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	// Ensure values of an initialized array are returned without evaluation.
+	t.Run("Eval", func(t *testing.T) {
+		s := z3.NewSolver()
+		defer MustCloseSolver(s)
+
+		if satisfiable, values, err := s.Solve([]glee.Expr{glee.NewBoolConstantExpr(true)}, []*glee.Array{array}); err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		} else if diff := cmp.Diff(values, [][]byte{{0, 0, 7, 0}}); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
+
+// Ensure decoding the model's interpretation of an array returns the same
+// values as evaluating each byte.
+func TestSolver_EvalArray(t *testing.T) {
+	array := glee.NewArray(100, 64)
+	var constraints []glee.Expr
+	for i := uint64(0); i < 64; i += 3 {
+		constraints = append(constraints, glee.NewBinaryExpr(glee.EQ,
+			array.Select(glee.NewConstantExpr64(i), 8, false),
+			glee.NewConstantExpr(i*7%251, 8),
+		))
+	}
+
+	var results [][][]byte
+	for _, perByte := range []bool{false, true} {
+		s := z3.NewSolver()
+		s.EvalPerByte = perByte
+		defer MustCloseSolver(s)
+
+		satisfiable, values, err := s.Solve(constraints, []*glee.Array{array})
+		if err != nil {
+			t.Fatal(err)
+		} else if !satisfiable {
+			t.Fatal("expected satisfiable")
+		}
+		for i := uint64(0); i < 64; i += 3 {
+			if got, want := values[0][i], byte(i*7%251); got != want {
+				t.Fatalf("EvalPerByte=%v: unexpected value at %d: %d", perByte, i, got)
+			}
+		}
+		results = append(results, values)
+	}
+
+	if diff := cmp.Diff(results[0], results[1]); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestNewSolverWithConfig(t *testing.T) {
+	array := glee.NewArray(100, 1)
+	x := array.Select(glee.NewConstantExpr(0, 64), 8, false)
+	constraints := []glee.Expr{
+		glee.NewBinaryExpr(glee.UGT, x, glee.NewConstantExpr(10, 8)),
+		glee.NewBinaryExpr(glee.ULT, x, glee.NewConstantExpr(20, 8)),
+	}
+
+	configs := map[string]z3.Config{
+		"Timeout":    {Timeout: time.Minute},
+		"RandomSeed": {RandomSeed: 42},
+		"Logic":      {Logic: "QF_ABV"},
+		"Tactics":    {Tactics: []string{"simplify", "solve-eqs", "smt"}},
+	}
+
+	for name, config := range configs {
+		for _, incremental := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/Incremental=%v", name, incremental), func(t *testing.T) {
+				s, err := z3.NewSolverWithConfig(config)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.Incremental = incremental
+				defer MustCloseSolver(s)
+
+				if satisfiable, values, err := s.Solve(constraints, []*glee.Array{array}); err != nil {
+					t.Fatal(err)
+				} else if !satisfiable {
+					t.Fatal("expected satisfiable")
+				} else if v := values[0][0]; v <= 10 || v >= 20 {
+					t.Fatalf("unexpected value: %d", v)
+				}
+
+				eq := glee.NewBinaryExpr(glee.EQ, x, glee.NewConstantExpr(5, 8))
+				if satisfiable, _, err := s.Solve(append(constraints[:1:1], eq), []*glee.Array{array}); err != nil {
+					t.Fatal(err)
+				} else if satisfiable {
+					t.Fatal("expected unsatisfiable")
+				}
+			})
+		}
+	}
+
+	// Tactics which cannot decide the constraints return an unknown result.
+	t.Run("ErrIncompleteTactics", func(t *testing.T) {
+		s, err := z3.NewSolverWithConfig(z3.Config{Tactics: []string{"simplify"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer MustCloseSolver(s)
+
+		if _, _, err := s.Solve(constraints, []*glee.Array{array}); err != glee.ErrSolverUnknown {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ErrInvalidTactic", func(t *testing.T) {
+		if _, err := z3.NewSolverWithConfig(z3.Config{Tactics: []string{"simplify", "no-such-tactic"}}); err == nil || !strings.Contains(err.Error(), `z3: invalid tactic "no-such-tactic"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+// BenchmarkSolver_ArrayEncoding compares the time to solve a read at a
+// symbolic index for each encoding by array size. The result is used to
+// choose DefaultITEThreshold.
+func BenchmarkSolver_ArrayEncoding(b *testing.B) {
+	encodings := []struct {
+		name     string
+		encoding z3.ArrayEncoding
+	}{
+		{"Store", z3.ArrayEncodingStore},
+		{"Bounded", z3.ArrayEncodingBounded},
+		{"ITE", z3.ArrayEncodingITE},
+	}
+
+	for _, size := range []uint64{8, 32, 128, 512} {
+		for _, enc := range encodings {
+			b.Run(fmt.Sprintf("%s/%d", enc.name, size), func(b *testing.B) {
+				s := z3.NewSolver()
+				s.ArrayEncoding = enc.encoding
+				defer MustCloseSolver(s)
+
+				// Search for the only byte of the array matching a value
+				// where every other byte is constant.
+				array, indexArray := glee.NewArray(100, uint(size)), glee.NewArray(200, 2)
+				index := glee.NewCastExpr(indexArray.Select(glee.NewConstantExpr64(0), 16, false), 64, false)
+				for i := uint64(0); i < size-1; i++ {
+					array = array.Store(glee.NewConstantExpr64(i), glee.NewConstantExpr(i%255, 8), false)
+				}
+				constraints := []glee.Expr{
+					glee.NewBinaryExpr(glee.ULT, index, glee.NewConstantExpr64(size)),
+					glee.NewBinaryExpr(glee.EQ, array.Select(index, 8, false), glee.NewConstantExpr(0xFF, 8)),
+				}
+				arrays := []*glee.Array{array, indexArray}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if satisfiable, _, err := s.Solve(constraints, arrays); err != nil {
+						b.Fatal(err)
+					} else if !satisfiable {
+						b.Fatal("expected satisfiable")
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSolver_EvalArray compares decoding the model's interpretation of
+// a solved array against evaluating each byte by array size.
+func BenchmarkSolver_EvalArray(b *testing.B) {
+	for _, size := range []uint64{64, 1024, 16384} {
+		for _, perByte := range []bool{false, true} {
+			name := "Interp"
+			if perByte {
+				name = "PerByte"
+			}
+
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				s := z3.NewSolver()
+				s.EvalPerByte = perByte
+				defer MustCloseSolver(s)
+
+				// Constrain a byte at both ends so the array is in the model.
+				array := glee.NewArray(100, uint(size))
+				constraints := []glee.Expr{
+					glee.NewBinaryExpr(glee.EQ, array.Select(glee.NewConstantExpr64(0), 8, false), glee.NewConstantExpr(1, 8)),
+					glee.NewBinaryExpr(glee.EQ, array.Select(glee.NewConstantExpr64(size-1), 8, false), glee.NewConstantExpr(2, 8)),
+				}
+				arrays := []*glee.Array{array}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if satisfiable, _, err := s.Solve(constraints, arrays); err != nil {
+						b.Fatal(err)
+					} else if !satisfiable {
+						b.Fatal("expected satisfiable")
+					}
+				}
+			})
+		}
+	}
+}
+
 func MustCloseSolver(s *z3.Solver) {
 	if err := s.Close(); err != nil {
 		panic(err)